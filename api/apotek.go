@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"src/apotek"
+)
+
+// ===============================================
+//   ENDPOINT PESAN OBAT
+// ===============================================
+//
+//   POST /api/pemesanan-obat/pesan            {email_pasien, daftar_obat: {id_obat: jumlah}}
+//   GET  /api/pemesanan-obat/{id}/ringkasan   rincian harga per obat dan total
+//   POST /api/resep/{id}/tebus                 {lama_hari} (opsional) → pesanan dari resep
+//   GET  /api/resep/{id}/pesanan               pesanan hasil tebus resep
+//
+// Logika pesanan ada di paket apotek dan sama dengan rs pharmacy.
+
+const (
+	viaPesanObat  = "POST /api/pemesanan-obat/pesan"
+	viaStatusObat = "pembayaran tagihan (POST /api/tagihan/{nomor}/pembayaran)"
+	// Stok hanya berubah lewat LWT pesanan dan pembatalannya; PATCH biasa
+	// akan menimpa pemotongan stok yang sedang berjalan.
+	viaStokObat = viaPesanObat + " dan pembatalannya (stok awal diisi saat POST /api/obat)"
+)
+
+func (s *Server) routeApotek() {
+	s.mux.HandleFunc("/api/pemesanan-obat/pesan", s.wrap(s.handlePesanObat))
+	s.mux.HandleFunc("/api/pemesanan-obat/{id}/ringkasan", s.wrap(s.handleRingkasanObat))
+	s.mux.HandleFunc("/api/resep/{id}/tebus", s.wrap(s.handleTebusResep))
+	s.mux.HandleFunc("/api/resep/{id}/pesanan", s.wrap(s.handlePesananResep))
+}
+
+func (s *Server) handlePesanObat(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	var body struct {
+		EmailPasien string         `json:"email_pasien"`
+		DaftarObat  map[string]int `json:"daftar_obat"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		return err
+	}
+	ringkasan, err := apotek.Pesan(apotek.Permintaan{EmailPasien: body.EmailPasien, Obat: body.DaftarObat}, time.Now())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/api/pemesanan-obat/"+ringkasan.IdPesanan)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": ringkasan})
+	return nil
+}
+
+func (s *Server) handleRingkasanObat(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	ringkasan, err := apotek.Lihat(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": ringkasan})
+	return nil
+}
+
+func (s *Server) handleTebusResep(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	var body struct {
+		LamaHari int `json:"lama_hari"`
+	}
+	if r.ContentLength != 0 {
+		if err := decodeBody(w, r, &body); err != nil {
+			return err
+		}
+	}
+	hasil, err := apotek.TebusResep(r.PathValue("id"), body.LamaHari, time.Now())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/api/resep/"+hasil.IdResep+"/pesanan")
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": hasil})
+	return nil
+}
+
+func (s *Server) handlePesananResep(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	hasil, err := apotek.StatusResep(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": hasil})
+	return nil
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"src/booking"
+	"src/queries"
+)
+
+// ===============================================
+//   ENDPOINT BOOKING JANJI TEMU
+// ===============================================
+//
+//   GET  /api/tenaga-medis/{email}/jadwal          jadwal praktik yang berlaku
+//   GET  /api/tenaga-medis/{email}/slot            slot kosong (?dari=YYYY-MM-DD&hari=7)
+//   POST /api/janji-temu/pesan                     {email_pasien, email_dokter, waktu, alasan}
+//   POST /api/janji-temu/{id}/jadwal-ulang         {waktu}
+//   POST /api/janji-temu/{id}/batal
+//
+// Logika booking ada di paket booking dan sama dengan rs appointment.
+
+const (
+	viaPesan       = "POST /api/janji-temu/pesan"
+	viaJadwalUlang = "POST /api/janji-temu/{id}/jadwal-ulang"
+)
+
+func (s *Server) routeBooking() {
+	s.mux.HandleFunc("/api/tenaga-medis/{email}/jadwal", s.wrap(s.handleJadwal))
+	s.mux.HandleFunc("/api/tenaga-medis/{email}/slot", s.wrap(s.handleSlot))
+	s.mux.HandleFunc("/api/janji-temu/pesan", s.wrap(s.handlePesan))
+	s.mux.HandleFunc("/api/janji-temu/{id}/jadwal-ulang", s.wrap(s.handleJadwalUlang))
+	s.mux.HandleFunc("/api/janji-temu/{id}/batal", s.wrap(s.handleBatal))
+}
+
+type jadwalJSON struct {
+	Hari       string `json:"hari"`
+	JamMulai   string `json:"jam_mulai"`
+	JamSelesai string `json:"jam_selesai"`
+	DurasiSlot int    `json:"durasi_slot"`
+}
+
+func (s *Server) handleJadwal(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	d, err := booking.JadwalDokter(r.PathValue("email"))
+	if err != nil {
+		return err
+	}
+	jadwal := make([]jadwalJSON, len(d.Jadwal))
+	for i, j := range d.Jadwal {
+		jadwal[i] = jadwalJSON{booking.NamaHari(j.Hari), j.Mulai.String(), j.Selesai.String(), int(j.Slot / time.Minute)}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+		"email":      d.Email,
+		"nama":       d.Nama,
+		"departemen": d.Departemen,
+		"id_rs":      d.IdRS,
+		"sumber":     d.Sumber,
+		"jadwal":     jadwal,
+	}})
+	return nil
+}
+
+func (s *Server) handleSlot(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	var details []string
+	now := time.Now()
+	dari := now
+	if v := r.URL.Query().Get("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			details = append(details, "dari harus berformat YYYY-MM-DD")
+		}
+		dari = t
+	}
+	hari := 7
+	if v := r.URL.Query().Get("hari"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 62 {
+			details = append(details, "hari harus bilangan bulat 1-62")
+		}
+		hari = n
+	}
+	if len(details) > 0 {
+		return &Error{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: "query string tidak valid", Details: details}
+	}
+
+	d, slots, err := booking.SlotKosong(r.PathValue("email"), dari, hari, now)
+	if err != nil {
+		return err
+	}
+	type slotJSON struct {
+		Mulai   string `json:"mulai"`
+		Selesai string `json:"selesai"`
+	}
+	out := make([]slotJSON, len(slots))
+	for i, sl := range slots {
+		out[i] = slotJSON{sl.Mulai.Format(queries.FormatWaktuJanji), sl.Selesai.Format(queries.FormatWaktuJanji)}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": out, "sumber": d.Sumber})
+	return nil
+}
+
+// waktuBody membaca field waktu body booking.
+func waktuBody(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, invalid([]string{"waktu wajib diisi"})
+	}
+	t, err := booking.ParseWaktu(s)
+	if err != nil {
+		return time.Time{}, invalid([]string{err.Error()})
+	}
+	return t, nil
+}
+
+func (s *Server) handlePesan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	var body struct {
+		EmailPasien string `json:"email_pasien"`
+		EmailDokter string `json:"email_dokter"`
+		Waktu       string `json:"waktu"`
+		Alasan      string `json:"alasan"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		return err
+	}
+	waktu, err := waktuBody(body.Waktu)
+	if err != nil {
+		return err
+	}
+
+	b, err := booking.Pesan(booking.Permintaan{
+		EmailPasien: body.EmailPasien,
+		EmailDokter: body.EmailDokter,
+		Waktu:       waktu,
+		Alasan:      body.Alasan,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/api/janji-temu/"+b.IDJanjiTemu)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": b})
+	return nil
+}
+
+func (s *Server) handleJadwalUlang(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	var body struct {
+		Waktu string `json:"waktu"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		return err
+	}
+	waktu, err := waktuBody(body.Waktu)
+	if err != nil {
+		return err
+	}
+	b, err := booking.JadwalUlang(r.PathValue("id"), waktu, time.Now())
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": b})
+	return nil
+}
+
+func (s *Server) handleBatal(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	b, err := booking.Batal(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": b})
+	return nil
+}
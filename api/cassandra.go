@@ -0,0 +1,190 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"src/cassandra"
+)
+
+// ===============================================
+//   CRUD CASSANDRA
+// ===============================================
+//
+// Create memakai INSERT ... IF NOT EXISTS dan update/delete memakai IF EXISTS
+// (lightweight transaction) sehingga key ganda menjadi 409 dan key yang tidak
+// ada menjadi 404 tanpa read terpisah. Field dengan Table disimpan di tabel
+// pendamping (mis. detail_pesanan_obat) dengan key yang sama. Pagination
+// memakai paging state driver sebagai cursor.
+
+// columns mengembalikan kolom tabel utama dan kolom per tabel pendamping.
+func (res *resource) columns() (main []string, companions map[string][]string) {
+	companions = map[string][]string{}
+	for _, f := range res.Fields {
+		if f.Table != "" {
+			companions[f.Table] = append(companions[f.Table], f.Name)
+		} else {
+			main = append(main, f.Name)
+		}
+	}
+	return main, companions
+}
+
+func cassandraList(res *resource, filters record, limit int, state []byte) ([]record, []byte, error) {
+	main, _ := res.columns()
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(main, ", "), res.Table)
+	var args []interface{}
+	if len(filters) > 0 {
+		var where []string
+		for _, name := range sortedKeys(filters) {
+			where = append(where, name+" = ?")
+			args = append(args, filters[name])
+		}
+		query += " WHERE " + strings.Join(where, " AND ") + " ALLOW FILTERING"
+	}
+
+	rows, next, err := cassandra.PageCassandra(query, args, limit, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := make([]record, len(rows))
+	for i, row := range rows {
+		out[i] = record(row)
+		if err := res.readCompanions(out[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, next, nil
+}
+
+func (res *resource) readCompanions(rec record) error {
+	_, companions := res.columns()
+	for table, cols := range companions {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", strings.Join(cols, ", "), table, res.Key)
+		rows, _, err := cassandra.PageCassandra(query, []interface{}{rec[res.Key]}, 1, nil)
+		if err != nil {
+			return err
+		}
+		for _, col := range cols {
+			rec[col] = nil
+			if len(rows) > 0 {
+				rec[col] = rows[0][col]
+			}
+		}
+	}
+	return nil
+}
+
+func cassandraGet(res *resource, key string) (record, error) {
+	main, _ := res.columns()
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", strings.Join(main, ", "), res.Table, res.Key)
+	rows, _, err := cassandra.PageCassandra(query, []interface{}{key}, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, notFound("%s %s tidak ditemukan", res.Title, key)
+	}
+	rec := record(rows[0])
+	return rec, res.readCompanions(rec)
+}
+
+// split memisahkan nilai record per tabel.
+func (res *resource) split(rec record) (main record, companions map[string]record) {
+	main, companions = record{}, map[string]record{}
+	for _, name := range sortedKeys(rec) {
+		f, _ := res.field(name)
+		if f.Table == "" {
+			main[name] = rec[name]
+			continue
+		}
+		if companions[f.Table] == nil {
+			companions[f.Table] = record{}
+		}
+		companions[f.Table][name] = rec[name]
+	}
+	return main, companions
+}
+
+func insertStatement(table string, rec record, suffix string) (string, []interface{}) {
+	cols := sortedKeys(rec)
+	args := make([]interface{}, len(cols))
+	for i, c := range cols {
+		args[i] = rec[c]
+	}
+	marks := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s", table, strings.Join(cols, ", "), marks, suffix), args
+}
+
+func cassandraCreate(res *resource, rec record) (record, error) {
+	main, companions := res.split(rec)
+	query, args := insertStatement(res.Table, main, " IF NOT EXISTS")
+	applied, _, err := cassandra.CASCassandra(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, conflict("%s %v sudah ada", res.Title, rec[res.Key])
+	}
+
+	for table, values := range companions {
+		values[res.Key] = rec[res.Key]
+		query, args := insertStatement(table, values, "")
+		if err := cassandra.InsertCassandra(query, args...); err != nil {
+			// Jangan tinggalkan baris utama tanpa pendampingnya.
+			cassandra.DeleteCassandra(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", res.Table, res.Key), rec[res.Key])
+			return nil, fmt.Errorf("gagal menulis %s: %v", table, err)
+		}
+	}
+	return cassandraGet(res, fmt.Sprint(rec[res.Key]))
+}
+
+func cassandraUpdate(res *resource, key string, rec record) (record, error) {
+	main, companions := res.split(rec)
+
+	if len(main) > 0 {
+		var sets []string
+		var args []interface{}
+		for _, name := range sortedKeys(main) {
+			sets = append(sets, name+" = ?")
+			args = append(args, main[name])
+		}
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ? IF EXISTS", res.Table, strings.Join(sets, ", "), res.Key)
+		applied, _, err := cassandra.CASCassandra(query, append(args, key)...)
+		if err != nil {
+			return nil, err
+		}
+		if !applied {
+			return nil, notFound("%s %s tidak ditemukan", res.Title, key)
+		}
+	} else if _, err := cassandraGet(res, key); err != nil {
+		return nil, err
+	}
+
+	for table, values := range companions {
+		values[res.Key] = key
+		query, args := insertStatement(table, values, "")
+		if err := cassandra.InsertCassandra(query, args...); err != nil {
+			return nil, fmt.Errorf("gagal menulis %s: %v", table, err)
+		}
+	}
+	return cassandraGet(res, key)
+}
+
+func cassandraDelete(res *resource, key string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = ? IF EXISTS", res.Table, res.Key)
+	applied, _, err := cassandra.CASCassandra(query, key)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return notFound("%s %s tidak ditemukan", res.Title, key)
+	}
+	_, companions := res.columns()
+	for table := range companions {
+		if err := cassandra.DeleteCassandra(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, res.Key), key); err != nil {
+			return fmt.Errorf("gagal menghapus %s: %v", table, err)
+		}
+	}
+	return nil
+}
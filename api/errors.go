@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"src/domain"
+)
+
+// ===============================================
+//   RESPONSE & ERROR
+// ===============================================
+//
+// Semua response berbentuk JSON:
+//   sukses  : {"data": ...} (+ "next_cursor" untuk list)
+//   error   : {"error": {"status": 404, "code": "not_found", "message": "...", "details": [...]}}
+
+// Error adalah error yang dikirim ke klien apa adanya. Error lain dianggap
+// error internal: pesannya dicatat di log dan klien hanya menerima 500.
+type Error struct {
+	Status  int      `json:"status"`
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Details) == 0 {
+		return e.Message
+	}
+	return e.Message + ": " + strings.Join(e.Details, "; ")
+}
+
+// Kode error yang dipakai di body response.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeValidation       = "validation_failed"
+	CodeNotFound         = "not_found"
+	CodeUnauthorized     = "unauthorized"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeUnsupportedMedia = "unsupported_media_type"
+	CodeInternal         = "internal_error"
+)
+
+func badRequest(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusNotFound, Code: CodeNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func conflict(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusConflict, Code: CodeConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// invalid membungkus daftar pesan validasi menjadi satu error 422.
+func invalid(details []string) *Error {
+	return &Error{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    CodeValidation,
+		Message: "data tidak valid",
+		Details: details,
+	}
+}
+
+// rejected membuat Error untuk penolakan dari paket layanan (booking,
+// apotek, layanan, tagihan, pembayaran) dengan status 401, 404, 409 atau 422.
+func rejected(status int, message string, details []string) *Error {
+	switch status {
+	case http.StatusUnauthorized:
+		return &Error{Status: status, Code: CodeUnauthorized, Message: message, Details: details}
+	case http.StatusNotFound:
+		return &Error{Status: status, Code: CodeNotFound, Message: message, Details: details}
+	case http.StatusConflict:
+		return &Error{Status: status, Code: CodeConflict, Message: message, Details: details}
+	}
+	if len(details) == 0 {
+		return invalid([]string{message})
+	}
+	return &Error{Status: http.StatusUnprocessableEntity, Code: CodeValidation, Message: message, Details: details}
+}
+
+// domainError memetakan Kind penolakan paket layanan ke status HTTP.
+func domainError(e *domain.Error) *Error {
+	switch e.Kind {
+	case domain.Unauthorized:
+		return rejected(http.StatusUnauthorized, e.Message, e.Details)
+	case domain.NotFound:
+		return rejected(http.StatusNotFound, e.Message, e.Details)
+	case domain.Conflict:
+		return rejected(http.StatusConflict, e.Message, e.Details)
+	}
+	return rejected(http.StatusUnprocessableEntity, e.Message, e.Details)
+}
+
+// joinedDetails memecah error hasil errors.Join menjadi satu pesan per baris.
+func joinedDetails(err error) []string {
+	var details []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			details = append(details, line)
+		}
+	}
+	return details
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(body); err != nil {
+		log.Printf("api: gagal menulis response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *Error
+	var de *domain.Error
+	if !errors.As(err, &apiErr) && errors.As(err, &de) {
+		apiErr = domainError(de)
+	}
+	if apiErr == nil {
+		log.Printf("api: %s %s: %v", r.Method, r.URL.Path, err)
+		apiErr = &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: "terjadi kesalahan pada server"}
+	}
+	writeJSON(w, apiErr.Status, map[string]interface{}{"error": apiErr})
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"src/queries"
+)
+
+// ===============================================
+//   ENDPOINT LAPORAN (query read katalog)
+// ===============================================
+//
+// GET /api/laporan/{nama}?param=nilai memanggil fungsi read yang sama dengan
+// rs read <nama>; parameternya memakai nama flag CLI dengan _ (max_stok).
+
+type laporan struct {
+	Name    string
+	Summary string
+	Run     func(q query) (interface{}, error)
+}
+
+// query membaca parameter query string dengan default, mengumpulkan
+// kesalahan parsing sebagai detail error 400.
+type query struct {
+	r       *http.Request
+	details *[]string
+}
+
+func (q query) text(name, def string, required bool) string {
+	v := strings.TrimSpace(q.r.URL.Query().Get(name))
+	if v == "" {
+		if required {
+			*q.details = append(*q.details, name+" wajib diisi")
+		}
+		return def
+	}
+	return v
+}
+
+func (q query) int(name string, def, min int) int {
+	v := q.r.URL.Query().Get(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		*q.details = append(*q.details, fmt.Sprintf("%s harus bilangan bulat >= %d", name, min))
+		return def
+	}
+	return n
+}
+
+func static[T any](fn func() (T, error)) func(query) (interface{}, error) {
+	return func(query) (interface{}, error) { return fn() }
+}
+
+// ok melaporkan apakah semua parameter valid; laporan hanya dijalankan
+// bila ok.
+func (q query) ok() bool { return len(*q.details) == 0 }
+
+var laporans = []laporan{
+	{"order-counts", "jumlah pesanan obat per pasien", static(queries.PatientOrderCounts)},
+	{"low-stock", "obat dengan stok < ?max_stok= (default 55)", func(q query) (interface{}, error) {
+		maxStok := q.int("max_stok", 55, 1)
+		if !q.ok() {
+			return nil, nil
+		}
+		return queries.LowStockMedicines(maxStok)
+	}},
+	{"baymin-logs", "log Baymin milik pasien ?email=", func(q query) (interface{}, error) {
+		email := q.text("email", "", true)
+		if email != "" {
+			if err := queries.ValidEmail(email); err != nil {
+				*q.details = append(*q.details, err.Error())
+			}
+		}
+		if !q.ok() {
+			return nil, nil
+		}
+		return queries.BayminLogs(email)
+	}},
+	{"doctor-appointments", "jumlah janji temu per tenaga medis", static(queries.MedikJanjiTemuCounts)},
+	{"prescription", "detail resep janji temu ?id_janji_temu=", func(q query) (interface{}, error) {
+		id := q.text("id_janji_temu", "", true)
+		if !q.ok() {
+			return nil, nil
+		}
+		return queries.DetailResepJanjiTemu(id)
+	}},
+	{"top-customers", "pasien dengan biaya pemesanan obat terbesar", static(queries.PatientOrderCosts)},
+	{"popular-services", "layanan medis yang paling sering dipesan", static(queries.MostOrderedServices)},
+	{"top-hospitals", "rumah sakit dengan janji temu terbanyak", static(queries.TopHospitalsByAppointments)},
+	{"hospitals-by-staff", "rumah sakit dengan tenaga medis terbanyak", static(queries.HospitalsByMedicalStaff)},
+	{"patients-without-prescription", "pasien dengan janji temu tanpa resep", static(queries.PatientsWithoutPrescriptions)},
+	{"specialists", "tenaga medis ?profesi= di ?kota= (default Dokter Spesialis Anak, Bandung), maks ?max=", func(q query) (interface{}, error) {
+		profesi := q.text("profesi", "Dokter Spesialis Anak", false)
+		kota := q.text("kota", "Bandung", false)
+		max := q.int("max", 50, 1)
+		if !q.ok() {
+			return nil, nil
+		}
+		return queries.DokterSpesialisDiKota(profesi, kota, max)
+	}},
+}
+
+func (s *Server) handleLaporan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	name := r.PathValue("name")
+	if name == "" {
+		list := make([]map[string]string, len(laporans))
+		for i, l := range laporans {
+			list[i] = map[string]string{"name": l.Name, "summary": l.Summary, "path": "/api/laporan/" + l.Name}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+		return nil
+	}
+
+	for _, l := range laporans {
+		if l.Name != name {
+			continue
+		}
+		var details []string
+		data, err := l.Run(query{r: r, details: &details})
+		if len(details) > 0 {
+			return &Error{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: "parameter laporan tidak valid", Details: details}
+		}
+		if err != nil {
+			return err
+		}
+		if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+			data = []struct{}{} // hasil kosong tetap array, bukan null
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+		return nil
+	}
+	return notFound("laporan %s tidak dikenal (lihat GET /api/laporan)", name)
+}
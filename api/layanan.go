@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"src/booking"
+	"src/layanan"
+	"src/queries"
+)
+
+// ===============================================
+//   ENDPOINT PESAN LAYANAN
+// ===============================================
+//
+//   POST /api/pemesanan-layanan/pesan             {email_pasien, id_rs, id_layanan, jadwal}
+//   POST /api/pemesanan-layanan/{id}/batal        batalkan dan kembalikan kuota
+//   GET  /api/pasien/{email}/pemesanan-layanan    ?limit=, jadwal terbaru dulu
+//   GET  /api/rumah-sakit/{id}/jadwal-layanan     ?dari=YYYY-MM-DD&hari=&limit=
+//
+// Logika pemesanan ada di paket layanan dan sama dengan rs service.
+
+const (
+	viaPesanLayanan  = "POST /api/pemesanan-layanan/pesan"
+	viaBatalLayanan  = "POST /api/pemesanan-layanan/{id}/batal"
+	viaStatusLayanan = viaBatalLayanan + " atau pembayaran tagihan (POST /api/tagihan/{nomor}/pembayaran)"
+)
+
+func (s *Server) routeLayanan() {
+	s.mux.HandleFunc("/api/pemesanan-layanan/pesan", s.wrap(s.handlePesanLayanan))
+	s.mux.HandleFunc("/api/pemesanan-layanan/{id}/batal", s.wrap(s.handleBatalLayanan))
+	s.mux.HandleFunc("/api/pasien/{email}/pemesanan-layanan", s.wrap(s.handleLayananPasien))
+	s.mux.HandleFunc("/api/rumah-sakit/{id}/jadwal-layanan", s.wrap(s.handleJadwalLayanan))
+}
+
+func (s *Server) handlePesanLayanan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	var body struct {
+		EmailPasien string `json:"email_pasien"`
+		IdRS        string `json:"id_rs"`
+		IdLayanan   string `json:"id_layanan"`
+		Jadwal      string `json:"jadwal"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		return err
+	}
+	if body.Jadwal == "" {
+		return invalid([]string{"jadwal wajib diisi"})
+	}
+	jadwal, err := booking.ParseWaktu(body.Jadwal)
+	if err != nil {
+		return invalid([]string{err.Error()})
+	}
+
+	p, err := layanan.Pesan(layanan.Permintaan{
+		EmailPasien: body.EmailPasien,
+		IdRS:        body.IdRS,
+		IdLayanan:   body.IdLayanan,
+		Jadwal:      jadwal,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/api/pemesanan-layanan/"+p.IdPesanan)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": p})
+	return nil
+}
+
+func (s *Server) handleBatalLayanan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	p, err := layanan.Batal(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": p})
+	return nil
+}
+
+func (s *Server) handleLayananPasien(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	limit, err := s.limitQuery(r)
+	if err != nil {
+		return err
+	}
+	list, err := layanan.PesananPasien(r.PathValue("email"), limit)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+	return nil
+}
+
+func (s *Server) handleJadwalLayanan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	limit, err := s.limitQuery(r)
+	if err != nil {
+		return err
+	}
+	var details []string
+	now := time.Now().In(queries.ZonaJadwal)
+	dari := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, queries.ZonaJadwal)
+	if v := r.URL.Query().Get("dari"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, queries.ZonaJadwal)
+		if err != nil {
+			details = append(details, "dari harus berformat YYYY-MM-DD")
+		}
+		dari = t
+	}
+	hari := 7
+	if v := r.URL.Query().Get("hari"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 62 {
+			details = append(details, "hari harus bilangan bulat 1-62")
+		}
+		hari = n
+	}
+	if len(details) > 0 {
+		return &Error{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: "query string tidak valid", Details: details}
+	}
+
+	list, err := layanan.JadwalRS(r.PathValue("id"), dari, hari, limit)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": list, "dari": dari.Format("2006-01-02"), "hari": hari})
+	return nil
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"src/neo4j"
+)
+
+// ===============================================
+//   CRUD NEO4J
+// ===============================================
+//
+// Node dibaca sebagai map projection berisi properti (tanpa field Secret)
+// dan key node ujung setiap link. Pagination memakai keyset pada key node:
+// cursor adalah key terakhir halaman sebelumnya.
+
+// projection membangun map projection node n untuk RETURN. Field Secret
+// hanya ikut bila secret true (untuk Check, tidak pernah ke klien).
+func (res *resource) projection(secret bool) string {
+	var parts []string
+	for _, f := range res.Fields {
+		switch {
+		case f.Secret && !secret:
+		case f.Link != nil:
+			parts = append(parts, fmt.Sprintf("%s: head([%s | x.%s])", f.Name, f.Link.pattern("n", "", "x:"+f.Link.Label), f.Link.Key))
+		default:
+			parts = append(parts, "."+f.Name)
+		}
+	}
+	return "n {" + strings.Join(parts, ", ") + "}"
+}
+
+// props memisahkan properti node dari link.
+func (res *resource) props(rec record) (props, links record) {
+	props, links = record{}, record{}
+	for name, v := range rec {
+		if f, _ := res.field(name); f.Link != nil {
+			links[name] = v
+		} else {
+			props[name] = v
+		}
+	}
+	return props, links
+}
+
+func neo4jList(res *resource, filters record, limit int, after string) ([]record, string, error) {
+	where := []string{"($after IS NULL OR n." + res.Key + " > $after)"}
+	params := map[string]interface{}{"limit": limit + 1, "after": nil}
+	if after != "" {
+		params["after"] = after
+	}
+	for i, name := range sortedKeys(filters) {
+		p := fmt.Sprintf("f%d", i)
+		params[p] = filters[name]
+		f, _ := res.field(name)
+		if f.Link != nil {
+			where = append(where, "exists { "+f.Link.pattern("n", "", fmt.Sprintf(":%s {%s: $%s}", f.Link.Label, f.Link.Key, p))+" }")
+		} else {
+			where = append(where, fmt.Sprintf("n.%s = $%s", name, p))
+		}
+	}
+
+	query := fmt.Sprintf("MATCH (n:%s) WHERE %s WITH n ORDER BY n.%s LIMIT $limit RETURN %s AS row",
+		res.Label, strings.Join(where, " AND "), res.Key, res.projection(false))
+	records, err := neo4j.ReadNeo4j(query, params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows := make([]record, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, plainRecord(r["row"]))
+	}
+	next := ""
+	if len(rows) > limit {
+		rows = rows[:limit]
+		next = fmt.Sprint(rows[limit-1][res.Key])
+	}
+	return rows, next, nil
+}
+
+func neo4jGet(res *resource, key string) (record, error) {
+	return neo4jRead(res, key, false)
+}
+
+// neo4jStored membaca node beserta field Secret untuk checkUpdate.
+func neo4jStored(res *resource, key string) (record, error) {
+	return neo4jRead(res, key, true)
+}
+
+func neo4jRead(res *resource, key string, secret bool) (record, error) {
+	query := fmt.Sprintf("MATCH (n:%s {%s: $key}) RETURN %s AS row", res.Label, res.Key, res.projection(secret))
+	records, err := neo4j.ReadNeo4j(query, map[string]interface{}{"key": key})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, notFound("%s %s tidak ditemukan", res.Title, key)
+	}
+	return plainRecord(records[0]["row"]), nil
+}
+
+func plainRecord(v interface{}) record {
+	m, _ := neo4j.Plain(v).(map[string]interface{})
+	return record(m)
+}
+
+// checkLinks memastikan setiap node ujung link ada, agar klien menerima 422
+// yang jelas alih-alih create/update yang diam-diam tidak terjadi.
+func (res *resource) checkLinks(links record) error {
+	var details []string
+	for _, name := range sortedKeys(links) {
+		f, _ := res.field(name)
+		query := fmt.Sprintf("MATCH (x:%s {%s: $value}) RETURN count(x) AS n", f.Link.Label, f.Link.Key)
+		records, err := neo4j.ReadNeo4j(query, map[string]interface{}{"value": links[name]})
+		if err != nil {
+			return err
+		}
+		if n, _ := records[0]["n"].(int64); n == 0 {
+			details = append(details, fmt.Sprintf("%s: %s %v tidak ditemukan", name, f.Link.Label, links[name]))
+		}
+	}
+	if len(details) > 0 {
+		return invalid(details)
+	}
+	return nil
+}
+
+func neo4jCreate(res *resource, rec record) (record, error) {
+	props, links := res.props(rec)
+	if err := res.checkLinks(links); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{"props": map[string]interface{}(props)}
+	var match, create []string
+	for i, name := range sortedKeys(links) {
+		f, _ := res.field(name)
+		t := fmt.Sprintf("t%d", i)
+		params[t] = links[name]
+		match = append(match, fmt.Sprintf("MATCH (%s:%s {%s: $%s})", t, f.Link.Label, f.Link.Key, t))
+		create = append(create, "CREATE "+f.Link.pattern("n", "", t))
+	}
+	query := strings.Join(append(append(match,
+		fmt.Sprintf("CREATE (n:%s) SET n = $props", res.Label)), create...), " ") +
+		fmt.Sprintf(" RETURN n.%s AS key", res.Key)
+
+	records, err := neo4j.CreateAndReturnNeo4j(query, params)
+	switch {
+	case neo4j.IsConstraintViolation(err):
+		return nil, conflict("%s %v sudah ada", res.Title, rec[res.Key])
+	case err != nil:
+		return nil, err
+	case len(records) == 0:
+		return nil, conflict("data relasi %s berubah saat menyimpan, coba lagi", res.Title)
+	}
+	return neo4jGet(res, fmt.Sprint(rec[res.Key]))
+}
+
+func neo4jUpdate(res *resource, key string, rec record) (record, error) {
+	props, links := res.props(rec)
+	if err := res.checkLinks(links); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{"key": key, "props": map[string]interface{}(props)}
+	clauses := []string{fmt.Sprintf("MATCH (n:%s {%s: $key})", res.Label, res.Key)}
+	for i, name := range sortedKeys(links) {
+		f, _ := res.field(name)
+		t := fmt.Sprintf("t%d", i)
+		params[t] = links[name]
+		clauses = append(clauses, fmt.Sprintf("MATCH (%s:%s {%s: $%s})", t, f.Link.Label, f.Link.Key, t))
+	}
+	clauses = append(clauses, "SET n += $props")
+	for i, name := range sortedKeys(links) {
+		f, _ := res.field(name)
+		t := fmt.Sprintf("t%d", i)
+		// Satu link = satu relationship: ganti yang lama.
+		clauses = append(clauses,
+			fmt.Sprintf("FOREACH (r IN [%s | old] | DELETE r)", f.Link.pattern("n", "old", ":"+f.Link.Label)),
+			"CREATE "+f.Link.pattern("n", "", t))
+	}
+	clauses = append(clauses, fmt.Sprintf("RETURN n.%s AS key", res.Key))
+
+	records, err := neo4j.CreateAndReturnNeo4j(strings.Join(clauses, " "), params)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, notFound("%s %s tidak ditemukan", res.Title, key)
+	}
+	return neo4jGet(res, key)
+}
+
+// neo4jDelete memeriksa guard dan menghapus node dalam satu statement
+// (satu transaksi), jadi relationship yang dibuat di antara pemeriksaan
+// dan penghapusan tidak ikut terhapus diam-diam.
+func neo4jDelete(res *resource, key string) error {
+	records, err := neo4j.CreateAndReturnNeo4j(res.deleteStatement(), map[string]interface{}{"key": key})
+	if err != nil {
+		return err
+	}
+	return res.deleted(key, records)
+}
+
+// deleteStatement membangun statement delete: setiap guard dihitung sebagai
+// g0, g1, ... dan node (beserta Cascade) hanya dihapus bila tidak ada guard
+// yang terpenuhi.
+func (res *resource) deleteStatement() string {
+	clauses := []string{fmt.Sprintf("MATCH (n:%s {%s: $key})", res.Label, res.Key)}
+	guards := make([]string, len(res.Guards))
+	for i, g := range res.Guards {
+		guards[i] = fmt.Sprintf("g%d", i)
+		clauses = append(clauses, fmt.Sprintf("WITH n%s, exists { %s } AS g%d", withGuards(guards[:i]), g.Pattern, i))
+	}
+	remove := []string{}
+	for _, rel := range res.Cascade {
+		remove = append(remove, fmt.Sprintf("FOREACH (c IN [(n)-[:%s]->(d) | d] | DETACH DELETE c)", rel))
+	}
+	remove = append(remove, "DETACH DELETE n")
+	if len(guards) == 0 {
+		clauses = append(clauses, remove...)
+	} else {
+		clauses = append(clauses, fmt.Sprintf("FOREACH (hapus IN CASE WHEN %s THEN [] ELSE [1] END | %s)", strings.Join(guards, " OR "), strings.Join(remove, " ")))
+	}
+	clauses = append(clauses, "RETURN true AS found"+withGuards(guards))
+	return strings.Join(clauses, " ")
+}
+
+func withGuards(guards []string) string {
+	if len(guards) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(guards, ", ")
+}
+
+// deleted menerjemahkan hasil deleteStatement: tanpa baris berarti node
+// tidak ada, guard yang bernilai true berarti node tidak dihapus.
+func (res *resource) deleted(key string, records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return notFound("%s %s tidak ditemukan", res.Title, key)
+	}
+	for i, g := range res.Guards {
+		if blocked, _ := records[0][fmt.Sprintf("g%d", i)].(bool); blocked {
+			return conflict("%s %s tidak bisa dihapus: %s", res.Title, key, g.Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"src/pembayaran"
+)
+
+// ===============================================
+//   ENDPOINT PEMBAYARAN
+// ===============================================
+//
+//   POST /api/tagihan/{nomor}/pembayaran       {gateway, berlaku_menit} → payment intent baru
+//   GET  /api/tagihan/{nomor}/pembayaran       pembayaran tagihan, terbaru dulu
+//   GET  /api/pembayaran/{id}                  status satu pembayaran
+//   POST /api/pembayaran/webhook/{gateway}     webhook dari gateway (body mentah, bertanda tangan)
+//
+// Webhook yang dikirim ulang dengan id event yang sama tetap dibalas 200
+// dengan "duplikat": true. Logika pembayaran ada di paket pembayaran dan
+// sama dengan rs payment.
+
+func (s *Server) routePembayaran() {
+	s.mux.HandleFunc("/api/tagihan/{nomor}/pembayaran", s.wrap(s.handlePembayaranTagihan))
+	s.mux.HandleFunc("/api/pembayaran/{id}", s.wrap(s.handlePembayaran))
+	s.mux.HandleFunc("/api/pembayaran/webhook/{gateway}", s.wrap(s.handleWebhookPembayaran))
+}
+
+func (s *Server) handlePembayaranTagihan(w http.ResponseWriter, r *http.Request) error {
+	nomor := r.PathValue("nomor")
+	switch r.Method {
+	case http.MethodGet:
+		list, err := pembayaran.DaftarTagihan(nomor)
+		if err != nil {
+			return err
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": list})
+		return nil
+	case http.MethodPost:
+	default:
+		return methodNotAllowed(http.MethodGet, http.MethodPost)
+	}
+
+	var body struct {
+		Gateway      string `json:"gateway"`
+		BerlakuMenit int    `json:"berlaku_menit"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		return err
+	}
+	if body.Gateway == "" {
+		body.Gateway = pembayaran.NamaLokal
+	}
+	if body.BerlakuMenit < 0 {
+		return invalid([]string{"berlaku_menit tidak boleh negatif"})
+	}
+	p, err := pembayaran.Buat(pembayaran.Permintaan{
+		NomorTagihan: nomor,
+		Gateway:      body.Gateway,
+		Berlaku:      time.Duration(body.BerlakuMenit) * time.Minute,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/api/pembayaran/"+p.IdPembayaran)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": p})
+	return nil
+}
+
+func (s *Server) handlePembayaran(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	p, err := pembayaran.Lihat(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": p})
+	return nil
+}
+
+// handleWebhookPembayaran membaca body mentah karena tanda tangan gateway
+// dihitung atas byte body persis seperti yang dikirim.
+func (s *Server) handleWebhookPembayaran(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &Error{Status: http.StatusRequestEntityTooLarge, Code: CodeBadRequest, Message: fmt.Sprintf("body melebihi %d byte", maxBodyBytes)}
+		}
+		return badRequest("gagal membaca body: %v", err)
+	}
+	h, err := pembayaran.Terima(r.PathValue("gateway"), body, r.Header, time.Now())
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": h})
+	return nil
+}
@@ -0,0 +1,627 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/indonesia"
+	"src/queries"
+	"src/uang"
+)
+
+// ===============================================
+//   RESOURCE
+// ===============================================
+//
+// Setiap resource REST dideskripsikan sekali (label/tabel, key, field,
+// relationship) dan statement CRUD-nya dibangkitkan dari deskripsi ini,
+// sama seperti seeder.Entity. Query laporan tetap memakai katalog query
+// lewat paket queries (lihat laporan.go).
+
+const (
+	storeCassandra = "cassandra"
+	storeNeo4j     = "neo4j"
+)
+
+type fieldType int
+
+const (
+	typeText      fieldType = iota
+	typeInt                 // int
+	typeFloat               // double
+	typeUang                // double berisi rupiah, paling banyak 2 desimal
+	typeDate                // "YYYY-MM-DD" (teks)
+	typeLocalTime           // "YYYY-MM-DD HH:MM:SS" (teks, format JanjiTemu di Neo4j)
+	typeTimestamp           // timestamp Cassandra, JSON RFC3339
+	typeCounts              // map<text, int>, mis. daftar_obat
+)
+
+type field struct {
+	Name        string
+	Type        fieldType
+	Required    bool               // wajib saat create
+	Secret      bool               // hanya bisa ditulis, tidak pernah dikembalikan
+	Filter      bool               // boleh dipakai sebagai ?nama=nilai saat list
+	Enum        []string           // nilai yang diperbolehkan
+	NonNegative bool               // angka tidak boleh < 0
+	Format      func(string) error // validasi format teks
+	Default     func() interface{} // nilai saat create bila tidak diisi
+
+	Table string // Cassandra: disimpan di tabel pendamping dengan key yang sama
+	Link  *link  // Neo4j: disimpan sebagai relationship, bukan properti
+
+	// Via berarti field hanya diubah lewat endpoint khusus (mis. booking),
+	// bukan lewat PATCH generik. Tanpa CreateVia field tetap diisi saat
+	// create (mis. stok awal obat).
+	Via string
+}
+
+// link adalah field yang direpresentasikan sebagai relationship ke node lain,
+// mis. email_pasien pada JanjiTemu = (j)-[:memiliki_janji]->(:Pasien {email}).
+type link struct {
+	Rel      string
+	Incoming bool // (target)-[:Rel]->(n)
+	Label    string
+	Key      string
+}
+
+func (l link) pattern(from, rel, to string) string {
+	if l.Incoming {
+		return fmt.Sprintf("(%s)<-[%s:%s]-(%s)", from, rel, l.Rel, to)
+	}
+	return fmt.Sprintf("(%s)-[%s:%s]->(%s)", from, rel, l.Rel, to)
+}
+
+// guard mencegah delete selama pola relationship masih ada.
+type guard struct {
+	Pattern string // pola Cypher dari node n, mis. (n)<-[:memiliki_janji]-(:JanjiTemu)
+	Message string
+}
+
+type resource struct {
+	Name     string // segmen URL, mis. janji-temu
+	Title    string // untuk pesan error, mis. "janji temu"
+	Store    string
+	Label    string // Neo4j
+	Table    string // Cassandra
+	Key      string
+	IDPrefix string // bila diisi, key dibuat otomatis saat create tanpa key
+	Fields   []field
+
+	Cascade []string // Neo4j: relationship keluar yang node ujungnya ikut dihapus
+	Guards  []guard  // Neo4j
+
+	// Check memvalidasi record secara utuh (lintas field): record create,
+	// atau record tersimpan yang digabung dengan perubahan PATCH.
+	Check func(rec record) error
+
+	// CreateVia berarti POST koleksi ditolak; record dibuat lewat endpoint
+	// tersebut.
+	CreateVia string
+	// DeleteVia berarti DELETE item ditolak; record dibatalkan lewat
+	// endpoint tersebut agar kuota dan tabel query ikut dibereskan.
+	DeleteVia string
+}
+
+// record adalah satu baris/node dengan nilai yang sudah dikonversi ke tipe
+// driver (int64, float64, time.Time, map[string]int, string).
+type record map[string]interface{}
+
+func (res *resource) field(name string) (field, bool) {
+	for _, f := range res.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func (res *resource) fieldNames() []string {
+	names := make([]string, len(res.Fields))
+	for i, f := range res.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// ===============================================
+//   DEFINISI
+// ===============================================
+
+var (
+	statusPemesanan = []string{"belum dibayar", "dijadwalkan", "sedang berlangsung", "selesai", "dibatalkan"}
+	labelObat       = []string{"analgesik", "antibiotik", "obat herbal"}
+	jenisLayanan    = []string{"vaksinasi", "fisioterapi", "laboratorium", "radiologi", "konsultasi", "rehabilitasi"}
+	jenisKelamin    = []string{indonesia.JenisKelaminLaki, indonesia.JenisKelaminPerempuan}
+)
+
+func now() interface{} { return time.Now().UTC().Truncate(time.Millisecond) }
+
+func constant(v string) func() interface{} { return func() interface{} { return v } }
+
+func person(extra ...field) []field {
+	fields := []field{
+		{Name: "email", Required: true, Format: queries.ValidEmail},
+		{Name: "nik"},
+		{Name: "kata_sandi", Required: true, Secret: true},
+		{Name: "nama_lengkap", Required: true},
+		{Name: "jenis_kelamin", Enum: jenisKelamin},
+		{Name: "tanggal_lahir", Type: typeDate, Required: true},
+		{Name: "nomor_telepon"},
+		{Name: "provinsi", Filter: true},
+		{Name: "kota", Filter: true},
+		{Name: "kecamatan"},
+		{Name: "jalan"},
+	}
+	return append(fields, extra...)
+}
+
+var resources = []*resource{
+	{
+		Name: "pasien", Title: "pasien", Store: storeNeo4j, Label: "Pasien", Key: "email",
+		Fields: person(),
+		Guards: []guard{{"(n)<-[:memiliki_janji]-(:JanjiTemu)", "pasien masih memiliki janji temu"}},
+		Check:  checkAs[queries.Pasien],
+	},
+	{
+		Name: "tenaga-medis", Title: "tenaga medis", Store: storeNeo4j, Label: "TenagaMedis", Key: "email",
+		Fields: person(
+			field{Name: "NIKes"},
+			field{Name: "profesi", Required: true, Filter: true},
+			field{Name: "nama_departemen", Filter: true, Link: &link{Rel: "bekerja_di", Label: "Departemen", Key: "nama_departemen"}},
+		),
+		Guards: []guard{{"(n)<-[:dengan_dokter]-(:JanjiTemu)", "tenaga medis masih memiliki janji temu"}},
+	},
+	{
+		Name: "rumah-sakit", Title: "rumah sakit", Store: storeNeo4j, Label: "RumahSakit", Key: "id_rs",
+		Fields: []field{
+			{Name: "id_rs", Required: true},
+			{Name: "email", Format: queries.ValidEmail},
+			{Name: "nama_rumah_sakit", Required: true},
+			{Name: "no_telepon"},
+			{Name: "provinsi", Filter: true},
+			{Name: "kota", Required: true, Filter: true},
+			{Name: "kecamatan"},
+			{Name: "jalan"},
+		},
+		Guards: []guard{
+			{"(n)-[:memiliki_departemen]->(:Departemen)", "rumah sakit masih memiliki departemen"},
+			{"(n)<-[:di_rs]-(:JanjiTemu)", "rumah sakit masih memiliki janji temu"},
+		},
+		Check: checkAs[queries.RumahSakit],
+	},
+	{
+		Name: "departemen", Title: "departemen", Store: storeNeo4j, Label: "Departemen", Key: "nama_departemen",
+		Fields: []field{
+			{Name: "nama_departemen", Required: true},
+			{Name: "gedung"},
+			{Name: "id_rs", Required: true, Filter: true, Link: &link{Rel: "memiliki_departemen", Incoming: true, Label: "RumahSakit", Key: "id_rs"}},
+		},
+		Guards: []guard{{"(n)<-[:bekerja_di]-(:TenagaMedis)", "departemen masih memiliki tenaga medis"}},
+	},
+	{
+		Name: "layanan-medis", Title: "layanan medis", Store: storeNeo4j, Label: "LayananMedis", Key: "id_layanan",
+		Fields: []field{
+			{Name: "id_layanan", Required: true},
+			{Name: "nama_layanan", Required: true, Filter: true, Enum: jenisLayanan},
+			{Name: "biaya_layanan", Type: typeUang, Required: true, NonNegative: true},
+		},
+	},
+	{
+		Name: "obat", Title: "obat", Store: storeCassandra, Table: "obat", Key: "id_obat",
+		Fields: []field{
+			{Name: "id_obat", Required: true},
+			{Name: "nama", Required: true},
+			{Name: "label", Filter: true, Enum: labelObat},
+			{Name: "harga", Type: typeUang, Required: true, NonNegative: true},
+			{Name: "stok", Type: typeInt, Required: true, NonNegative: true, Via: viaStokObat},
+		},
+	},
+	{
+		Name: "janji-temu", Title: "janji temu", Store: storeNeo4j, Label: "JanjiTemu", Key: "id_janji_temu", IDPrefix: "JT-",
+		Fields: []field{
+			{Name: "id_janji_temu"},
+			{Name: "waktu_pelaksanaan", Type: typeLocalTime, Required: true, Via: viaJadwalUlang},
+			{Name: "durasi_menit", Type: typeInt, Via: viaJadwalUlang},
+			{Name: "alasan"},
+			{Name: "status", Filter: true, Enum: statusPemesanan, Default: constant("dijadwalkan")},
+			{Name: "email_pasien", Required: true, Filter: true, Via: viaPesan, Link: &link{Rel: "memiliki_janji", Label: "Pasien", Key: "email"}},
+			{Name: "email_dokter", Required: true, Filter: true, Via: viaPesan, Link: &link{Rel: "dengan_dokter", Label: "TenagaMedis", Key: "email"}},
+			{Name: "id_rs", Required: true, Filter: true, Via: viaPesan, Link: &link{Rel: "di_rs", Label: "RumahSakit", Key: "id_rs"}},
+		},
+		// Janji temu dibuat dan dipindah lewat endpoint booking agar jadwal
+		// praktik dan bentrok selalu diperiksa (lihat booking.go).
+		CreateVia: viaPesan,
+		Guards:    []guard{{"(n)-[:menghasilkan_resep]->(:Resep)", "janji temu sudah menghasilkan resep"}},
+	},
+	{
+		Name: "resep", Title: "resep", Store: storeNeo4j, Label: "Resep", Key: "id_resep", IDPrefix: "R-",
+		Fields: []field{
+			{Name: "id_resep"},
+			{Name: "penyakit", Required: true},
+			{Name: "id_janji_temu", Required: true, Filter: true, Link: &link{Rel: "menghasilkan_resep", Incoming: true, Label: "JanjiTemu", Key: "id_janji_temu"}},
+		},
+		Cascade: []string{"memiliki_detail"},
+	},
+	{
+		Name: "pemesanan-obat", Title: "pemesanan obat", Store: storeCassandra, Table: "pemesanan_obat", Key: "id_pesanan", IDPrefix: "POB-",
+		Fields: []field{
+			{Name: "id_pesanan"},
+			{Name: "email_pemesan", Required: true, Filter: true, Format: queries.ValidEmail},
+			{Name: "waktu_pemesanan", Type: typeTimestamp, Default: now},
+			{Name: "status_pemesanan", Filter: true, Enum: statusPemesanan, Default: constant("belum dibayar"), Via: viaStatusObat},
+			{Name: "total_harga", Type: typeUang, Via: viaPesanObat},
+			{Name: "daftar_obat", Type: typeCounts, Required: true, Table: "detail_pesanan_obat", Via: viaPesanObat},
+		},
+		// Pesanan obat dibuat lewat layanan apotek agar harga dan stok
+		// selalu diambil dari tabel obat (lihat apotek.go).
+		CreateVia: viaPesanObat,
+	},
+	{
+		Name: "pemesanan-layanan", Title: "pemesanan layanan", Store: storeCassandra, Table: "pemesanan_layanan", Key: "id_pesanan", IDPrefix: "PL-",
+		Fields: []field{
+			{Name: "id_pesanan"},
+			{Name: "email_pemesan", Required: true, Filter: true, Format: queries.ValidEmail, Via: viaPesanLayanan},
+			{Name: "id_rs", Filter: true, Via: viaPesanLayanan},
+			{Name: "id_layanan", Filter: true, Via: viaPesanLayanan},
+			{Name: "nama_layanan", Via: viaPesanLayanan},
+			{Name: "biaya_layanan", Type: typeUang, Via: viaPesanLayanan},
+			{Name: "waktu_pemesanan", Type: typeTimestamp, Default: now},
+			{Name: "jadwal_pelaksanaan", Type: typeTimestamp, Required: true, Via: viaPesanLayanan},
+			{Name: "status_pemesanan", Filter: true, Enum: statusPemesanan, Default: constant("belum dibayar"), Via: viaStatusLayanan},
+		},
+		// Pemesanan layanan dibuat dan dibatalkan lewat paket layanan agar
+		// penawaran RS, biaya, kapasitas harian dan tabel query per pasien/RS
+		// tetap konsisten (lihat layanan.go).
+		CreateVia: viaPesanLayanan,
+		DeleteVia: viaBatalLayanan,
+	},
+}
+
+func lookupResource(name string) (*resource, bool) {
+	for _, res := range resources {
+		if res.Name == name {
+			return res, true
+		}
+	}
+	return nil, false
+}
+
+// checkAs memvalidasi record dengan Validate milik struct di paket queries
+// (tag JSON struct tersebut sama dengan nama field resource).
+func checkAs[T interface{ Validate() error }](rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return v.Validate()
+}
+
+// ===============================================
+//   DECODE & VALIDASI
+// ===============================================
+
+const maxBodyBytes = 1 << 20
+
+// decodeBody membaca satu objek JSON dari body ke v. Field yang tidak
+// dikenal struct v ditolak.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return &Error{Status: http.StatusUnsupportedMediaType, Code: CodeUnsupportedMedia, Message: "Content-Type harus application/json"}
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &Error{Status: http.StatusRequestEntityTooLarge, Code: CodeBadRequest, Message: fmt.Sprintf("body melebihi %d byte", maxBodyBytes)}
+		}
+		if errors.Is(err, io.EOF) {
+			return badRequest("body kosong, kirim objek JSON")
+		}
+		return badRequest("body bukan objek JSON yang valid: %v", err)
+	}
+	if dec.More() {
+		return badRequest("body hanya boleh berisi satu objek JSON")
+	}
+	return nil
+}
+
+// decodeRecord membaca body JSON menjadi record. Untuk create, field wajib
+// diperiksa dan nilai default/key otomatis diisi; untuk update (PATCH) hanya
+// field yang dikirim yang diperiksa, dan null berarti mengosongkan field.
+func (res *resource) decodeRecord(w http.ResponseWriter, r *http.Request, create bool) (record, error) {
+	var raw map[string]json.RawMessage
+	if err := decodeBody(w, r, &raw); err != nil {
+		return nil, err
+	}
+
+	rec := record{}
+	var details []string
+	failed := map[string]bool{} // field yang sudah gagal tidak dilaporkan lagi sebagai wajib
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f, ok := res.field(name)
+		if !ok {
+			details = append(details, fmt.Sprintf("field %s tidak dikenal (pilihan: %s)", name, strings.Join(res.fieldNames(), ", ")))
+			continue
+		}
+		via := f.Via != "" && !create
+		if string(raw[name]) == "null" && !via {
+			if create || f.Required || f.Name == res.Key || f.Link != nil {
+				details = append(details, fmt.Sprintf("%s tidak boleh null", name))
+				failed[name] = true
+			} else {
+				rec[name] = nil
+			}
+			continue
+		}
+		if via {
+			details = append(details, fmt.Sprintf("%s hanya bisa diubah lewat %s", name, f.Via))
+			failed[name] = true
+			continue
+		}
+		v, err := f.decode(raw[name])
+		if err != nil {
+			details = append(details, err.Error())
+			failed[name] = true
+			continue
+		}
+		rec[name] = v
+	}
+
+	if create {
+		if _, ok := rec[res.Key]; !ok && res.IDPrefix != "" {
+			rec[res.Key] = domain.NewID(res.IDPrefix)
+		}
+		for _, f := range res.Fields {
+			if _, ok := rec[f.Name]; ok || failed[f.Name] {
+				continue
+			}
+			switch {
+			case f.Default != nil:
+				rec[f.Name] = f.Default()
+			case f.Required || f.Name == res.Key:
+				details = append(details, fmt.Sprintf("%s wajib diisi", f.Name))
+			}
+		}
+		if len(details) == 0 && res.Check != nil {
+			if err := res.Check(rec); err != nil {
+				details = append(details, joinedDetails(err)...)
+			}
+		}
+	} else if _, ok := rec[res.Key]; ok {
+		details = append(details, fmt.Sprintf("%s adalah key dan tidak bisa diubah", res.Key))
+	}
+
+	if len(details) > 0 {
+		return nil, invalid(details)
+	}
+	if !create && len(rec) == 0 {
+		return nil, invalid([]string{"tidak ada field yang diubah"})
+	}
+	return rec, nil
+}
+
+// checkUpdate menjalankan Check pada record tersimpan yang sudah digabung
+// dengan perubahan PATCH, agar update tidak melanggar aturan yang
+// diperiksa saat create (mis. mengosongkan kota rumah sakit).
+func (res *resource) checkUpdate(key string, rec record) error {
+	if res.Check == nil {
+		return nil
+	}
+	var stored record
+	var err error
+	if res.Store == storeNeo4j {
+		stored, err = neo4jStored(res, key)
+	} else {
+		stored, err = cassandraGet(res, key)
+	}
+	if err != nil {
+		return err
+	}
+	if err := res.Check(merge(stored, rec)); err != nil {
+		return invalid(joinedDetails(err))
+	}
+	return nil
+}
+
+// merge menimpa record tersimpan dengan perubahan PATCH; nil berarti field
+// dikosongkan.
+func merge(stored, rec record) record {
+	out := record{}
+	for name, v := range stored {
+		out[name] = v
+	}
+	for name, v := range rec {
+		if v == nil {
+			delete(out, name)
+		} else {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// decode mengubah nilai JSON menjadi tipe driver sesuai tipe field.
+func (f field) decode(raw json.RawMessage) (interface{}, error) {
+	switch f.Type {
+	case typeInt:
+		var n int64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("%s harus bilangan bulat", f.Name)
+		}
+		if f.NonNegative && n < 0 {
+			return nil, fmt.Errorf("%s tidak boleh negatif", f.Name)
+		}
+		return int(n), nil
+	case typeFloat:
+		var x float64
+		if err := json.Unmarshal(raw, &x); err != nil {
+			return nil, fmt.Errorf("%s harus angka", f.Name)
+		}
+		if f.NonNegative && x < 0 {
+			return nil, fmt.Errorf("%s tidak boleh negatif", f.Name)
+		}
+		return x, nil
+	case typeUang:
+		var r uang.Rupiah
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("%s harus nominal rupiah dengan paling banyak 2 desimal", f.Name)
+		}
+		if f.NonNegative && r < 0 {
+			return nil, fmt.Errorf("%s tidak boleh negatif", f.Name)
+		}
+		return r.Float(), nil
+	case typeCounts:
+		var m map[string]int
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("%s harus objek {\"id\": jumlah}", f.Name)
+		}
+		if len(m) == 0 {
+			return nil, fmt.Errorf("%s tidak boleh kosong", f.Name)
+		}
+		for k, n := range m {
+			if strings.TrimSpace(k) == "" || n <= 0 {
+				return nil, fmt.Errorf("%s: jumlah %q harus > 0", f.Name, k)
+			}
+		}
+		return m, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("%s harus teks", f.Name)
+	}
+	return f.parse(s)
+}
+
+// parse mengubah teks (body JSON atau query string) menjadi nilai field.
+func (f field) parse(s string) (interface{}, error) {
+	switch f.Type {
+	case typeInt:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s harus bilangan bulat", f.Name)
+		}
+		return n, nil
+	case typeFloat:
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s harus angka", f.Name)
+		}
+		return x, nil
+	case typeUang:
+		r, err := uang.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s harus nominal rupiah dengan paling banyak 2 desimal", f.Name)
+		}
+		return r.Float(), nil
+	case typeDate:
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, fmt.Errorf("%s %q harus berformat YYYY-MM-DD", f.Name, s)
+		}
+		return s, nil
+	case typeLocalTime:
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t.UTC().Format("2006-01-02 15:04:05"), nil
+		}
+		if _, err := time.Parse("2006-01-02 15:04:05", s); err != nil {
+			return nil, fmt.Errorf("%s %q harus berformat YYYY-MM-DD HH:MM:SS atau RFC3339", f.Name, s)
+		}
+		return s, nil
+	case typeTimestamp:
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("%s %q harus berformat RFC3339, mis. 2025-01-01T09:00:00Z", f.Name, s)
+		}
+		return t.UTC(), nil
+	case typeCounts:
+		return nil, fmt.Errorf("%s tidak bisa dipakai sebagai filter", f.Name)
+	}
+
+	if f.Required && strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("%s wajib diisi", f.Name)
+	}
+	if len(f.Enum) > 0 && !contains(f.Enum, s) {
+		return nil, fmt.Errorf("%s %q tidak dikenal (pilihan: %s)", f.Name, s, strings.Join(f.Enum, ", "))
+	}
+	if f.Format != nil {
+		if err := f.Format(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// filters membaca ?field=nilai untuk field yang boleh difilter.
+func (res *resource) filters(r *http.Request) (record, error) {
+	out := record{}
+	var details []string
+	for name, values := range r.URL.Query() {
+		if name == "limit" || name == "cursor" {
+			continue
+		}
+		f, ok := res.field(name)
+		if !ok || !f.Filter {
+			var allowed []string
+			for _, f := range res.Fields {
+				if f.Filter {
+					allowed = append(allowed, f.Name)
+				}
+			}
+			details = append(details, fmt.Sprintf("filter %s tidak didukung (pilihan: %s)", name, strings.Join(allowed, ", ")))
+			continue
+		}
+		v, err := f.parse(values[len(values)-1])
+		if err != nil {
+			details = append(details, err.Error())
+			continue
+		}
+		out[name] = v
+	}
+	if len(details) > 0 {
+		sort.Strings(details)
+		return nil, &Error{Status: http.StatusBadRequest, Code: CodeBadRequest, Message: "query string tidak valid", Details: details}
+	}
+	return out, nil
+}
+
+// sortedKeys mengembalikan key record terurut agar statement yang
+// dibangkitkan deterministik.
+func sortedKeys(rec record) []string {
+	keys := make([]string, 0, len(rec))
+	for k := range rec {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,364 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ===============================================
+//   SERVER HTTP
+// ===============================================
+//
+// Endpoint:
+//   GET    /healthz
+//   GET    /api                        daftar resource & laporan
+//   GET    /api/{resource}             list (?limit=&cursor=&<filter>=)
+//   POST   /api/{resource}             create
+//   GET    /api/{resource}/{key}       detail
+//   PATCH  /api/{resource}/{key}       update sebagian
+//   DELETE /api/{resource}/{key}       hapus
+//   GET    /api/laporan[/{nama}]       laporan read (lihat laporan.go)
+//   ...    /api/janji-temu/pesan dst.  booking janji temu (lihat booking.go)
+//   ...    /api/pemesanan-obat/pesan   pesan obat (lihat apotek.go)
+//   ...    /api/tagihan                tagihan pasien (lihat tagihan.go)
+//   ...    /api/pembayaran             pembayaran & webhook gateway (lihat pembayaran.go)
+//
+// Server memakai koneksi global paket cassandra dan neo4j; keduanya harus
+// sudah terhubung sebelum Run.
+
+// Config mengatur server API.
+type Config struct {
+	Addr            string
+	DefaultLimit    int           // ukuran halaman bila ?limit tidak diisi
+	MaxLimit        int           // batas atas ?limit
+	ShutdownTimeout time.Duration // waktu tunggu request yang sedang berjalan saat berhenti
+	AccessLog       bool          // catat setiap request lewat Logf
+	Logf            func(format string, args ...interface{})
+}
+
+// DefaultConfig adalah konfigurasi rs serve tanpa flag.
+var DefaultConfig = Config{
+	Addr:            ":8080",
+	DefaultLimit:    20,
+	MaxLimit:        100,
+	ShutdownTimeout: 10 * time.Second,
+	AccessLog:       true,
+}
+
+type Server struct {
+	cfg Config
+	mux *http.ServeMux
+}
+
+func New(cfg Config) *Server {
+	if cfg.Logf == nil {
+		cfg.Logf = log.Printf
+	}
+	s := &Server{cfg: cfg, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/healthz", s.wrap(s.handleHealth))
+	s.mux.HandleFunc("/api", s.wrap(s.handleIndex))
+	s.mux.HandleFunc("/api/laporan", s.wrap(s.handleLaporan))
+	s.mux.HandleFunc("/api/laporan/{name}", s.wrap(s.handleLaporan))
+	s.routeBooking()
+	s.routeApotek()
+	s.routeLayanan()
+	s.routeTagihan()
+	s.routePembayaran()
+	s.mux.HandleFunc("/api/{resource}", s.wrap(s.handleCollection))
+	s.mux.HandleFunc("/api/{resource}/{key}", s.wrap(s.handleItem))
+	s.mux.HandleFunc("/", s.wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return notFound("endpoint %s tidak ada (lihat GET /api)", r.URL.Path)
+	}))
+	return s
+}
+
+// Handler mengembalikan handler HTTP server (untuk test atau server lain).
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Run melayani request sampai ctx selesai, lalu berhenti menerima koneksi
+// baru dan menunggu request yang sedang berjalan paling lama
+// ShutdownTimeout.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.cfg.Addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	s.cfg.Logf("API berjalan di http://%s\n", ln.Addr())
+
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.cfg.Logf("Menghentikan API, menunggu request berjalan (maks %s)...\n", s.cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("shutdown tidak selesai dalam %s: %v", s.cfg.ShutdownTimeout, err)
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	s.cfg.Logf("API berhenti.\n")
+	return nil
+}
+
+// ===============================================
+//   MIDDLEWARE
+// ===============================================
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// wrap mengubah handler yang mengembalikan error menjadi http.HandlerFunc:
+// error ditulis sebagai body error JSON, panic menjadi 500, dan setiap
+// request dicatat di log.
+func (s *Server) wrap(h func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			if p := recover(); p != nil {
+				writeError(rec, r, fmt.Errorf("panic: %v", p))
+			}
+			if !s.cfg.AccessLog {
+				return
+			}
+			s.cfg.Logf("%s %s %d %s\n", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Microsecond))
+		}()
+		if err := h(rec, r); err != nil {
+			writeError(rec, r, err)
+		}
+	}
+}
+
+func methodNotAllowed(allowed ...string) *Error {
+	return &Error{
+		Status:  http.StatusMethodNotAllowed,
+		Code:    CodeMethodNotAllowed,
+		Message: "method tidak didukung, gunakan " + strings.Join(allowed, ", "),
+	}
+}
+
+// ===============================================
+//   HANDLER
+// ===============================================
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) error {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	return nil
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	type fieldInfo struct {
+		Name     string   `json:"name"`
+		Required bool     `json:"required,omitempty"`
+		Filter   bool     `json:"filter,omitempty"`
+		Enum     []string `json:"enum,omitempty"`
+	}
+	type resourceInfo struct {
+		Name   string      `json:"name"`
+		Path   string      `json:"path"`
+		Store  string      `json:"store"`
+		Key    string      `json:"key"`
+		Fields []fieldInfo `json:"fields"`
+	}
+	var list []resourceInfo
+	for _, res := range resources {
+		info := resourceInfo{Name: res.Name, Path: "/api/" + res.Name, Store: res.Store, Key: res.Key}
+		for _, f := range res.Fields {
+			info.Fields = append(info.Fields, fieldInfo{Name: f.Name, Required: f.Required, Filter: f.Filter, Enum: f.Enum})
+		}
+		list = append(list, info)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+		"resources": list,
+		"laporan":   "/api/laporan",
+	}})
+	return nil
+}
+
+func (s *Server) resource(r *http.Request) (*resource, error) {
+	name := r.PathValue("resource")
+	res, ok := lookupResource(name)
+	if !ok {
+		return nil, notFound("resource %s tidak dikenal (lihat GET /api)", name)
+	}
+	return res, nil
+}
+
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request) error {
+	res, err := s.resource(r)
+	if err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return s.list(w, r, res)
+	case http.MethodPost:
+		if res.CreateVia != "" {
+			return &Error{Status: http.StatusMethodNotAllowed, Code: CodeMethodNotAllowed, Message: fmt.Sprintf("%s dibuat lewat %s", res.Title, res.CreateVia)}
+		}
+		rec, err := res.decodeRecord(w, r, true)
+		if err != nil {
+			return err
+		}
+		var created record
+		if res.Store == storeNeo4j {
+			created, err = neo4jCreate(res, rec)
+		} else {
+			created, err = cassandraCreate(res, rec)
+		}
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Location", fmt.Sprintf("/api/%s/%v", res.Name, rec[res.Key]))
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"data": created})
+		return nil
+	}
+	return methodNotAllowed(http.MethodGet, http.MethodPost)
+}
+
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request) error {
+	res, err := s.resource(r)
+	if err != nil {
+		return err
+	}
+	key := r.PathValue("key")
+	neo := res.Store == storeNeo4j
+
+	var rec record
+	switch r.Method {
+	case http.MethodGet:
+		if neo {
+			rec, err = neo4jGet(res, key)
+		} else {
+			rec, err = cassandraGet(res, key)
+		}
+	case http.MethodPatch:
+		if rec, err = res.decodeRecord(w, r, false); err != nil {
+			return err
+		}
+		if err = res.checkUpdate(key, rec); err != nil {
+			return err
+		}
+		if neo {
+			rec, err = neo4jUpdate(res, key, rec)
+		} else {
+			rec, err = cassandraUpdate(res, key, rec)
+		}
+	case http.MethodDelete:
+		if res.DeleteVia != "" {
+			return &Error{Status: http.StatusMethodNotAllowed, Code: CodeMethodNotAllowed, Message: fmt.Sprintf("%s dibatalkan lewat %s", res.Title, res.DeleteVia)}
+		}
+		if neo {
+			err = neo4jDelete(res, key)
+		} else {
+			err = cassandraDelete(res, key)
+		}
+		if err == nil {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return err
+	default:
+		return methodNotAllowed(http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": rec})
+	return nil
+}
+
+// limitQuery membaca ?limit dengan batas konfigurasi server.
+func (s *Server) limitQuery(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return s.cfg.DefaultLimit, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > s.cfg.MaxLimit {
+		return 0, badRequest("limit harus bilangan bulat 1-%d", s.cfg.MaxLimit)
+	}
+	return n, nil
+}
+
+// list melayani GET koleksi dengan cursor pagination. Cursor bersifat opaque
+// bagi klien: kirim ulang next_cursor dari response sebelumnya apa adanya.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, res *resource) error {
+	limit, err := s.limitQuery(r)
+	if err != nil {
+		return err
+	}
+	var cursor []byte
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		c, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil || len(c) == 0 {
+			return badRequest("cursor tidak valid")
+		}
+		cursor = c
+	}
+	filters, err := res.filters(r)
+	if err != nil {
+		return err
+	}
+
+	var rows []record
+	var next []byte
+	if res.Store == storeNeo4j {
+		var after string
+		rows, after, err = neo4jList(res, filters, limit, string(cursor))
+		if after != "" {
+			next = []byte(after)
+		}
+	} else {
+		rows, next, err = cassandraList(res, filters, limit, cursor)
+	}
+	if err != nil {
+		return err
+	}
+	if rows == nil {
+		rows = []record{}
+	}
+
+	body := map[string]interface{}{"data": rows, "next_cursor": nil}
+	if next != nil {
+		body["next_cursor"] = base64.RawURLEncoding.EncodeToString(next)
+	}
+	writeJSON(w, http.StatusOK, body)
+	return nil
+}
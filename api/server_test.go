@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"src/queries"
+)
+
+// Request di test ini ditolak sebelum menyentuh database, jadi server bisa
+// dijalankan tanpa koneksi Cassandra/Neo4j.
+func TestValidasiRequest(t *testing.T) {
+	h := New(Config{DefaultLimit: 20, MaxLimit: 100}).Handler()
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		status  int
+		details []string
+	}{
+		{"create tanpa field wajib", http.MethodPost, "/api/obat", `{"id_obat": "O9001"}`,
+			http.StatusUnprocessableEntity, []string{"nama wajib diisi", "harga wajib diisi", "stok wajib diisi"}},
+		{"create stok negatif", http.MethodPost, "/api/obat", `{"id_obat": "O9001", "nama": "Uji", "harga": 1000, "stok": -1}`,
+			http.StatusUnprocessableEntity, []string{"stok tidak boleh negatif"}},
+		{"patch stok", http.MethodPatch, "/api/obat/O0001", `{"stok": 5}`,
+			http.StatusUnprocessableEntity, []string{"stok hanya bisa diubah lewat " + viaStokObat}},
+		{"patch stok null", http.MethodPatch, "/api/obat/O0001", `{"stok": null}`,
+			http.StatusUnprocessableEntity, []string{"stok hanya bisa diubah lewat " + viaStokObat}},
+		{"patch key", http.MethodPatch, "/api/obat/O0001", `{"id_obat": "O0002"}`,
+			http.StatusUnprocessableEntity, []string{"id_obat adalah key dan tidak bisa diubah"}},
+		{"patch field wajib null", http.MethodPatch, "/api/rumah-sakit/RS001", `{"kota": null}`,
+			http.StatusUnprocessableEntity, []string{"kota tidak boleh null"}},
+		{"patch kosong", http.MethodPatch, "/api/obat/O0001", `{}`,
+			http.StatusUnprocessableEntity, []string{"tidak ada field yang diubah"}},
+		{"field tidak dikenal", http.MethodPatch, "/api/layanan-medis/L001", `{"harga": 1}`,
+			http.StatusUnprocessableEntity, []string{"field harga tidak dikenal (pilihan: id_layanan, nama_layanan, biaya_layanan)"}},
+		{"create lewat endpoint khusus", http.MethodPost, "/api/janji-temu", `{}`, http.StatusMethodNotAllowed, nil},
+		{"delete lewat endpoint khusus", http.MethodDelete, "/api/pemesanan-layanan/PL-1", ``, http.StatusMethodNotAllowed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, ingin %d (body %s)", rec.Code, tt.status, rec.Body)
+			}
+			var body struct{ Error Error }
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if tt.details != nil && !reflect.DeepEqual(body.Error.Details, tt.details) {
+				t.Fatalf("details = %q, ingin %q", body.Error.Details, tt.details)
+			}
+		})
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	stored := record{"id_rs": "RS001", "nama_rumah_sakit": "RS Uji", "kota": "Bandung", "email": "rs@rs.com"}
+	tests := []struct {
+		name    string
+		patch   record
+		wantErr bool
+	}{
+		{"ubah kota", record{"kota": "Depok"}, false},
+		{"kosongkan email opsional", record{"email": nil}, false},
+		{"email tidak valid", record{"email": "bukan-email"}, true},
+		{"kota kosong", record{"kota": ""}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAs[queries.RumahSakit](merge(stored, tt.patch))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check(merge) = %v, ingin error %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, ok := stored["kota"]; !ok || stored["kota"] != "Bandung" {
+		t.Fatalf("merge mengubah record tersimpan: %v", stored)
+	}
+}
+
+func TestDeleteGuard(t *testing.T) {
+	res, _ := lookupResource("rumah-sakit")
+	stmt := res.deleteStatement()
+	for _, want := range []string{
+		"WITH n, exists { (n)-[:memiliki_departemen]->(:Departemen) } AS g0",
+		"WITH n, g0, exists { (n)<-[:di_rs]-(:JanjiTemu) } AS g1",
+		"FOREACH (hapus IN CASE WHEN g0 OR g1 THEN [] ELSE [1] END | DETACH DELETE n)",
+		"RETURN true AS found, g0, g1",
+	} {
+		if !strings.Contains(stmt, want) {
+			t.Errorf("deleteStatement tidak memuat %q:\n%s", want, stmt)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		records []map[string]interface{}
+		status  int
+	}{
+		{"tidak ditemukan", nil, http.StatusNotFound},
+		{"masih punya departemen", []map[string]interface{}{{"found": true, "g0": true, "g1": false}}, http.StatusConflict},
+		{"masih punya janji temu", []map[string]interface{}{{"found": true, "g0": false, "g1": true}}, http.StatusConflict},
+		{"terhapus", []map[string]interface{}{{"found": true, "g0": false, "g1": false}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := res.deleted("RS001", tt.records)
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatalf("deleted = %v, ingin nil", err)
+				}
+				return
+			}
+			e, ok := err.(*Error)
+			if !ok || e.Status != tt.status {
+				t.Fatalf("deleted = %v, ingin status %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestDeleteCascade(t *testing.T) {
+	res, _ := lookupResource("resep")
+	want := "MATCH (n:Resep {id_resep: $key}) FOREACH (c IN [(n)-[:memiliki_detail]->(d) | d] | DETACH DELETE c) DETACH DELETE n RETURN true AS found"
+	if got := res.deleteStatement(); got != want {
+		t.Fatalf("deleteStatement =\n%s\ningin\n%s", got, want)
+	}
+}
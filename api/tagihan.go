@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"src/tagihan"
+	"src/uang"
+)
+
+// ===============================================
+//   ENDPOINT TAGIHAN
+// ===============================================
+//
+//   POST /api/tagihan                  {email_pasien, pesanan, diskon, ppn, jatuh_tempo} → tagihan baru
+//   POST /api/tagihan/pratinjau        body sama, dihitung tanpa disimpan
+//   GET  /api/tagihan/{nomor}          ?format=json (default), html atau text
+//   GET  /api/pasien/{email}/tagihan   ?limit=, terbaru dulu
+//
+// diskon berupa "10%" atau nominal (50000); ppn dalam persen, default 11.
+// Logika tagihan ada di paket tagihan dan sama dengan rs invoice.
+
+func (s *Server) routeTagihan() {
+	s.mux.HandleFunc("/api/tagihan", s.wrap(s.handleBuatTagihan))
+	s.mux.HandleFunc("/api/tagihan/pratinjau", s.wrap(s.handlePratinjauTagihan))
+	s.mux.HandleFunc("/api/tagihan/{nomor}", s.wrap(s.handleTagihan))
+	s.mux.HandleFunc("/api/pasien/{email}/tagihan", s.wrap(s.handleTagihanPasien))
+}
+
+// permintaanTagihan membaca body POST tagihan.
+func permintaanTagihan(w http.ResponseWriter, r *http.Request) (tagihan.Permintaan, error) {
+	var body struct {
+		EmailPasien string          `json:"email_pasien"`
+		Pesanan     []string        `json:"pesanan"`
+		Diskon      json.RawMessage `json:"diskon"`
+		PPN         *uang.Persen    `json:"ppn"`
+		JatuhTempo  int             `json:"jatuh_tempo"`
+	}
+	if err := decodeBody(w, r, &body); err != nil {
+		return tagihan.Permintaan{}, err
+	}
+	p := tagihan.Permintaan{
+		EmailPasien: body.EmailPasien,
+		Pesanan:     body.Pesanan,
+		TarifPPN:    tagihan.TarifPPNDefault,
+		JatuhTempo:  body.JatuhTempo,
+	}
+	if body.PPN != nil {
+		p.TarifPPN = *body.PPN
+	}
+	if len(body.Diskon) > 0 && string(body.Diskon) != "null" {
+		var teks string
+		if err := json.Unmarshal(body.Diskon, &teks); err != nil {
+			teks = string(body.Diskon)
+		}
+		d, err := tagihan.ParseDiskon(teks)
+		if err != nil {
+			return p, invalid([]string{"diskon: " + err.Error()})
+		}
+		p.Diskon = d
+	}
+	return p, nil
+}
+
+func (s *Server) handleBuatTagihan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	p, err := permintaanTagihan(w, r)
+	if err != nil {
+		return err
+	}
+	t, err := tagihan.Buat(p, time.Now())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", "/api/tagihan/"+t.Nomor)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"data": t})
+	return nil
+}
+
+func (s *Server) handlePratinjauTagihan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed(http.MethodPost)
+	}
+	p, err := permintaanTagihan(w, r)
+	if err != nil {
+		return err
+	}
+	t, err := tagihan.Susun(p, time.Now())
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": t})
+	return nil
+}
+
+func (s *Server) handleTagihan(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	f := r.URL.Query().Get("format")
+	if f == "" {
+		f = tagihan.JSON
+	}
+	if err := tagihan.CekFormat(f); err != nil {
+		return badRequest("%v", err)
+	}
+	t, err := tagihan.Lihat(r.PathValue("nomor"))
+	if err != nil {
+		return err
+	}
+	switch f {
+	case tagihan.HTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case tagihan.Teks:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": t})
+		return nil
+	}
+	var b strings.Builder
+	if err := tagihan.Tulis(&b, t, f); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write([]byte(b.String()))
+	return err
+}
+
+func (s *Server) handleTagihanPasien(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed(http.MethodGet)
+	}
+	limit, err := s.limitQuery(r)
+	if err != nil {
+		return err
+	}
+	list, err := tagihan.DaftarPasien(r.PathValue("email"), limit)
+	if err != nil {
+		return err
+	}
+	type ringkasan struct {
+		Nomor  string      `json:"nomor"`
+		Dibuat time.Time   `json:"dibuat"`
+		Total  uang.Rupiah `json:"total"`
+		Status string      `json:"status"`
+	}
+	out := make([]ringkasan, len(list))
+	for i, t := range list {
+		out[i] = ringkasan{Nomor: t.Nomor, Dibuat: t.Dibuat, Total: t.Total, Status: t.Status}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": out})
+	return nil
+}
@@ -0,0 +1,357 @@
+package apotek
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/queries"
+	"src/uang"
+)
+
+// ===============================================
+//   PEMESANAN OBAT
+// ===============================================
+//
+// Alur pesan: pastikan pasien ada → baca obat yang diminta dari tabel obat
+// (id yang tidak ada ditolak) → hitung harga dari obat.harga → potong stok
+// tiap obat dengan LWT (urut id_obat) → tulis pemesanan_obat dan
+// detail_pesanan_obat dalam satu LOGGED BATCH beserta total_harga. Bila
+// salah satu langkah setelah pemotongan stok gagal, stok yang sudah
+// dipotong dikembalikan.
+//
+// Dipakai oleh rs pharmacy ... dan endpoint POST /api/pemesanan-obat/pesan;
+// tebus resep (resep.go) memakai alur yang sama.
+
+// StatusBaru adalah status pesanan yang baru dibuat.
+const StatusBaru = "belum dibayar"
+
+// MaksObat membatasi jumlah jenis obat dalam satu pesanan.
+const MaksObat = 50
+
+// casRetry adalah jumlah percobaan LWT per obat bila stok berubah di
+// antara baca dan tulis karena pesanan lain.
+const casRetry = 5
+
+// ParseDaftarObat membaca daftar seperti "O0001=2,O0002" (jumlah default 1).
+// Id yang sama dijumlahkan.
+func ParseDaftarObat(s string) (map[string]int, error) {
+	out := map[string]int{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, jumlahText, hasJumlah := strings.Cut(part, "=")
+		id = strings.TrimSpace(id)
+		jumlah := 1
+		if hasJumlah {
+			n, err := strconv.Atoi(strings.TrimSpace(jumlahText))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("jumlah %s harus bilangan bulat >= 1", id)
+			}
+			jumlah = n
+		}
+		if id == "" {
+			return nil, fmt.Errorf("id obat kosong pada %q", part)
+		}
+		out[id] += jumlah
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("daftar obat kosong")
+	}
+	return out, nil
+}
+
+// Permintaan adalah pesanan obat dari satu pasien.
+type Permintaan struct {
+	IdPesanan   string // kosong = dibuat otomatis
+	EmailPasien string
+	Obat        map[string]int // id_obat -> jumlah
+}
+
+// Baris adalah satu obat dalam ringkasan pesanan.
+type Baris struct {
+	IdObat   string      `json:"id_obat"`
+	Nama     string      `json:"nama"`
+	Jumlah   int         `json:"jumlah"`
+	Harga    uang.Rupiah `json:"harga"`
+	Subtotal uang.Rupiah `json:"subtotal"`
+	SisaStok *int        `json:"sisa_stok,omitempty"`
+}
+
+// Ringkasan adalah pesanan obat beserta rincian harganya.
+type Ringkasan struct {
+	IdPesanan      string      `json:"id_pesanan"`
+	EmailPemesan   string      `json:"email_pemesan"`
+	WaktuPemesanan time.Time   `json:"waktu_pemesanan"`
+	Status         string      `json:"status_pemesanan"`
+	Obat           []Baris     `json:"daftar_obat"`
+	TotalHarga     uang.Rupiah `json:"total_harga"`
+}
+
+func (p Permintaan) validate() error {
+	var details []string
+	if err := queries.ValidEmail(p.EmailPasien); err != nil {
+		details = append(details, "email_pasien: "+err.Error())
+	}
+	switch {
+	case len(p.Obat) == 0:
+		details = append(details, "daftar obat kosong")
+	case len(p.Obat) > MaksObat:
+		details = append(details, fmt.Sprintf("paling banyak %d jenis obat per pesanan", MaksObat))
+	}
+	for _, id := range sortedIDs(p.Obat) {
+		if p.Obat[id] < 1 {
+			details = append(details, fmt.Sprintf("jumlah %s harus >= 1", id))
+		}
+	}
+	if len(details) > 0 {
+		return &domain.Error{Kind: domain.Invalid, Message: "pesanan obat tidak valid", Details: details}
+	}
+	return nil
+}
+
+// Pesan membuat pesanan obat dan mengembalikan ringkasannya.
+func Pesan(p Permintaan, now time.Time) (*Ringkasan, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	ada, err := queries.PasienAda(p.EmailPasien)
+	if err != nil {
+		return nil, err
+	}
+	if !ada {
+		return nil, domain.Fail(domain.NotFound, "pasien %s tidak ditemukan", p.EmailPasien)
+	}
+
+	ids := sortedIDs(p.Obat)
+	katalog, err := queries.ObatUntukPesanan(ids)
+	if err != nil {
+		return nil, err
+	}
+	var unknown, kurang []string
+	for _, id := range ids {
+		o, ok := katalog[id]
+		switch {
+		case !ok:
+			unknown = append(unknown, fmt.Sprintf("obat %s tidak ada", id))
+		case o.Stok < p.Obat[id]:
+			kurang = append(kurang, fmt.Sprintf("stok %s (%s) tinggal %d, diminta %d", id, o.Nama, o.Stok, p.Obat[id]))
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: "obat tidak ditemukan di katalog", Details: unknown}
+	}
+	if len(kurang) > 0 {
+		return nil, &domain.Error{Kind: domain.Conflict, Message: "stok obat tidak cukup", Details: kurang}
+	}
+
+	if p.IdPesanan == "" {
+		p.IdPesanan = domain.NewID("POB-")
+	}
+	r := &Ringkasan{
+		IdPesanan:      p.IdPesanan,
+		EmailPemesan:   p.EmailPasien,
+		WaktuPemesanan: now.UTC().Truncate(time.Millisecond),
+		Status:         StatusBaru,
+	}
+	var reserved []Baris
+	for _, id := range ids {
+		o := katalog[id]
+		sisa, err := reserve(o, p.Obat[id])
+		if err != nil {
+			release(reserved)
+			return nil, err
+		}
+		b := Baris{IdObat: id, Nama: o.Nama, Jumlah: p.Obat[id], Harga: o.Harga, Subtotal: o.Harga.Kali(p.Obat[id]), SisaStok: &sisa}
+		reserved = append(reserved, b)
+		r.TotalHarga += b.Subtotal
+	}
+	r.Obat = reserved
+
+	err = queries.SimpanPemesananObat(queries.PemesananObat{
+		IdPesanan:       r.IdPesanan,
+		EmailPemesan:    r.EmailPemesan,
+		WaktuPemesanan:  r.WaktuPemesanan,
+		StatusPemesanan: r.Status,
+		TotalHarga:      r.TotalHarga,
+		DaftarObat:      p.Obat,
+		StokDipesan:     true,
+	})
+	if err != nil {
+		release(reserved)
+		return nil, err
+	}
+	return r, nil
+}
+
+// reserve memotong stok obat sebanyak jumlah dan mengembalikan sisa stok.
+func reserve(o queries.ObatPesanan, jumlah int) (int, error) {
+	stok := o.Stok
+	for i := 0; i < casRetry; i++ {
+		if stok < jumlah {
+			return 0, &domain.Error{Kind: domain.Conflict, Message: "stok obat tidak cukup",
+				Details: []string{fmt.Sprintf("stok %s (%s) tinggal %d, diminta %d", o.IdObat, o.Nama, stok, jumlah)}}
+		}
+		applied, sekarang, err := queries.UbahStokObat(o.IdObat, stok, stok-jumlah)
+		if err != nil {
+			return 0, err
+		}
+		if applied {
+			return sekarang, nil
+		}
+		stok = sekarang
+	}
+	return 0, domain.Fail(domain.Conflict, "stok %s sedang diubah pesanan lain, coba lagi", o.IdObat)
+}
+
+// release mengembalikan stok yang sudah dipotong. Kegagalan hanya dicatat
+// karena pesanan sudah dianggap gagal.
+func release(lines []Baris) {
+	for _, b := range lines {
+		stok := *b.SisaStok
+		ok := false
+		for i := 0; i < casRetry && !ok; i++ {
+			applied, sekarang, err := queries.UbahStokObat(b.IdObat, stok, stok+b.Jumlah)
+			if err != nil {
+				break
+			}
+			ok, stok = applied, sekarang
+		}
+		if !ok {
+			log.Printf("gagal mengembalikan stok %s sebanyak %d; periksa stok obat secara manual", b.IdObat, b.Jumlah)
+		}
+	}
+}
+
+// Batal adalah hasil pembatalan satu pesanan expired.
+type Batal struct {
+	IdPesanan string
+	Applied   bool   // false: status sudah berubah, pesanan tidak disentuh
+	Status    string // status setelah percobaan
+}
+
+// BatalkanExpired membatalkan pesanan yang masih belum dibayar (LWT) dan
+// mengembalikan stok obatnya. Pesanan yang statusnya sudah berubah
+// (mis. baru saja dibayar) dilewati, jadi stok tidak pernah dikembalikan
+// dua kali. Kegagalan per pesanan dicatat dan tidak menghentikan sisanya;
+// pembatalan ctx menghentikannya dan mengembalikan ctx.Err().
+func BatalkanExpired(ctx context.Context, orders []queries.PesananExpired) ([]Batal, error) {
+	out := make([]Batal, 0, len(orders))
+	for _, o := range orders {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		b, err := Batalkan(o.IdPesanan)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+// Batalkan membatalkan satu pesanan yang masih belum dibayar (LWT) dan
+// mengembalikan stok obatnya. Kegagalan mengembalikan stok hanya dicatat
+// karena pesanan sudah dibatalkan.
+func Batalkan(id string) (Batal, error) {
+	applied, sekarang, err := queries.CancelOrder(id)
+	if err != nil {
+		return Batal{}, err
+	}
+	if applied {
+		if err := KembalikanStok(id); err != nil {
+			log.Printf("gagal mengembalikan stok pesanan %s: %v; periksa stok obat secara manual", id, err)
+		}
+	}
+	return Batal{IdPesanan: id, Applied: applied, Status: sekarang}, nil
+}
+
+// KembalikanStok mengembalikan stok semua obat dalam pesanan yang
+// dibatalkan di luar alur pesan (mis. pembayaran kedaluwarsa). Pemanggil
+// memastikan pesanan baru saja berubah menjadi dibatalkan agar stok tidak
+// dikembalikan dua kali. Pesanan yang stoknya tidak dipotong saat pesan
+// (mis. data seeder) dilewati.
+func KembalikanStok(id string) error {
+	p, err := queries.RingkasanPemesananObat(id)
+	if err != nil || p == nil || !p.StokDipesan {
+		return err
+	}
+	katalog, err := queries.ObatUntukPesanan(sortedIDs(p.DaftarObat))
+	if err != nil {
+		return err
+	}
+	release(barisKembali(p, katalog))
+	return nil
+}
+
+// barisKembali mengembalikan baris obat yang stoknya perlu dikembalikan
+// saat pesanan p dibatalkan, dengan SisaStok berisi stok saat ini.
+func barisKembali(p *queries.PemesananObat, katalog map[string]queries.ObatPesanan) []Baris {
+	if !p.StokDipesan {
+		return nil
+	}
+	var lines []Baris
+	for _, idObat := range sortedIDs(p.DaftarObat) {
+		o, ok := katalog[idObat]
+		if !ok {
+			continue
+		}
+		stok := o.Stok
+		lines = append(lines, Baris{IdObat: idObat, Nama: o.Nama, Jumlah: p.DaftarObat[idObat], SisaStok: &stok})
+	}
+	return lines
+}
+
+// Lihat membaca ringkasan pesanan. Harga per obat adalah harga saat ini;
+// total adalah total yang tersimpan saat pesan (dihitung ulang dari harga
+// saat ini untuk pesanan lama tanpa total_harga).
+func Lihat(id string) (*Ringkasan, error) {
+	p, err := queries.RingkasanPemesananObat(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, domain.Fail(domain.NotFound, "pesanan obat %s tidak ditemukan", id)
+	}
+	ids := sortedIDs(p.DaftarObat)
+	katalog, err := queries.ObatUntukPesanan(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Ringkasan{
+		IdPesanan:      p.IdPesanan,
+		EmailPemesan:   p.EmailPemesan,
+		WaktuPemesanan: p.WaktuPemesanan,
+		Status:         p.StatusPemesanan,
+		TotalHarga:     p.TotalHarga,
+	}
+	var hitung uang.Rupiah
+	for _, id := range ids {
+		o := katalog[id]
+		b := Baris{IdObat: id, Nama: o.Nama, Jumlah: p.DaftarObat[id], Harga: o.Harga, Subtotal: o.Harga.Kali(p.DaftarObat[id])}
+		r.Obat = append(r.Obat, b)
+		hitung += b.Subtotal
+	}
+	if r.TotalHarga == 0 {
+		r.TotalHarga = hitung
+	}
+	return r, nil
+}
+
+func sortedIDs(m map[string]int) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,41 @@
+package apotek
+
+import (
+	"testing"
+
+	"src/queries"
+)
+
+func TestBarisKembali(t *testing.T) {
+	katalog := map[string]queries.ObatPesanan{
+		"O0001": {IdObat: "O0001", Nama: "Parasetamol", Stok: 10},
+		"O0002": {IdObat: "O0002", Nama: "Amoksisilin", Stok: 4},
+	}
+	daftar := map[string]int{"O0002": 1, "O0001": 3, "O9999": 2}
+	tests := []struct {
+		name   string
+		p      queries.PemesananObat
+		jumlah map[string]int
+	}{
+		{"stok dipotong saat pesan", queries.PemesananObat{IdPesanan: "POB-1", DaftarObat: daftar, StokDipesan: true},
+			map[string]int{"O0001": 3, "O0002": 1}},
+		{"pesanan seeder tanpa potong stok", queries.PemesananObat{IdPesanan: "POB00001", DaftarObat: daftar},
+			map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := barisKembali(&tt.p, katalog)
+			if len(lines) != len(tt.jumlah) {
+				t.Fatalf("barisKembali = %d baris, ingin %d", len(lines), len(tt.jumlah))
+			}
+			for _, b := range lines {
+				if b.Jumlah != tt.jumlah[b.IdObat] {
+					t.Errorf("jumlah %s = %d, ingin %d", b.IdObat, b.Jumlah, tt.jumlah[b.IdObat])
+				}
+				if *b.SisaStok != katalog[b.IdObat].Stok {
+					t.Errorf("sisa stok %s = %d, ingin %d", b.IdObat, *b.SisaStok, katalog[b.IdObat].Stok)
+				}
+			}
+		})
+	}
+}
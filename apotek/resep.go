@@ -0,0 +1,170 @@
+package apotek
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/queries"
+)
+
+// ===============================================
+//   TEBUS RESEP
+// ===============================================
+//
+// Resep di Neo4j dijadikan pesanan obat di Cassandra: pasien diambil dari
+// JanjiTemu yang menghasilkan resep, jumlah tiap obat = dosis per hari ×
+// lama pengobatan. Lama pengobatan diambil dari properti lama_hari pada
+// DetailResep, lalu Resep, lalu nilai yang diminta (default LamaDefault).
+// Tautan resep → pesanan disimpan di resep_pemesanan_obat dan diklaim
+// sebelum pesanan dibuat sehingga satu resep hanya bisa ditebus sekali.
+
+// LamaDefault adalah lama pengobatan bila resep tidak mencatatnya.
+const LamaDefault = 3
+
+// MaksLama membatasi lama pengobatan yang diminta.
+const MaksLama = 90
+
+var (
+	reKaliSehari = regexp.MustCompile(`(?i)(\d+)\s*[x×]\s*(sehari|per\s*hari|/\s*hari)`)
+	reTiapJam    = regexp.MustCompile(`(?i)(setiap|tiap)\s*(\d+)\s*jam`)
+)
+
+// DosisPerHari membaca berapa kali obat diminum per hari dari teks dosis
+// seperti "2x Sehari", "3 x per hari" atau "setiap 8 jam".
+func DosisPerHari(dosis string) (int, error) {
+	if m := reKaliSehari.FindStringSubmatch(dosis); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n > 0 {
+			return n, nil
+		}
+	}
+	if m := reTiapJam.FindStringSubmatch(dosis); m != nil {
+		if n, _ := strconv.Atoi(m[2]); n > 0 && n <= 24 && 24%n == 0 {
+			return 24 / n, nil
+		}
+	}
+	if strings.Contains(strings.ToLower(dosis), "sehari sekali") {
+		return 1, nil
+	}
+	return 0, fmt.Errorf("dosis %q tidak dikenali (contoh: 2x Sehari, setiap 8 jam)", dosis)
+}
+
+// BarisResep adalah satu DetailResep beserta jumlah yang dipesan.
+type BarisResep struct {
+	IdObat   string `json:"id_obat"`
+	Dosis    string `json:"dosis"`
+	PerHari  int    `json:"per_hari"`
+	LamaHari int    `json:"lama_hari"`
+	Jumlah   int    `json:"jumlah"`
+}
+
+// HasilTebus adalah resep beserta pesanan obat hasil tebusnya.
+type HasilTebus struct {
+	IdResep     string       `json:"id_resep"`
+	Penyakit    string       `json:"penyakit,omitempty"`
+	IdJanjiTemu string       `json:"id_janji_temu,omitempty"`
+	EmailPasien string       `json:"email_pasien"`
+	Resep       []BarisResep `json:"resep,omitempty"`
+	Pesanan     *Ringkasan   `json:"pesanan"`
+}
+
+// rincianResep membaca resep dan menghitung jumlah tiap obat.
+func rincianResep(idResep string, lamaDiminta int) (*HasilTebus, map[string]int, error) {
+	r, err := queries.ResepUntukPesanan(idResep)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r == nil {
+		return nil, nil, domain.Fail(domain.NotFound, "resep %s tidak ditemukan", idResep)
+	}
+	h := &HasilTebus{IdResep: r.IdResep, Penyakit: r.Penyakit, IdJanjiTemu: r.IdJanjiTemu, EmailPasien: r.EmailPasien}
+
+	var details []string
+	if r.EmailPasien == "" {
+		details = append(details, "resep tidak terhubung ke pasien lewat janji temu")
+	}
+	if len(r.Detail) == 0 {
+		details = append(details, "resep tidak punya DetailResep")
+	}
+	jumlah := map[string]int{}
+	for _, d := range r.Detail {
+		perHari, err := DosisPerHari(d.Dosis)
+		if err != nil {
+			details = append(details, d.IdObat+": "+err.Error())
+			continue
+		}
+		lama := lamaDiminta
+		switch {
+		case d.LamaHari > 0:
+			lama = d.LamaHari
+		case r.LamaHari > 0:
+			lama = r.LamaHari
+		}
+		b := BarisResep{IdObat: d.IdObat, Dosis: d.Dosis, PerHari: perHari, LamaHari: lama, Jumlah: perHari * lama}
+		h.Resep = append(h.Resep, b)
+		jumlah[b.IdObat] += b.Jumlah
+	}
+	if len(details) > 0 {
+		return nil, nil, &domain.Error{Kind: domain.Invalid, Message: fmt.Sprintf("resep %s tidak bisa ditebus", idResep), Details: details}
+	}
+	return h, jumlah, nil
+}
+
+// TebusResep membuat pesanan obat dari resep. lamaHari dipakai untuk obat
+// yang lama pengobatannya tidak tercatat di resep (0 = LamaDefault).
+func TebusResep(idResep string, lamaHari int, now time.Time) (*HasilTebus, error) {
+	if lamaHari == 0 {
+		lamaHari = LamaDefault
+	}
+	if lamaHari < 1 || lamaHari > MaksLama {
+		return nil, domain.Fail(domain.Invalid, "lama pengobatan harus 1-%d hari", MaksLama)
+	}
+	h, jumlah, err := rincianResep(idResep, lamaHari)
+	if err != nil {
+		return nil, err
+	}
+
+	idPesanan := domain.NewID("POB-")
+	applied, lama, err := queries.KlaimResep(queries.TautanResep{
+		IdResep:     h.IdResep,
+		IdPesanan:   idPesanan,
+		EmailPasien: h.EmailPasien,
+		WaktuDibuat: now.UTC().Truncate(time.Millisecond),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, &domain.Error{Kind: domain.Conflict, Message: fmt.Sprintf("resep %s sudah ditebus", h.IdResep),
+			Details: []string{fmt.Sprintf("pesanan %s dibuat %s", lama.IdPesanan, lama.WaktuDibuat.Local().Format("2006-01-02 15:04"))}}
+	}
+
+	h.Pesanan, err = Pesan(Permintaan{IdPesanan: idPesanan, EmailPasien: h.EmailPasien, Obat: jumlah}, now)
+	if err != nil {
+		if lepasErr := queries.LepasResep(h.IdResep, idPesanan); lepasErr != nil {
+			log.Printf("%v; resep %s tidak bisa ditebus ulang sampai tautannya dihapus", lepasErr, h.IdResep)
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+// StatusResep membaca pesanan hasil tebus resep beserta statusnya.
+func StatusResep(idResep string) (*HasilTebus, error) {
+	t, err := queries.TautanResepPesanan(idResep)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, domain.Fail(domain.NotFound, "resep %s belum ditebus", idResep)
+	}
+	pesanan, err := Lihat(t.IdPesanan)
+	if err != nil {
+		return nil, err
+	}
+	return &HasilTebus{IdResep: t.IdResep, EmailPasien: t.EmailPasien, Pesanan: pesanan}, nil
+}
@@ -0,0 +1,329 @@
+package arsip
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/queries"
+	"src/uang"
+)
+
+// ===============================================
+//   ARSIP SEBELUM HAPUS
+// ===============================================
+//
+// Sebelum dihapus, entitas disalin lengkap ke arsip: semua baris Cassandra
+// yang membentuknya (mis. pemesanan_obat + detail_pesanan_obat), atau untuk
+// entitas graph node beserta relationship dan kunci unik tetangganya.
+// Salinan itu cukup untuk membuat ulang entitas di store asalnya dengan
+// Pulihkan. Dipakai rs delete cancelled-orders, rs delete
+// stale-appointments, aksi archive rs retention dan rs archive restore.
+
+// DirBawaan adalah direktori arsip bila RS_ARSIP tidak diisi.
+const DirBawaan = "data/arsip"
+
+// Dir mengembalikan direktori arsip: env RS_ARSIP atau DirBawaan.
+func Dir() string {
+	if v := os.Getenv("RS_ARSIP"); v != "" {
+		return v
+	}
+	return DirBawaan
+}
+
+// Entri adalah salinan satu entitas yang diarsipkan.
+type Entri struct {
+	ID         string    `json:"id"`
+	Entitas    string    `json:"entitas"`
+	Kunci      string    `json:"kunci"`
+	Sumber     string    `json:"sumber"` // perintah atau aturan retensi yang menghapus
+	Diarsipkan time.Time `json:"diarsipkan"`
+	// Baris berisi baris Cassandra per tabel (entitas Cassandra).
+	Baris map[string][]map[string]interface{} `json:"baris,omitempty"`
+	// Node berisi node beserta relationship-nya (entitas Neo4j).
+	Node *Node `json:"node,omitempty"`
+}
+
+// Node adalah salinan satu node graph.
+type Node struct {
+	Label    []string               `json:"label"`
+	Properti map[string]interface{} `json:"properti"`
+	Relasi   []Relasi               `json:"relasi"`
+}
+
+// Relasi adalah salinan satu relationship node. Tetangga disimpan sebagai
+// label dan properti unik (constraint schema), bukan id internal Neo4j.
+type Relasi struct {
+	Tipe     string                 `json:"tipe"`
+	Keluar   bool                   `json:"keluar"` // true: node -> tetangga
+	Properti map[string]interface{} `json:"properti"`
+	Label    string                 `json:"label"`
+	Kunci    string                 `json:"kunci,omitempty"`
+	Nilai    interface{}            `json:"nilai,omitempty"`
+}
+
+// Isi meringkas isi salinan untuk tabel.
+func (e Entri) Isi() string {
+	if e.Node != nil {
+		return fmt.Sprintf("node %s, %d relasi", strings.Join(e.Node.Label, ":"), len(e.Node.Relasi))
+	}
+	tables := make([]string, 0, len(e.Baris))
+	for t, rows := range e.Baris {
+		tables = append(tables, fmt.Sprintf("%s (%d)", t, len(rows)))
+	}
+	sort.Strings(tables)
+	return strings.Join(tables, ", ")
+}
+
+// Jenis adalah entitas yang bisa diarsipkan dan dipulihkan.
+type Jenis struct {
+	Nama  string
+	Store string // queries.StoreCassandra atau queries.StoreNeo4j
+	Isi   string
+
+	// salin membuat entri dari record hasil pindai/pratinjau; record yang
+	// sudah tidak ada di database dilewati.
+	salin func(recs []map[string]interface{}) ([]Entri, error)
+	// pulihkan membuat ulang entri; false bila entitasnya sudah ada.
+	pulihkan func(e Entri) (bool, string, error)
+}
+
+// Daftar adalah semua entitas yang bisa diarsipkan, urut nama.
+var Daftar = []*Jenis{
+	{
+		Nama:     "janji_temu",
+		Store:    queries.StoreNeo4j,
+		Isi:      "node JanjiTemu, relationship dan kunci tetangga (dibaca ulang dari Neo4j)",
+		salin:    salinJanjiTemu,
+		pulihkan: pulihkanJanjiTemu,
+	},
+	{
+		Nama:     "log_aktivitas",
+		Store:    queries.StoreCassandra,
+		Isi:      "baris log_aktivitas apa adanya",
+		salin:    salinBaris("log_aktivitas", "id_perangkat", "waktu_aktivitas"),
+		pulihkan: pulihkanLog,
+	},
+	{
+		Nama:     "pemesanan_layanan",
+		Store:    queries.StoreCassandra,
+		Isi:      "baris pemesanan_layanan apa adanya; tabel per pasien/per RS dibuat ulang darinya",
+		salin:    salinBaris("pemesanan_layanan", "id_pesanan"),
+		pulihkan: pulihkanLayanan,
+	},
+	{
+		Nama:     "pemesanan_obat",
+		Store:    queries.StoreCassandra,
+		Isi:      "pemesanan_obat dan detail_pesanan_obat (dibaca ulang dari Cassandra)",
+		salin:    salinPesananObat,
+		pulihkan: pulihkanPesananObat,
+	},
+}
+
+// Cari mengembalikan jenis entitas bernama nama.
+func Cari(nama string) (*Jenis, error) {
+	for _, j := range Daftar {
+		if j.Nama == nama {
+			return j, nil
+		}
+	}
+	names := make([]string, len(Daftar))
+	for i, j := range Daftar {
+		names[i] = j.Nama
+	}
+	return nil, fmt.Errorf("entitas arsip %q tidak dikenal (pilihan: %v)", nama, names)
+}
+
+// Salin membuat entri arsip untuk record entitas. Record cukup berisi
+// kunci untuk pemesanan_obat dan janji_temu (dibaca ulang); entitas lain
+// harus berupa baris lengkap.
+func Salin(entitas, sumber string, recs []map[string]interface{}, now time.Time) ([]Entri, error) {
+	j, err := Cari(entitas)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, nil
+	}
+	out, err := j.salin(recs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].ID = domain.NewID("ARS-")
+		out[i].Entitas = j.Nama
+		out[i].Sumber = sumber
+		out[i].Diarsipkan = now
+	}
+	return out, nil
+}
+
+// Pulihkan membuat ulang entitas dari entri arsip. Hasilnya false bila
+// entitas dengan kunci yang sama sudah ada (tidak ditimpa); catatan
+// menjelaskan bagian yang tidak bisa dipulihkan.
+func Pulihkan(e Entri) (bool, string, error) {
+	j, err := Cari(e.Entitas)
+	if err != nil {
+		return false, "", err
+	}
+	return j.pulihkan(e)
+}
+
+func salinPesananObat(recs []map[string]interface{}) ([]Entri, error) {
+	var out []Entri
+	for _, r := range recs {
+		id := teks(r["id_pesanan"])
+		header, detail, err := queries.SalinanPesananObat(id)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			continue
+		}
+		baris := map[string][]map[string]interface{}{"pemesanan_obat": {header}}
+		if detail != nil {
+			baris["detail_pesanan_obat"] = []map[string]interface{}{detail}
+		}
+		out = append(out, Entri{Kunci: id, Baris: baris})
+	}
+	return out, nil
+}
+
+func pulihkanPesananObat(e Entri) (bool, string, error) {
+	h, err := satu(e, "pemesanan_obat")
+	if err != nil {
+		return false, "", err
+	}
+	p := queries.PesananObatLengkap{
+		PesananObat:     queries.PesananObat{IdPesanan: teks(h["id_pesanan"]), EmailPemesan: teks(h["email_pemesan"]), WaktuPemesanan: waktu(h["waktu_pemesanan"])},
+		StatusPemesanan: teks(h["status_pemesanan"]),
+		TotalHarga:      uang.DariFloat(angka(h["total_harga"])),
+	}
+	catatan := "tanpa detail_pesanan_obat"
+	if rows := e.Baris["detail_pesanan_obat"]; len(rows) > 0 {
+		p.DaftarObat = petaInt(rows[0]["daftar_obat"])
+		catatan = fmt.Sprintf("%d jenis obat", len(p.DaftarObat))
+	}
+	ok, err := queries.PulihkanPesananObat(p)
+	return ok, catatan, err
+}
+
+func pulihkanLayanan(e Entri) (bool, string, error) {
+	r, err := satu(e, "pemesanan_layanan")
+	if err != nil {
+		return false, "", err
+	}
+	p := queries.PemesananLayanan{
+		IdPesanan:         teks(r["id_pesanan"]),
+		EmailPemesan:      teks(r["email_pemesan"]),
+		IdRS:              teks(r["id_rs"]),
+		IdLayanan:         teks(r["id_layanan"]),
+		NamaLayanan:       teks(r["nama_layanan"]),
+		BiayaLayanan:      uang.DariFloat(angka(r["biaya_layanan"])),
+		WaktuPemesanan:    waktu(r["waktu_pemesanan"]),
+		JadwalPelaksanaan: waktu(r["jadwal_pelaksanaan"]),
+		StatusPemesanan:   teks(r["status_pemesanan"]),
+	}
+	ok, err := queries.PulihkanPemesananLayanan(p)
+	return ok, "kuota harian tidak dipesan ulang", err
+}
+
+func pulihkanLog(e Entri) (bool, string, error) {
+	r, err := satu(e, "log_aktivitas")
+	if err != nil {
+		return false, "", err
+	}
+	ok, err := queries.PulihkanLogAktivitas(queries.LogAktivitas{
+		IDPerangkat:     teks(r["id_perangkat"]),
+		WaktuAktivitas:  waktu(r["waktu_aktivitas"]),
+		DetailAktivitas: teks(r["detail_aktivitas"]),
+	})
+	return ok, "", err
+}
+
+func salinJanjiTemu(recs []map[string]interface{}) ([]Entri, error) {
+	ids := make([]string, len(recs))
+	for i, r := range recs {
+		ids[i] = teks(r["id_janji_temu"])
+	}
+	records, err := queries.SalinanJanjiTemu(ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Entri, 0, len(records))
+	for _, rec := range records {
+		n := &Node{Properti: peta(rec["properti"])}
+		for _, l := range daftar(rec["label"]) {
+			n.Label = append(n.Label, teks(l))
+		}
+		for _, item := range daftar(rec["relasi"]) {
+			r := peta(item)
+			keluar, _ := r["keluar"].(bool)
+			n.Relasi = append(n.Relasi, Relasi{
+				Tipe:     teks(r["tipe"]),
+				Keluar:   keluar,
+				Properti: peta(r["properti"]),
+				Label:    teks(r["label"]),
+				Kunci:    teks(r["kunci"]),
+				Nilai:    r["nilai"],
+			})
+		}
+		out = append(out, Entri{Kunci: teks(rec["id_janji_temu"]), Node: n})
+	}
+	return out, nil
+}
+
+func pulihkanJanjiTemu(e Entri) (bool, string, error) {
+	if e.Node == nil {
+		return false, "", fmt.Errorf("arsip %s tidak berisi node", e.ID)
+	}
+	var relasi []map[string]interface{}
+	for _, r := range e.Node.Relasi {
+		if r.Kunci == "" || r.Nilai == nil {
+			continue
+		}
+		relasi = append(relasi, map[string]interface{}{
+			"tipe": r.Tipe, "keluar": r.Keluar, "properti": r.Properti,
+			"label": r.Label, "kunci": r.Kunci, "nilai": r.Nilai,
+		})
+	}
+	ok, n, err := queries.PulihkanJanjiTemu(e.Node.Properti, relasi)
+	if !ok || err != nil {
+		return ok, "", err
+	}
+	catatan := fmt.Sprintf("%d/%d relasi dibuat", n, len(e.Node.Relasi))
+	if n < len(e.Node.Relasi) {
+		catatan += " (tetangga lain sudah tidak ada)"
+	}
+	return true, catatan, nil
+}
+
+// salinBaris menyalin record apa adanya sebagai satu baris tabel.
+func salinBaris(tabel string, kunci ...string) func([]map[string]interface{}) ([]Entri, error) {
+	return func(recs []map[string]interface{}) ([]Entri, error) {
+		out := make([]Entri, len(recs))
+		for i, r := range recs {
+			parts := make([]string, len(kunci))
+			for k, kolom := range kunci {
+				if t, ok := r[kolom].(time.Time); ok {
+					parts[k] = t.Local().Format("2006-01-02 15:04:05")
+				} else {
+					parts[k] = teks(r[kolom])
+				}
+			}
+			out[i] = Entri{Kunci: strings.Join(parts, "/"), Baris: map[string][]map[string]interface{}{tabel: {r}}}
+		}
+		return out, nil
+	}
+}
+
+func satu(e Entri, tabel string) (map[string]interface{}, error) {
+	rows := e.Baris[tabel]
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("arsip %s tidak berisi baris %s", e.ID, tabel)
+	}
+	return rows[0], nil
+}
@@ -0,0 +1,294 @@
+package arsip
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ===============================================
+//   PENYIMPANAN ARSIP LOKAL
+// ===============================================
+//
+// Arsip disimpan sebagai JSON Lines ter-gzip, satu berkas per entitas per
+// tanggal: <Dir>/<entitas>-<YYYY-MM-DD>.jsonl.gz. Setiap Simpan menambah
+// member gzip baru sehingga berkas tetap bisa dibaca zcat dan penulisan
+// yang terputus tidak merusak member sebelumnya.
+
+// Berkas adalah penyimpanan arsip di direktori lokal.
+type Berkas struct {
+	Dir string
+}
+
+// Filter memilih entri saat membaca arsip; field kosong tidak memfilter.
+type Filter struct {
+	ID      []string
+	Entitas string
+	Kunci   []string
+}
+
+func (f Filter) cocok(e Entri) bool {
+	if f.Entitas != "" && e.Entitas != f.Entitas {
+		return false
+	}
+	if len(f.ID) > 0 && !contains(f.ID, e.ID) {
+		return false
+	}
+	if len(f.Kunci) > 0 && !contains(f.Kunci, e.Kunci) {
+		return false
+	}
+	return true
+}
+
+// Simpan menambahkan entri ke berkas arsip dan memastikan isinya sudah
+// tertulis ke disk sebelum kembali, sehingga aman untuk langsung menghapus.
+func (b Berkas) Simpan(entri []Entri) error {
+	if len(entri) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(b.Dir, 0o755); err != nil {
+		return fmt.Errorf("gagal membuat direktori arsip: %v", err)
+	}
+	perBerkas := map[string][]Entri{}
+	var paths []string
+	for _, e := range entri {
+		path := filepath.Join(b.Dir, fmt.Sprintf("%s-%s.jsonl.gz", e.Entitas, e.Diarsipkan.Format("2006-01-02")))
+		if _, ok := perBerkas[path]; !ok {
+			paths = append(paths, path)
+		}
+		perBerkas[path] = append(perBerkas[path], e)
+	}
+	for _, path := range paths {
+		if err := tulis(path, perBerkas[path]); err != nil {
+			return fmt.Errorf("gagal menulis arsip %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func tulis(path string, entri []Entri) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	w := bufio.NewWriter(gz)
+	enc := json.NewEncoder(w)
+	for _, e := range entri {
+		if err = enc.Encode(e); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Baca mengembalikan entri yang cocok dengan filter, terbaru lebih dulu.
+// Direktori yang belum ada berarti arsip kosong.
+func (b Berkas) Baca(f Filter) ([]Entri, error) {
+	pattern := "*.jsonl.gz"
+	if f.Entitas != "" {
+		pattern = f.Entitas + "-*.jsonl.gz"
+	}
+	paths, err := filepath.Glob(filepath.Join(b.Dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+	var out []Entri
+	for _, path := range paths {
+		entri, err := baca(path)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca arsip %s: %v", path, err)
+		}
+		for _, e := range entri {
+			if f.cocok(e) {
+				out = append(out, e)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Diarsipkan.After(out[j].Diarsipkan) })
+	return out, nil
+}
+
+func baca(path string) ([]Entri, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+	dec.UseNumber()
+	var out []Entri
+	for {
+		var e Entri
+		if err := dec.Decode(&e); errors.Is(err, io.EOF) {
+			return out, nil
+		} else if err != nil {
+			return out, err
+		}
+		normalisasiEntri(&e)
+		out = append(out, e)
+	}
+}
+
+// Terbaru menyisakan entri terbaru per entitas dan kunci dari hasil Baca,
+// mis. bila data yang sama diarsipkan lagi setelah dipulihkan.
+func Terbaru(entri []Entri) []Entri {
+	seen := map[string]bool{}
+	var out []Entri
+	for _, e := range entri {
+		k := e.Entitas + "\x00" + e.Kunci
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, e)
+	}
+	return out
+}
+
+// normalisasiEntri mengubah angka JSON kembali menjadi int64 atau float64
+// agar properti Neo4j tidak berubah tipe saat dipulihkan.
+func normalisasiEntri(e *Entri) {
+	for _, rows := range e.Baris {
+		for _, r := range rows {
+			normalisasiPeta(r)
+		}
+	}
+	if e.Node == nil {
+		return
+	}
+	normalisasiPeta(e.Node.Properti)
+	for i := range e.Node.Relasi {
+		normalisasiPeta(e.Node.Relasi[i].Properti)
+		e.Node.Relasi[i].Nilai = normalisasi(e.Node.Relasi[i].Nilai)
+	}
+}
+
+func normalisasiPeta(m map[string]interface{}) {
+	for k, v := range m {
+		m[k] = normalisasi(v)
+	}
+}
+
+func normalisasi(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		normalisasiPeta(t)
+	case []interface{}:
+		for i := range t {
+			t[i] = normalisasi(t[i])
+		}
+	}
+	return v
+}
+
+func teks(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func angka(v interface{}) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case float32:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case int:
+		return float64(t)
+	}
+	return 0
+}
+
+// waktu membaca timestamp dari baris asli (time.Time) atau arsip (RFC3339).
+func waktu(v interface{}) time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return t
+	case string:
+		parsed, _ := time.Parse(time.RFC3339Nano, t)
+		return parsed
+	}
+	return time.Time{}
+}
+
+func petaInt(v interface{}) map[string]int {
+	switch t := v.(type) {
+	case map[string]int:
+		return t
+	case map[string]interface{}:
+		out := make(map[string]int, len(t))
+		for k, n := range t {
+			out[k] = int(angka(n))
+		}
+		return out
+	}
+	return nil
+}
+
+func peta(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func daftar(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		return t
+	case []string:
+		out := make([]interface{}, len(t))
+		for i, s := range t {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package arsip
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"src/domain"
+)
+
+// TestSimpanBaca memastikan salinan yang ditulis ke berkas terbaca kembali
+// dengan nilai yang sama seperti yang dipakai Pulihkan.
+func TestSimpanBaca(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	jadwal := time.Date(2025, 1, 7, 2, 30, 0, 0, time.UTC)
+	layanan := map[string]interface{}{
+		"id_pesanan":         "PL000001",
+		"email_pemesan":      "pasien1@mail.com",
+		"id_rs":              "RS001",
+		"biaya_layanan":      150000.0,
+		"waktu_pemesanan":    now,
+		"jadwal_pelaksanaan": jadwal,
+		"status_pemesanan":   "dibatalkan",
+	}
+	log := map[string]interface{}{
+		"id_perangkat":     "BAYMIN-0001",
+		"waktu_aktivitas":  now,
+		"detail_aktivitas": "cek suhu",
+	}
+	obat := Entri{Entitas: "pemesanan_obat", Kunci: "PO000001", Baris: map[string][]map[string]interface{}{
+		"pemesanan_obat":      {{"id_pesanan": "PO000001", "total_harga": 12500.5}},
+		"detail_pesanan_obat": {{"id_pesanan": "PO000001", "daftar_obat": map[string]int{"O001": 2, "O002": 1}}},
+	}}
+	janji := Entri{Entitas: "janji_temu", Kunci: "JT00001", Node: &Node{
+		Label:    []string{"JanjiTemu"},
+		Properti: map[string]interface{}{"id_janji_temu": "JT00001", "durasi": int64(30), "biaya": 2.5},
+		Relasi: []Relasi{{Tipe: "menghadiri", Keluar: false, Label: "Pasien", Kunci: "email",
+			Nilai: "pasien1@mail.com", Properti: map[string]interface{}{"urutan": int64(1)}}},
+	}}
+
+	var entri []Entri
+	for _, c := range []struct {
+		entitas string
+		rec     map[string]interface{}
+	}{{"pemesanan_layanan", layanan}, {"log_aktivitas", log}} {
+		e, err := Salin(c.entitas, "test", []map[string]interface{}{c.rec}, now)
+		if err != nil {
+			t.Fatalf("Salin %s: %v", c.entitas, err)
+		}
+		entri = append(entri, e...)
+	}
+	for _, e := range []Entri{obat, janji} {
+		e.ID = domain.NewID("ARS-")
+		e.Sumber = "test"
+		e.Diarsipkan = now
+		entri = append(entri, e)
+	}
+
+	b := Berkas{Dir: t.TempDir()}
+	if err := b.Simpan(entri); err != nil {
+		t.Fatal(err)
+	}
+	// Simpan kedua menambah member gzip baru pada berkas yang sama.
+	if err := b.Simpan(entri[:1]); err != nil {
+		t.Fatal(err)
+	}
+	semua, err := b.Baca(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(semua) != len(entri)+1 {
+		t.Fatalf("Baca = %d entri, ingin %d", len(semua), len(entri)+1)
+	}
+
+	cari := func(entitas string) Entri {
+		t.Helper()
+		got, err := b.Baca(Filter{Entitas: entitas})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = Terbaru(got)
+		if len(got) != 1 {
+			t.Fatalf("Baca %s = %d entri setelah Terbaru, ingin 1", entitas, len(got))
+		}
+		return got[0]
+	}
+
+	t.Run("pemesanan_layanan", func(t *testing.T) {
+		e := cari("pemesanan_layanan")
+		if e.Kunci != "PL000001" || e.Sumber != "test" || !e.Diarsipkan.Equal(now) {
+			t.Fatalf("entri = %+v", e)
+		}
+		r, err := satu(e, "pemesanan_layanan")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := teks(r["email_pemesan"]); got != "pasien1@mail.com" {
+			t.Errorf("email_pemesan = %q", got)
+		}
+		if got := angka(r["biaya_layanan"]); got != 150000 {
+			t.Errorf("biaya_layanan = %v", got)
+		}
+		if got := waktu(r["jadwal_pelaksanaan"]); !got.Equal(jadwal) {
+			t.Errorf("jadwal_pelaksanaan = %v, ingin %v", got, jadwal)
+		}
+	})
+
+	t.Run("log_aktivitas", func(t *testing.T) {
+		e := cari("log_aktivitas")
+		if want := "BAYMIN-0001/" + now.Local().Format("2006-01-02 15:04:05"); e.Kunci != want {
+			t.Errorf("kunci = %q, ingin %q", e.Kunci, want)
+		}
+		r, _ := satu(e, "log_aktivitas")
+		if got := waktu(r["waktu_aktivitas"]); !got.Equal(now) {
+			t.Errorf("waktu_aktivitas = %v", got)
+		}
+	})
+
+	t.Run("pemesanan_obat", func(t *testing.T) {
+		e := cari("pemesanan_obat")
+		h, _ := satu(e, "pemesanan_obat")
+		if got := angka(h["total_harga"]); got != 12500.5 {
+			t.Errorf("total_harga = %v", got)
+		}
+		d, _ := satu(e, "detail_pesanan_obat")
+		if got, want := petaInt(d["daftar_obat"]), map[string]int{"O001": 2, "O002": 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("daftar_obat = %v, ingin %v", got, want)
+		}
+	})
+
+	t.Run("janji_temu", func(t *testing.T) {
+		e := cari("janji_temu")
+		if e.Node == nil {
+			t.Fatal("node hilang")
+		}
+		// Angka bulat harus kembali menjadi int64 agar tipe properti Neo4j
+		// tidak berubah saat dipulihkan.
+		if got, ok := e.Node.Properti["durasi"].(int64); !ok || got != 30 {
+			t.Errorf("durasi = %#v, ingin int64(30)", e.Node.Properti["durasi"])
+		}
+		if got, ok := e.Node.Properti["biaya"].(float64); !ok || got != 2.5 {
+			t.Errorf("biaya = %#v, ingin 2.5", e.Node.Properti["biaya"])
+		}
+		if len(e.Node.Relasi) != 1 || e.Node.Relasi[0].Nilai != "pasien1@mail.com" || e.Node.Relasi[0].Properti["urutan"] != int64(1) {
+			t.Errorf("relasi = %+v", e.Node.Relasi)
+		}
+	})
+}
+
+func TestBacaDirektoriKosong(t *testing.T) {
+	entri, err := Berkas{Dir: t.TempDir() + "/belum-ada"}.Baca(Filter{})
+	if err != nil || len(entri) != 0 {
+		t.Fatalf("Baca = %v, %v; ingin kosong tanpa error", entri, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	e := Entri{ID: "ARS-1", Entitas: "janji_temu", Kunci: "JT00001"}
+	tests := []struct {
+		name string
+		f    Filter
+		want bool
+	}{
+		{"kosong", Filter{}, true},
+		{"entitas cocok", Filter{Entitas: "janji_temu"}, true},
+		{"entitas lain", Filter{Entitas: "log_aktivitas"}, false},
+		{"id", Filter{ID: []string{"ARS-2", "ARS-1"}}, true},
+		{"id lain", Filter{ID: []string{"ARS-2"}}, false},
+		{"kunci", Filter{Entitas: "janji_temu", Kunci: []string{"JT00001"}}, true},
+		{"kunci lain", Filter{Kunci: []string{"JT00002"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.cocok(e); got != tt.want {
+				t.Fatalf("cocok = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
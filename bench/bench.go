@@ -0,0 +1,98 @@
+package bench
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ===============================================
+//   PENGUKURAN LATENSI
+// ===============================================
+
+// Config mengatur jumlah iterasi per query.
+type Config struct {
+	Iterations int // iterasi yang diukur
+	Warmup     int // iterasi pemanasan (tidak diukur)
+}
+
+// Stats adalah ringkasan latensi satu query pada satu engine.
+type Stats struct {
+	Query  string
+	Engine string
+	N      int // iterasi yang berhasil diukur
+	Errors int
+
+	Min, P50, P95, P99, Max, Mean time.Duration
+	Total                         time.Duration
+	// Throughput adalah operasi per detik bila query dijalankan berurutan.
+	Throughput float64
+
+	// Err adalah error pertama yang terjadi (nil bila semua iterasi sukses).
+	Err error
+}
+
+// OK melaporkan apakah ada iterasi yang berhasil diukur.
+func (s Stats) OK() bool { return s.N > 0 }
+
+// Measure menjalankan fn sebanyak cfg.Warmup lalu cfg.Iterations kali.
+// Bila pemanasan pertama gagal, query dianggap tidak bisa dijalankan dan
+// pengukuran dilewati.
+func Measure(query, engine string, cfg Config, fn func() error) Stats {
+	s := Stats{Query: query, Engine: engine}
+
+	for i := 0; i < cfg.Warmup; i++ {
+		if err := fn(); err != nil {
+			s.Errors++
+			if s.Err == nil {
+				s.Err = err
+			}
+			if i == 0 {
+				return s
+			}
+		}
+	}
+
+	samples := make([]time.Duration, 0, cfg.Iterations)
+	for i := 0; i < cfg.Iterations; i++ {
+		start := time.Now()
+		err := fn()
+		elapsed := time.Since(start)
+		if err != nil {
+			s.Errors++
+			if s.Err == nil {
+				s.Err = err
+			}
+			continue
+		}
+		samples = append(samples, elapsed)
+	}
+
+	s.N = len(samples)
+	if s.N == 0 {
+		return s
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	for _, d := range samples {
+		s.Total += d
+	}
+	s.Min, s.Max = samples[0], samples[s.N-1]
+	s.P50 = percentile(samples, 50)
+	s.P95 = percentile(samples, 95)
+	s.P99 = percentile(samples, 99)
+	s.Mean = s.Total / time.Duration(s.N)
+	if s.Total > 0 {
+		s.Throughput = float64(s.N) / s.Total.Seconds()
+	}
+	return s
+}
+
+// percentile memakai metode nearest-rank pada sampel yang sudah terurut.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
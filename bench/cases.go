@@ -0,0 +1,59 @@
+package bench
+
+import (
+	"src/queries"
+)
+
+// ===============================================
+//   DAFTAR QUERY YANG DIBANDINGKAN
+// ===============================================
+
+// Case adalah satu query beserta padanannya di sql_equivalents.sql.
+// Parameter native sama dengan literal di statement SQL (dan default
+// perintah rs) sehingga kedua engine mengerjakan pertanyaan yang sama.
+type Case struct {
+	Name      string // nama perintah rs read, mis. "low-stock"
+	Legacy    string // nama query lama = key di queries.SQLEquivalents()
+	Cassandra bool   // query native butuh koneksi Cassandra
+	Neo4j     bool   // query native butuh koneksi Neo4j
+	Native    func() error
+}
+
+func discard[T any](fn func() (T, error)) func() error {
+	return func() error {
+		_, err := fn()
+		return err
+	}
+}
+
+// Cases berisi semua query read yang punya padanan SQL.
+var Cases = []Case{
+	{"order-counts", "read1", true, false, discard(queries.PatientOrderCounts)},
+	{"low-stock", "read2", true, false, discard(func() ([]queries.MedicineStock, error) {
+		return queries.LowStockMedicines(55)
+	})},
+	{"baymin-logs", "read3", true, true, discard(func() ([]queries.BayminLog, error) {
+		return queries.BayminLogs("pasien1@mail.com")
+	})},
+	{"doctor-appointments", "read4", false, true, discard(queries.MedikJanjiTemuCounts)},
+	{"prescription", "read5", true, true, discard(func() ([]queries.DetailResep, error) {
+		return queries.DetailResepJanjiTemu("JT00011")
+	})},
+	{"top-customers", "read6", true, false, discard(queries.PatientOrderCosts)},
+	{"popular-services", "read7", false, true, discard(queries.MostOrderedServices)},
+	{"top-hospitals", "read8", false, true, discard(queries.TopHospitalsByAppointments)},
+	{"hospitals-by-staff", "read9", false, true, discard(queries.HospitalsByMedicalStaff)},
+	{"patients-without-prescription", "read10", false, true, discard(queries.PatientsWithoutPrescriptions)},
+	{"specialists", "special_graph", false, true, discard(func() ([]queries.DokterSpesialis, error) {
+		return queries.DokterSpesialisDiKota("Dokter Spesialis Anak", "Bandung", 50)
+	})},
+}
+
+// CaseNames mengembalikan nama semua Case sesuai urutan.
+func CaseNames() []string {
+	names := make([]string, len(Cases))
+	for i, c := range Cases {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -0,0 +1,370 @@
+package bench
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"src/seeder"
+)
+
+// ===============================================
+//   SQLITE: skema relasional sql_equivalents.sql
+// ===============================================
+//
+// Dataset seeder dimuat ke skema relasional yang dipakai statement di
+// queries/sql_equivalents.sql (tabel user, pasien, tenaga_medis, ...).
+// Relationship graph menjadi foreign key biasa:
+//   bekerja_di          -> tenaga_medis.id_departemen (+ id_rs departemennya)
+//   memiliki_departemen -> departemen.id_rs
+//   memiliki_janji/dengan_dokter/di_rs -> kolom email_pasien,
+//                          email_tenaga_medis, id_rs di janji_temu
+//   menghasilkan_resep  -> hasil_janji_temu
+//   lokasi_layanan      -> layanan_medis (per rumah sakit)
+
+const sqliteSchema = `
+CREATE TABLE user (
+	email TEXT PRIMARY KEY,
+	nama_lengkap TEXT,
+	nomor_telepon TEXT
+);
+CREATE TABLE pasien (
+	email TEXT PRIMARY KEY,
+	nik TEXT,
+	jenis_kelamin TEXT,
+	tanggal_lahir TEXT,
+	provinsi TEXT,
+	kota TEXT,
+	kecamatan TEXT,
+	jalan TEXT
+);
+CREATE TABLE rumah_sakit (
+	id_rs TEXT PRIMARY KEY,
+	email TEXT,
+	nama_rumah_sakit TEXT,
+	no_telepon TEXT,
+	provinsi TEXT,
+	kota TEXT,
+	kecamatan TEXT,
+	jalan TEXT
+);
+CREATE TABLE departemen (
+	id_departemen TEXT PRIMARY KEY,
+	nama_departemen TEXT,
+	gedung TEXT,
+	id_rs TEXT REFERENCES rumah_sakit(id_rs)
+);
+CREATE TABLE tenaga_medis (
+	email TEXT PRIMARY KEY,
+	profesi TEXT,
+	id_departemen TEXT REFERENCES departemen(id_departemen),
+	id_rs TEXT REFERENCES rumah_sakit(id_rs)
+);
+CREATE TABLE layanan_medis (
+	id_rs TEXT,
+	id_layanan TEXT,
+	nama_layanan TEXT,
+	biaya_layanan REAL,
+	PRIMARY KEY (id_rs, id_layanan)
+);
+CREATE TABLE obat (
+	id_obat TEXT PRIMARY KEY,
+	nama TEXT,
+	label TEXT,
+	harga REAL,
+	stok INTEGER
+);
+CREATE TABLE pemesanan_obat (
+	id_pesanan TEXT PRIMARY KEY,
+	email_pemesan TEXT,
+	waktu_pemesanan TEXT,
+	status_pemesanan TEXT
+);
+CREATE TABLE detail_pesanan (
+	id_pesanan TEXT,
+	id_obat TEXT,
+	jumlah INTEGER,
+	PRIMARY KEY (id_pesanan, id_obat)
+);
+CREATE TABLE pemesanan_layanan (
+	id_pesanan TEXT PRIMARY KEY,
+	email_pemesan TEXT,
+	id_rs TEXT,
+	id_layanan TEXT,
+	waktu_pemesanan TEXT,
+	jadwal_pelaksanaan TEXT,
+	status_pemesanan TEXT
+);
+CREATE TABLE baymin (
+	id_perangkat TEXT PRIMARY KEY,
+	warna TEXT,
+	email_pasien TEXT
+);
+CREATE TABLE log_aktivitas (
+	id_perangkat TEXT,
+	waktu_aktivitas TEXT,
+	detail_aktivitas TEXT,
+	PRIMARY KEY (id_perangkat, waktu_aktivitas)
+);
+CREATE TABLE janji_temu (
+	id_janji_temu TEXT PRIMARY KEY,
+	waktu_pelaksanaan TEXT,
+	alasan TEXT,
+	status TEXT,
+	email_pasien TEXT,
+	email_tenaga_medis TEXT,
+	id_rs TEXT
+);
+CREATE TABLE resep (
+	id_resep TEXT PRIMARY KEY,
+	penyakit TEXT
+);
+CREATE TABLE hasil_janji_temu (
+	id_janji_temu TEXT,
+	id_resep TEXT,
+	PRIMARY KEY (id_janji_temu, id_resep)
+);
+CREATE TABLE detail_resep (
+	id_detail_resep TEXT PRIMARY KEY,
+	id_resep TEXT,
+	id_obat TEXT,
+	dosis TEXT
+);
+`
+
+// Index dibuat setelah data dimuat; padanan constraint/index Neo4j dan
+// partition key Cassandra agar perbandingan adil.
+const sqliteIndexes = `
+CREATE INDEX idx_tenaga_medis_profesi ON tenaga_medis(profesi);
+CREATE INDEX idx_tenaga_medis_departemen ON tenaga_medis(id_departemen);
+CREATE INDEX idx_tenaga_medis_rs ON tenaga_medis(id_rs);
+CREATE INDEX idx_departemen_rs ON departemen(id_rs);
+CREATE INDEX idx_rumah_sakit_kota ON rumah_sakit(kota);
+CREATE INDEX idx_obat_stok ON obat(stok);
+CREATE INDEX idx_pemesanan_obat_email ON pemesanan_obat(email_pemesan);
+CREATE INDEX idx_pemesanan_layanan_rs ON pemesanan_layanan(id_rs, id_layanan);
+CREATE INDEX idx_baymin_pasien ON baymin(email_pasien);
+CREATE INDEX idx_janji_temu_pasien ON janji_temu(email_pasien);
+CREATE INDEX idx_janji_temu_dokter ON janji_temu(email_tenaga_medis);
+CREATE INDEX idx_janji_temu_rs ON janji_temu(id_rs);
+CREATE INDEX idx_hasil_janji_temu_resep ON hasil_janji_temu(id_resep);
+CREATE INDEX idx_detail_resep_resep ON detail_resep(id_resep);
+ANALYZE;
+`
+
+// OpenSQLite membuka database SQLite (path ":memory:" untuk in-memory).
+// Koneksi dibatasi satu agar database in-memory tidak terpecah per koneksi.
+func OpenSQLite(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka SQLite %s: %v", path, err)
+	}
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("gagal membuka SQLite %s: %v", path, err)
+	}
+	return db, nil
+}
+
+// Loaded melaporkan apakah database sudah berisi skema benchmark.
+func Loaded(db *sql.DB) bool {
+	var n int
+	err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'janji_temu'`).Scan(&n)
+	return err == nil && n > 0
+}
+
+type sqliteTable struct {
+	name    string
+	columns []string
+	rows    func(ds *seeder.Dataset) [][]interface{}
+}
+
+// LoadSQLite mengosongkan database lalu memuat dataset dalam satu
+// transaksi dan mengembalikan jumlah baris per tabel.
+func LoadSQLite(db *sql.DB, ds *seeder.Dataset) (map[string]int, error) {
+	tables := sqliteTables()
+	for _, t := range tables {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + t.name); err != nil {
+			return nil, fmt.Errorf("gagal drop tabel %s: %v", t.name, err)
+		}
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("gagal membuat skema SQLite: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	counts := map[string]int{}
+	for _, t := range tables {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(t.columns)), ", ")
+		stmt, err := tx.Prepare(fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+			t.name, strings.Join(t.columns, ", "), placeholders))
+		if err != nil {
+			return nil, fmt.Errorf("gagal menyiapkan insert %s: %v", t.name, err)
+		}
+		for _, row := range t.rows(ds) {
+			if _, err := stmt.Exec(row...); err != nil {
+				stmt.Close()
+				return nil, fmt.Errorf("gagal insert %s: %v", t.name, err)
+			}
+			counts[t.name]++
+		}
+		stmt.Close()
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(sqliteIndexes); err != nil {
+		return nil, fmt.Errorf("gagal membuat index SQLite: %v", err)
+	}
+	return counts, nil
+}
+
+// SQLQuery mengembalikan fungsi yang menjalankan statement dan membaca
+// seluruh barisnya, padanan queries.* yang juga membaca semua hasil.
+func SQLQuery(db *sql.DB, statement string) func() error {
+	return func() error {
+		rows, err := db.Query(statement)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		cols, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+}
+
+// ===============================================
+//   PEMETAAN DATASET -> TABEL
+// ===============================================
+
+func sqlValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format("2006-01-02 15:04:05")
+	}
+	return v
+}
+
+func pick(row seeder.Row, columns ...string) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = sqlValue(row[c])
+	}
+	return values
+}
+
+func each(rows []seeder.Row, columns ...string) [][]interface{} {
+	out := make([][]interface{}, len(rows))
+	for i, r := range rows {
+		out[i] = pick(r, columns...)
+	}
+	return out
+}
+
+func sqliteTables() []sqliteTable {
+	return []sqliteTable{
+		{"user", []string{"email", "nama_lengkap", "nomor_telepon"}, func(ds *seeder.Dataset) [][]interface{} {
+			return append(each(ds.Pasien, "email", "nama_lengkap", "nomor_telepon"),
+				each(ds.TenagaMedis, "email", "nama_lengkap", "nomor_telepon")...)
+		}},
+		{"pasien", []string{"email", "nik", "jenis_kelamin", "tanggal_lahir", "provinsi", "kota", "kecamatan", "jalan"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.Pasien, "email", "nik", "jenis_kelamin", "tanggal_lahir", "provinsi", "kota", "kecamatan", "jalan")
+		}},
+		{"rumah_sakit", []string{"id_rs", "email", "nama_rumah_sakit", "no_telepon", "provinsi", "kota", "kecamatan", "jalan"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.RumahSakit, "id_rs", "email", "nama_rumah_sakit", "no_telepon", "provinsi", "kota", "kecamatan", "jalan")
+		}},
+		{"departemen", []string{"id_departemen", "nama_departemen", "gedung", "id_rs"}, func(ds *seeder.Dataset) [][]interface{} {
+			rs := rsDepartemen(ds)
+			out := make([][]interface{}, len(ds.Departemen))
+			for i, d := range ds.Departemen {
+				out[i] = []interface{}{d["nama_departemen"], d["nama_departemen"], d["gedung"], rs[d["nama_departemen"]]}
+			}
+			return out
+		}},
+		{"tenaga_medis", []string{"email", "profesi", "id_departemen", "id_rs"}, func(ds *seeder.Dataset) [][]interface{} {
+			rs := rsDepartemen(ds)
+			dept := map[interface{}]interface{}{}
+			for _, r := range ds.BekerjaDi {
+				dept[r["email_tm"]] = r["nama_dept"]
+			}
+			out := make([][]interface{}, len(ds.TenagaMedis))
+			for i, tm := range ds.TenagaMedis {
+				d := dept[tm["email"]]
+				out[i] = []interface{}{tm["email"], tm["profesi"], d, rs[d]}
+			}
+			return out
+		}},
+		{"layanan_medis", []string{"id_rs", "id_layanan", "nama_layanan", "biaya_layanan"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.Penawaran, "id_rs", "id_layanan", "nama_layanan", "biaya_layanan")
+		}},
+		{"obat", []string{"id_obat", "nama", "label", "harga", "stok"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.Obat, "id_obat", "nama", "label", "harga", "stok")
+		}},
+		{"pemesanan_obat", []string{"id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.PemesananObat, "id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan")
+		}},
+		{"detail_pesanan", []string{"id_pesanan", "id_obat", "jumlah"}, func(ds *seeder.Dataset) [][]interface{} {
+			var out [][]interface{}
+			for _, d := range ds.DetailPesananObat {
+				daftar, _ := d["daftar_obat"].(map[string]int)
+				for idObat, jumlah := range daftar {
+					out = append(out, []interface{}{d["id_pesanan"], idObat, jumlah})
+				}
+			}
+			return out
+		}},
+		{"pemesanan_layanan", []string{"id_pesanan", "email_pemesan", "id_rs", "id_layanan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.PemesananLayanan, "id_pesanan", "email_pemesan", "id_rs", "id_layanan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan")
+		}},
+		{"baymin", []string{"id_perangkat", "warna", "email_pasien"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.Baymin, "id_perangkat", "warna", "email_pasien")
+		}},
+		{"log_aktivitas", []string{"id_perangkat", "waktu_aktivitas", "detail_aktivitas"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.LogAktivitas, "id_perangkat", "waktu_aktivitas", "detail_aktivitas")
+		}},
+		{"janji_temu", []string{"id_janji_temu", "waktu_pelaksanaan", "alasan", "status", "email_pasien", "email_tenaga_medis", "id_rs"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.JanjiTemu, "id_janji_temu", "waktu_pelaksanaan", "alasan", "status", "p_email", "t_email", "id_rs")
+		}},
+		{"resep", []string{"id_resep", "penyakit"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.Resep, "id_resep", "penyakit")
+		}},
+		{"hasil_janji_temu", []string{"id_janji_temu", "id_resep"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.Resep, "id_janji_temu", "id_resep")
+		}},
+		{"detail_resep", []string{"id_detail_resep", "id_resep", "id_obat", "dosis"}, func(ds *seeder.Dataset) [][]interface{} {
+			return each(ds.DetailResep, "id_detail_resep", "id_resep", "id_obat", "dosis")
+		}},
+	}
+}
+
+// rsDepartemen memetakan nama departemen ke id_rs pemiliknya.
+func rsDepartemen(ds *seeder.Dataset) map[interface{}]interface{} {
+	m := make(map[interface{}]interface{}, len(ds.MemilikiDepartemen))
+	for _, r := range ds.MemilikiDepartemen {
+		m[r["nama_dept"]] = r["id_rs"]
+	}
+	return m
+}
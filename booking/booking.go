@@ -0,0 +1,373 @@
+package booking
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/queries"
+)
+
+// ===============================================
+//   BOOKING JANJI TEMU
+// ===============================================
+//
+// Alur pesan: baca jadwal dokter → pastikan waktu adalah awal slot praktik
+// di masa depan → janji_temu.pesan membuat JanjiTemu beserta
+// memiliki_janji/dengan_dokter/di_rs dalam satu transaksi yang menolak
+// janji temu dokter atau pasien yang beririsan. Rumah sakit janji temu
+// adalah rumah sakit departemen dokter. Jadwal ulang memakai pemeriksaan
+// yang sama; batal hanya mengubah status sehingga slot kembali kosong.
+//
+// Dipakai oleh rs appointment ... dan endpoint booking di paket api.
+
+// DurasiDefault dipakai untuk janji temu lama yang belum mencatat durasi.
+const DurasiDefault = 30 * time.Minute
+
+// ParseWaktu membaca waktu janji temu: "2006-01-02 15:04", dengan detik,
+// dengan T, atau RFC3339 (dikonversi ke waktu lokal).
+func ParseWaktu(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(time.Local), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("waktu %q harus berformat YYYY-MM-DD HH:MM", s)
+}
+
+// ===============================================
+//   JADWAL
+// ===============================================
+
+// JadwalDokter membaca tenaga medis beserta jadwal praktik yang berlaku:
+// jadwal dokter bila ada, selain itu jadwal departemennya, selain itu
+// jadwal rumah sakitnya.
+func JadwalDokter(email string) (*Dokter, error) {
+	if err := queries.ValidEmail(email); err != nil {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: err.Error()}
+	}
+	tm, err := queries.JadwalTenagaMedis(email)
+	if err != nil {
+		return nil, err
+	}
+	if tm == nil {
+		return nil, domain.Fail(domain.NotFound, "tenaga medis %s tidak ditemukan", email)
+	}
+
+	d := &Dokter{Email: tm.Email, Nama: tm.Nama, Departemen: tm.Departemen, IdRS: tm.IdRS}
+	for _, level := range []struct {
+		sumber string
+		list   []queries.JadwalPraktik
+	}{
+		{queries.PemilikDokter, tm.JadwalDokter},
+		{queries.PemilikDepartemen, tm.JadwalDepartemen},
+		{queries.PemilikRumahSakit, tm.JadwalRS},
+	} {
+		if len(level.list) == 0 {
+			continue
+		}
+		if d.Jadwal, err = jadwalDari(level.list); err != nil {
+			return nil, err
+		}
+		d.Sumber = level.sumber
+		break
+	}
+	return d, nil
+}
+
+// SimpanJadwal mengganti jadwal praktik pemilik (queries.PemilikDokter,
+// PemilikDepartemen atau PemilikRumahSakit) pada hari-hari jadwal.Hari.
+// Mengembalikan jumlah hari yang disimpan.
+func SimpanJadwal(pemilik, kunci string, hari []time.Weekday, j Jadwal) (int, error) {
+	if err := validPemilik(pemilik, kunci); err != nil {
+		return 0, err
+	}
+	if err := j.Validate(); err != nil {
+		return 0, &domain.Error{Kind: domain.Invalid, Message: err.Error()}
+	}
+	n, err := queries.SimpanJadwalPraktik(pemilik, kunci, namaHariList(hari), j.Mulai.String(), j.Selesai.String(), int(j.Slot/time.Minute))
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, domain.Fail(domain.NotFound, "%s %s tidak ditemukan", pemilik, kunci)
+	}
+	return n, nil
+}
+
+// HapusJadwal menghapus jadwal praktik pemilik pada hari-hari tersebut
+// (hari libur) dan mengembalikan jumlah jadwal yang terhapus.
+func HapusJadwal(pemilik, kunci string, hari []time.Weekday) (int, error) {
+	if err := validPemilik(pemilik, kunci); err != nil {
+		return 0, err
+	}
+	found, n, err := queries.HapusJadwalPraktik(pemilik, kunci, namaHariList(hari))
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, domain.Fail(domain.NotFound, "%s %s tidak ditemukan", pemilik, kunci)
+	}
+	return n, nil
+}
+
+func validPemilik(pemilik, kunci string) error {
+	switch pemilik {
+	case queries.PemilikDokter, queries.PemilikDepartemen, queries.PemilikRumahSakit:
+	default:
+		return domain.Fail(domain.Invalid, "pemilik jadwal %q tidak dikenal", pemilik)
+	}
+	if strings.TrimSpace(kunci) == "" {
+		return domain.Fail(domain.Invalid, "%s wajib diisi", pemilik)
+	}
+	return nil
+}
+
+func namaHariList(hari []time.Weekday) []string {
+	out := make([]string, len(hari))
+	for i, d := range hari {
+		out[i] = NamaHari(d)
+	}
+	return out
+}
+
+// ===============================================
+//   SLOT KOSONG
+// ===============================================
+
+// SlotKosong mengembalikan dokter dan slot praktiknya yang belum terisi
+// janji temu selama hari hari sejak tanggal dari. Slot yang sudah lewat
+// dari now tidak dikembalikan.
+func SlotKosong(email string, dari time.Time, hari int, now time.Time) (*Dokter, []Slot, error) {
+	if hari < 1 || hari > 62 {
+		return nil, nil, domain.Fail(domain.Invalid, "jumlah hari harus 1-62, bukan %d", hari)
+	}
+	d, err := JadwalDokter(email)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.Sumber == "" {
+		return d, nil, nil
+	}
+
+	y, m, day := dari.Date()
+	start := time.Date(y, m, day, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, hari)
+	if start.Before(now) {
+		start = now
+	}
+	if !start.Before(end) {
+		return d, nil, nil
+	}
+
+	// Janji temu yang dimulai sebelum start masih bisa menempati slot awal.
+	janji, err := queries.JanjiTemuTerjadwal(d.Email, start.Add(-24*time.Hour), end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, Kosong(d.Slots(start, end), slotTerisi(janji)), nil
+}
+
+// slotTerisi mengubah janji temu menjadi slot yang ditempatinya. Janji temu
+// yang dibatalkan tidak menempati slot sehingga slotnya bisa dipesan lagi.
+func slotTerisi(janji []queries.JanjiTerjadwal) []Slot {
+	terisi := make([]Slot, 0, len(janji))
+	for _, j := range janji {
+		if j.Status == "dibatalkan" {
+			continue
+		}
+		s, err := slotJanji(j.WaktuPelaksanaan, j.DurasiMenit)
+		if err != nil {
+			continue // data lama dengan format waktu lain tidak memblokir slot
+		}
+		terisi = append(terisi, s)
+	}
+	return terisi
+}
+
+func slotJanji(waktu string, durasiMenit int) (Slot, error) {
+	t, err := time.ParseInLocation(queries.FormatWaktuJanji, waktu, time.Local)
+	if err != nil {
+		return Slot{}, err
+	}
+	durasi := DurasiDefault
+	if durasiMenit > 0 {
+		durasi = time.Duration(durasiMenit) * time.Minute
+	}
+	return Slot{Mulai: t, Selesai: t.Add(durasi)}, nil
+}
+
+// ===============================================
+//   PESAN, JADWAL ULANG, BATAL
+// ===============================================
+
+// Permintaan adalah data booking janji temu baru.
+type Permintaan struct {
+	ID          string // kosong = dibuat otomatis
+	EmailPasien string
+	EmailDokter string
+	Waktu       time.Time
+	Alasan      string
+}
+
+// Pesan membuat janji temu pada slot praktik dokter yang masih kosong.
+func Pesan(p Permintaan, now time.Time) (*queries.Booking, error) {
+	var details []string
+	if err := queries.ValidEmail(p.EmailPasien); err != nil {
+		details = append(details, "email_pasien: "+err.Error())
+	}
+	if err := queries.ValidEmail(p.EmailDokter); err != nil {
+		details = append(details, "email_dokter: "+err.Error())
+	}
+	if p.Waktu.IsZero() {
+		details = append(details, "waktu wajib diisi")
+	}
+	if len(details) > 0 {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: "permintaan booking tidak valid", Details: details}
+	}
+
+	d, slot, err := slotDokter(p.EmailDokter, p.Waktu, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.ID == "" {
+		p.ID = domain.NewID("JT-")
+	}
+	req := queries.PesanJanji{
+		ID:            p.ID,
+		EmailPasien:   p.EmailPasien,
+		EmailDokter:   d.Email,
+		IdRS:          d.IdRS,
+		Waktu:         slot.Mulai.Format(queries.FormatWaktuJanji),
+		Durasi:        int(slot.Selesai.Sub(slot.Mulai) / time.Minute),
+		DurasiDefault: int(DurasiDefault / time.Minute),
+		Alasan:        strings.TrimSpace(p.Alasan),
+	}
+	created, err := queries.PesanJanjiTemu(req)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		if err := bentrok(req.ID, d.Email, p.EmailPasien, req.Waktu, req.Durasi); err != nil {
+			return nil, err
+		}
+		return nil, domain.Fail(domain.NotFound, "pasien %s tidak ditemukan", p.EmailPasien)
+	}
+	return booked(req.ID)
+}
+
+// JadwalUlang memindahkan janji temu ke slot praktik lain dokter yang sama.
+func JadwalUlang(id string, waktu time.Time, now time.Time) (*queries.Booking, error) {
+	b, err := aktif(id)
+	if err != nil {
+		return nil, err
+	}
+	_, slot, err := slotDokter(b.EmailDokter, waktu, now)
+	if err != nil {
+		return nil, err
+	}
+
+	w := slot.Mulai.Format(queries.FormatWaktuJanji)
+	durasi := int(slot.Selesai.Sub(slot.Mulai) / time.Minute)
+	moved, err := queries.JadwalUlangJanjiTemu(id, w, durasi, int(DurasiDefault/time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		if err := bentrok(id, b.EmailDokter, b.EmailPasien, w, durasi); err != nil {
+			return nil, err
+		}
+		// Status berubah di antara pembacaan dan update.
+		return nil, domain.Fail(domain.Conflict, "janji temu %s tidak bisa dijadwalkan ulang, status berubah", id)
+	}
+	return booked(id)
+}
+
+// Batal membatalkan janji temu yang belum selesai atau dibatalkan.
+func Batal(id string) (*queries.Booking, error) {
+	if _, err := aktif(id); err != nil {
+		return nil, err
+	}
+	ok, err := queries.BatalkanJanjiTemu(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.Fail(domain.Conflict, "janji temu %s tidak bisa dibatalkan, status berubah", id)
+	}
+	return booked(id)
+}
+
+// aktif membaca janji temu yang masih boleh diubah.
+func aktif(id string) (*queries.Booking, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, domain.Fail(domain.Invalid, "id janji temu wajib diisi")
+	}
+	b, err := queries.BookingJanjiTemu(id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, domain.Fail(domain.NotFound, "janji temu %s tidak ditemukan", id)
+	}
+	if b.Status == "selesai" || b.Status == "dibatalkan" {
+		return nil, domain.Fail(domain.Conflict, "janji temu %s sudah %s", id, b.Status)
+	}
+	return b, nil
+}
+
+// slotDokter memastikan waktu adalah awal slot praktik dokter di masa depan.
+func slotDokter(email string, waktu, now time.Time) (*Dokter, Slot, error) {
+	if !waktu.After(now) {
+		return nil, Slot{}, domain.Fail(domain.Invalid, "waktu %s sudah lewat", waktu.Format("2006-01-02 15:04"))
+	}
+	d, err := JadwalDokter(email)
+	if err != nil {
+		return nil, Slot{}, err
+	}
+	if d.IdRS == "" {
+		return nil, Slot{}, domain.Fail(domain.Invalid, "tenaga medis %s tidak terhubung ke rumah sakit lewat departemen", d.Email)
+	}
+	if d.Sumber == "" {
+		return nil, Slot{}, domain.Fail(domain.Invalid, "tenaga medis %s belum punya jadwal praktik (atur dengan rs appointment set-schedule)", d.Email)
+	}
+	slot, err := d.SlotPada(waktu)
+	if err != nil {
+		return nil, Slot{}, &domain.Error{Kind: domain.Invalid, Message: err.Error()}
+	}
+	return d, slot, nil
+}
+
+// bentrok mengembalikan domain.Error Conflict berisi janji temu yang beririsan,
+// atau nil bila tidak ada.
+func bentrok(id, emailDokter, emailPasien, waktu string, durasi int) error {
+	list, err := queries.BentrokJanjiTemu(id, emailDokter, emailPasien, waktu, durasi, int(DurasiDefault/time.Minute))
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	e := domain.Fail(domain.Conflict, "jadwal %s bentrok dengan janji temu lain", waktu)
+	for _, b := range list {
+		e.Details = append(e.Details, fmt.Sprintf("%s pukul %s (jadwal %s)", b.IDJanjiTemu, b.WaktuPelaksanaan, b.Peran))
+	}
+	return e
+}
+
+func booked(id string) (*queries.Booking, error) {
+	b, err := queries.BookingJanjiTemu(id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, domain.Fail(domain.NotFound, "janji temu %s tidak ditemukan", id)
+	}
+	return b, nil
+}
@@ -0,0 +1,51 @@
+package booking
+
+import (
+	"reflect"
+	"testing"
+
+	"src/queries"
+)
+
+func TestSlotTerisi(t *testing.T) {
+	tests := []struct {
+		name  string
+		janji []queries.JanjiTerjadwal
+		want  []Slot
+	}{
+		{"dijadwalkan", []queries.JanjiTerjadwal{
+			{IDJanjiTemu: "JT1", WaktuPelaksanaan: "2025-01-06 09:00:00", DurasiMenit: 45, Status: "dijadwalkan"},
+		}, []Slot{slot("09:00", "09:45")}},
+		{"tanpa durasi memakai DurasiDefault", []queries.JanjiTerjadwal{
+			{IDJanjiTemu: "JT1", WaktuPelaksanaan: "2025-01-06 09:00:00"},
+		}, []Slot{slot("09:00", "09:30")}},
+		{"format waktu lama dilewati", []queries.JanjiTerjadwal{
+			{IDJanjiTemu: "JT1", WaktuPelaksanaan: "06/01/2025 09:00", DurasiMenit: 30},
+		}, []Slot{}},
+		{"dibatalkan lalu dipesan lagi", []queries.JanjiTerjadwal{
+			{IDJanjiTemu: "JT1", WaktuPelaksanaan: "2025-01-06 09:00:00", DurasiMenit: 30, Status: "dibatalkan"},
+			{IDJanjiTemu: "JT2", WaktuPelaksanaan: "2025-01-06 09:00:00", DurasiMenit: 30, Status: "dijadwalkan"},
+		}, []Slot{slot("09:00", "09:30")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slotTerisi(tt.janji); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("slotTerisi = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotDibatalkanKosongLagi(t *testing.T) {
+	slots := []Slot{slot("09:00", "09:30"), slot("09:30", "10:00")}
+	janji := []queries.JanjiTerjadwal{
+		{IDJanjiTemu: "JT1", WaktuPelaksanaan: "2025-01-06 09:00:00", DurasiMenit: 30, Status: "dijadwalkan"},
+	}
+	if got := Kosong(slots, slotTerisi(janji)); !reflect.DeepEqual(got, slots[1:]) {
+		t.Fatalf("sebelum batal: Kosong = %v, ingin %v", got, slots[1:])
+	}
+	janji[0].Status = "dibatalkan"
+	if got := Kosong(slots, slotTerisi(janji)); !reflect.DeepEqual(got, slots) {
+		t.Fatalf("setelah batal: Kosong = %v, ingin %v", got, slots)
+	}
+}
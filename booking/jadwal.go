@@ -0,0 +1,279 @@
+package booking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"src/queries"
+)
+
+// ===============================================
+//   JADWAL PRAKTIK & SLOT
+// ===============================================
+//
+// Jadwal praktik berlaku per hari dalam seminggu: satu rentang jam
+// (mis. 08:00-16:00) yang dibagi menjadi slot berdurasi tetap. Janji temu
+// hanya boleh dimulai di awal slot dan menempati satu slot. Semua waktu
+// adalah waktu lokal (time.Local), sama seperti waktu_pelaksanaan.
+
+// namaHari mengikuti urutan time.Weekday (Minggu = 0).
+var namaHari = []string{"minggu", "senin", "selasa", "rabu", "kamis", "jumat", "sabtu"}
+
+// urutanHari adalah urutan tampilan dan rentang hari: senin..minggu.
+var urutanHari = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// NamaHari mengembalikan nama hari seperti disimpan di JadwalPraktik.
+func NamaHari(d time.Weekday) string { return namaHari[d] }
+
+func parseNamaHari(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "jum'at" {
+		s = "jumat"
+	}
+	for i, n := range namaHari {
+		if n == s {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("hari %q tidak dikenal (senin, selasa, rabu, kamis, jumat, sabtu, minggu)", s)
+}
+
+// ParseHari membaca daftar hari seperti "senin-jumat", "sabtu,minggu" atau
+// "senin,rabu-jumat". Rentang mengikuti urutan senin..minggu.
+func ParseHari(s string) ([]time.Weekday, error) {
+	seen := map[time.Weekday]bool{}
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		a, err := parseNamaHari(from)
+		if err != nil {
+			return nil, err
+		}
+		b := a
+		if isRange {
+			if b, err = parseNamaHari(to); err != nil {
+				return nil, err
+			}
+		}
+		ia, ib := posisiHari(a), posisiHari(b)
+		if ia > ib {
+			return nil, fmt.Errorf("rentang hari %q terbalik (urutan senin..minggu)", strings.TrimSpace(part))
+		}
+		for _, d := range urutanHari[ia : ib+1] {
+			seen[d] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("daftar hari kosong")
+	}
+	var out []time.Weekday
+	for _, d := range urutanHari {
+		if seen[d] {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func posisiHari(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+// Jam adalah jam dalam sehari sebagai menit sejak 00:00.
+type Jam int
+
+// ParseJam membaca HH:MM.
+func ParseJam(s string) (Jam, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("jam %q harus berformat HH:MM", s)
+	}
+	return Jam(t.Hour()*60 + t.Minute()), nil
+}
+
+func (j Jam) String() string { return fmt.Sprintf("%02d:%02d", int(j)/60, int(j)%60) }
+
+// ParseRentangJam membaca rentang HH:MM-HH:MM.
+func ParseRentangJam(s string) (mulai, selesai Jam, err error) {
+	a, b, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("rentang jam %q harus berformat HH:MM-HH:MM", s)
+	}
+	if mulai, err = ParseJam(a); err != nil {
+		return 0, 0, err
+	}
+	if selesai, err = ParseJam(b); err != nil {
+		return 0, 0, err
+	}
+	if selesai <= mulai {
+		return 0, 0, fmt.Errorf("rentang jam %q: jam selesai harus setelah jam mulai", s)
+	}
+	return mulai, selesai, nil
+}
+
+// Jadwal adalah jam praktik pada satu hari.
+type Jadwal struct {
+	Hari    time.Weekday
+	Mulai   Jam
+	Selesai Jam
+	Slot    time.Duration
+}
+
+// Validate memastikan rentang jam bisa dibagi menjadi minimal satu slot.
+func (j Jadwal) Validate() error {
+	if j.Slot < 5*time.Minute || j.Slot%time.Minute != 0 {
+		return fmt.Errorf("durasi slot %s harus kelipatan menit dan minimal 5 menit", j.Slot)
+	}
+	if j.Selesai <= j.Mulai {
+		return fmt.Errorf("jam selesai %s harus setelah jam mulai %s", j.Selesai, j.Mulai)
+	}
+	if time.Duration(j.Selesai-j.Mulai)*time.Minute < j.Slot {
+		return fmt.Errorf("rentang %s-%s lebih pendek dari satu slot %s", j.Mulai, j.Selesai, j.SlotText())
+	}
+	return nil
+}
+
+func (j Jadwal) String() string {
+	return fmt.Sprintf("%s %s-%s (slot %s)", NamaHari(j.Hari), j.Mulai, j.Selesai, j.SlotText())
+}
+
+// SlotText menampilkan durasi slot dalam menit, mis. "30 menit".
+func (j Jadwal) SlotText() string {
+	return fmt.Sprintf("%d menit", int(j.Slot/time.Minute))
+}
+
+// slots mengembalikan semua slot jadwal pada tanggal day.
+func (j Jadwal) slots(day time.Time) []Slot {
+	y, m, d := day.Date()
+	var out []Slot
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.Local).Add(time.Duration(j.Mulai) * time.Minute)
+	end := time.Date(y, m, d, 0, 0, 0, 0, time.Local).Add(time.Duration(j.Selesai) * time.Minute)
+	for t := start; !t.Add(j.Slot).After(end); t = t.Add(j.Slot) {
+		out = append(out, Slot{Mulai: t, Selesai: t.Add(j.Slot)})
+	}
+	return out
+}
+
+func jadwalDari(list []queries.JadwalPraktik) ([]Jadwal, error) {
+	out := make([]Jadwal, 0, len(list))
+	for _, jp := range list {
+		hari, err := parseNamaHari(jp.Hari)
+		if err != nil {
+			return nil, err
+		}
+		mulai, err := ParseJam(jp.JamMulai)
+		if err != nil {
+			return nil, err
+		}
+		selesai, err := ParseJam(jp.JamSelesai)
+		if err != nil {
+			return nil, err
+		}
+		j := Jadwal{Hari: hari, Mulai: mulai, Selesai: selesai, Slot: time.Duration(jp.DurasiSlot) * time.Minute}
+		if err := j.Validate(); err != nil {
+			return nil, fmt.Errorf("jadwal %s tersimpan tidak valid: %v", jp.Hari, err)
+		}
+		out = append(out, j)
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Hari != out[b].Hari {
+			return posisiHari(out[a].Hari) < posisiHari(out[b].Hari)
+		}
+		return out[a].Mulai < out[b].Mulai
+	})
+	return out, nil
+}
+
+// Slot adalah rentang waktu [Mulai, Selesai).
+type Slot struct {
+	Mulai   time.Time
+	Selesai time.Time
+}
+
+func (s Slot) overlaps(o Slot) bool {
+	return s.Mulai.Before(o.Selesai) && o.Mulai.Before(s.Selesai)
+}
+
+// ===============================================
+//   DOKTER
+// ===============================================
+
+// Dokter adalah tenaga medis dengan jadwal praktik yang berlaku untuknya.
+type Dokter struct {
+	Email      string
+	Nama       string
+	Departemen string
+	IdRS       string
+	// Sumber adalah pemilik jadwal yang berlaku: queries.PemilikDokter,
+	// PemilikDepartemen, PemilikRumahSakit, atau "" bila belum ada jadwal.
+	Sumber string
+	Jadwal []Jadwal
+}
+
+func (d *Dokter) jadwalHari(day time.Weekday) []Jadwal {
+	var out []Jadwal
+	for _, j := range d.Jadwal {
+		if j.Hari == day {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// Slots mengembalikan semua slot praktik yang dimulai dalam [dari, sampai).
+func (d *Dokter) Slots(dari, sampai time.Time) []Slot {
+	var out []Slot
+	y, m, day := dari.Date()
+	for date := time.Date(y, m, day, 0, 0, 0, 0, time.Local); date.Before(sampai); date = date.AddDate(0, 0, 1) {
+		for _, j := range d.jadwalHari(date.Weekday()) {
+			for _, s := range j.slots(date) {
+				if !s.Mulai.Before(dari) && s.Mulai.Before(sampai) {
+					out = append(out, s)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// SlotPada mengembalikan slot yang dimulai tepat pada waktu t, atau error
+// yang menjelaskan jam praktik hari itu.
+func (d *Dokter) SlotPada(t time.Time) (Slot, error) {
+	jadwal := d.jadwalHari(t.Weekday())
+	if len(jadwal) == 0 {
+		return Slot{}, fmt.Errorf("%s tidak praktik pada hari %s", d.Email, NamaHari(t.Weekday()))
+	}
+	var jam []string
+	for _, j := range jadwal {
+		for _, s := range j.slots(t) {
+			if s.Mulai.Equal(t) {
+				return s, nil
+			}
+		}
+		jam = append(jam, fmt.Sprintf("%s-%s tiap %s", j.Mulai, j.Selesai, j.SlotText()))
+	}
+	return Slot{}, fmt.Errorf("%s bukan awal slot praktik %s hari %s (%s)",
+		t.Format("15:04"), d.Email, NamaHari(t.Weekday()), strings.Join(jam, ", "))
+}
+
+// Kosong membuang slot yang beririsan dengan salah satu slot terisi.
+func Kosong(slots, terisi []Slot) []Slot {
+	var out []Slot
+	for _, s := range slots {
+		free := true
+		for _, b := range terisi {
+			if s.overlaps(b) {
+				free = false
+				break
+			}
+		}
+		if free {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package booking
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func lokal(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func slot(mulai, selesai string) Slot {
+	return Slot{Mulai: lokal("2025-01-06 " + mulai), Selesai: lokal("2025-01-06 " + selesai)}
+}
+
+func TestSlotOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Slot
+		want bool
+	}{
+		{"mulai sama", slot("09:00", "09:30"), slot("09:00", "09:30"), true},
+		{"mulai sama durasi beda", slot("09:00", "09:30"), slot("09:00", "10:00"), true},
+		{"bersentuhan di akhir", slot("09:00", "09:30"), slot("09:30", "10:00"), false},
+		{"bersentuhan di awal", slot("09:30", "10:00"), slot("09:00", "09:30"), false},
+		{"beririsan sebagian", slot("09:00", "09:30"), slot("09:15", "09:45"), true},
+		{"di dalam", slot("09:00", "10:00"), slot("09:15", "09:30"), true},
+		{"terpisah", slot("09:00", "09:30"), slot("10:00", "10:30"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.overlaps(tt.b); got != tt.want {
+				t.Errorf("overlaps(%v, %v) = %v, ingin %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := tt.b.overlaps(tt.a); got != tt.want {
+				t.Errorf("overlaps(%v, %v) = %v, ingin %v (simetris)", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKosong(t *testing.T) {
+	slots := []Slot{slot("09:00", "09:30"), slot("09:30", "10:00"), slot("10:00", "10:30")}
+	tests := []struct {
+		name   string
+		terisi []Slot
+		want   []Slot
+	}{
+		{"tanpa janji temu", nil, slots},
+		{"satu slot terisi", []Slot{slot("09:30", "10:00")}, []Slot{slots[0], slots[2]}},
+		{"janji temu panjang menutup dua slot", []Slot{slot("09:15", "10:00")}, []Slot{slots[2]}},
+		{"bersentuhan sebelum slot pertama", []Slot{slot("08:30", "09:00")}, slots},
+		{"bersentuhan setelah slot terakhir", []Slot{slot("10:30", "11:00")}, slots},
+		{"semua terisi", []Slot{slot("08:00", "12:00")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Kosong(slots, tt.terisi); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Kosong = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotPada(t *testing.T) {
+	d := &Dokter{Email: "tm1@rs.com", Jadwal: []Jadwal{
+		{Hari: time.Monday, Mulai: 9 * 60, Selesai: 10*60 + 15, Slot: 30 * time.Minute},
+	}}
+	tests := []struct {
+		name    string
+		waktu   string
+		want    Slot
+		wantErr bool
+	}{
+		{"slot pertama", "2025-01-06 09:00", slot("09:00", "09:30"), false},
+		{"slot terakhir yang muat", "2025-01-06 09:30", slot("09:30", "10:00"), false},
+		{"sisa jam tidak cukup satu slot", "2025-01-06 10:00", Slot{}, true},
+		{"di tengah slot", "2025-01-06 09:15", Slot{}, true},
+		{"sebelum jam praktik", "2025-01-06 08:30", Slot{}, true},
+		{"hari tidak praktik", "2025-01-07 09:00", Slot{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.SlotPada(lokal(tt.waktu))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SlotPada(%s) = %v, ingin error", tt.waktu, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SlotPada(%s): %v", tt.waktu, err)
+			}
+			if got != tt.want {
+				t.Fatalf("SlotPada(%s) = %v, ingin %v", tt.waktu, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlots(t *testing.T) {
+	d := &Dokter{Jadwal: []Jadwal{
+		{Hari: time.Monday, Mulai: 9 * 60, Selesai: 10 * 60, Slot: 30 * time.Minute},
+		{Hari: time.Tuesday, Mulai: 13 * 60, Selesai: 14 * 60, Slot: 60 * time.Minute},
+	}}
+	got := d.Slots(lokal("2025-01-06 09:10"), lokal("2025-01-08 00:00"))
+	want := []Slot{
+		slot("09:30", "10:00"),
+		{Mulai: lokal("2025-01-07 13:00"), Selesai: lokal("2025-01-07 14:00")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Slots = %v, ingin %v", got, want)
+	}
+}
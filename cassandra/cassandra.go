@@ -1,29 +1,70 @@
 package cassandra
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"syscall"
+
 	"github.com/gocql/gocql"
 )
 
 var Session *gocql.Session
 
+// Keyspace aplikasi; query seeder dan schema memakai nama ini secara eksplisit.
+const Keyspace = "rumahsakit"
+
+// Config berisi parameter koneksi Cassandra.
+type Config struct {
+	Host     string
+	Port     int
+	Keyspace string
+}
+
+// ConfigFromEnv membaca CASSANDRA_HOST dan CASSANDRA_PORT dengan nilai
+// default untuk docker-compose.
+func ConfigFromEnv() Config {
+	return Config{
+		Host:     getEnv("CASSANDRA_HOST", "127.0.0.1"),
+		Port:     getEnvInt("CASSANDRA_PORT", 9042),
+		Keyspace: Keyspace,
+	}
+}
+
+// DefaultConfig dipakai InitCassandra. CLI dapat menimpanya dengan nilai
+// dari flag atau file konfigurasi sebelum seeder/simulator terhubung.
+var DefaultConfig = ConfigFromEnv()
+
+// Cluster membuat konfigurasi cluster gocql. Keyspace kosong berarti
+// koneksi tanpa keyspace (dipakai saat membuat keyspace).
+func (c Config) Cluster() *gocql.ClusterConfig {
+	cluster := gocql.NewCluster(c.Host)
+	cluster.Port = c.Port
+	cluster.Keyspace = c.Keyspace
+	cluster.Consistency = gocql.Quorum
+	return cluster
+}
+
 // ====================================
 // Init Cassandra connection
 // ====================================
 func InitCassandra() {
-	cluster := gocql.NewCluster(getEnv("CASSANDRA_HOST", "127.0.0.1"))
-	cluster.Port = getEnvInt("CASSANDRA_PORT", 9042)
-	cluster.Keyspace = "rumahsakit"
-	cluster.Consistency = gocql.Quorum
+	if err := Connect(DefaultConfig); err != nil {
+		log.Fatalf("Cassandra connection failed: %v", err)
+	}
+	fmt.Println("Connected to Cassandra")
+}
 
-	session, err := cluster.CreateSession()
+// Connect membuka Session global dengan konfigurasi yang diberikan.
+func Connect(cfg Config) error {
+	session, err := cfg.Cluster().CreateSession()
 	if err != nil {
-		log.Fatalf("Cassandra connection failed: %v", err)
+		return err
 	}
 	Session = session
-	fmt.Println("Connected to Cassandra")
+	return nil
 }
 
 // Close session
@@ -33,7 +74,6 @@ func Close() {
 	}
 }
 
-
 // ====================================
 // CRUD Functions
 // ====================================
@@ -48,12 +88,121 @@ func InsertCassandra(query string, params ...interface{}) error {
 	return ExecCassandra(query, params...)
 }
 
+// Batch Insert (UNLOGGED). Use for rows in the same partition; a batch
+// spanning many partitions puts load on a single coordinator.
+func BatchInsertCassandra(query string, rows [][]interface{}) error {
+	batch := Session.NewBatch(gocql.UnloggedBatch)
+	for _, args := range rows {
+		batch.Query(query, args...)
+	}
+	return Session.ExecuteBatch(batch)
+}
+
+// Statement is one CQL statement with its parameters, used in a batch.
+type Statement struct {
+	Query  string
+	Params []interface{}
+}
+
+// LoggedBatchCassandra writes statements for different tables or partitions
+// as one LOGGED batch: either all of them are eventually applied or none.
+func LoggedBatchCassandra(statements ...Statement) error {
+	batch := Session.NewBatch(gocql.LoggedBatch)
+	for _, s := range statements {
+		batch.Query(s.Query, s.Params...)
+	}
+	return Session.ExecuteBatch(batch)
+}
+
 // Read (SELECT)
 func SelectCassandra(query string, params ...interface{}) (*gocql.Iter, error) {
 	iter := Session.Query(query, params...).Iter()
 	return iter, nil
 }
 
+// ColumnsCassandra executes a read and returns the names of its result columns.
+// Only the first page (one row) is fetched.
+func ColumnsCassandra(query string, params ...interface{}) ([]string, error) {
+	iter := Session.Query(query, params...).PageSize(1).Iter()
+	var names []string
+	for _, c := range iter.Columns() {
+		names = append(names, c.Name)
+	}
+	return names, iter.Close()
+}
+
+// PrepareCassandra asks the server to prepare the statement without executing it, so
+// syntax errors and unknown tables/columns surface for writes too. The
+// params are marshalled against the partition key types.
+func PrepareCassandra(query string, params ...interface{}) error {
+	_, err := Session.Query(query, params...).GetRoutingKey()
+	return err
+}
+
+// StreamCassandra executes any statement and calls fn for every row with
+// the result column names, fetching pageSize rows per round trip. When trace
+// is non-nil tracing is enabled and the server-side trace is written to it.
+// Returning an error from fn stops reading further pages.
+func StreamCassandra(query string, params []interface{}, pageSize int, trace io.Writer, fn func(columns []string, row []interface{}) error) ([]string, error) {
+	q := Session.Query(query, params...).PageSize(pageSize)
+	if trace != nil {
+		q = q.Trace(gocql.NewTraceWriter(Session, trace))
+	}
+	iter := q.Iter()
+
+	var columns []string
+	for _, c := range iter.Columns() {
+		columns = append(columns, c.Name)
+	}
+	for {
+		m := map[string]interface{}{}
+		if !iter.MapScan(m) {
+			break
+		}
+		row := make([]interface{}, len(columns))
+		for i, name := range columns {
+			row[i] = m[name]
+		}
+		if err := fn(columns, row); err != nil {
+			iter.Close()
+			return columns, err
+		}
+	}
+	return columns, iter.Close()
+}
+
+// PageCassandra reads one page of at most pageSize rows starting at the
+// driver paging state (nil = first page). The returned state is nil when
+// there are no more rows.
+func PageCassandra(query string, params []interface{}, pageSize int, state []byte) ([]map[string]interface{}, []byte, error) {
+	iter := Session.Query(query, params...).PageSize(pageSize).PageState(state).Iter()
+	var rows []map[string]interface{}
+	for n := iter.NumRows(); n > 0; n-- {
+		row := map[string]interface{}{}
+		if !iter.MapScan(row) {
+			break
+		}
+		rows = append(rows, row)
+	}
+	next := iter.PageState()
+	if err := iter.Close(); err != nil {
+		return nil, nil, err
+	}
+	if len(next) == 0 {
+		next = nil
+	}
+	return rows, next, nil
+}
+
+// CASCassandra executes a lightweight transaction (INSERT ... IF NOT EXISTS,
+// UPDATE ... IF ...) and reports whether it was applied. When it was not,
+// previous holds the current values of the row returned by the server.
+func CASCassandra(query string, params ...interface{}) (applied bool, previous map[string]interface{}, err error) {
+	previous = map[string]interface{}{}
+	applied, err = Session.Query(query, params...).MapScanCAS(previous)
+	return applied, previous, err
+}
+
 // Update
 func UpdateCassandra(query string, params ...interface{}) error {
 	return ExecCassandra(query, params...)
@@ -64,6 +213,30 @@ func DeleteCassandra(query string, params ...interface{}) error {
 	return ExecCassandra(query, params...)
 }
 
+// IsTransient reports whether err is a temporary failure (timeout,
+// overloaded or unavailable node) that is safe to retry.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gocql.ErrTimeoutNoResponse) || errors.Is(err, gocql.ErrConnectionClosed) || errors.Is(err, gocql.ErrNoConnections) {
+		return true
+	}
+
+	var reqErr gocql.RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Code() {
+		case gocql.ErrCodeUnavailable, gocql.ErrCodeOverloaded, gocql.ErrCodeBootstrapping,
+			gocql.ErrCodeWriteTimeout, gocql.ErrCodeReadTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 // --- Helper ---
 func getEnv(key string, def string) string {
 	val, ok := lookupEnv(key)
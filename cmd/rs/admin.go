@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"src/cassandra"
+	"src/schema"
+	"src/seeder"
+	"src/simulator"
+)
+
+// ===============================================
+//   rs schema init
+// ===============================================
+
+var schemaGroup = &group{
+	Name:    "schema",
+	Summary: "kelola schema database",
+	Commands: []*command{
+		// Cassandra tidak dihubungkan lebih dulu karena keyspace mungkin
+		// belum ada; schema.CreateCassandra membuka koneksinya sendiri.
+		{Name: "init", Summary: "buat keyspace, tabel Cassandra dan constraint Neo4j", Stores: useNeo4j, Raw: true, Setup: static(schemaInit)},
+	},
+}
+
+func schemaInit(e *env) error {
+	defer cassandra.Close()
+	errCassandra := schema.CreateCassandra(e.opts.Cassandra)
+	errNeo4j := schema.CreateNeo4j()
+	return errors.Join(errCassandra, errNeo4j)
+}
+
+// ===============================================
+//   rs seed
+// ===============================================
+//
+// Contoh:
+//   rs seed                                  # profil default
+//   rs seed --profile tiny --seed 42         # dataset kecil & deterministik
+//   rs seed --only cassandra --obat 5000     # hanya tabel Cassandra
+//   rs seed --only obat,pasien
+//   rs seed --verify-only                    # cek konsistensi data yang ada
+//   rs seed --seed 42 --export fixtures      # tulis fixture CSV, tanpa database
+//   rs seed --import fixtures                # muat fixture ke database
+//   rs seed --resume                         # lanjutkan run yang terputus
+//   rs seed --reset --profile tiny           # kosongkan data lalu seed ulang
+
+func seedMain(args []string) int {
+	fs := flag.NewFlagSet("rs seed", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs seed [flags]\n\nEntitas untuk --only: %v\n\nFlags:\n", seeder.EntityNames())
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, false)
+	verify := fs.Bool("verify", false, "verifikasi konsistensi lintas store setelah seeding")
+	verifyOnly := fs.Bool("verify-only", false, "hanya verifikasi konsistensi, tanpa seeding")
+	exportDir := fs.String("export", "", "tulis dataset ke direktori fixture tanpa menyentuh database")
+	exportFormat := fs.String("export-format", seeder.FormatCSV, "format fixture untuk --export: csv atau jsonl")
+	importDir := fs.String("import", "", "muat fixture dari direktori (hasil --export) alih-alih membangkitkan data")
+	reset := fs.Bool("reset", false, "hapus data hasil seeding sebelumnya (per key, dari checkpoint) sebelum seeding")
+
+	cfg, err := seeder.ParseFlags(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err == nil {
+		err = opts.resolve(fs)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	if *exportDir != "" {
+		if *exportFormat != seeder.FormatCSV && *exportFormat != seeder.FormatJSONL {
+			fmt.Fprintf(os.Stderr, "Error: --export-format harus csv atau jsonl, bukan %q\n", *exportFormat)
+			return 2
+		}
+		report, err := seeder.Export(cfg, *exportDir, *exportFormat)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		report.Print()
+		fmt.Printf("Fixture ditulis ke %s\n", *exportDir)
+		return 0
+	}
+
+	if *reset && cfg.Resume {
+		fmt.Fprintln(os.Stderr, "Error: --reset dan --resume tidak bisa dipakai bersamaan")
+		return 2
+	}
+	if *reset {
+		if err := seeder.Reset(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+	}
+
+	// Ctrl+C menghentikan seeding dengan rapi; checkpoint tetap tersimpan
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	exitCode := 0
+	if !*verifyOnly {
+		var report seeder.Report
+		if *importDir != "" {
+			report, err = seeder.Import(ctx, cfg, *importDir)
+		} else {
+			report, err = seeder.Run(ctx, cfg)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		report.Print()
+		if report.Failed() > 0 || report.Interrupted {
+			exitCode = 1
+		}
+	}
+
+	if *verify || *verifyOnly {
+		checks := seeder.Verify()
+		seeder.PrintChecks(checks)
+		for _, c := range checks {
+			if !c.OK() {
+				exitCode = 1
+			}
+		}
+	}
+	return exitCode
+}
+
+// ===============================================
+//   rs simulate
+// ===============================================
+//
+// Contoh:
+//   rs simulate                                        # real-time, sampai Ctrl+C
+//   rs simulate --speed 1440 --duration 10m            # 1 hari virtual per menit
+//   rs simulate --start 2025-01-01T00:00:00Z --until 2025-07-01T00:00:00Z --speed 0
+//   rs simulate --dry-run --speed 0 --until 2025-01-02T00:00:00Z --start 2025-01-01T00:00:00Z
+
+func simulateMain(args []string) int {
+	fs := flag.NewFlagSet("rs simulate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs simulate [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, false)
+	cfg, err := simulator.ParseFlags(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err == nil {
+		err = opts.resolve(fs)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := simulator.Run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	report.Print()
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
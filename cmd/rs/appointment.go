@@ -0,0 +1,380 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"src/booking"
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs appointment ...
+// ===============================================
+//
+// Contoh:
+//   rs appointment set-schedule --departemen Kardiologi --hari senin-jumat --jam 08:00-16:00 --slot 30m
+//   rs appointment set-schedule --dokter tm1@rs.com --hari sabtu,minggu --libur
+//   rs appointment slots --dokter tm1@rs.com --dari 2025-01-06 --hari 3
+//   rs appointment book --pasien pasien1@mail.com --dokter tm1@rs.com --waktu "2025-01-06 09:00"
+//   rs appointment reschedule --id JT-K7Q2M9XA --waktu "2025-01-07 10:30"
+//   rs appointment cancel --id JT-K7Q2M9XA
+
+var appointmentGroup = &group{
+	Name:    "appointment",
+	Summary: "jadwal praktik dokter dan booking janji temu tanpa bentrok",
+	Commands: []*command{
+		{Name: "set-schedule", Summary: "atur jam praktik --dokter, --departemen atau --rs per --hari", Stores: useNeo4j, Setup: appointmentSetSchedule},
+		{Name: "schedule", Summary: "jadwal praktik yang berlaku untuk --dokter", Stores: useNeo4j, Setup: appointmentSchedule},
+		{Name: "slots", Summary: "slot kosong --dokter selama --hari sejak --dari", Stores: useNeo4j, Setup: appointmentSlots},
+		{Name: "book", Summary: "pesan janji temu --pasien dengan --dokter pada --waktu", Stores: useNeo4j, Setup: appointmentBook},
+		{Name: "reschedule", Summary: "pindahkan janji temu --id ke --waktu", Stores: useNeo4j, Setup: appointmentReschedule},
+		{Name: "cancel", Summary: "batalkan janji temu --id", Stores: useNeo4j, Setup: appointmentCancel},
+	},
+}
+
+// bookingResult menampilkan janji temu hasil pesan/jadwal ulang/batal.
+func bookingResult(title string, b *queries.Booking, notes ...string) render.Result {
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "id_janji_temu", Header: "ID Janji Temu"},
+			{Key: "waktu_pelaksanaan", Header: "Waktu"},
+			{Key: "durasi_menit", Header: "Durasi (menit)"},
+			{Key: "status", Header: "Status"},
+			{Key: "email_pasien", Header: "Pasien", Width: 30},
+			{Key: "email_dokter", Header: "Dokter", Width: 30},
+			{Key: "id_rs", Header: "ID RS"},
+		},
+		Notes: notes,
+	}
+	res.Add(b.IDJanjiTemu, b.WaktuPelaksanaan, b.DurasiMenit, b.Status, b.EmailPasien, b.EmailDokter, b.IdRS)
+	return res
+}
+
+// waktuFlag adalah flag.Value untuk waktu janji temu (booking.ParseWaktu).
+type waktuFlag struct{ t time.Time }
+
+func (w *waktuFlag) String() string {
+	if w.t.IsZero() {
+		return ""
+	}
+	return w.t.Format("2006-01-02 15:04")
+}
+
+func (w *waktuFlag) Set(s string) (err error) {
+	w.t, err = booking.ParseWaktu(s)
+	return err
+}
+
+func required(name, v string) error {
+	if strings.TrimSpace(v) == "" {
+		return fmt.Errorf("--%s wajib diisi", name)
+	}
+	return nil
+}
+
+// requiredEmail memvalidasi flag email wajib sebelum koneksi dibuka.
+func requiredEmail(name, v string) error {
+	if err := required(name, v); err != nil {
+		return err
+	}
+	return queries.ValidEmail(v)
+}
+
+// ===============================================
+//   JADWAL PRAKTIK
+// ===============================================
+
+func appointmentSetSchedule(fs *flag.FlagSet) action {
+	dokter := fs.String("dokter", "", "email tenaga medis (jadwal khusus dokter)")
+	departemen := fs.String("departemen", "", "nama departemen (berlaku untuk semua dokternya)")
+	rs := fs.String("rs", "", "ID rumah sakit (berlaku untuk dokter yang departemennya tidak punya jadwal)")
+	hariText := fs.String("hari", "senin-jumat", "hari praktik, mis. senin-jumat atau sabtu,minggu")
+	jam := fs.String("jam", "08:00-16:00", "jam praktik HH:MM-HH:MM")
+	slot := fs.Duration("slot", 30*time.Minute, "durasi satu slot janji temu")
+	libur := fs.Bool("libur", false, "hapus jadwal pada --hari (tidak praktik)")
+
+	var pemilik, kunci string
+	var hari []time.Weekday
+	var jadwal booking.Jadwal
+	return action{
+		Check: func() error {
+			n := 0
+			for _, o := range []struct{ pemilik, kunci string }{
+				{queries.PemilikDokter, *dokter},
+				{queries.PemilikDepartemen, *departemen},
+				{queries.PemilikRumahSakit, *rs},
+			} {
+				if strings.TrimSpace(o.kunci) != "" {
+					pemilik, kunci = o.pemilik, strings.TrimSpace(o.kunci)
+					n++
+				}
+			}
+			if n != 1 {
+				return fmt.Errorf("isi tepat satu dari --dokter, --departemen atau --rs")
+			}
+			var err error
+			if hari, err = booking.ParseHari(*hariText); err != nil {
+				return err
+			}
+			if *libur {
+				return nil
+			}
+			mulai, selesai, err := booking.ParseRentangJam(*jam)
+			if err != nil {
+				return err
+			}
+			jadwal = booking.Jadwal{Mulai: mulai, Selesai: selesai, Slot: *slot}
+			return jadwal.Validate()
+		},
+		Run: func(e *env) error {
+			res := render.Result{
+				Title: "APPOINTMENT: Jadwal Praktik " + strings.ReplaceAll(pemilik, "_", " ") + " " + kunci,
+				Columns: []render.Column{
+					{Key: "hari", Header: "Hari"},
+					{Key: "jam", Header: "Jam Praktik"},
+					{Key: "slot", Header: "Slot"},
+				},
+			}
+			if *libur {
+				var n int
+				err := e.measure(func() (err error) {
+					n, err = booking.HapusJadwal(pemilik, kunci, hari)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				for _, d := range hari {
+					res.Add(booking.NamaHari(d), "libur", "-")
+				}
+				res.Notes = []string{fmt.Sprintf("✓ %d jadwal praktik dihapus.", n)}
+				return e.render(res)
+			}
+
+			var n int
+			err := e.measure(func() (err error) {
+				n, err = booking.SimpanJadwal(pemilik, kunci, hari, jadwal)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			for _, d := range hari {
+				res.Add(booking.NamaHari(d), fmt.Sprintf("%s-%s", jadwal.Mulai, jadwal.Selesai), jadwal.SlotText())
+			}
+			res.Notes = []string{fmt.Sprintf("✓ %d hari jadwal praktik disimpan; jadwal lama pada hari tersebut diganti.", n)}
+			return e.render(res)
+		},
+	}
+}
+
+func appointmentSchedule(fs *flag.FlagSet) action {
+	dokter := fs.String("dokter", "", "email tenaga medis (wajib)")
+	return action{
+		Check: func() error { return requiredEmail("dokter", *dokter) },
+		Run: func(e *env) error {
+			var d *booking.Dokter
+			err := e.measure(func() (err error) {
+				d, err = booking.JadwalDokter(*dokter)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: "APPOINTMENT: Jadwal Praktik " + d.Email,
+				Columns: []render.Column{
+					{Key: "hari", Header: "Hari"},
+					{Key: "jam_mulai", Header: "Mulai"},
+					{Key: "jam_selesai", Header: "Selesai"},
+					{Key: "slot", Header: "Slot"},
+				},
+				Empty: "Belum ada jadwal praktik untuk dokter, departemen maupun rumah sakitnya.",
+				Notes: []string{
+					fmt.Sprintf("Dokter: %s, departemen: %s, rumah sakit: %s", d.Nama, orDash(d.Departemen), orDash(d.IdRS)),
+				},
+			}
+			if d.Sumber != "" {
+				res.Notes = append(res.Notes, "Sumber jadwal: "+strings.ReplaceAll(d.Sumber, "_", " "))
+			}
+			for _, j := range d.Jadwal {
+				res.Add(booking.NamaHari(j.Hari), j.Mulai.String(), j.Selesai.String(), j.SlotText())
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ===============================================
+//   SLOT & BOOKING
+// ===============================================
+
+func appointmentSlots(fs *flag.FlagSet) action {
+	dokter := fs.String("dokter", "", "email tenaga medis (wajib)")
+	dari := fs.String("dari", "", "tanggal awal YYYY-MM-DD (default: hari ini)")
+	hari := fs.Int("hari", 7, "jumlah hari yang ditampilkan (1-62)")
+	var start time.Time
+	return action{
+		Check: func() error {
+			if err := requiredEmail("dokter", *dokter); err != nil {
+				return err
+			}
+			start = time.Now()
+			if *dari != "" {
+				t, err := time.ParseInLocation("2006-01-02", *dari, time.Local)
+				if err != nil {
+					return fmt.Errorf("--dari %q harus berformat YYYY-MM-DD", *dari)
+				}
+				start = t
+			}
+			if *hari < 1 || *hari > 62 {
+				return fmt.Errorf("--hari harus 1-62, bukan %d", *hari)
+			}
+			return nil
+		},
+		Run: func(e *env) error {
+			var d *booking.Dokter
+			var slots []booking.Slot
+			err := e.measure(func() (err error) {
+				d, slots, err = booking.SlotKosong(*dokter, start, *hari, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: fmt.Sprintf("APPOINTMENT: Slot Kosong %s (%d hari sejak %s)", d.Email, *hari, start.Format("2006-01-02")),
+				Columns: []render.Column{
+					{Key: "tanggal", Header: "Tanggal"},
+					{Key: "hari", Header: "Hari"},
+					{Key: "mulai", Header: "Mulai"},
+					{Key: "selesai", Header: "Selesai"},
+				},
+				Empty: "Tidak ada slot kosong.",
+			}
+			if d.Sumber == "" {
+				res.Empty = "Dokter belum punya jadwal praktik (atur dengan rs appointment set-schedule)."
+			}
+			for _, s := range slots {
+				res.Add(s.Mulai.Format("2006-01-02"), booking.NamaHari(s.Mulai.Weekday()), s.Mulai.Format("15:04"), s.Selesai.Format("15:04"))
+			}
+			if len(slots) > 0 {
+				res.Notes = []string{fmt.Sprintf("%d slot kosong. Pesan dengan: rs appointment book --dokter %s --pasien EMAIL --waktu \"%s\"",
+					len(slots), d.Email, slots[0].Mulai.Format("2006-01-02 15:04"))}
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func appointmentBook(fs *flag.FlagSet) action {
+	var p booking.Permintaan
+	fs.StringVar(&p.EmailPasien, "pasien", "", "email pasien (wajib)")
+	fs.StringVar(&p.EmailDokter, "dokter", "", "email tenaga medis (wajib)")
+	waktu := &waktuFlag{}
+	fs.Var(waktu, "waktu", "awal slot, mis. \"2025-01-06 09:00\" (wajib)")
+	fs.StringVar(&p.Alasan, "alasan", "", "keluhan atau alasan janji temu")
+	return action{
+		Check: func() error {
+			if err := requiredEmail("pasien", p.EmailPasien); err != nil {
+				return err
+			}
+			if err := requiredEmail("dokter", p.EmailDokter); err != nil {
+				return err
+			}
+			p.Waktu = waktu.t
+			return required("waktu", waktu.String())
+		},
+		Run: func(e *env) error {
+			var b *queries.Booking
+			err := e.measure(func() (err error) {
+				b, err = booking.Pesan(p, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return e.render(bookingResult("APPOINTMENT: Janji Temu Dipesan", b, "✓ Janji temu berhasil dipesan."))
+		},
+	}
+}
+
+func appointmentReschedule(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID janji temu (wajib)")
+	waktu := &waktuFlag{}
+	fs.Var(waktu, "waktu", "awal slot baru, mis. \"2025-01-07 10:30\" (wajib)")
+	return action{
+		Check: func() error {
+			if err := required("id", *id); err != nil {
+				return err
+			}
+			return required("waktu", waktu.String())
+		},
+		Run: func(e *env) error {
+			var b *queries.Booking
+			err := e.measure(func() (err error) {
+				b, err = booking.JadwalUlang(*id, waktu.t, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return e.render(bookingResult("APPOINTMENT: Janji Temu Dijadwalkan Ulang", b, "✓ Janji temu berhasil dipindahkan."))
+		},
+	}
+}
+
+func appointmentCancel(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID janji temu (wajib)")
+	g := bindGuard(fs)
+	return action{
+		Check: func() error {
+			if err := required("id", *id); err != nil {
+				return err
+			}
+			return g.check()
+		},
+		Run: func(e *env) error {
+			before, err := queries.BookingJanjiTemu(*id)
+			if err != nil {
+				return err
+			}
+			if before == nil {
+				return fmt.Errorf("janji temu %s tidak ditemukan", *id)
+			}
+
+			p := plan{
+				Title:   "APPOINTMENT: Batalkan Janji Temu",
+				Action:  "janji temu akan diubah menjadi 'dibatalkan'",
+				Preview: bookingResult("", before),
+			}
+			if ok, err := g.approve(e, p); !ok || err != nil {
+				return err
+			}
+
+			var b *queries.Booking
+			err = e.measure(func() (err error) {
+				b, err = booking.Batal(*id)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return e.render(bookingResult(p.Title, b, fmt.Sprintf("✓ Status %s → %s; slotnya bisa dipesan lagi.", before.Status, b.Status)))
+		},
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"src/arsip"
+	"src/render"
+)
+
+// ===============================================
+//   rs archive ...
+// ===============================================
+//
+// Contoh:
+//   rs archive list --entity janji_temu --limit 0
+//   rs archive show --id ARS-K7Q2M9XA
+//   rs archive restore --id ARS-K7Q2M9XA,ARS-P3LW8D2Q
+//   rs archive restore --entity pemesanan_obat --key POB00001 --yes
+//
+// Arsip ditulis oleh rs delete cancelled-orders, rs delete
+// stale-appointments dan aksi archive rs retention ke --dir (env RS_ARSIP
+// atau data/arsip). Restore membuat ulang entitas di store asalnya dan
+// melewati yang sudah ada.
+
+var archiveGroup = &group{
+	Name:    "archive",
+	Summary: "lihat dan pulihkan data yang diarsipkan sebelum dihapus",
+	Commands: []*command{
+		{Name: "entities", Summary: "entitas yang bisa diarsipkan dan isi salinannya", Setup: static(archiveEntities)},
+		{Name: "list", Summary: "entri arsip terbaru (default 50), bisa difilter --entity, --key atau --id", Setup: archiveList},
+		{Name: "show", Summary: "isi lengkap entri arsip --id", Setup: archiveShow},
+		{Name: "restore", Summary: "buat ulang entitas dari arsip (--id, atau --entity dengan --key)", Stores: useBoth, Setup: archiveRestore},
+	},
+}
+
+// archiver mendaftarkan flag arsip untuk perintah delete.
+type archiver struct {
+	dir string
+	off bool
+}
+
+func bindArchive(fs *flag.FlagSet) *archiver {
+	a := &archiver{}
+	fs.StringVar(&a.dir, "archive-dir", arsip.Dir(), "direktori arsip sebelum hapus (env RS_ARSIP)")
+	fs.BoolVar(&a.off, "no-archive", false, "hapus tanpa menyimpan salinan ke arsip")
+	return a
+}
+
+// save menyalin record ke arsip sebelum dihapus. Kegagalan berarti tidak
+// ada yang boleh dihapus.
+func (a *archiver) save(entitas, sumber string, recs []map[string]interface{}) (int, error) {
+	if a.off {
+		return 0, nil
+	}
+	entri, err := arsip.Salin(entitas, sumber, recs, time.Now())
+	if err == nil {
+		err = arsip.Berkas{Dir: a.dir}.Simpan(entri)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("gagal mengarsipkan %s, tidak ada yang dihapus: %v (pakai --no-archive untuk hapus tanpa arsip)", entitas, err)
+	}
+	return len(entri), nil
+}
+
+// note menjelaskan hasil arsip untuk laporan delete.
+func (a *archiver) note(entitas string, n int) string {
+	if a.off {
+		return "Tanpa arsip (--no-archive): data yang dihapus tidak bisa dipulihkan."
+	}
+	return fmt.Sprintf("%d salinan diarsipkan ke %s; pulihkan dengan: rs archive restore --entity %s --key <kunci>", n, a.dir, entitas)
+}
+
+func archiveEntities(e *env) error {
+	res := render.Result{
+		Title: "ARCHIVE: Entitas",
+		Columns: []render.Column{
+			{Key: "entitas", Header: "Entitas"},
+			{Key: "store", Header: "Store"},
+			{Key: "isi", Header: "Isi Salinan", Width: 70},
+		},
+	}
+	for _, j := range arsip.Daftar {
+		res.Add(j.Nama, j.Store, j.Isi)
+	}
+	return e.render(res)
+}
+
+// archiveFilter mendaftarkan --dir, --entity, --key dan --id.
+func archiveFilter(fs *flag.FlagSet) (*string, *arsip.Filter) {
+	dir := fs.String("dir", arsip.Dir(), "direktori arsip (env RS_ARSIP)")
+	f := &arsip.Filter{}
+	fs.StringVar(&f.Entitas, "entity", "", "hanya entitas ini (lihat rs archive entities)")
+	fs.Func("key", "kunci entitas dipisah koma, mis. id_pesanan atau id_janji_temu", splitList(&f.Kunci))
+	fs.Func("id", "id entri arsip dipisah koma", splitList(&f.ID))
+	return dir, f
+}
+
+func checkFilter(f *arsip.Filter) error {
+	if f.Entitas != "" {
+		if _, err := arsip.Cari(f.Entitas); err != nil {
+			return err
+		}
+	}
+	if len(f.Kunci) > 0 && f.Entitas == "" {
+		return errors.New("--key membutuhkan --entity")
+	}
+	return nil
+}
+
+func archiveList(fs *flag.FlagSet) action {
+	dir, f := archiveFilter(fs)
+	return action{
+		Check: func() error { return checkFilter(f) },
+		Run: func(e *env) error {
+			var entri []arsip.Entri
+			err := e.measure(func() (err error) {
+				entri, err = arsip.Berkas{Dir: *dir}.Baca(*f)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := archiveTable("ARCHIVE: Entri Arsip", entri)
+			res.Limit = 50
+			res.Empty = "Tidak ada entri arsip di " + *dir + "."
+			return e.render(res)
+		},
+	}
+}
+
+func archiveTable(title string, entri []arsip.Entri) render.Result {
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "id", Header: "ID Arsip"},
+			{Key: "entitas", Header: "Entitas"},
+			{Key: "kunci", Header: "Kunci", Width: 30},
+			{Key: "sumber", Header: "Sumber", Width: 30},
+			{Key: "diarsipkan", Header: "Diarsipkan"},
+			{Key: "isi", Header: "Isi", Width: 45},
+		},
+	}
+	for _, a := range entri {
+		res.Add(a.ID, a.Entitas, a.Kunci, a.Sumber, a.Diarsipkan.Local().Format("2006-01-02 15:04:05"), a.Isi())
+	}
+	return res
+}
+
+func archiveShow(fs *flag.FlagSet) action {
+	dir := fs.String("dir", arsip.Dir(), "direktori arsip (env RS_ARSIP)")
+	id := fs.String("id", "", "id entri arsip (wajib)")
+	return action{
+		Check: func() error { return required("id", *id) },
+		Run: func(e *env) error {
+			entri, err := arsip.Berkas{Dir: *dir}.Baca(arsip.Filter{ID: []string{*id}})
+			if err != nil {
+				return err
+			}
+			if len(entri) == 0 {
+				return fmt.Errorf("entri arsip %s tidak ada di %s", *id, *dir)
+			}
+			a := entri[0]
+			res := render.Result{
+				Title: "ARCHIVE: " + a.ID,
+				Columns: []render.Column{
+					{Key: "bagian", Header: "Bagian"},
+					{Key: "kolom", Header: "Kolom", Width: 30},
+					{Key: "nilai", Header: "Nilai", Width: 60},
+				},
+				Notes: []string{fmt.Sprintf("%s %s, dihapus oleh %s pada %s.", a.Entitas, a.Kunci, a.Sumber,
+					a.Diarsipkan.Local().Format("2006-01-02 15:04:05"))},
+			}
+			tables := make([]string, 0, len(a.Baris))
+			for t := range a.Baris {
+				tables = append(tables, t)
+			}
+			sort.Strings(tables)
+			for _, t := range tables {
+				for _, row := range a.Baris[t] {
+					addProps(&res, t, row)
+				}
+			}
+			if n := a.Node; n != nil {
+				res.Add("node", "label", strings.Join(n.Label, ":"))
+				addProps(&res, "node", n.Properti)
+				for _, r := range n.Relasi {
+					arah := fmt.Sprintf("-[:%s]->", r.Tipe)
+					if !r.Keluar {
+						arah = fmt.Sprintf("<-[:%s]-", r.Tipe)
+					}
+					tetangga := fmt.Sprintf("(:%s {%s: %v})", r.Label, orDash(r.Kunci), r.Nilai)
+					if len(r.Properti) > 0 {
+						tetangga += fmt.Sprintf(" %v", r.Properti)
+					}
+					res.Add("relasi", arah, tetangga)
+				}
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func addProps(res *render.Result, bagian string, props map[string]interface{}) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		res.Add(bagian, k, fmt.Sprint(props[k]))
+	}
+}
+
+func archiveRestore(fs *flag.FlagSet) action {
+	dir, f := archiveFilter(fs)
+	g := bindGuard(fs)
+	return action{
+		Check: func() error {
+			if err := g.check(); err != nil {
+				return err
+			}
+			if len(f.ID) == 0 && f.Entitas == "" {
+				return errors.New("pilih arsip dengan --id atau --entity (dengan --key)")
+			}
+			return checkFilter(f)
+		},
+		Run: func(e *env) error {
+			entri, err := arsip.Berkas{Dir: *dir}.Baca(*f)
+			if err != nil {
+				return err
+			}
+			// Kunci yang diarsipkan berkali-kali dipulihkan dari salinan terbaru.
+			if len(f.ID) == 0 {
+				entri = arsip.Terbaru(entri)
+			}
+
+			const title = "ARCHIVE: Pulihkan"
+			p := plan{
+				Title:   title,
+				Action:  "entitas akan dipulihkan dari arsip",
+				Preview: archiveTable("", entri),
+				Notes:   []string{"Entitas yang sudah ada di database dilewati, tidak ditimpa."},
+			}
+			if len(entri) == 0 {
+				return fmt.Errorf("tidak ada entri arsip yang cocok di %s", *dir)
+			}
+			if ok, err := g.approve(e, p); !ok || err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: title,
+				Columns: []render.Column{
+					{Key: "id", Header: "ID Arsip"},
+					{Key: "entitas", Header: "Entitas"},
+					{Key: "kunci", Header: "Kunci", Width: 30},
+					{Key: "status", Header: "Status", Width: 40},
+					{Key: "catatan", Header: "Catatan", Width: 45},
+				},
+			}
+			dipulihkan, gagal := 0, 0
+			e.measure(func() error {
+				for _, a := range entri {
+					ok, catatan, err := arsip.Pulihkan(a)
+					status := "dipulihkan"
+					switch {
+					case err != nil:
+						status = "gagal: " + err.Error()
+						gagal++
+					case !ok:
+						status = "dilewati: sudah ada"
+						catatan = ""
+					default:
+						dipulihkan++
+					}
+					res.Add(a.ID, a.Entitas, a.Kunci, status, orDash(catatan))
+				}
+				return nil
+			})
+			res.Notes = []string{fmt.Sprintf("%d dari %d entitas dipulihkan.", dipulihkan, len(entri))}
+			if err := e.render(res); err != nil {
+				return err
+			}
+			if gagal > 0 {
+				return fmt.Errorf("%d entitas gagal dipulihkan", gagal)
+			}
+			return nil
+		},
+	}
+}
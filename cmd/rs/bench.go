@@ -0,0 +1,363 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"src/bench"
+	"src/queries"
+	"src/render"
+	"src/seeder"
+)
+
+// ===============================================
+//   rs bench
+// ===============================================
+//
+// Menjalankan setiap query read N kali (setelah warm-up) di Cassandra/Neo4j
+// dan statement padanannya dari queries/sql_equivalents.sql di SQLite yang
+// dimuat dengan dataset seeder yang sama (--profile/--seed/--now harus sama
+// dengan saat rs seed).
+//
+// Contoh:
+//   rs bench --seed 42 --now 2025-01-01T00:00:00Z --profile tiny
+//   rs bench --seed 42 --n 100 --queries specialists,top-hospitals --output markdown --out bench.md
+//   rs bench --engines sqlite --profile large --seed 1 --sqlite bench.db
+//   rs bench --engines sqlite --sqlite bench.db --skip-load --output json
+
+const (
+	engineNative = "native"
+	engineSQLite = "sqlite"
+)
+
+type benchFlags struct {
+	cfg      bench.Config
+	queries  []string
+	engines  []string
+	profile  string
+	seed     int64
+	now      time.Time
+	sqlite   string
+	skipLoad bool
+}
+
+func (b *benchFlags) has(engine string) bool {
+	for _, e := range b.engines {
+		if e == engine {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *benchFlags) validate() error {
+	if err := atLeast("n", b.cfg.Iterations, 1); err != nil {
+		return err
+	}
+	if err := atLeast("warmup", b.cfg.Warmup, 0); err != nil {
+		return err
+	}
+	if len(b.engines) == 0 {
+		return fmt.Errorf("--engines tidak boleh kosong")
+	}
+	for _, e := range b.engines {
+		if e != engineNative && e != engineSQLite {
+			return fmt.Errorf("engine %q tidak dikenal (pilihan: %s, %s)", e, engineNative, engineSQLite)
+		}
+	}
+	known := bench.CaseNames()
+	for _, q := range b.queries {
+		if !contains(known, q) {
+			return fmt.Errorf("query %q tidak dikenal (pilihan: %s)", q, strings.Join(known, ", "))
+		}
+	}
+	if b.has(engineSQLite) && !b.skipLoad {
+		if _, ok := seeder.Profiles[b.profile]; !ok {
+			return fmt.Errorf("profil %q tidak dikenal (pilihan: %s)", b.profile, strings.Join(seeder.ProfileNames(), ", "))
+		}
+		// Tanpa seed, dataset SQLite pasti berbeda dengan isi Cassandra/Neo4j
+		if b.seed == 0 && b.has(engineNative) {
+			return fmt.Errorf("--seed wajib diisi (samakan dengan rs seed) agar SQLite berisi dataset yang sama")
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(dst *[]string) func(string) error {
+	return func(s string) error {
+		*dst = nil
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				*dst = append(*dst, part)
+			}
+		}
+		return nil
+	}
+}
+
+func benchMain(args []string) int {
+	fs := flag.NewFlagSet("rs bench", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs bench [flags]\n\nQuery: %s\n\nFlags:\n", strings.Join(bench.CaseNames(), ", "))
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, true)
+	e := &env{opts: opts, out: os.Stdout}
+	e.view.Bind(fs)
+
+	b := &benchFlags{engines: []string{engineNative, engineSQLite}}
+	fs.IntVar(&b.cfg.Iterations, "n", 20, "jumlah iterasi yang diukur per query")
+	fs.IntVar(&b.cfg.Warmup, "warmup", 3, "jumlah iterasi pemanasan (tidak diukur)")
+	fs.Func("queries", "query yang dijalankan, dipisah koma (default: semua)", splitList(&b.queries))
+	fs.Func("engines", "engine yang diukur: native,sqlite (default: keduanya)", splitList(&b.engines))
+	fs.StringVar(&b.profile, "profile", "default", "profil dataset seeder untuk SQLite: "+strings.Join(seeder.ProfileNames(), ", "))
+	fs.Int64Var(&b.seed, "seed", 0, "seed dataset (sama dengan rs seed --seed)")
+	fs.Func("now", "waktu acuan dataset RFC3339 (sama dengan rs seed --now; default: 2025-01-01T00:00:00Z bila --seed diisi)", func(s string) error {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("format waktu harus RFC3339, mis. 2025-01-01T00:00:00Z")
+		}
+		b.now = t
+		return nil
+	})
+	fs.StringVar(&b.sqlite, "sqlite", ":memory:", "file database SQLite (:memory: = in-memory)")
+	fs.BoolVar(&b.skipLoad, "skip-load", false, "pakai isi --sqlite yang sudah ada tanpa memuat ulang dataset")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: argumen tidak dikenal: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	err := opts.resolve(fs)
+	if err == nil {
+		err = e.view.Validate()
+	}
+	if err == nil {
+		err = b.validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	closeOut, err := e.openOut()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeOut()
+
+	if err := runBench(e, b); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+func runBench(e *env, b *benchFlags) error {
+	cases := bench.Cases
+	if len(b.queries) > 0 {
+		cases = nil
+		for _, c := range bench.Cases {
+			if contains(b.queries, c.Name) {
+				cases = append(cases, c)
+			}
+		}
+	}
+
+	var notes []string
+	var runSQL func(statement string) func() error
+	if b.has(engineSQLite) {
+		db, err := bench.OpenSQLite(b.sqlite)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		if b.skipLoad && bench.Loaded(db) {
+			notes = append(notes, "SQLite: memakai isi "+b.sqlite+" yang sudah ada (--skip-load)")
+		} else {
+			note, err := loadSQLite(e, b, db)
+			if err != nil {
+				return err
+			}
+			notes = append(notes, note)
+		}
+		runSQL = func(statement string) func() error { return bench.SQLQuery(db, statement) }
+	}
+
+	if b.has(engineNative) {
+		var need stores
+		for _, c := range cases {
+			if c.Cassandra {
+				need |= useCassandra
+			}
+			if c.Neo4j {
+				need |= useNeo4j
+			}
+		}
+		closeStores, err := e.opts.connect(need)
+		if err != nil {
+			return err
+		}
+		defer closeStores()
+	}
+
+	statements := queries.SQLEquivalents()
+	var results []bench.Stats
+	for _, c := range cases {
+		if b.has(engineNative) {
+			e.opts.logf("Benchmark %s (native)...\n", c.Name)
+			results = append(results, bench.Measure(c.Name, engineNative, b.cfg, c.Native))
+		}
+		if b.has(engineSQLite) {
+			e.opts.logf("Benchmark %s (sqlite)...\n", c.Name)
+			statement, ok := statements[c.Legacy]
+			if !ok {
+				results = append(results, bench.Stats{Query: c.Name, Engine: engineSQLite,
+					Err: fmt.Errorf("statement %s tidak ada di sql_equivalents.sql", c.Legacy)})
+				continue
+			}
+			results = append(results, bench.Measure(c.Name, engineSQLite, b.cfg, runSQL(statement)))
+		}
+	}
+
+	return e.render(benchReport(b, cases, results, notes))
+}
+
+// loadSQLite membangkitkan ulang dataset seeder lalu memuatnya ke SQLite.
+func loadSQLite(e *env, b *benchFlags, db *sql.DB) (string, error) {
+	cfg := seeder.Profiles[b.profile]
+	cfg.Seed, cfg.Now = b.seed, b.now
+	// Default yang sama dengan rs seed, jadi --seed saja sudah cukup untuk
+	// membandingkan dua run.
+	cfg.ResolveSeed()
+
+	e.opts.logf("Memuat dataset (profil %s, seed %d, now %s) ke SQLite %s...\n",
+		b.profile, cfg.Seed, cfg.Now.Format(time.RFC3339), b.sqlite)
+	start := time.Now()
+	counts, err := bench.LoadSQLite(db, seeder.Generate(cfg))
+	if err != nil {
+		return "", err
+	}
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return fmt.Sprintf("SQLite %s: %d baris dari profil %s, seed %d, now %s (dimuat dalam %.1fs)",
+		b.sqlite, total, b.profile, cfg.Seed, cfg.Now.Format(time.RFC3339), time.Since(start).Seconds()), nil
+}
+
+// ===============================================
+//   LAPORAN
+// ===============================================
+
+func millis(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+
+func formatFloat(layout string) func(interface{}) string {
+	return func(v interface{}) string {
+		f, ok := v.(float64)
+		if !ok {
+			return "-"
+		}
+		return fmt.Sprintf(layout, f)
+	}
+}
+
+func benchReport(b *benchFlags, cases []bench.Case, results []bench.Stats, notes []string) render.Result {
+	ms := formatFloat("%.2f")
+	res := render.Result{
+		Title: fmt.Sprintf("BENCHMARK: native vs SQLite (n=%d, warmup=%d)", b.cfg.Iterations, b.cfg.Warmup),
+		Columns: []render.Column{
+			{Key: "query", Header: "Query", Width: 30},
+			{Key: "legacy", Header: "Query Lama"},
+			{Key: "engine", Header: "Engine"},
+			{Key: "n", Header: "N"},
+			{Key: "errors", Header: "Error"},
+			{Key: "min_ms", Header: "Min (ms)", Format: ms},
+			{Key: "p50_ms", Header: "p50 (ms)", Format: ms},
+			{Key: "p95_ms", Header: "p95 (ms)", Format: ms},
+			{Key: "p99_ms", Header: "p99 (ms)", Format: ms},
+			{Key: "max_ms", Header: "Max (ms)", Format: ms},
+			{Key: "ops_per_sec", Header: "Ops/detik", Format: formatFloat("%.1f")},
+			{Key: "p50_vs_sqlite", Header: "p50 / SQLite", Format: formatFloat("%.2fx")},
+		},
+		Empty: "Tidak ada query yang dijalankan.",
+	}
+
+	legacy := map[string]string{}
+	for _, c := range cases {
+		legacy[c.Name] = c.Legacy
+	}
+	sqliteP50 := map[string]time.Duration{}
+	for _, s := range results {
+		if s.Engine == engineSQLite && s.OK() {
+			sqliteP50[s.Query] = s.P50
+		}
+	}
+
+	var comparisons, failures []string
+	for _, s := range results {
+		if !s.OK() {
+			res.Add(s.Query, legacy[s.Query], s.Engine, s.N, s.Errors, nil, nil, nil, nil, nil, nil, nil)
+			failures = append(failures, fmt.Sprintf("  %s (%s): %v", s.Query, s.Engine, s.Err))
+			continue
+		}
+
+		var ratio interface{}
+		if base, ok := sqliteP50[s.Query]; ok && base > 0 {
+			r := float64(s.P50) / float64(base)
+			ratio = r
+			if s.Engine == engineNative {
+				comparisons = append(comparisons, compareLine(s.Query, r))
+			}
+		}
+		res.Add(s.Query, legacy[s.Query], s.Engine, s.N, s.Errors,
+			millis(s.Min), millis(s.P50), millis(s.P95), millis(s.P99), millis(s.Max), s.Throughput, ratio)
+		if s.Errors > 0 {
+			failures = append(failures, fmt.Sprintf("  %s (%s): %d iterasi gagal, pertama: %v", s.Query, s.Engine, s.Errors, s.Err))
+		}
+	}
+
+	res.Notes = append(res.Notes, notes...)
+	if len(comparisons) > 0 {
+		res.Notes = append(res.Notes, "", "Perbandingan p50 (native vs SQLite):")
+		res.Notes = append(res.Notes, comparisons...)
+	}
+	if len(failures) > 0 {
+		res.Notes = append(res.Notes, "", "⚠️  Query gagal:")
+		res.Notes = append(res.Notes, failures...)
+	}
+	return res
+}
+
+func compareLine(query string, ratio float64) string {
+	switch {
+	case ratio < 1:
+		return fmt.Sprintf("  %-30s native %.1fx lebih cepat", query, 1/ratio)
+	case ratio > 1:
+		return fmt.Sprintf("  %-30s SQLite %.1fx lebih cepat", query, ratio)
+	default:
+		return fmt.Sprintf("  %-30s sama cepat", query)
+	}
+}
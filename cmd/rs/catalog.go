@@ -0,0 +1,237 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs catalog ...
+// ===============================================
+//
+// Contoh:
+//   rs catalog list --store neo4j
+//   rs catalog show --name obat.stok_menipis      # siap di-copy ke cqlsh
+//   rs catalog check                              # cek semua entri ke database
+//   rs catalog check --name janji_temu            # hanya janji_temu.*
+
+var catalogGroup = &group{
+	Name:    "catalog",
+	Summary: "katalog query .cql/.cypher yang dijalankan perintah rs",
+	Commands: []*command{
+		{Name: "list", Summary: "daftar entri katalog beserta param dan kolomnya", Setup: catalogList},
+		{Name: "show", Summary: "cetak statement --name dengan contoh nilai param", Raw: true, Setup: catalogShow},
+		{Name: "check", Summary: "jalankan setiap entri (tanpa mengubah data) dan cocokkan kolomnya", Stores: useBoth, Setup: catalogCheck},
+	},
+}
+
+// catalogFilter memilih entri berdasarkan --store dan --name.
+type catalogFilter struct {
+	store string
+	names []string
+}
+
+func bindCatalogFilter(fs *flag.FlagSet) *catalogFilter {
+	f := &catalogFilter{}
+	fs.StringVar(&f.store, "store", "", "hanya entri store ini: cassandra atau neo4j (default semua)")
+	fs.Func("name", "nama entri atau awalan sebelum titik, dipisah koma (mis. obat,janji_temu.hapus)", splitList(&f.names))
+	return f
+}
+
+func (f *catalogFilter) check() error {
+	if f.store != "" && f.store != queries.StoreCassandra && f.store != queries.StoreNeo4j {
+		return fmt.Errorf("--store harus cassandra atau neo4j, bukan %q", f.store)
+	}
+	return nil
+}
+
+func (f *catalogFilter) match(s queries.Statement) bool {
+	if f.store != "" && s.Store != f.store {
+		return false
+	}
+	if len(f.names) == 0 {
+		return true
+	}
+	for _, n := range f.names {
+		if s.Name == n || strings.HasPrefix(s.Name, n+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// selected memuat katalog dan mengembalikan entri yang cocok. --name yang
+// tidak cocok dengan entri manapun dianggap salah ketik.
+func (f *catalogFilter) selected() ([]queries.Statement, error) {
+	all, err := queries.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat katalog: %v", err)
+	}
+	for _, n := range f.names {
+		found := false
+		for _, s := range all {
+			if s.Name == n || strings.HasPrefix(s.Name, n+".") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("tidak ada entri katalog %q", n)
+		}
+	}
+
+	var out []queries.Statement
+	for _, s := range all {
+		if f.match(s) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func mode(s queries.Statement) string {
+	if s.Write() {
+		return "write"
+	}
+	return "read"
+}
+
+func paramNames(s queries.Statement) string {
+	names := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// ===============================================
+//   IMPLEMENTASI
+// ===============================================
+
+func catalogList(fs *flag.FlagSet) action {
+	f := bindCatalogFilter(fs)
+	return action{
+		Check: f.check,
+		Run: func(e *env) error {
+			statements, err := f.selected()
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: "Katalog Query",
+				Columns: []render.Column{
+					{Key: "name", Header: "Nama", Width: 34},
+					{Key: "store", Header: "Store"},
+					{Key: "mode", Header: "Mode"},
+					{Key: "params", Header: "Param", Width: 30},
+					{Key: "columns", Header: "Kolom", Width: 40},
+					{Key: "used", Header: "Dipakai", Format: yesNo},
+					{Key: "doc", Header: "Keterangan", Width: 60},
+					{Key: "source", Header: "Sumber"},
+				},
+				Empty: "Tidak ada entri katalog yang cocok.",
+			}
+			for _, s := range statements {
+				res.Add(s.Name, s.Store, mode(s), paramNames(s), strings.Join(s.Columns, ", "), s.Used, s.Doc, s.Source)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func yesNo(v interface{}) string {
+	if b, _ := v.(bool); b {
+		return "ya"
+	}
+	return "-"
+}
+
+func catalogShow(fs *flag.FlagSet) action {
+	name := fs.String("name", "", "nama entri katalog (wajib)")
+	placeholders := fs.Bool("placeholders", false, "cetak ?/$nama apa adanya, bukan contoh nilai")
+	return action{
+		Check: func() error {
+			if *name == "" {
+				return fmt.Errorf("--name wajib diisi (lihat rs catalog list)")
+			}
+			return nil
+		},
+		Run: func(e *env) error {
+			s, err := queries.Lookup(*name)
+			if err != nil {
+				return err
+			}
+
+			comment := "--"
+			if s.Store == queries.StoreNeo4j {
+				comment = "//"
+			}
+			fmt.Fprintf(e.out, "%s %s (%s)\n", comment, s.Name, s.Source)
+			fmt.Fprintf(e.out, "%s %s\n", comment, s.Doc)
+			for _, p := range s.Params {
+				fmt.Fprintf(e.out, "%s param: %s %s %s\n", comment, p.Name, p.Type, p.Example)
+			}
+			if *placeholders {
+				fmt.Fprintln(e.out, s.Text+";")
+			} else {
+				fmt.Fprintln(e.out, s.Inline())
+			}
+			return nil
+		},
+	}
+}
+
+func catalogCheck(fs *flag.FlagSet) action {
+	f := bindCatalogFilter(fs)
+	return action{
+		Check: f.check,
+		Run: func(e *env) error {
+			statements, err := f.selected()
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: "Cek Katalog Query",
+				Columns: []render.Column{
+					{Key: "name", Header: "Nama", Width: 34},
+					{Key: "store", Header: "Store"},
+					{Key: "mode", Header: "Mode"},
+					{Key: "status", Header: "Status"},
+					{Key: "detail", Header: "Detail", Width: 80},
+				},
+				Empty: "Tidak ada entri katalog yang cocok.",
+			}
+
+			failed := 0
+			e.measure(func() error {
+				for _, s := range statements {
+					columns, err := s.Check()
+					status, detail := "OK", strings.Join(columns, ", ")
+					if err != nil {
+						failed++
+						status, detail = "GAGAL", err.Error()
+					} else if detail == "" {
+						detail = "(tanpa kolom hasil)"
+					}
+					res.Add(s.Name, s.Store, mode(s), status, detail)
+				}
+				return nil
+			})
+
+			res.Notes = append(res.Notes, fmt.Sprintf("%d entri dicek, %d gagal. Cypher diperiksa dengan EXPLAIN; CQL write hanya di-prepare; tidak ada data yang diubah.", len(statements), failed))
+			if err := e.render(res); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d entri katalog tidak cocok dengan database", failed)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"src/render"
+)
+
+// ===============================================
+//   PENGAMAN PERINTAH DESTRUKTIF
+// ===============================================
+//
+// Perintah update/delete yang mengubah banyak data lebih dulu mengumpulkan
+// data yang akan terdampak (plan), lalu guard memutuskan:
+//   --dry-run          tampilkan jumlah + sampel, tidak mengubah apa pun
+//   --max-affected N   batalkan bila data terdampak > N (0 = tanpa batas)
+//   --yes              lewati konfirmasi; tanpa flag ini pengguna ditanya
+//                      di terminal (stdin non-interaktif dianggap menolak)
+
+var errAborted = errors.New("dibatalkan, tidak ada data yang diubah")
+
+type guard struct {
+	dryRun      bool
+	yes         bool
+	maxAffected int
+	sample      int
+
+	in     io.Reader
+	prompt io.Writer
+}
+
+func bindGuard(fs *flag.FlagSet) *guard {
+	g := &guard{in: os.Stdin, prompt: os.Stderr}
+	fs.BoolVar(&g.dryRun, "dry-run", false, "tampilkan data yang akan terdampak tanpa mengubah apa pun")
+	fs.BoolVar(&g.yes, "yes", false, "lewati konfirmasi interaktif")
+	fs.IntVar(&g.maxAffected, "max-affected", 1000, "batalkan bila jumlah data terdampak melebihi nilai ini (0 = tanpa batas)")
+	fs.IntVar(&g.sample, "sample", 10, "jumlah contoh data yang ditampilkan pada preview")
+	return g
+}
+
+func (g *guard) check() error {
+	if err := atLeast("max-affected", g.maxAffected, 0); err != nil {
+		return err
+	}
+	return atLeast("sample", g.sample, 1)
+}
+
+// plan adalah daftar data yang akan diubah sebuah perintah. Preview.Rows
+// berisi seluruh data terdampak; hanya --sample baris yang ditampilkan.
+type plan struct {
+	Title   string
+	Action  string // mis. "pesanan obat akan dihapus"
+	Preview render.Result
+	Notes   []string
+}
+
+// approve menampilkan preview sesuai flag dan melaporkan apakah perintah
+// boleh dieksekusi. Pada --dry-run preview dirender ke output biasa dan
+// hasilnya false tanpa error.
+func (g *guard) approve(e *env, p plan) (bool, error) {
+	n := len(p.Preview.Rows)
+	summary := fmt.Sprintf("%d %s", n, p.Action)
+	over := g.maxAffected > 0 && n > g.maxAffected
+
+	if g.dryRun {
+		res := g.preview(p)
+		res.Title = "DRY-RUN: " + p.Title
+		res.Notes = append([]string{summary}, res.Notes...)
+		if over {
+			res.Notes = append(res.Notes, fmt.Sprintf("⚠️  Melebihi --max-affected %d: eksekusi akan dibatalkan.", g.maxAffected))
+		}
+		res.Notes = append(res.Notes, "Tidak ada data yang diubah (--dry-run).")
+		return false, e.render(res)
+	}
+
+	if n == 0 {
+		return true, nil
+	}
+	if over {
+		return false, fmt.Errorf("%s, melebihi --max-affected %d; naikkan batasnya atau periksa dengan --dry-run", summary, g.maxAffected)
+	}
+	return g.confirm(e, summary, g.preview(p))
+}
+
+// confirm meminta persetujuan untuk summary: --yes langsung setuju, stdin
+// non-interaktif menolak, selain itu preview ditampilkan lalu pengguna
+// ditanya.
+func (g *guard) confirm(e *env, summary string, preview render.Result) (bool, error) {
+	if g.yes {
+		e.opts.logf("%s (--yes)\n", summary)
+		return true, nil
+	}
+
+	if !interactive(g.in) {
+		return false, fmt.Errorf("%s; konfirmasi butuh terminal interaktif, gunakan --yes untuk melanjutkan", summary)
+	}
+	if err := render.Render(g.prompt, preview, render.Options{Format: render.Table, Limit: -1}); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(g.prompt, "%s. Lanjutkan? [y/N] ", summary)
+	answer, _ := bufio.NewReader(g.in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "ya", "yes":
+		return true, nil
+	}
+	return false, errAborted
+}
+
+func (g *guard) preview(p plan) render.Result {
+	res := p.Preview
+	res.Title = p.Title
+	res.Limit = g.sample
+	res.Empty = "Tidak ada data yang terdampak."
+	res.Notes = append([]string(nil), p.Notes...)
+	if n := len(p.Preview.Rows); n > g.sample {
+		res.Notes = append(res.Notes, fmt.Sprintf("Menampilkan %d dari %d data (ubah dengan --sample).", g.sample, n))
+	}
+	return res
+}
+
+func interactive(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
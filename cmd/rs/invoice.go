@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"src/queries"
+	"src/render"
+	"src/tagihan"
+	"src/uang"
+)
+
+// ===============================================
+//   rs invoice ...
+// ===============================================
+//
+// Contoh:
+//   rs invoice preview --pasien pasien1@mail.com --diskon 10%
+//   rs invoice create --pasien pasien1@mail.com --pesanan POB-K7Q2M9XA,PL-P3LW8D2Q --ppn 0
+//   rs invoice show --nomor INV-202501-00001 --format html --out tagihan.html
+//   rs invoice list --pasien pasien1@mail.com
+//
+// Tagihan menggabungkan pesanan obat dan pemesanan layanan pasien yang
+// belum dibayar dan belum ditagih. Diskon dipotong dari subtotal, PPN
+// dihitung dari DPP (subtotal - diskon).
+
+var invoiceGroup = &group{
+	Name:    "invoice",
+	Summary: "tagihan pesanan obat dan layanan pasien dengan diskon dan PPN",
+	Commands: []*command{
+		{Name: "preview", Summary: "hitung tagihan --pasien tanpa menyimpan", Stores: useCassandra, Raw: true, Setup: invoicePreview},
+		{Name: "create", Summary: "buat tagihan bernomor untuk --pasien", Stores: useCassandra, Raw: true, Setup: invoiceCreate},
+		{Name: "show", Summary: "cetak tagihan --nomor sebagai teks, HTML atau JSON", Stores: useCassandra, Raw: true, Setup: invoiceShow},
+		{Name: "list", Summary: "tagihan --pasien, terbaru dulu (default 20)", Stores: useCassandra, Setup: invoiceList},
+	},
+}
+
+// bindInvoice mendaftarkan flag permintaan tagihan dan --format.
+func bindInvoice(fs *flag.FlagSet) (func() (tagihan.Permintaan, error), *string) {
+	var p tagihan.Permintaan
+	fs.StringVar(&p.EmailPasien, "pasien", "", "email pasien (wajib)")
+	fs.Func("pesanan", "hanya id pesanan ini, dipisah koma (default: semua yang belum ditagih)", splitList(&p.Pesanan))
+	diskon := fs.String("diskon", "", "diskon atas subtotal, persen (10%) atau nominal (50000)")
+	ppn := fs.String("ppn", tagihan.TarifPPNDefault.String(), "tarif PPN atas DPP, mis. 11 atau 0")
+	fs.IntVar(&p.JatuhTempo, "jatuh-tempo", tagihan.JatuhTempoDefault, fmt.Sprintf("hari sampai jatuh tempo (1-%d)", tagihan.MaksJatuhTempo))
+	f := bindInvoiceFormat(fs)
+	return func() (tagihan.Permintaan, error) {
+		if err := requiredEmail("pasien", p.EmailPasien); err != nil {
+			return p, err
+		}
+		var err error
+		if p.Diskon, err = tagihan.ParseDiskon(*diskon); err != nil {
+			return p, fmt.Errorf("--diskon: %v", err)
+		}
+		if p.TarifPPN, err = uang.ParsePersen(*ppn); err != nil {
+			return p, fmt.Errorf("--ppn: %v", err)
+		}
+		if p.JatuhTempo < 1 || p.JatuhTempo > tagihan.MaksJatuhTempo {
+			return p, fmt.Errorf("--jatuh-tempo harus 1-%d, bukan %d", tagihan.MaksJatuhTempo, p.JatuhTempo)
+		}
+		return p, tagihan.CekFormat(*f)
+	}, f
+}
+
+func bindInvoiceFormat(fs *flag.FlagSet) *string {
+	return fs.String("format", tagihan.Teks, "format dokumen: "+strings.Join(tagihan.Formats, ", "))
+}
+
+func invoicePreview(fs *flag.FlagSet) action {
+	parse, f := bindInvoice(fs)
+	var p tagihan.Permintaan
+	return action{
+		Check: func() (err error) {
+			p, err = parse()
+			return err
+		},
+		Run: func(e *env) error {
+			var t *tagihan.Tagihan
+			err := e.measure(func() (err error) {
+				t, err = tagihan.Susun(p, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return tagihan.Tulis(e.out, t, *f)
+		},
+	}
+}
+
+func invoiceCreate(fs *flag.FlagSet) action {
+	parse, f := bindInvoice(fs)
+	var p tagihan.Permintaan
+	return action{
+		Check: func() (err error) {
+			p, err = parse()
+			return err
+		},
+		Run: func(e *env) error {
+			var t *tagihan.Tagihan
+			err := e.measure(func() (err error) {
+				t, err = tagihan.Buat(p, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			e.opts.logf("✓ Tagihan %s dibuat untuk %d pesanan.\n", t.Nomor, len(t.Pesanan()))
+			return tagihan.Tulis(e.out, t, *f)
+		},
+	}
+}
+
+func invoiceShow(fs *flag.FlagSet) action {
+	nomor := fs.String("nomor", "", "nomor tagihan, mis. INV-202501-00001 (wajib)")
+	f := bindInvoiceFormat(fs)
+	return action{
+		Check: func() error {
+			if err := required("nomor", *nomor); err != nil {
+				return err
+			}
+			return tagihan.CekFormat(*f)
+		},
+		Run: func(e *env) error {
+			var t *tagihan.Tagihan
+			err := e.measure(func() (err error) {
+				t, err = tagihan.Lihat(*nomor)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return tagihan.Tulis(e.out, t, *f)
+		},
+	}
+}
+
+// invoiceListLimit adalah jumlah tagihan default rs invoice list (--limit).
+const invoiceListLimit = 20
+
+func invoiceList(fs *flag.FlagSet) action {
+	pasien := fs.String("pasien", "", "email pasien (wajib)")
+	return action{
+		Check: func() error { return requiredEmail("pasien", *pasien) },
+		Run: func(e *env) error {
+			var list []queries.Tagihan
+			err := e.measure(func() (err error) {
+				list, err = tagihan.DaftarPasien(*pasien, e.fetch(invoiceListLimit))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{
+				Title: "INVOICE: Tagihan " + *pasien,
+				Columns: []render.Column{
+					{Key: "nomor", Header: "Nomor"},
+					{Key: "dibuat", Header: "Dibuat"},
+					{Key: "total", Header: "Total"},
+					{Key: "status", Header: "Status"},
+				},
+				Limit: invoiceListLimit,
+				Empty: "Pasien belum punya tagihan.",
+				Notes: []string{"Rincian: rs invoice show --nomor <nomor>"},
+			}
+			for _, t := range list {
+				res.Add(t.Nomor, t.Dibuat.Local().Format("2006-01-02 15:04"), t.Total, orDash(t.Status))
+			}
+			return e.render(res)
+		},
+	}
+}
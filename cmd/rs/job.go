@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"src/jobs"
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs job ...
+// ===============================================
+//
+// Contoh:
+//   rs job list
+//   rs job run --nama expire-orders
+//   rs job pause --nama retention
+//   rs job resume --nama retention
+//   rs job schedule --nama expire-orders --cron "*/15 * * * *"
+//   rs job schedule --nama expire-orders --default
+//   rs job history --nama expire-orders --limit 10
+//
+// Job dijalankan terjadwal oleh rs worker; lihat jobs.Semua.
+
+var jobGroup = &group{
+	Name:    "job",
+	Summary: "job pemeliharaan terjadwal: daftar, run manual, jeda dan riwayat",
+	Commands: []*command{
+		{Name: "list", Summary: "semua job dengan jadwal, status, run terakhir dan lease", Stores: useCassandra, Setup: static(jobList)},
+		{Name: "run", Summary: "jalankan job --nama sekarang (tetap lewat lease)", Stores: useBoth, Setup: jobRun},
+		{Name: "pause", Summary: "jeda job --nama; worker melewatinya sampai resume", Stores: useCassandra, Setup: jobPause(true)},
+		{Name: "resume", Summary: "lanjutkan job --nama yang dijeda", Stores: useCassandra, Setup: jobPause(false)},
+		{Name: "schedule", Summary: "ganti jadwal cron job --nama (--default: kembali ke bawaan)", Stores: useCassandra, Setup: jobSchedule},
+		{Name: "history", Summary: "riwayat run job --nama, terbaru dulu", Stores: useCassandra, Setup: jobHistory},
+	},
+}
+
+const jobTime = "2006-01-02 15:04"
+
+// jobFlag mendaftarkan --nama dan mengembalikan pencarinya untuk Check.
+func jobFlag(fs *flag.FlagSet) func() (*jobs.Job, error) {
+	nama := fs.String("nama", "", "nama job (wajib; lihat rs job list)")
+	return func() (*jobs.Job, error) {
+		if err := required("nama", *nama); err != nil {
+			return nil, err
+		}
+		return jobs.Cari(*nama)
+	}
+}
+
+func jobList(e *env) error {
+	names := make([]string, len(jobs.Semua))
+	for i, j := range jobs.Semua {
+		names[i] = j.Nama
+	}
+	var (
+		konf   map[string]queries.KonfigurasiJob
+		last   map[string]queries.RunJob
+		leases map[string]queries.LeaseJob
+	)
+	err := e.measure(func() (err error) {
+		if konf, err = queries.SemuaKonfigurasiJob(); err != nil {
+			return err
+		}
+		if last, err = queries.RunTerakhir(names); err != nil {
+			return err
+		}
+		leases, err = queries.SemuaLeaseJob()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "JOB: Job Pemeliharaan",
+		Columns: []render.Column{
+			{Key: "nama", Header: "Job"},
+			{Key: "jadwal", Header: "Jadwal"},
+			{Key: "status", Header: "Status"},
+			{Key: "berikutnya", Header: "Run Berikutnya"},
+			{Key: "terakhir", Header: "Run Terakhir"},
+			{Key: "hasil", Header: "Hasil"},
+			{Key: "lease", Header: "Lease", Width: 30},
+			{Key: "ringkasan", Header: "Ringkasan", Width: 50},
+		},
+		Notes: []string{"Jadwal bertanda * diubah lewat rs job schedule; run berikutnya berlaku untuk rs worker yang sedang berjalan."},
+	}
+	now := time.Now()
+	for _, j := range jobs.Semua {
+		k := konf[j.Nama]
+		spec, label := j.JadwalBawaan, j.JadwalBawaan
+		if k.Jadwal != "" {
+			spec, label = k.Jadwal, k.Jadwal+" *"
+		}
+		status, next := "aktif", "-"
+		if k.Dijeda {
+			status = "dijeda"
+		}
+		if jd, err := jobs.ParseJadwal(spec); err != nil {
+			status = "jadwal tidak valid"
+		} else if !k.Dijeda {
+			next = jd.Next(now).Format(jobTime)
+		}
+		terakhir, hasil := "-", "-"
+		if r, ok := last[j.Nama]; ok {
+			terakhir = r.Mulai.Local().Format(jobTime)
+			hasil = fmt.Sprintf("%s (%d)", r.Status, r.Jumlah)
+		}
+		lease := "-"
+		if l, ok := leases[j.Nama]; ok {
+			lease = fmt.Sprintf("%s s/d %s", l.Pemilik, l.Sampai.Local().Format("15:04:05"))
+		}
+		res.Add(j.Nama, label, status, next, terakhir, hasil, lease, j.Ringkasan)
+	}
+	return e.render(res)
+}
+
+func jobRun(fs *flag.FlagSet) action {
+	cari := jobFlag(fs)
+	lease := fs.Duration("lease", 5*time.Minute, "lama lease; diperpanjang selama job berjalan")
+	var j *jobs.Job
+	return action{
+		Check: func() (err error) {
+			if *lease < 3*time.Second {
+				return fmt.Errorf("--lease harus >= 3s")
+			}
+			j, err = cari()
+			return err
+		},
+		Run: func(e *env) error {
+			r := &jobs.Runner{ID: jobs.DefaultID(), Lease: *lease, Logf: e.opts.logf}
+			var run queries.RunJob
+			err := e.measure(func() (err error) {
+				run, err = r.Jalankan(j, jobs.PemicuManual)
+				return err
+			})
+			if run.IdRun == "" {
+				// Lease dipegang instance lain atau gagal diambil: job tidak berjalan.
+				return err
+			}
+			// Run yang gagal tetap dicatat dan ditampilkan sebelum error.
+			res := render.Result{Title: "JOB: Run " + j.Nama, Columns: jobRunColumns}
+			addJobRun(&res, run)
+			if rerr := e.render(res); rerr != nil {
+				return rerr
+			}
+			return err
+		},
+	}
+}
+
+func jobPause(dijeda bool) func(fs *flag.FlagSet) action {
+	return func(fs *flag.FlagSet) action {
+		cari := jobFlag(fs)
+		var j *jobs.Job
+		return action{
+			Check: func() (err error) {
+				j, err = cari()
+				return err
+			},
+			Run: func(e *env) error {
+				if err := e.measure(func() error { return queries.AturJedaJob(j.Nama, dijeda, time.Now()) }); err != nil {
+					return err
+				}
+				note := "✓ Job dilanjutkan; worker menjalankannya lagi pada jadwal berikutnya."
+				status := "aktif"
+				if dijeda {
+					note = "✓ Job dijeda; worker melewatinya sampai rs job resume. rs job run tetap bisa dipakai."
+					status = "dijeda"
+				}
+				res := render.Result{Title: "JOB: " + j.Nama, Notes: []string{note},
+					Columns: []render.Column{{Key: "nama", Header: "Job"}, {Key: "status", Header: "Status"}}}
+				res.Add(j.Nama, status)
+				return e.render(res)
+			},
+		}
+	}
+}
+
+func jobSchedule(fs *flag.FlagSet) action {
+	cari := jobFlag(fs)
+	spec := fs.String("cron", "", "jadwal cron 5 kolom, @daily atau \"@every 15m\"")
+	bawaan := fs.Bool("default", false, "kembalikan ke jadwal bawaan job")
+	var j *jobs.Job
+	return action{
+		Check: func() (err error) {
+			if j, err = cari(); err != nil {
+				return err
+			}
+			switch {
+			case *bawaan && *spec != "":
+				return fmt.Errorf("--cron dan --default tidak bisa dipakai bersamaan")
+			case *bawaan:
+				return nil
+			case *spec == "":
+				return fmt.Errorf("--cron wajib diisi (atau --default)")
+			}
+			_, err = jobs.ParseJadwal(*spec)
+			return err
+		},
+		Run: func(e *env) error {
+			simpan := strings.TrimSpace(*spec)
+			if err := e.measure(func() error { return queries.AturJadwalJob(j.Nama, simpan, time.Now()) }); err != nil {
+				return err
+			}
+			efektif := simpan
+			if efektif == "" {
+				efektif = j.JadwalBawaan
+			}
+			jd, _ := jobs.ParseJadwal(efektif)
+			res := render.Result{Title: "JOB: Jadwal " + j.Nama,
+				Notes: []string{"✓ Jadwal disimpan; rs worker memakainya pada cek berikutnya tanpa restart."},
+				Columns: []render.Column{
+					{Key: "nama", Header: "Job"},
+					{Key: "jadwal", Header: "Jadwal"},
+					{Key: "berikutnya", Header: "Run Berikutnya"},
+				}}
+			res.Add(j.Nama, efektif, jd.Next(time.Now()).Format(jobTime))
+			return e.render(res)
+		},
+	}
+}
+
+var jobRunColumns = []render.Column{
+	{Key: "mulai", Header: "Mulai"},
+	{Key: "durasi_ms", Header: "Durasi (ms)"},
+	{Key: "status", Header: "Status"},
+	{Key: "jumlah", Header: "Jumlah"},
+	{Key: "pemicu", Header: "Pemicu"},
+	{Key: "pemilik", Header: "Instance"},
+	{Key: "pesan", Header: "Pesan", Width: 60},
+}
+
+func addJobRun(res *render.Result, r queries.RunJob) {
+	res.Add(r.Mulai.Local().Format("2006-01-02 15:04:05"), r.DurasiMs, r.Status, r.Jumlah, r.Pemicu, r.Pemilik, orDash(r.Pesan))
+}
+
+// jobHistoryLimit adalah jumlah run default rs job history (--limit).
+const jobHistoryLimit = 20
+
+func jobHistory(fs *flag.FlagSet) action {
+	cari := jobFlag(fs)
+	var j *jobs.Job
+	return action{
+		Check: func() (err error) {
+			j, err = cari()
+			return err
+		},
+		Run: func(e *env) error {
+			var list []queries.RunJob
+			err := e.measure(func() (err error) {
+				list, err = queries.RiwayatJob(j.Nama, e.fetch(jobHistoryLimit))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "JOB: Riwayat " + j.Nama, Columns: jobRunColumns, Limit: jobHistoryLimit,
+				Empty: "Belum ada run yang tercatat (riwayat disimpan 90 hari)."}
+			for _, r := range list {
+				addJobRun(&res, r)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+// ===============================================
+//   rs worker: penjadwal job
+// ===============================================
+//
+// Contoh:
+//   rs worker                                  # semua job
+//   rs worker --job expire-orders,retention --cek 10s
+//
+// Beberapa worker boleh berjalan bersamaan: setiap run mengambil lease di
+// job_lease sehingga satu job hanya dijalankan satu instance. Ctrl-C /
+// SIGTERM menunggu job yang sedang berjalan selesai.
+
+func workerMain(args []string) int {
+	fs := flag.NewFlagSet("rs worker", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs worker [flags]\n\nJalankan job pemeliharaan sesuai jadwal (lihat rs job list).\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, false)
+	r := &jobs.Runner{}
+	fs.StringVar(&r.ID, "id", jobs.DefaultID(), "identitas instance pemegang lease")
+	fs.DurationVar(&r.Lease, "lease", 5*time.Minute, "lama lease; diperpanjang tiap sepertiganya selama job berjalan")
+	cek := fs.Duration("cek", 30*time.Second, "selang cek jadwal dan konfigurasi job")
+	only := fs.String("job", "", "daftar job dipisah koma (default: semua)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: argumen tidak dikenal: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	err := opts.resolve(fs)
+	daftar := jobs.Semua
+	if err == nil && *only != "" {
+		daftar = nil
+		for _, nama := range strings.Split(*only, ",") {
+			j, cerr := jobs.Cari(strings.TrimSpace(nama))
+			if cerr != nil {
+				err = cerr
+				break
+			}
+			daftar = append(daftar, j)
+		}
+	}
+	switch {
+	case err != nil:
+	case r.ID == "":
+		err = fmt.Errorf("--id wajib diisi")
+	case r.Lease < 3*time.Second:
+		err = fmt.Errorf("--lease harus >= 3s")
+	case *cek < time.Second:
+		err = fmt.Errorf("--cek harus >= 1s")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	need := useCassandra
+	for _, j := range daftar {
+		if j.Neo4j {
+			need = useBoth
+		}
+	}
+	closeDB, err := opts.connect(need)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeDB()
+
+	r.Logf = func(format string, args ...interface{}) {
+		opts.logf(time.Now().Format("15:04:05 ")+format, args...)
+	}
+	r.Logf("worker %s berjalan: %d job, cek tiap %s\n", r.ID, len(daftar), *cek)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := r.Worker(ctx, daftar, *cek); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"src/queries"
+)
+
+// ===============================================
+//   rs: CLI tunggal untuk schema, seeding, simulasi dan query
+// ===============================================
+//
+// Contoh:
+//   go run ./cmd/rs schema init
+//   go run ./cmd/rs seed --profile tiny --seed 42
+//   go run ./cmd/rs read top-customers
+//   go run ./cmd/rs update expire-orders --cassandra-host 10.0.0.5
+//   go run ./cmd/rs delete cancelled-orders --config rs.json
+//
+// Atau build sekali: go build -o rs ./cmd/rs
+
+// command adalah satu subperintah di bawah grup (read, update, ...).
+type command struct {
+	Name    string
+	Summary string
+	Stores  stores
+	// Raw berarti perintah mencetak teks bebas, bukan render.Result, sehingga
+	// flag --output/--columns/--sort/--limit tidak didaftarkan.
+	Raw bool
+	// Setup mendaftarkan flag khusus perintah lalu mengembalikan action
+	// yang dijalankan setelah flag di-parse.
+	Setup func(fs *flag.FlagSet) action
+}
+
+// action adalah hasil Setup. Check (opsional) memvalidasi nilai flag
+// sebelum koneksi dibuka; kegagalannya adalah usage error (exit 2). Run
+// dijalankan setelah koneksi terbuka.
+type action struct {
+	Check func() error
+	Run   func(e *env) error
+}
+
+type group struct {
+	Name     string
+	Summary  string
+	Commands []*command
+}
+
+func (g *group) find(name string) *command {
+	for _, c := range g.Commands {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// standalone adalah perintah tingkat atas yang mengelola flag sendiri.
+type standalone struct {
+	Name    string
+	Summary string
+	Main    func(args []string) int
+}
+
+var groups []*group
+
+var standalones = []standalone{
+	{"seed", "isi database dengan data dummy (lihat rs seed -h)", seedMain},
+	{"simulate", "jalankan simulasi workload (lihat rs simulate -h)", simulateMain},
+	{"bench", "bandingkan latensi query native dengan padanan SQL (lihat rs bench -h)", benchMain},
+	{"shell", "REPL interaktif CQL dan Cypher (lihat rs shell -h)", shellMain},
+	{"script", "jalankan file .cql/.cypher statement demi statement (lihat rs script -h)", scriptMain},
+	{"serve", "jalankan HTTP REST API (lihat rs serve -h)", serveMain},
+	{"worker", "jalankan job pemeliharaan terjadwal (lihat rs worker -h)", workerMain},
+}
+
+func init() {
+	groups = []*group{readGroup, insertGroup, updateGroup, deleteGroup, appointmentGroup, pharmacyGroup, serviceGroup, invoiceGroup, paymentGroup, jobGroup, retentionGroup, archiveGroup, schemaGroup, catalogGroup}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(os.Stdout)
+		return 0
+	}
+
+	for _, s := range standalones {
+		if s.Name == args[0] {
+			return s.Main(args[1:])
+		}
+	}
+
+	for _, g := range groups {
+		if g.Name != args[0] {
+			continue
+		}
+		if len(args) < 2 || args[1] == "-h" || args[1] == "--help" || args[1] == "help" {
+			groupUsage(os.Stdout, g)
+			return 0
+		}
+		c := g.find(args[1])
+		if c == nil {
+			fmt.Fprintf(os.Stderr, "Error: perintah %q tidak dikenal di grup %s\n\n", args[1], g.Name)
+			groupUsage(os.Stderr, g)
+			return 2
+		}
+		return runCommand(g, c, args[2:])
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: perintah %q tidak dikenal\n\n", args[0])
+	usage(os.Stderr)
+	return 2
+}
+
+func runCommand(g *group, c *command, args []string) int {
+	fs := flag.NewFlagSet("rs "+g.Name+" "+c.Name, flag.ContinueOnError)
+	opts := bindOptions(fs, true)
+	e := &env{opts: opts, out: os.Stdout}
+	if !c.Raw {
+		e.view.Bind(fs)
+	}
+	act := c.Setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs %s %s [flags]\n\n%s\n\nFlags:\n", g.Name, c.Name, c.Summary)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: argumen tidak dikenal: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	if err := opts.resolve(fs); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	if !c.Raw {
+		if err := e.view.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+	}
+	if act.Check != nil {
+		if err := act.Check(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+	}
+
+	// Katalog query dimuat sebelum koneksi agar file .cql/.cypher yang
+	// tidak cocok dengan kode gagal cepat, bukan di tengah eksekusi.
+	if _, err := queries.Catalog(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: katalog query:", err)
+		return 1
+	}
+
+	closeOut, err := e.openOut()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeOut()
+
+	closeStores, err := opts.connect(c.Stores)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeStores()
+
+	if err := act.Run(e); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	if e.elapsed > 0 {
+		opts.logf("\nTime: %.3f seconds (%d ms)\n", e.elapsed.Seconds(), e.elapsed.Milliseconds())
+	}
+	return 0
+}
+
+// ===============================================
+//   USAGE
+// ===============================================
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: rs <perintah> [subperintah] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Perintah:")
+	for _, g := range groups {
+		fmt.Fprintf(w, "  %-10s %s\n", g.Name, g.Summary)
+		for _, c := range g.Commands {
+			fmt.Fprintf(w, "      %-32s %s\n", c.Name, c.Summary)
+		}
+	}
+	for _, s := range standalones {
+		fmt.Fprintf(w, "  %-10s %s\n", s.Name, s.Summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flag koneksi (semua perintah): --cassandra-host, --cassandra-port, --neo4j-uri,")
+	fmt.Fprintln(w, "--neo4j-user, --neo4j-password, --config FILE. Flag output (read/insert/update/")
+	fmt.Fprintln(w, "delete): --output table|json|jsonl|csv|markdown, --columns, --sort, --limit,")
+	fmt.Fprintln(w, "--out FILE, --quiet.")
+	fmt.Fprintln(w, "Jalankan 'rs <perintah> <subperintah> -h' untuk detail flag.")
+}
+
+func groupUsage(w io.Writer, g *group) {
+	fmt.Fprintf(w, "Usage: rs %s <subperintah> [flags]\n\n%s\n\nSubperintah:\n", g.Name, g.Summary)
+	for _, c := range g.Commands {
+		fmt.Fprintf(w, "  %-32s %s\n", c.Name, c.Summary)
+	}
+}
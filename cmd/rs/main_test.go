@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+// TestCommandFlags memastikan flag perintah tidak bentrok dengan flag
+// koneksi dan flag tampilan (--output/--columns/--sort/--limit) yang
+// didaftarkan runCommand; flag ganda membuat flag.FlagSet panic.
+func TestCommandFlags(t *testing.T) {
+	for _, g := range groups {
+		for _, c := range g.Commands {
+			t.Run(g.Name+"/"+c.Name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("setup panic: %v", r)
+					}
+				}()
+				fs := flag.NewFlagSet("rs "+g.Name+" "+c.Name, flag.ContinueOnError)
+				fs.SetOutput(io.Discard)
+				bindOptions(fs, true)
+				e := &env{}
+				if !c.Raw {
+					e.view.Bind(fs)
+				}
+				c.Setup(fs)
+			})
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/render"
+)
+
+// ===============================================
+//   FLAG BERSAMA: koneksi, konfigurasi, output
+// ===============================================
+//
+// Urutan prioritas: flag eksplisit > file --config > environment > default.
+
+type options struct {
+	Cassandra  cassandra.Config
+	Neo4j      neo4j.Config
+	ConfigFile string
+	Out        string
+	Quiet      bool
+}
+
+// fileConfig adalah format file --config (JSON).
+type fileConfig struct {
+	Cassandra struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	} `json:"cassandra"`
+	Neo4j struct {
+		URI      string `json:"uri"`
+		User     string `json:"user"`
+		Password string `json:"password"`
+	} `json:"neo4j"`
+}
+
+// bindOptions mendaftarkan flag koneksi dan --config; withOutput menambah
+// --out dan --quiet untuk perintah yang menulis hasil query.
+func bindOptions(fs *flag.FlagSet, withOutput bool) *options {
+	o := &options{
+		Cassandra: cassandra.DefaultConfig,
+		Neo4j:     neo4j.DefaultConfig,
+	}
+	fs.StringVar(&o.Cassandra.Host, "cassandra-host", o.Cassandra.Host, "host Cassandra (env CASSANDRA_HOST)")
+	fs.IntVar(&o.Cassandra.Port, "cassandra-port", o.Cassandra.Port, "port Cassandra (env CASSANDRA_PORT)")
+	fs.StringVar(&o.Neo4j.URI, "neo4j-uri", o.Neo4j.URI, "URI Neo4j (env NEO4J_URI)")
+	fs.StringVar(&o.Neo4j.User, "neo4j-user", o.Neo4j.User, "user Neo4j (env NEO4J_USER)")
+	fs.StringVar(&o.Neo4j.Password, "neo4j-password", o.Neo4j.Password, "password Neo4j (env NEO4J_PASSWORD)")
+	fs.StringVar(&o.ConfigFile, "config", os.Getenv("RS_CONFIG"), "file konfigurasi koneksi JSON (env RS_CONFIG)")
+	if withOutput {
+		fs.StringVar(&o.Out, "out", "", "tulis hasil ke file alih-alih stdout")
+		fs.BoolVar(&o.Quiet, "quiet", false, "jangan cetak pesan koneksi dan waktu eksekusi")
+	}
+	return o
+}
+
+// resolve menerapkan file --config di bawah flag yang diset eksplisit, lalu
+// menjadikan hasilnya konfigurasi default paket cassandra dan neo4j.
+func (o *options) resolve(fs *flag.FlagSet) error {
+	if o.ConfigFile != "" {
+		data, err := os.ReadFile(o.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("gagal membaca config: %v", err)
+		}
+		var fc fileConfig
+		if err := json.Unmarshal(data, &fc); err != nil {
+			return fmt.Errorf("config %s tidak valid: %v", o.ConfigFile, err)
+		}
+
+		explicit := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+		setString := func(name string, dst *string, val string) {
+			if val != "" && !explicit[name] {
+				*dst = val
+			}
+		}
+		setString("cassandra-host", &o.Cassandra.Host, fc.Cassandra.Host)
+		if fc.Cassandra.Port != 0 && !explicit["cassandra-port"] {
+			o.Cassandra.Port = fc.Cassandra.Port
+		}
+		setString("neo4j-uri", &o.Neo4j.URI, fc.Neo4j.URI)
+		setString("neo4j-user", &o.Neo4j.User, fc.Neo4j.User)
+		setString("neo4j-password", &o.Neo4j.Password, fc.Neo4j.Password)
+	}
+
+	cassandra.DefaultConfig = o.Cassandra
+	neo4j.DefaultConfig = o.Neo4j
+	return nil
+}
+
+// ===============================================
+//   STORE & KONEKSI
+// ===============================================
+
+type stores int
+
+const (
+	useCassandra stores = 1 << iota
+	useNeo4j
+	useBoth = useCassandra | useNeo4j
+)
+
+// connect membuka koneksi ke store yang dibutuhkan dan mengembalikan fungsi
+// penutupnya.
+func (o *options) connect(s stores) (func(), error) {
+	var closers []func()
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	if s&useCassandra != 0 {
+		if err := cassandra.Connect(o.Cassandra); err != nil {
+			return nil, fmt.Errorf("koneksi Cassandra %s:%d gagal: %v", o.Cassandra.Host, o.Cassandra.Port, err)
+		}
+		closers = append(closers, cassandra.Close)
+		o.logf("Connected to Cassandra\n")
+	}
+	if s&useNeo4j != 0 {
+		if err := neo4j.Connect(o.Neo4j); err != nil {
+			closeAll()
+			return nil, fmt.Errorf("koneksi Neo4j %s gagal: %v", o.Neo4j.URI, err)
+		}
+		closers = append(closers, neo4j.CloseNeo4j)
+		o.logf("Connected to Neo4j\n")
+	}
+	return closeAll, nil
+}
+
+// logf menulis pesan status ke stderr kecuali --quiet.
+func (o *options) logf(format string, args ...interface{}) {
+	if !o.Quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// ===============================================
+//   ENV: konteks eksekusi satu perintah
+// ===============================================
+
+type env struct {
+	opts    *options
+	view    render.Options
+	out     io.Writer
+	elapsed time.Duration
+}
+
+// openOut mengarahkan output ke file --out bila diisi dan mengembalikan
+// fungsi penutupnya.
+func (e *env) openOut() (func(), error) {
+	if e.opts.Out == "" {
+		return func() {}, nil
+	}
+	f, err := os.Create(e.opts.Out)
+	if err != nil {
+		return nil, err
+	}
+	e.out = f
+	return func() { f.Close() }, nil
+}
+
+// render menampilkan hasil perintah sesuai --output/--columns/--sort/--limit.
+func (e *env) render(res render.Result) error {
+	return render.Render(e.out, res, e.view)
+}
+
+// fetch mengembalikan jumlah baris yang perlu dibaca dari database untuk
+// hasil dengan Limit def: nilai --limit bila diisi (0 = semua), semua bila
+// --sort diisi karena urutan baru diketahui setelah semua baris dibaca,
+// selain itu def.
+func (e *env) fetch(def int) int {
+	switch {
+	case len(e.view.Sort) > 0:
+		return 0
+	case e.view.Limit >= 0:
+		return e.view.Limit
+	}
+	return def
+}
+
+// measure menjalankan fn dan mencatat durasinya sebagai waktu eksekusi query
+// (tanpa waktu menampilkan hasil).
+func (e *env) measure(fn func() error) error {
+	start := time.Now()
+	err := fn()
+	e.elapsed += time.Since(start)
+	return err
+}
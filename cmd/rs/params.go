@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"src/retensi"
+)
+
+// ===============================================
+//   PARAMETER PERINTAH
+// ===============================================
+
+// static dipakai perintah yang tidak punya flag khusus.
+func static(fn func(e *env) error) func(fs *flag.FlagSet) action {
+	return func(*flag.FlagSet) action { return action{Run: fn} }
+}
+
+// atLeast memvalidasi flag integer dengan batas bawah.
+func atLeast(name string, v, floor int) error {
+	if v < floor {
+		return fmt.Errorf("--%s harus >= %d, bukan %d", name, floor, v)
+	}
+	return nil
+}
+
+// ===============================================
+//   AGE: rentang waktu mundur dari sekarang
+// ===============================================
+
+// age adalah flag.Value untuk rentang waktu seperti "48h", "2d", "2w",
+// "6mo" atau "1y"; sintaksnya sama dengan umur aturan retensi.
+type age struct{ retensi.Umur }
+
+func mustAge(s string) *age {
+	a := &age{}
+	if err := a.Set(s); err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// positive memvalidasi bahwa rentang waktu flag name tidak nol.
+func (a *age) positive(name string) error {
+	if a.IsZero() {
+		return fmt.Errorf("--%s tidak boleh 0", name)
+	}
+	return nil
+}
+
+// ===============================================
+//   RECORD INSERT: flag dan/atau file JSON
+// ===============================================
+
+// recordField memetakan satu flag ke satu field string sebuah record.
+type recordField[T any] struct {
+	Flag  string
+	Usage string
+	Ptr   func(*T) *string
+}
+
+// bindRecords mendaftarkan --file dan satu flag per field. Fungsi yang
+// dikembalikan dipanggil setelah parse: record dibaca dari --file (objek
+// atau array JSON) atau dimulai dari satu record kosong, lalu field yang
+// flag-nya diset eksplisit ditimpa pada setiap record dan semua record
+// divalidasi.
+func bindRecords[T interface{ Validate() error }](fs *flag.FlagSet, fields []recordField[T]) func() ([]T, error) {
+	file := fs.String("file", "", "baca record dari file JSON (objek atau array); flag field menimpa nilai file")
+	values := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		values[f.Flag] = fs.String(f.Flag, "", f.Usage)
+	}
+
+	return func() ([]T, error) {
+		records := []T{*new(T)}
+		if *file != "" {
+			var err error
+			if records, err = readRecords[T](*file); err != nil {
+				return nil, err
+			}
+		}
+
+		explicit := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+		for i := range records {
+			for _, f := range fields {
+				if explicit[f.Flag] {
+					*f.Ptr(&records[i]) = *values[f.Flag]
+				}
+			}
+			if err := records[i].Validate(); err != nil {
+				if len(records) == 1 {
+					return nil, err
+				}
+				return nil, fmt.Errorf("record #%d: %w", i+1, err)
+			}
+		}
+		return records, nil
+	}
+}
+
+func readRecords[T any](path string) ([]T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file record: %v", err)
+	}
+	data = bytes.TrimSpace(data)
+
+	dec := func(v interface{}) error {
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.DisallowUnknownFields()
+		return d.Decode(v)
+	}
+
+	var records []T
+	if len(data) > 0 && data[0] == '[' {
+		err = dec(&records)
+	} else {
+		var r T
+		err = dec(&r)
+		records = []T{r}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file record %s tidak valid: %v", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file record %s tidak berisi record", path)
+	}
+	return records, nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"src/pembayaran"
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs payment ...
+// ===============================================
+//
+// Contoh:
+//   rs payment create --tagihan INV-202501-00001
+//   rs payment simulate --id PAY-K7Q2M9XA --hasil dibayar
+//   rs payment simulate --id PAY-K7Q2M9XA --hasil dibayar --ulang 2 --webhook http://localhost:8080/api/pembayaran/webhook/lokal
+//   rs payment show --id PAY-K7Q2M9XA
+//   rs payment list --tagihan INV-202501-00001
+//
+// Pembayaran selalu untuk satu tagihan (rs invoice create). simulate
+// berperan sebagai gateway lokal: event ditandatangani lalu dikirim ke
+// --webhook, atau diproses langsung bila --webhook kosong. Gateway lokal
+// hanya terpasang bila env RS_PAYMENT_LOKAL aktif dan RS_PAYMENT_SECRET
+// diisi (rahasia yang sama dengan rs serve).
+
+var paymentGroup = &group{
+	Name:    "payment",
+	Summary: "pembayaran tagihan lewat gateway dan simulasi webhook gateway lokal",
+	Commands: []*command{
+		{Name: "create", Summary: "buat payment intent untuk --tagihan", Stores: useCassandra, Setup: paymentCreate},
+		{Name: "show", Summary: "status pembayaran --id", Stores: useCassandra, Setup: paymentShow},
+		{Name: "list", Summary: "pembayaran --tagihan, terbaru dulu", Stores: useCassandra, Setup: paymentList},
+		{Name: "simulate", Summary: "kirim webhook --hasil gateway lokal untuk pembayaran --id", Stores: useCassandra, Setup: paymentSimulate},
+	},
+}
+
+// paymentColumns adalah kolom tabel pembayaran.
+var paymentColumns = []render.Column{
+	{Key: "id_pembayaran", Header: "ID Pembayaran"},
+	{Key: "nomor_tagihan", Header: "Tagihan"},
+	{Key: "jumlah", Header: "Jumlah"},
+	{Key: "status", Header: "Status"},
+	{Key: "gateway", Header: "Gateway"},
+	{Key: "kedaluwarsa", Header: "Berlaku Sampai"},
+	{Key: "diperbarui", Header: "Diperbarui"},
+	{Key: "alasan", Header: "Alasan", Width: 30},
+}
+
+func addPayment(res *render.Result, p queries.Pembayaran) {
+	res.Add(p.IdPembayaran, p.NomorTagihan, p.Jumlah, p.Status, p.Gateway,
+		p.Kedaluwarsa.Local().Format("2006-01-02 15:04"), p.Diperbarui.Local().Format("2006-01-02 15:04"), orDash(p.Alasan))
+}
+
+func paymentCreate(fs *flag.FlagSet) action {
+	var p pembayaran.Permintaan
+	fs.StringVar(&p.NomorTagihan, "tagihan", "", "nomor tagihan yang dibayar (wajib)")
+	fs.StringVar(&p.Gateway, "gateway", pembayaran.NamaLokal, "gateway pembayaran ("+pembayaran.NamaLokal+" butuh env "+pembayaran.EnvLokal+" dan "+pembayaran.EnvRahasia+")")
+	berlaku := &age{}
+	fs.Var(berlaku, "berlaku", "masa berlaku intent, mis. 1h atau 2d (default: sampai jatuh tempo tagihan)")
+	return action{
+		Check: func() error {
+			if err := required("tagihan", p.NomorTagihan); err != nil {
+				return err
+			}
+			if p.Gateway == pembayaran.NamaLokal {
+				_, err := pasangLokal()
+				return err
+			}
+			return nil
+		},
+		Run: func(e *env) error {
+			now := time.Now()
+			if !berlaku.IsZero() {
+				p.Berlaku = now.Sub(berlaku.Before(now))
+			}
+			var r *queries.Pembayaran
+			err := e.measure(func() (err error) {
+				r, err = pembayaran.Buat(p, now)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "PAYMENT: Pembayaran Dibuat", Columns: paymentColumns,
+				Notes: []string{
+					"URL bayar: " + r.URLBayar,
+					fmt.Sprintf("Simulasi hasil gateway lokal: rs payment simulate --id %s --hasil dibayar", r.IdPembayaran),
+				}}
+			addPayment(&res, *r)
+			return e.render(res)
+		},
+	}
+}
+
+// pasangLokal memasang gateway lokal untuk perintah payment. Gateway ini
+// hanya dipakai bila diaktifkan eksplisit lewat env, dengan rahasia yang
+// tidak kosong.
+func pasangLokal() (*pembayaran.Lokal, error) {
+	if !pembayaran.LokalDiaktifkan() {
+		return nil, fmt.Errorf("gateway %s tidak aktif: set %s=1 dan %s (sama dengan rs serve)",
+			pembayaran.NamaLokal, pembayaran.EnvLokal, pembayaran.EnvRahasia)
+	}
+	return pembayaran.PasangLokal(os.Getenv(pembayaran.EnvRahasia))
+}
+
+func paymentShow(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID pembayaran (wajib)")
+	return action{
+		Check: func() error { return required("id", *id) },
+		Run: func(e *env) error {
+			var r *queries.Pembayaran
+			err := e.measure(func() (err error) {
+				r, err = pembayaran.Lihat(*id)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "PAYMENT: Pembayaran " + r.IdPembayaran, Columns: paymentColumns,
+				Notes: []string{"URL bayar: " + orDash(r.URLBayar), "Ref gateway: " + orDash(r.RefGateway)}}
+			addPayment(&res, *r)
+			return e.render(res)
+		},
+	}
+}
+
+func paymentList(fs *flag.FlagSet) action {
+	nomor := fs.String("tagihan", "", "nomor tagihan (wajib)")
+	return action{
+		Check: func() error { return required("tagihan", *nomor) },
+		Run: func(e *env) error {
+			var list []queries.Pembayaran
+			err := e.measure(func() (err error) {
+				list, err = pembayaran.DaftarTagihan(*nomor)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "PAYMENT: Pembayaran Tagihan " + *nomor, Columns: paymentColumns,
+				Empty: "Tagihan belum punya pembayaran."}
+			for _, p := range list {
+				addPayment(&res, p)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func paymentSimulate(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID pembayaran dengan gateway lokal (wajib)")
+	hasil := fs.String("hasil", "", "hasil pembayaran: "+strings.Join(pembayaran.Jenis, ", ")+" (wajib)")
+	alasan := fs.String("alasan", "", "alasan untuk hasil gagal, mis. \"kartu ditolak\"")
+	webhook := fs.String("webhook", "", "URL webhook, mis. http://localhost:8080/api/pembayaran/webhook/lokal (default: diproses langsung)")
+	ulang := fs.Int("ulang", 1, "kirim event yang sama sebanyak ini (uji idempotensi)")
+	var lokal *pembayaran.Lokal
+	return action{
+		Check: func() (err error) {
+			if err := required("id", *id); err != nil {
+				return err
+			}
+			if err := required("hasil", *hasil); err != nil {
+				return err
+			}
+			if err := atLeast("ulang", *ulang, 1); err != nil {
+				return err
+			}
+			lokal, err = pasangLokal()
+			return err
+		},
+		Run: func(e *env) error {
+			p, err := pembayaran.Lihat(*id)
+			if err != nil {
+				return err
+			}
+			ev, err := lokal.Picu(*p, *hasil, *alasan, time.Now())
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: fmt.Sprintf("PAYMENT: Webhook %s untuk %s", ev.Jenis, p.IdPembayaran),
+				Columns: []render.Column{
+					{Key: "kirim", Header: "Kirim"},
+					{Key: "id_event", Header: "ID Event"},
+					{Key: "duplikat", Header: "Duplikat"},
+					{Key: "status", Header: "Status Pembayaran"},
+					{Key: "status_tagihan", Header: "Status Tagihan"},
+					{Key: "pesanan_diubah", Header: "Pesanan Diubah"},
+				},
+			}
+			for i := 1; i <= *ulang; i++ {
+				var h *pembayaran.Hasil
+				err := e.measure(func() (err error) {
+					h, err = lokal.Kirim(ev, *webhook, time.Now())
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				var ubah []string
+				for _, c := range h.Pesanan {
+					ubah = append(ubah, c.IdPesanan+" → "+c.StatusBaru)
+				}
+				res.Add(i, h.IdEvent, h.Duplikat, h.Status, orDash(h.StatusTagihan), orDash(strings.Join(ubah, ", ")))
+				for _, c := range h.Catatan {
+					res.Notes = append(res.Notes, fmt.Sprintf("[%d] %s", i, c))
+				}
+			}
+			return e.render(res)
+		},
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"src/apotek"
+	"src/render"
+)
+
+// ===============================================
+//   rs pharmacy ...
+// ===============================================
+//
+// Contoh:
+//   rs pharmacy order --pasien pasien1@mail.com --obat O0001=2,O0003
+//   rs pharmacy show --id POB-K7Q2M9XA
+//   rs pharmacy fill --resep R00001 --hari 5
+//   rs pharmacy prescription --resep R00001
+
+var pharmacyGroup = &group{
+	Name:    "pharmacy",
+	Summary: "pemesanan obat dengan harga katalog dan reservasi stok",
+	Commands: []*command{
+		{Name: "order", Summary: "pesan --obat untuk --pasien (stok langsung dipotong)", Stores: useBoth, Setup: pharmacyOrder},
+		{Name: "show", Summary: "ringkasan pesanan obat --id", Stores: useCassandra, Setup: pharmacyShow},
+		{Name: "fill", Summary: "tebus --resep menjadi pesanan obat (jumlah = dosis × --hari)", Stores: useBoth, Setup: pharmacyFill},
+		{Name: "prescription", Summary: "pesanan obat hasil tebus --resep dan statusnya", Stores: useCassandra, Setup: pharmacyPrescription},
+	},
+}
+
+// orderResult menampilkan rincian pesanan obat, satu baris per obat.
+func orderResult(title string, r *apotek.Ringkasan, notes ...string) render.Result {
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "id_obat", Header: "ID Obat"},
+			{Key: "nama", Header: "Nama Obat", Width: 30},
+			{Key: "jumlah", Header: "Jumlah"},
+			{Key: "harga", Header: "Harga"},
+			{Key: "subtotal", Header: "Subtotal"},
+		},
+		Empty: "Pesanan tidak berisi obat.",
+	}
+	for _, b := range r.Obat {
+		res.Add(b.IdObat, b.Nama, b.Jumlah, b.Harga, b.Subtotal)
+	}
+	res.Notes = append([]string{
+		fmt.Sprintf("Pesanan %s oleh %s (%s), status %s.", r.IdPesanan, r.EmailPemesan,
+			r.WaktuPemesanan.Local().Format("2006-01-02 15:04"), r.Status),
+		"Total: " + r.TotalHarga.String(),
+	}, notes...)
+	return res
+}
+
+func pharmacyOrder(fs *flag.FlagSet) action {
+	pasien := fs.String("pasien", "", "email pasien pemesan (wajib)")
+	obat := fs.String("obat", "", "daftar id_obat=jumlah dipisah koma, mis. O0001=2,O0003 (wajib)")
+	var p apotek.Permintaan
+	return action{
+		Check: func() error {
+			if err := requiredEmail("pasien", *pasien); err != nil {
+				return err
+			}
+			if err := required("obat", *obat); err != nil {
+				return err
+			}
+			daftar, err := apotek.ParseDaftarObat(*obat)
+			if err != nil {
+				return fmt.Errorf("--obat: %v", err)
+			}
+			p = apotek.Permintaan{EmailPasien: *pasien, Obat: daftar}
+			return nil
+		},
+		Run: func(e *env) error {
+			var r *apotek.Ringkasan
+			err := e.measure(func() (err error) {
+				r, err = apotek.Pesan(p, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return e.render(orderResult("PHARMACY: Pesanan Obat Dibuat", r, "✓ Stok sudah dipotong; pesanan menunggu pembayaran."))
+		},
+	}
+}
+
+func pharmacyShow(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID pesanan obat (wajib)")
+	return action{
+		Check: func() error { return required("id", *id) },
+		Run: func(e *env) error {
+			var r *apotek.Ringkasan
+			err := e.measure(func() (err error) {
+				r, err = apotek.Lihat(*id)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return e.render(orderResult("PHARMACY: Pesanan Obat", r, "Harga per obat adalah harga katalog saat ini; total adalah total saat pesan."))
+		},
+	}
+}
+
+func pharmacyFill(fs *flag.FlagSet) action {
+	resep := fs.String("resep", "", "ID resep (wajib)")
+	hari := fs.Int("hari", apotek.LamaDefault, "lama pengobatan bila resep tidak mencatat lama_hari")
+	return action{
+		Check: func() error {
+			if err := required("resep", *resep); err != nil {
+				return err
+			}
+			if *hari < 1 || *hari > apotek.MaksLama {
+				return fmt.Errorf("--hari harus 1-%d", apotek.MaksLama)
+			}
+			return nil
+		},
+		Run: func(e *env) error {
+			var h *apotek.HasilTebus
+			err := e.measure(func() (err error) {
+				h, err = apotek.TebusResep(*resep, *hari, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			notes := []string{fmt.Sprintf("Resep %s (%s) untuk %s lewat janji temu %s.", h.IdResep, orDash(h.Penyakit), h.EmailPasien, h.IdJanjiTemu)}
+			for _, b := range h.Resep {
+				notes = append(notes, fmt.Sprintf("%s: %s × %d hari = %d", b.IdObat, b.Dosis, b.LamaHari, b.Jumlah))
+			}
+			notes = append(notes, fmt.Sprintf("✓ Resep %s ditebus; lacak dengan rs pharmacy prescription --resep %s", h.IdResep, h.IdResep))
+			return e.render(orderResult("PHARMACY: Pesanan Obat dari Resep", h.Pesanan, notes...))
+		},
+	}
+}
+
+func pharmacyPrescription(fs *flag.FlagSet) action {
+	resep := fs.String("resep", "", "ID resep (wajib)")
+	return action{
+		Check: func() error { return required("resep", *resep) },
+		Run: func(e *env) error {
+			var h *apotek.HasilTebus
+			err := e.measure(func() (err error) {
+				h, err = apotek.StatusResep(*resep)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return e.render(orderResult("PHARMACY: Pesanan Obat Resep "+h.IdResep, h.Pesanan))
+		},
+	}
+}
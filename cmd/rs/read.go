@@ -0,0 +1,361 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs read ...
+// ===============================================
+
+var readGroup = &group{
+	Name:    "read",
+	Summary: "query baca (laporan) dari Cassandra dan/atau Neo4j",
+	Commands: []*command{
+		{Name: "order-counts", Summary: "jumlah pesanan obat per pasien", Stores: useCassandra, Setup: static(readOrderCounts)},
+		{Name: "low-stock", Summary: "obat dengan stok di bawah --max-stok", Stores: useCassandra, Setup: readLowStock},
+		{Name: "baymin-logs", Summary: "log aktivitas Baymin milik pasien --email", Stores: useBoth, Setup: readBayminLogs},
+		{Name: "doctor-appointments", Summary: "jumlah janji temu per tenaga medis", Stores: useNeo4j, Setup: static(readDoctorAppointments)},
+		{Name: "prescription", Summary: "detail resep janji temu --id", Stores: useBoth, Setup: readPrescription},
+		{Name: "top-customers", Summary: "pasien dengan biaya pemesanan obat terbesar", Stores: useCassandra, Setup: static(readTopCustomers)},
+		{Name: "popular-services", Summary: "layanan medis yang paling sering dipesan", Stores: useNeo4j, Setup: static(readPopularServices)},
+		{Name: "top-hospitals", Summary: "rumah sakit dengan janji temu terbanyak", Stores: useNeo4j, Setup: static(readTopHospitals)},
+		{Name: "hospitals-by-staff", Summary: "rumah sakit dengan tenaga medis terbanyak", Stores: useNeo4j, Setup: static(readHospitalsByStaff)},
+		{Name: "patients-without-prescription", Summary: "pasien dengan janji temu tanpa resep", Stores: useNeo4j, Setup: static(readPatientsWithoutPrescription)},
+		{Name: "specialists", Summary: "tenaga medis --profesi di --kota (graph traversal)", Stores: useNeo4j, Setup: readSpecialists},
+	},
+}
+
+// ===============================================
+//   IMPLEMENTASI
+// ===============================================
+
+func readOrderCounts(e *env) error {
+	var patients []queries.PatientOrderCount
+	err := e.measure(func() (err error) {
+		patients, err = queries.PatientOrderCounts()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "Jumlah Pesanan Obat per Pasien",
+		Columns: []render.Column{
+			{Key: "email", Header: "Email Pemesan", Width: 40},
+			{Key: "total_pesanan", Header: "Total Pesanan"},
+		},
+		Numbered: true,
+		Empty:    "Tidak ada data pemesanan obat.",
+		Limit:    10,
+	}
+	for _, p := range patients {
+		res.Add(p.Email, p.TotalPesanan)
+	}
+	return e.render(res)
+}
+
+func readLowStock(fs *flag.FlagSet) action {
+	maxStok := fs.Int("max-stok", 55, "tampilkan obat dengan stok kurang dari nilai ini")
+	return action{
+		Check: func() error { return atLeast("max-stok", *maxStok, 1) },
+		Run:   func(e *env) error { return lowStock(e, *maxStok) },
+	}
+}
+
+func lowStock(e *env, maxStok int) error {
+	var medicines []queries.MedicineStock
+	err := e.measure(func() (err error) {
+		medicines, err = queries.LowStockMedicines(maxStok)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: fmt.Sprintf("Daftar Obat dengan Stok Kurang dari %d", maxStok),
+		Columns: []render.Column{
+			{Key: "id_obat", Header: "ID Obat"},
+			{Key: "nama", Header: "Nama", Width: 30},
+			{Key: "label", Header: "Label", Width: 20},
+			{Key: "stok", Header: "Stok"},
+		},
+		Empty: fmt.Sprintf("Tidak ada data obat dengan stok kurang dari %d.", maxStok),
+	}
+	for _, m := range medicines {
+		res.Add(m.IDObat, m.Nama, m.Label, m.Stok)
+	}
+	return e.render(res)
+}
+
+func readBayminLogs(fs *flag.FlagSet) action {
+	email := fs.String("email", "pasien1@mail.com", "email pasien pemilik Baymin")
+	return action{
+		Check: func() error { return queries.ValidEmail(*email) },
+		Run:   func(e *env) error { return bayminLogs(e, *email) },
+	}
+}
+
+func bayminLogs(e *env, email string) error {
+	var logs []queries.BayminLog
+	err := e.measure(func() (err error) {
+		logs, err = queries.BayminLogs(email)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "Log Aktivitas Baymin Pasien " + email,
+		Columns: []render.Column{
+			{Key: "waktu_aktivitas", Header: "Waktu Aktivitas"},
+			{Key: "nama_pasien", Header: "Nama Pasien", Width: 25},
+			{Key: "detail_aktivitas", Header: "Detail Aktivitas"},
+		},
+		Empty: "Tidak ada log aktivitas untuk pasien ini.",
+	}
+	for _, l := range logs {
+		res.Add(l.WaktuAktivitas, l.Nama, l.DetailAktivitas)
+	}
+	return e.render(res)
+}
+
+func readDoctorAppointments(e *env) error {
+	var mediks []queries.MedikJanjiTemu
+	err := e.measure(func() (err error) {
+		mediks, err = queries.MedikJanjiTemuCounts()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "Jumlah Janji Temu per Tenaga Medis",
+		Columns: []render.Column{
+			{Key: "email", Header: "Email", Width: 30},
+			{Key: "nama_lengkap", Header: "Nama Lengkap", Width: 35},
+			{Key: "profesi", Header: "Profesi", Width: 28},
+			{Key: "jumlah_janji_temu", Header: "Jumlah Janji Temu"},
+		},
+		Numbered: true,
+		Empty:    "Tidak ada data janji temu dengan tenaga medis.",
+		Limit:    10,
+	}
+	for _, m := range mediks {
+		res.Add(m.Email, m.Nama, m.Profesi, m.JumlahJanjiTemu)
+	}
+	return e.render(res)
+}
+
+func readPrescription(fs *flag.FlagSet) action {
+	id := fs.String("id", "JT00011", "ID janji temu")
+	return action{
+		Check: func() error {
+			if strings.TrimSpace(*id) == "" {
+				return fmt.Errorf("--id wajib diisi")
+			}
+			return nil
+		},
+		Run: func(e *env) error { return prescription(e, *id) },
+	}
+}
+
+func prescription(e *env, idJanjiTemu string) error {
+	var details []queries.DetailResep
+	err := e.measure(func() (err error) {
+		details, err = queries.DetailResepJanjiTemu(idJanjiTemu)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "Detail Resep untuk Janji Temu " + idJanjiTemu,
+		Columns: []render.Column{
+			{Key: "id_janji_temu", Header: "ID Janji Temu"},
+			{Key: "penyakit", Header: "Penyakit", Width: 25},
+			{Key: "nama_obat", Header: "Nama Obat", Width: 25},
+			{Key: "label_obat", Header: "Label Obat", Width: 25},
+			{Key: "dosis", Header: "Dosis"},
+		},
+		Empty: "Tidak ada data detail resep.",
+	}
+	for _, d := range details {
+		res.Add(d.IDJanjiTemu, d.Penyakit, d.NamaObat, d.LabelObat, d.Dosis)
+	}
+	return e.render(res)
+}
+
+func readTopCustomers(e *env) error {
+	var patients []queries.PatientOrderCost
+	err := e.measure(func() (err error) {
+		patients, err = queries.PatientOrderCosts()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "PASIEN DENGAN BIAYA PEMESANAN OBAT TERBESAR",
+		Columns: []render.Column{
+			{Key: "email", Header: "Email Pemesan", Width: 40},
+			{Key: "total_biaya", Header: "Total Biaya"},
+		},
+		Numbered: true,
+		Empty:    "Tidak ada data pemesanan obat.",
+		Limit:    5,
+	}
+	for _, p := range patients {
+		res.Add(p.Email, p.TotalBiaya)
+	}
+	return e.render(res)
+}
+
+func readPopularServices(e *env) error {
+	var services []queries.LayananStats
+	err := e.measure(func() (err error) {
+		services, err = queries.MostOrderedServices()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "LAYANAN MEDIS YANG PALING SERING DIPESAN",
+		Columns: []render.Column{
+			{Key: "nama_layanan", Header: "Nama Layanan", Width: 45},
+			{Key: "jumlah_pesanan", Header: "Jumlah Pesanan"},
+		},
+		Numbered: true,
+		Empty:    "Tidak ada data layanan medis.",
+		Limit:    10,
+	}
+	for _, s := range services {
+		res.Add(s.NamaLayanan, s.JumlahPesanan)
+	}
+	return e.render(res)
+}
+
+func readTopHospitals(e *env) error {
+	return hospitalReport(e, queries.TopHospitalsByAppointments,
+		"RUMAH SAKIT DENGAN JUMLAH JANJI TEMU TERBANYAK",
+		render.Column{Key: "jumlah_janji_temu", Header: "Jumlah Janji Temu"})
+}
+
+func readHospitalsByStaff(e *env) error {
+	return hospitalReport(e, queries.HospitalsByMedicalStaff,
+		"RUMAH SAKIT DENGAN JUMLAH TENAGA MEDIS TERBANYAK",
+		render.Column{Key: "jumlah_tenaga_medis", Header: "Jumlah Tenaga Medis"})
+}
+
+func hospitalReport(e *env, query func() ([]queries.RumahSakitStats, error), title string, column render.Column) error {
+	var hospitals []queries.RumahSakitStats
+	err := e.measure(func() (err error) {
+		hospitals, err = query()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "nama_rumah_sakit", Header: "Nama Rumah Sakit", Width: 45},
+			column,
+		},
+		Numbered: true,
+		Empty:    "Tidak ada data rumah sakit.",
+		Limit:    10,
+	}
+	for _, h := range hospitals {
+		res.Add(h.NamaRumahSakit, h.Jumlah)
+	}
+	return e.render(res)
+}
+
+func readPatientsWithoutPrescription(e *env) error {
+	var patients []queries.PasienNoResep
+	err := e.measure(func() (err error) {
+		patients, err = queries.PatientsWithoutPrescriptions()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "PASIEN DENGAN JANJI TEMU TANPA RESEP",
+		Columns: []render.Column{
+			{Key: "nama_lengkap", Header: "Nama Lengkap", Width: 35},
+			{Key: "email", Header: "Email", Width: 30},
+			{Key: "jumlah_janji_temu", Header: "Jml Janji Temu"},
+		},
+		Numbered: true,
+		Empty:    "Tidak ada data pasien.",
+		Limit:    10,
+	}
+	for _, p := range patients {
+		res.Add(p.NamaLengkap, p.Email, p.JumlahJanjiTemu)
+	}
+	return e.render(res)
+}
+
+func readSpecialists(fs *flag.FlagSet) action {
+	profesi := fs.String("profesi", "Dokter Spesialis Anak", "profesi tenaga medis (mis. \"Dokter Umum\", \"Bidan\")")
+	kota := fs.String("kota", "Bandung", "kota rumah sakit")
+	maxRows := fs.Int("max", 50, "jumlah maksimum baris yang diambil dari Neo4j")
+	return action{
+		Check: func() error {
+			switch {
+			case strings.TrimSpace(*profesi) == "":
+				return fmt.Errorf("--profesi wajib diisi")
+			case strings.TrimSpace(*kota) == "":
+				return fmt.Errorf("--kota wajib diisi")
+			}
+			return atLeast("max", *maxRows, 1)
+		},
+		Run: func(e *env) error { return specialists(e, *profesi, *kota, *maxRows) },
+	}
+}
+
+func specialists(e *env, profesi, kota string, maxRows int) error {
+	var dokters []queries.DokterSpesialis
+	err := e.measure(func() (err error) {
+		dokters, err = queries.DokterSpesialisDiKota(profesi, kota, maxRows)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: fmt.Sprintf("SPECIAL GRAPH: Cari %s di %s", profesi, kota),
+		Columns: []render.Column{
+			{Key: "nama_dokter", Header: "Nama Dokter", Width: 30},
+			{Key: "telepon", Header: "Telepon"},
+			{Key: "departemen", Header: "Departemen", Width: 25},
+			{Key: "rumah_sakit", Header: "Rumah Sakit", Width: 30},
+			{Key: "alamat", Header: "Alamat", Width: 30},
+		},
+		Numbered: true,
+		Empty:    fmt.Sprintf("Tidak ada %s yang ditemukan di %s.", profesi, kota),
+		Notes:    []string{fmt.Sprintf("Total dokter ditemukan: %d", len(dokters))},
+	}
+	for _, d := range dokters {
+		res.Add(d.NamaDokter, d.Telepon, d.Departemen, d.RumahSakit, d.Alamat)
+	}
+	return e.render(res)
+}
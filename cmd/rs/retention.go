@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"src/render"
+	"src/retensi"
+)
+
+// ===============================================
+//   rs retention ...
+// ===============================================
+//
+// Contoh:
+//   rs retention entities                         # entitas, kolom kondisi & waktu
+//   rs retention check --policy retensi.json      # validasi berkas tanpa database
+//   rs retention run --dry-run                    # hitung data yang cocok per aturan
+//   rs retention run --rule log-aktivitas-lama --yes
+//
+// Berkas kebijakan bawaan adalah env RS_RETENSI atau retensi.json. Run
+// tanpa --dry-run lebih dulu menghitung data yang cocok (seperti --dry-run),
+// lalu meminta konfirmasi sesuai --yes/--max-affected sebelum mengubah data.
+
+var retentionGroup = &group{
+	Name:    "retention",
+	Summary: "kebijakan retensi data deklaratif (delete/archive/anonymize) untuk kedua store",
+	Commands: []*command{
+		{Name: "entities", Summary: "entitas yang bisa dipakai aturan beserta kolom kondisi dan waktunya", Setup: static(retentionEntities)},
+		{Name: "check", Summary: "validasi berkas --policy dan tampilkan aturannya", Setup: retentionCheck},
+		{Name: "run", Summary: "jalankan aturan --policy per batch dengan batas laju dan laporkan hasilnya", Stores: useBoth, Setup: retentionRun},
+	},
+}
+
+func retentionEntities(e *env) error {
+	res := render.Result{
+		Title: "RETENTION: Entitas",
+		Columns: []render.Column{
+			{Key: "entitas", Header: "Entitas"},
+			{Key: "store", Header: "Store"},
+			{Key: "kunci", Header: "Kunci"},
+			{Key: "waktu", Header: "Kolom Waktu"},
+			{Key: "kondisi", Header: "Kolom Kondisi"},
+			{Key: "anonymize", Header: "Anonymize", Width: 40},
+			{Key: "ringkasan", Header: "Ringkasan", Width: 50},
+		},
+		Notes: []string{"Kolom waktu pertama adalah bawaan umur; pilih yang lain dengan \"waktu\" pada aturan."},
+	}
+	for _, en := range retensi.Daftar {
+		res.Add(en.Nama, en.Store, strings.Join(en.Kunci, ", "), strings.Join(en.Waktu, ", "),
+			strings.Join(en.Kolom, ", "), en.Anonim, en.Ringkasan)
+	}
+	return e.render(res)
+}
+
+// bindPolicy mendaftarkan --policy dan mengembalikan pemuatnya untuk Check.
+func bindPolicy(fs *flag.FlagSet) func() (*retensi.Kebijakan, error) {
+	path := fs.String("policy", retensi.BerkasKebijakan(), "berkas kebijakan retensi JSON (env RS_RETENSI)")
+	return func() (*retensi.Kebijakan, error) { return retensi.Muat(*path) }
+}
+
+func retentionCheck(fs *flag.FlagSet) action {
+	muat := bindPolicy(fs)
+	var k *retensi.Kebijakan
+	return action{
+		Check: func() (err error) {
+			k, err = muat()
+			return err
+		},
+		Run: func(e *env) error {
+			res := render.Result{
+				Title: "RETENTION: Aturan",
+				Columns: []render.Column{
+					{Key: "aturan", Header: "Aturan"},
+					{Key: "entitas", Header: "Entitas"},
+					{Key: "aksi", Header: "Aksi"},
+					{Key: "kondisi", Header: "Kondisi", Width: 40},
+					{Key: "umur", Header: "Umur"},
+					{Key: "waktu", Header: "Kolom Waktu"},
+					{Key: "batch", Header: "Batch"},
+					{Key: "per_detik", Header: "Per Detik"},
+					{Key: "maks", Header: "Maks"},
+					{Key: "status", Header: "Status"},
+				},
+				Notes: []string{fmt.Sprintf("✓ Kebijakan valid; aksi archive menulis ke %s.", k.Arsip)},
+			}
+			for _, a := range k.Aturan {
+				batch, perDetik := k.Batch, k.PerDetik
+				if a.Batch > 0 {
+					batch = a.Batch
+				}
+				if a.PerDetik > 0 {
+					perDetik = a.PerDetik
+				}
+				status := "aktif"
+				if a.Nonaktif {
+					status = "nonaktif"
+				}
+				res.Add(a.Nama, a.Entitas, a.Aksi, kondisiText(a.Kondisi), a.Umur.String(), a.KolomWaktu(),
+					batch, orDash(perDetikText(perDetik)), orDash(maksText(a.Maks)), status)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func retentionRun(fs *flag.FlagSet) action {
+	muat := bindPolicy(fs)
+	var rules []string
+	fs.Func("rule", "hanya aturan ini, dipisah koma (termasuk yang nonaktif)", splitList(&rules))
+	g := bindGuard(fs)
+	var k *retensi.Kebijakan
+	return action{
+		Check: func() (err error) {
+			if err := g.check(); err != nil {
+				return err
+			}
+			if k, err = muat(); err != nil {
+				return err
+			}
+			_, err = k.Pilih(rules)
+			return err
+		},
+		Run: func(e *env) error {
+			now := time.Now()
+			opsi := retensi.Opsi{DryRun: true, Aturan: rules, Now: now, Contoh: g.sample}
+			var plan []retensi.Laporan
+			err := e.measure(func() (err error) {
+				plan, err = k.Jalankan(context.Background(), opsi)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			total := 0
+			for _, l := range plan {
+				total += l.Cocok
+			}
+			summary := fmt.Sprintf("%d data cocok dengan %d aturan retensi", total, len(plan))
+			if g.dryRun || total == 0 {
+				res := retentionReport("DRY-RUN: Kebijakan Retensi", plan)
+				res.Notes = append([]string{summary}, res.Notes...)
+				res.Notes = append(res.Notes, retentionSamples(plan)...)
+				if g.maxAffected > 0 && total > g.maxAffected {
+					res.Notes = append(res.Notes, fmt.Sprintf("⚠️  Melebihi --max-affected %d: eksekusi akan dibatalkan.", g.maxAffected))
+				}
+				res.Notes = append(res.Notes, "Tidak ada data yang diubah.")
+				return e.render(res)
+			}
+			if g.maxAffected > 0 && total > g.maxAffected {
+				return fmt.Errorf("%s, melebihi --max-affected %d; naikkan batasnya, pakai \"maks\" pada aturan atau periksa dengan --dry-run", summary, g.maxAffected)
+			}
+			preview := retentionReport("Kebijakan Retensi", plan)
+			preview.Notes = retentionSamples(plan)
+			if ok, err := g.confirm(e, summary, preview); !ok || err != nil {
+				return err
+			}
+
+			opsi.DryRun = false
+			opsi.Logf = e.opts.logf
+			var hasil []retensi.Laporan
+			err = e.measure(func() (err error) {
+				hasil, err = k.Jalankan(context.Background(), opsi)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := retentionReport("RETENTION: Kebijakan Retensi", hasil)
+			var failed []string
+			for _, l := range hasil {
+				if l.Err != nil {
+					failed = append(failed, l.Aturan)
+				}
+			}
+			if err := e.render(res); err != nil {
+				return err
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("aturan gagal: %s (data yang sudah diproses tetap berubah)", strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func retentionReport(title string, list []retensi.Laporan) render.Result {
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "aturan", Header: "Aturan"},
+			{Key: "entitas", Header: "Entitas"},
+			{Key: "aksi", Header: "Aksi"},
+			{Key: "batas", Header: "Lebih Tua Dari"},
+			{Key: "dipindai", Header: "Dipindai"},
+			{Key: "cocok", Header: "Cocok"},
+			{Key: "diproses", Header: "Diproses"},
+			{Key: "dilewati", Header: "Dilewati"},
+			{Key: "batch", Header: "Batch"},
+			{Key: "durasi_ms", Header: "Durasi (ms)"},
+			{Key: "status", Header: "Status", Width: 50},
+		},
+		Empty: "Tidak ada aturan aktif.",
+	}
+	for _, l := range list {
+		batas := "-"
+		if !l.Batas.IsZero() {
+			batas = l.Batas.Local().Format("2006-01-02 15:04")
+		}
+		res.Add(l.Aturan, l.Entitas, l.Aksi, batas, l.Dipindai, l.Cocok, l.Diproses, l.Dilewati,
+			l.Batch, l.Durasi.Milliseconds(), l.Status())
+	}
+	return res
+}
+
+func retentionSamples(list []retensi.Laporan) []string {
+	var notes []string
+	for _, l := range list {
+		if len(l.Contoh) == 0 {
+			continue
+		}
+		more := ""
+		if l.Cocok > len(l.Contoh) {
+			more = fmt.Sprintf(" (+%d lagi)", l.Cocok-len(l.Contoh))
+		}
+		notes = append(notes, fmt.Sprintf("%s: %s%s", l.Aturan, strings.Join(l.Contoh, ", "), more))
+	}
+	return notes
+}
+
+func kondisiText(kondisi map[string]interface{}) string {
+	if len(kondisi) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(kondisi))
+	for k := range kondisi {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if list, ok := kondisi[k].([]interface{}); ok {
+			vals := make([]string, len(list))
+			for j, v := range list {
+				vals[j] = fmt.Sprint(v)
+			}
+			parts[i] = fmt.Sprintf("%s in (%s)", k, strings.Join(vals, ", "))
+			continue
+		}
+		parts[i] = fmt.Sprintf("%s = %v", k, kondisi[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func perDetikText(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/detik", n)
+}
+
+func maksText(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/render"
+	"src/script"
+)
+
+// ===============================================
+//   rs script: jalankan file .cql / .cypher
+// ===============================================
+//
+// File dipecah menjadi statement (komentar, ; di dalam string dan statement
+// multi-baris ditangani script.Split), lalu dijalankan berurutan ke store
+// sesuai ekstensi file.
+//
+// Contoh:
+//   rs script setup.cql data.cypher
+//   rs script --var email=pasien1@mail.com --var max=55 laporan.cql
+//   rs script --vars vars.json --continue-on-error migrasi.cypher
+//   cat query.txt | rs script --store neo4j -
+//   rs script --dry-run setup.cql            # cetak statement tanpa koneksi
+
+type scriptFlags struct {
+	store           string
+	vars            map[string]string
+	params          map[string]interface{}
+	continueOnError bool
+	dryRun          bool
+	rows            bool
+	limit           int
+}
+
+// scriptStatement adalah satu statement siap jalan beserta asalnya.
+type scriptStatement struct {
+	source  string // file:baris
+	dialect script.Dialect
+	text    string
+}
+
+// scriptOutcome adalah hasil satu statement untuk ringkasan akhir.
+type scriptOutcome struct {
+	stmt    scriptStatement
+	status  string
+	rows    int
+	elapsed time.Duration
+	detail  string
+}
+
+const (
+	statusOK      = "OK"
+	statusFailed  = "GAGAL"
+	statusSkipped = "DILEWATI"
+)
+
+func scriptMain(args []string) int {
+	fs := flag.NewFlagSet("rs script", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs script [flags] FILE...\n\n"+
+			"Menjalankan statement di FILE berurutan: .cql ke Cassandra, .cypher/.cyp ke Neo4j\n"+
+			"(- = stdin, wajib dengan --store). Variabel dari --var/--vars dipakai sebagai\n"+
+			"${nama} (teks), $nama (parameter Cypher) dan :nama (parameter CQL).\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, true)
+	e := &env{opts: opts, out: os.Stdout, view: render.Options{Limit: -1}}
+	s := &scriptFlags{vars: map[string]string{}, params: map[string]interface{}{}}
+	fs.StringVar(&e.view.Format, "output", render.Table, "format hasil: "+strings.Join(render.Formats, ", ")+" (selain table/markdown hanya ringkasan)")
+	fs.StringVar(&s.store, "store", "", "store untuk semua file: cassandra atau neo4j (default dari ekstensi)")
+	fs.Func("var", "variabel nama=nilai; boleh diulang (nilai JSON, selain itu teks)", s.setVar)
+	fs.Func("vars", "file JSON berisi objek variabel {\"nama\": nilai}", s.loadVars)
+	fs.BoolVar(&s.continueOnError, "continue-on-error", false, "lanjutkan statement berikutnya bila ada yang gagal")
+	fs.BoolVar(&s.dryRun, "dry-run", false, "cetak statement setelah substitusi tanpa menjalankannya")
+	fs.BoolVar(&s.rows, "rows", true, "tampilkan baris hasil setiap statement")
+	fs.IntVar(&s.limit, "limit", 20, "baris yang ditampilkan per statement (0 = semua)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	err := opts.resolve(fs)
+	if err == nil {
+		err = e.view.Validate()
+	}
+	if err == nil {
+		err = atLeast("limit", s.limit, 0)
+	}
+	var stmts []scriptStatement
+	if err == nil {
+		stmts, err = s.load(fs.Args())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	closeOut, err := e.openOut()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeOut()
+
+	if s.dryRun {
+		printScript(e.out, stmts, s.vars)
+		return 0
+	}
+
+	var need stores
+	for _, st := range stmts {
+		need |= storeOf(st.dialect)
+	}
+	closeDB, err := opts.connect(need)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeDB()
+
+	if err := runScript(e, s, stmts); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+func (s *scriptFlags) setVar(arg string) error {
+	name, value, ok := strings.Cut(arg, "=")
+	if !ok || !paramName.MatchString(name) {
+		return fmt.Errorf("format --var harus nama=nilai")
+	}
+	s.vars[name] = value
+	s.params[name] = parseParamValue(value)
+	return nil
+}
+
+func (s *scriptFlags) loadVars(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("%s harus berisi objek JSON: %v", path, err)
+	}
+	for name, raw := range m {
+		if !paramName.MatchString(name) {
+			return fmt.Errorf("%s: nama variabel %q tidak valid", path, name)
+		}
+		// string dipakai apa adanya untuk ${nama}, nilai lain sebagai JSON
+		text := string(raw)
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			text = str
+		}
+		s.vars[name] = text
+		s.params[name] = parseParamValue(string(raw))
+	}
+	return nil
+}
+
+// load membaca dan memecah semua file. Statement terakhir tanpa ; tetap
+// diikutkan, sama seperti cqlsh -f dan cypher-shell -f.
+func (s *scriptFlags) load(paths []string) ([]scriptStatement, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("tidak ada file script (lihat rs script -h)")
+	}
+	var forced *script.Dialect
+	if s.store != "" {
+		d, err := parseStore(s.store)
+		if err != nil {
+			return nil, err
+		}
+		forced = &d
+	}
+
+	var out []scriptStatement
+	for _, path := range paths {
+		var d script.Dialect
+		switch {
+		case forced != nil:
+			d = *forced
+		case path == "-":
+			return nil, fmt.Errorf("stdin (-) butuh --store cassandra atau neo4j")
+		default:
+			var ok bool
+			if d, ok = script.DialectOf(path); !ok {
+				return nil, fmt.Errorf("%s: ekstensi tidak dikenal (.cql, .cypher, .cyp); pakai --store", path)
+			}
+		}
+
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+			path = "stdin"
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		stmts, rest := script.Split(string(data), d)
+		if rest != "" {
+			line := strings.Count(string(data), "\n") + 1 - strings.Count(strings.TrimLeft(rest, " \t\r\n"), "\n")
+			stmts = append(stmts, script.Statement{Text: strings.TrimSpace(rest), Line: line})
+		}
+		for _, st := range stmts {
+			out = append(out, scriptStatement{source: fmt.Sprintf("%s:%d", path, st.Line), dialect: d, text: st.Text})
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("tidak ada statement di %s", strings.Join(paths, ", "))
+	}
+	return out, nil
+}
+
+func parseStore(s string) (script.Dialect, error) {
+	switch s {
+	case "cassandra":
+		return script.CQL, nil
+	case "neo4j":
+		return script.Cypher, nil
+	}
+	return script.CQL, fmt.Errorf("--store harus cassandra atau neo4j, bukan %q", s)
+}
+
+func storeOf(d script.Dialect) stores {
+	if d == script.Cypher {
+		return useNeo4j
+	}
+	return useCassandra
+}
+
+func storeName(d script.Dialect) string {
+	if d == script.Cypher {
+		return "neo4j"
+	}
+	return "cassandra"
+}
+
+// printScript mencetak statement --dry-run dengan ${nama} sudah diganti;
+// parameter $nama/:nama dibiarkan karena nilainya dikirim terpisah.
+func printScript(w io.Writer, stmts []scriptStatement, vars map[string]string) {
+	for i, st := range stmts {
+		comment := "--"
+		if st.dialect == script.Cypher {
+			comment = "//"
+		}
+		fmt.Fprintf(w, "%s [%d/%d] %s (%s)\n", comment, i+1, len(stmts), st.source, storeName(st.dialect))
+		text, err := script.Expand(st.text, vars)
+		if err != nil {
+			fmt.Fprintf(w, "%s ⚠️  %v\n", comment, err)
+			text = st.text
+		}
+		fmt.Fprintf(w, "%s;\n\n", text)
+	}
+	fmt.Fprintf(w, "%d statement, tidak ada yang dijalankan (--dry-run).\n", len(stmts))
+}
+
+// ===============================================
+//   EKSEKUSI
+// ===============================================
+
+// runScript menjalankan statement berurutan. Hasil setiap statement dirender
+// langsung (table/markdown); ringkasan selalu dirender di akhir.
+func runScript(e *env, s *scriptFlags, stmts []scriptStatement) error {
+	human := e.view.Format == render.Table || e.view.Format == render.Markdown
+	var outcomes []scriptOutcome
+	failed := 0
+
+	for i, st := range stmts {
+		res, o := execStatement(st, s)
+		outcomes = append(outcomes, o)
+		if o.status == statusFailed {
+			failed++
+		}
+
+		if human {
+			res.Title = fmt.Sprintf("[%d/%d] %s (%s): %s", i+1, len(stmts), st.source, storeName(st.dialect), preview(st.text, 70))
+			switch {
+			case o.status == statusFailed:
+				res.Columns, res.Rows = nil, nil
+				res.Empty = "❌ " + o.detail
+			case o.status == statusSkipped:
+				res.Columns, res.Rows = nil, nil
+				res.Empty = "⏭️  " + o.detail
+			case !s.rows:
+				res.Columns, res.Rows = nil, nil
+				res.Empty = o.detail
+			case o.detail != "":
+				res.Notes = append(res.Notes, o.detail)
+			}
+			res.Notes = append(res.Notes, fmt.Sprintf("(%d baris, %.3f ms)", o.rows, ms(o.elapsed)))
+			if err := e.render(res); err != nil {
+				return err
+			}
+		}
+
+		if o.status == statusFailed && !s.continueOnError {
+			break
+		}
+	}
+
+	summary := render.Result{
+		Title: "Ringkasan Script",
+		Columns: []render.Column{
+			{Key: "no", Header: "No"},
+			{Key: "source", Header: "Sumber", Width: 40},
+			{Key: "store", Header: "Store"},
+			{Key: "status", Header: "Status"},
+			{Key: "rows", Header: "Baris"},
+			{Key: "ms", Header: "Waktu (ms)", Format: func(v interface{}) string { return fmt.Sprintf("%.3f", v) }},
+			{Key: "statement", Header: "Statement", Width: 50},
+			{Key: "detail", Header: "Keterangan", Width: 60},
+		},
+	}
+	var total time.Duration
+	for i, o := range outcomes {
+		total += o.elapsed
+		summary.Add(i+1, o.stmt.source, storeName(o.stmt.dialect), o.status, o.rows, ms(o.elapsed), preview(o.stmt.text, 50), o.detail)
+	}
+	summary.Notes = append(summary.Notes, fmt.Sprintf("%d dari %d statement dijalankan, %d gagal, total %.3f ms.", len(outcomes), len(stmts), failed, ms(total)))
+	if n := len(stmts) - len(outcomes); n > 0 {
+		summary.Notes = append(summary.Notes, fmt.Sprintf("%d statement tidak dijalankan karena error (pakai --continue-on-error untuk melanjutkan).", n))
+	}
+	if err := e.render(summary); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d statement gagal", failed)
+	}
+	return nil
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func preview(text string, width int) string {
+	flat := strings.Join(strings.Fields(text), " ")
+	if len(flat) > width {
+		return flat[:width-3] + "..."
+	}
+	return flat
+}
+
+var useKeyspace = regexp.MustCompile(`(?i)^USE\s+"?(\w+)"?$`)
+
+// execStatement menjalankan satu statement dan mengumpulkan barisnya.
+// Waktu mencakup eksekusi dan pengambilan semua halaman hasil.
+func execStatement(st scriptStatement, s *scriptFlags) (render.Result, scriptOutcome) {
+	o := scriptOutcome{stmt: st, status: statusOK}
+	res := render.Result{Empty: "(tanpa baris hasil)", Limit: s.limit}
+	fail := func(err error) (render.Result, scriptOutcome) {
+		o.status, o.detail = statusFailed, err.Error()
+		return res, o
+	}
+
+	text, err := script.Expand(st.text, s.vars)
+	if err != nil {
+		return fail(fmt.Errorf("%v (pakai --var)", err))
+	}
+	setColumns := func(columns []string) {
+		if res.Columns == nil {
+			for _, c := range columns {
+				res.Columns = append(res.Columns, render.Column{Key: c, Width: 60})
+			}
+		}
+	}
+	collect := func(columns []string, row []interface{}) error {
+		setColumns(columns)
+		res.Rows = append(res.Rows, row)
+		o.rows++
+		return nil
+	}
+
+	start := time.Now()
+	if st.dialect == script.Cypher {
+		var summary neo4j.Summary
+		summary, err = neo4j.StreamNeo4j(text, s.params, collect)
+		var counters []string
+		for _, c := range summary.Counters {
+			counters = append(counters, fmt.Sprintf("%s: %d", c.Name, c.Value))
+		}
+		o.detail = strings.Join(counters, ", ")
+	} else {
+		// Session gocql sudah terikat ke keyspace; USE tidak didukung driver.
+		if m := useKeyspace.FindStringSubmatch(text); m != nil {
+			if strings.EqualFold(m[1], cassandra.Keyspace) {
+				o.status, o.detail = statusSkipped, "session sudah memakai keyspace "+cassandra.Keyspace
+				return res, o
+			}
+			return fail(fmt.Errorf("USE %s tidak didukung; session memakai keyspace %s (tulis keyspace.tabel)", m[1], cassandra.Keyspace))
+		}
+		var args []interface{}
+		text, args, err = script.BindCQL(text, s.params)
+		if err != nil {
+			return fail(fmt.Errorf("%v (pakai --var)", err))
+		}
+		if n := script.Positional(text); n > len(args) {
+			return fail(fmt.Errorf("statement memakai placeholder ?; tulis sebagai :nama lalu isi dengan --var, atau pakai rs catalog show"))
+		}
+		var columns []string
+		columns, err = cassandra.StreamCassandra(text, args, 1000, nil, collect)
+		setColumns(columns)
+	}
+	o.elapsed = time.Since(start)
+	if err != nil {
+		return fail(err)
+	}
+	return res, o
+}
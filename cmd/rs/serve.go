@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"src/api"
+	"src/pembayaran"
+)
+
+// ===============================================
+//   rs serve: HTTP REST API
+// ===============================================
+//
+// Contoh:
+//   rs serve                              # http://localhost:8080/api
+//   rs serve --addr 127.0.0.1:9000 --max-limit 500
+//   RS_PAYMENT_SECRET=... rs serve --payment-lokal   # gateway pembayaran palsu
+//
+// Gateway pembayaran lokal (palsu) hanya dipasang dengan --payment-lokal
+// atau env RS_PAYMENT_LOKAL, dan server menolak start bila
+// RS_PAYMENT_SECRET kosong.
+//
+// Ctrl-C / SIGTERM menghentikan server setelah request yang sedang berjalan
+// selesai (maks --shutdown-timeout).
+
+func serveMain(args []string) int {
+	fs := flag.NewFlagSet("rs serve", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs serve [flags]\n\nREST API JSON di atas Cassandra dan Neo4j. GET /api menampilkan semua resource.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, false)
+	cfg := api.DefaultConfig
+	fs.StringVar(&cfg.Addr, "addr", cfg.Addr, "alamat listen host:port")
+	fs.IntVar(&cfg.DefaultLimit, "default-limit", cfg.DefaultLimit, "ukuran halaman list bila ?limit tidak diisi")
+	fs.IntVar(&cfg.MaxLimit, "max-limit", cfg.MaxLimit, "batas atas ?limit")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "waktu tunggu request berjalan saat server dihentikan")
+	fs.BoolVar(&cfg.AccessLog, "access-log", cfg.AccessLog, "catat setiap request ke stderr")
+	lokal := fs.Bool("payment-lokal", pembayaran.LokalDiaktifkan(),
+		"pasang gateway pembayaran lokal (palsu, untuk pengembangan); rahasia webhook dari env "+pembayaran.EnvRahasia+" (env "+pembayaran.EnvLokal+")")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: argumen tidak dikenal: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	err := opts.resolve(fs)
+	if err == nil {
+		err = atLeast("max-limit", cfg.MaxLimit, 1)
+	}
+	if err == nil {
+		err = atLeast("default-limit", cfg.DefaultLimit, 1)
+	}
+	if err == nil && cfg.DefaultLimit > cfg.MaxLimit {
+		err = fmt.Errorf("--default-limit %d melebihi --max-limit %d", cfg.DefaultLimit, cfg.MaxLimit)
+	}
+	if err == nil && *lokal {
+		_, err = pembayaran.PasangLokal(os.Getenv(pembayaran.EnvRahasia))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	closeDB, err := opts.connect(useBoth)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeDB()
+
+	cfg.Logf = opts.logf
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := api.New(cfg).Run(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"src/layanan"
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs service ...
+// ===============================================
+//
+// Contoh:
+//   rs service book --pasien pasien1@mail.com --rs RS001 --layanan L001 --jadwal "2025-01-06 09:00"
+//   rs service cancel --id PL-K7Q2M9XA
+//   rs service list --pasien pasien1@mail.com
+//   rs service schedule --rs RS001 --dari 2025-01-06 --hari 7
+
+var serviceGroup = &group{
+	Name:    "service",
+	Summary: "pemesanan layanan medis per rumah sakit dengan kapasitas harian",
+	Commands: []*command{
+		{Name: "book", Summary: "pesan --layanan di --rs untuk --pasien pada --jadwal", Stores: useBoth, Setup: serviceBook},
+		{Name: "cancel", Summary: "batalkan pemesanan layanan --id dan kembalikan kuotanya", Stores: useCassandra, Setup: serviceCancel},
+		{Name: "list", Summary: "pemesanan layanan --pasien, jadwal terbaru dulu", Stores: useCassandra, Setup: serviceList},
+		{Name: "schedule", Summary: "jadwal layanan --rs selama --hari sejak --dari", Stores: useCassandra, Setup: serviceSchedule},
+	},
+}
+
+// serviceColumns adalah kolom tabel pemesanan layanan.
+var serviceColumns = []render.Column{
+	{Key: "id_pesanan", Header: "ID Pesanan"},
+	{Key: "jadwal_pelaksanaan", Header: "Jadwal"},
+	{Key: "id_rs", Header: "ID RS"},
+	{Key: "layanan", Header: "Layanan", Width: 30},
+	{Key: "biaya_layanan", Header: "Biaya"},
+	{Key: "email_pemesan", Header: "Pasien", Width: 30},
+	{Key: "status_pemesanan", Header: "Status"},
+}
+
+// Jumlah baris default service list dan schedule; --limit menggantinya dan
+// ikut membatasi baris yang dibaca dari database.
+const (
+	serviceListLimit     = 20
+	serviceScheduleLimit = 100
+)
+
+func addService(res *render.Result, p queries.PemesananLayanan) {
+	nama := orDash(p.IdLayanan)
+	if p.NamaLayanan != "" {
+		nama += " " + p.NamaLayanan
+	}
+	res.Add(p.IdPesanan, p.JadwalPelaksanaan.In(queries.ZonaJadwal).Format("2006-01-02 15:04"), orDash(p.IdRS), nama,
+		p.BiayaLayanan, p.EmailPemesan, p.StatusPemesanan)
+}
+
+func serviceBook(fs *flag.FlagSet) action {
+	var p layanan.Permintaan
+	fs.StringVar(&p.EmailPasien, "pasien", "", "email pasien (wajib)")
+	fs.StringVar(&p.IdRS, "rs", "", "ID rumah sakit (wajib)")
+	fs.StringVar(&p.IdLayanan, "layanan", "", "ID layanan yang ditawarkan rumah sakit (wajib)")
+	jadwal := &waktuFlag{}
+	fs.Var(jadwal, "jadwal", "jadwal pelaksanaan, mis. \"2025-01-06 09:00\" (wajib)")
+	return action{
+		Check: func() error {
+			if err := requiredEmail("pasien", p.EmailPasien); err != nil {
+				return err
+			}
+			if err := required("rs", p.IdRS); err != nil {
+				return err
+			}
+			if err := required("layanan", p.IdLayanan); err != nil {
+				return err
+			}
+			p.Jadwal = jadwal.t
+			return required("jadwal", jadwal.String())
+		},
+		Run: func(e *env) error {
+			var r *queries.PemesananLayanan
+			err := e.measure(func() (err error) {
+				r, err = layanan.Pesan(p, time.Now())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "SERVICE: Layanan Dipesan", Columns: serviceColumns,
+				Notes: []string{"✓ Biaya dicatat sesuai lokasi_layanan saat ini; pesanan menunggu pembayaran."}}
+			addService(&res, *r)
+			return e.render(res)
+		},
+	}
+}
+
+func serviceCancel(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID pemesanan layanan (wajib)")
+	return action{
+		Check: func() error { return required("id", *id) },
+		Run: func(e *env) error {
+			var r *queries.PemesananLayanan
+			err := e.measure(func() (err error) {
+				r, err = layanan.Batal(*id)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "SERVICE: Layanan Dibatalkan", Columns: serviceColumns,
+				Notes: []string{"✓ Pemesanan dibatalkan dan kuota hariannya dikembalikan."}}
+			addService(&res, *r)
+			return e.render(res)
+		},
+	}
+}
+
+func serviceList(fs *flag.FlagSet) action {
+	pasien := fs.String("pasien", "", "email pasien (wajib)")
+	return action{
+		Check: func() error { return requiredEmail("pasien", *pasien) },
+		Run: func(e *env) error {
+			var list []queries.PemesananLayanan
+			err := e.measure(func() (err error) {
+				list, err = layanan.PesananPasien(*pasien, e.fetch(serviceListLimit))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{Title: "SERVICE: Pemesanan Layanan " + *pasien, Columns: serviceColumns,
+				Limit: serviceListLimit, Empty: "Pasien belum punya pemesanan layanan."}
+			for _, p := range list {
+				addService(&res, p)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func serviceSchedule(fs *flag.FlagSet) action {
+	rs := fs.String("rs", "", "ID rumah sakit (wajib)")
+	dari := fs.String("dari", "", "tanggal awal YYYY-MM-DD (default: hari ini)")
+	hari := fs.Int("hari", 7, "jumlah hari yang ditampilkan (1-62)")
+	var start time.Time
+	return action{
+		Check: func() error {
+			if err := required("rs", *rs); err != nil {
+				return err
+			}
+			now := time.Now().In(queries.ZonaJadwal)
+			start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, queries.ZonaJadwal)
+			if *dari != "" {
+				t, err := time.ParseInLocation("2006-01-02", *dari, queries.ZonaJadwal)
+				if err != nil {
+					return fmt.Errorf("--dari %q harus berformat YYYY-MM-DD", *dari)
+				}
+				start = t
+			}
+			if *hari < 1 || *hari > 62 {
+				return fmt.Errorf("--hari harus 1-62, bukan %d", *hari)
+			}
+			return nil
+		},
+		Run: func(e *env) error {
+			var list []queries.PemesananLayanan
+			err := e.measure(func() (err error) {
+				list, err = layanan.JadwalRS(*rs, start, *hari, e.fetch(serviceScheduleLimit))
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res := render.Result{
+				Title:   fmt.Sprintf("SERVICE: Jadwal Layanan %s (%d hari sejak %s)", *rs, *hari, start.Format("2006-01-02")),
+				Columns: serviceColumns,
+				Limit:   serviceScheduleLimit,
+				Empty:   "Tidak ada layanan terjadwal.",
+			}
+			for _, p := range list {
+				addService(&res, p)
+			}
+			return e.render(res)
+		},
+	}
+}
@@ -0,0 +1,635 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peterh/liner"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/render"
+	"src/script"
+)
+
+// ===============================================
+//   rs shell: REPL CQL & Cypher
+// ===============================================
+//
+// Contoh:
+//   rs shell                         # mulai di mode CQL
+//   rs shell --mode cypher --output json
+//
+// Di dalam shell:
+//   cql> SELECT id_obat, stok FROM obat
+//     -> WHERE stok < :max ALLOW FILTERING;
+//   cql> :param max => 55
+//   cql> :cypher                     # pindah mode
+//   cypher> :cql SELECT * FROM obat LIMIT 3;   # satu statement di mode lain
+//   cypher> :profile on
+//   cypher> :help
+
+const shellHelp = `Statement diakhiri ; dan boleh lebih dari satu baris. Perintah shell:
+  :cql | :cypher [statement;]   pindah mode, atau jalankan satu statement di mode itu
+  :param nama => nilai          set parameter (nilai JSON; selain itu dianggap teks)
+  :params [clear]               tampilkan atau hapus semua parameter
+  :unparam nama                 hapus satu parameter
+  :format table|json|jsonl|csv|markdown
+  :page N                       baris per halaman table/markdown (0 = tanpa paging)
+  :timing on|off                tampilkan waktu eksekusi
+  :explain on|off               (Cypher) tampilkan plan tanpa menjalankan statement
+  :profile on|off               (Cypher) jalankan dengan PROFILE dan tampilkan plan
+  :tracing on|off               (CQL) tampilkan tracing session Cassandra
+  :history [N]                  N statement terakhir (default 20)
+  :status                       mode, koneksi dan pengaturan saat ini
+  :help                         bantuan ini
+  :quit | :exit | Ctrl-D        keluar
+Parameter dipakai sebagai $nama di Cypher dan :nama di CQL.`
+
+var errStopPaging = errors.New("paging dihentikan")
+
+type shell struct {
+	mode     script.Dialect
+	params   map[string]interface{}
+	view     render.Options
+	pageSize int
+	timing   bool
+	explain  bool
+	profile  bool
+	tracing  bool
+
+	out         io.Writer
+	in          lineReader
+	interactive bool
+	history     []string
+
+	errCassandra error // nil = terhubung
+	errNeo4j     error
+}
+
+// lineReader membaca satu baris input; liner dipakai di terminal, bufio
+// bila stdin di-pipe (tanpa prompt).
+type lineReader interface {
+	Prompt(prompt string) (string, error)
+	AppendHistory(item string)
+}
+
+type plainReader struct{ s *bufio.Scanner }
+
+func (r plainReader) Prompt(string) (string, error) {
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.s.Text(), nil
+}
+
+func (plainReader) AppendHistory(string) {}
+
+func shellMain(args []string) int {
+	fs := flag.NewFlagSet("rs shell", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: rs shell [flags]\n\nREPL CQL dan Cypher. Ketik :help di dalam shell.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	opts := bindOptions(fs, true)
+	sh := &shell{params: map[string]interface{}{}, view: render.Options{Limit: -1}}
+	mode := fs.String("mode", "cql", "mode awal: cql atau cypher")
+	fs.StringVar(&sh.view.Format, "output", render.Table, "format hasil: "+strings.Join(render.Formats, ", "))
+	fs.IntVar(&sh.pageSize, "page-size", 50, "baris per halaman table/markdown (0 = tanpa paging)")
+	fs.BoolVar(&sh.timing, "timing", true, "tampilkan waktu eksekusi setiap statement")
+	history := fs.String("history", defaultHistoryFile(), "file riwayat statement (kosong = tanpa riwayat)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: argumen tidak dikenal: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	err := opts.resolve(fs)
+	if err == nil {
+		err = sh.view.Validate()
+	}
+	if err == nil {
+		sh.mode, err = parseDialect(*mode)
+	}
+	if err == nil {
+		err = atLeast("page-size", sh.pageSize, 0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+
+	e := &env{opts: opts, out: os.Stdout}
+	closeOut, err := e.openOut()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeOut()
+	sh.out = e.out
+
+	// Shell tetap berguna bila hanya satu store yang hidup.
+	closeCassandra, err := opts.connect(useCassandra)
+	if sh.errCassandra = err; err == nil {
+		defer closeCassandra()
+	} else {
+		fmt.Fprintln(os.Stderr, "⚠️ ", err)
+	}
+	closeNeo4j, err := opts.connect(useNeo4j)
+	if sh.errNeo4j = err; err == nil {
+		defer closeNeo4j()
+	} else {
+		fmt.Fprintln(os.Stderr, "⚠️ ", err)
+	}
+
+	sh.interactive = interactive(os.Stdin) && liner.TerminalSupported()
+	if sh.interactive {
+		ln := liner.NewLiner()
+		defer ln.Close()
+		ln.SetCtrlCAborts(true)
+		ln.SetMultiLineMode(true)
+		if *history != "" {
+			if f, err := os.Open(*history); err == nil {
+				ln.ReadHistory(f)
+				f.Close()
+			}
+			defer func() {
+				if f, err := os.Create(*history); err == nil {
+					ln.WriteHistory(f)
+					f.Close()
+				}
+			}()
+		}
+		sh.in = ln
+		fmt.Fprintln(os.Stderr, "rs shell - ketik :help untuk bantuan, :quit untuk keluar")
+	} else {
+		sc := bufio.NewScanner(os.Stdin)
+		sc.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+		sh.in = plainReader{sc}
+	}
+
+	if err := sh.loop(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".rs_history")
+}
+
+func parseDialect(s string) (script.Dialect, error) {
+	switch strings.ToLower(s) {
+	case "cql":
+		return script.CQL, nil
+	case "cypher":
+		return script.Cypher, nil
+	}
+	return script.CQL, fmt.Errorf("mode %q tidak dikenal (cql atau cypher)", s)
+}
+
+// ===============================================
+//   LOOP
+// ===============================================
+
+// loop membaca input sampai EOF atau :quit. Error statement dicetak dan
+// shell berlanjut; hanya error membaca input yang menghentikan loop.
+func (sh *shell) loop() error {
+	var buf strings.Builder
+	for {
+		prompt := sh.mode.String() + "> "
+		if buf.Len() > 0 {
+			prompt = strings.Repeat(" ", len(prompt)-3) + "-> "
+		}
+		line, err := sh.in.Prompt(prompt)
+		if errors.Is(err, liner.ErrPromptAborted) {
+			buf.Reset()
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			if rest := strings.TrimSpace(buf.String()); rest != "" {
+				// statement terakhir tanpa ; tetap dijalankan
+				sh.run(sh.mode, rest)
+			}
+			if sh.interactive {
+				fmt.Fprintln(os.Stderr)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if buf.Len() == 0 && strings.HasPrefix(strings.TrimSpace(line), ":") {
+			sh.in.AppendHistory(strings.TrimSpace(line))
+			if quit := sh.meta(strings.TrimSpace(line)); quit {
+				return nil
+			}
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		stmts, rest := script.Split(buf.String(), sh.mode)
+		for _, st := range stmts {
+			sh.remember(st.Text + ";")
+			sh.run(sh.mode, st.Text)
+		}
+		buf.Reset()
+		buf.WriteString(rest)
+	}
+}
+
+func (sh *shell) remember(stmt string) {
+	flat := strings.Join(strings.Fields(stmt), " ")
+	sh.history = append(sh.history, flat)
+	sh.in.AppendHistory(flat)
+}
+
+// meta menjalankan perintah shell (:...). Mengembalikan true untuk keluar.
+func (sh *shell) meta(line string) bool {
+	cmd, arg, _ := strings.Cut(line[1:], " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(cmd) {
+	case "quit", "exit", "q":
+		return true
+	case "help", "h", "?":
+		fmt.Fprintln(sh.out, shellHelp)
+	case "cql", "cypher":
+		d, _ := parseDialect(cmd)
+		if arg == "" {
+			sh.mode = d
+			return false
+		}
+		stmts, rest := script.Split(arg, d)
+		if rest != "" {
+			stmts = append(stmts, script.Statement{Text: strings.TrimSpace(rest)})
+		}
+		for _, st := range stmts {
+			sh.remember(":" + cmd + " " + st.Text + ";")
+			sh.run(d, st.Text)
+		}
+	case "param":
+		name, value, ok := strings.Cut(arg, "=>")
+		name = strings.TrimSpace(name)
+		if !ok || !paramName.MatchString(name) {
+			sh.errorf("format: :param nama => nilai")
+			return false
+		}
+		sh.params[name] = parseParamValue(strings.TrimSpace(value))
+	case "params":
+		if arg == "clear" {
+			sh.params = map[string]interface{}{}
+			return false
+		}
+		sh.printParams()
+	case "unparam":
+		delete(sh.params, arg)
+	case "format":
+		view := sh.view
+		view.Format = arg
+		if err := view.Validate(); err != nil {
+			sh.errorf("%v", err)
+			return false
+		}
+		sh.view = view
+	case "page":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			sh.errorf("format: :page N (N >= 0)")
+			return false
+		}
+		sh.pageSize = n
+	case "timing", "explain", "profile", "tracing":
+		on, err := parseToggle(arg)
+		if err != nil {
+			sh.errorf("format: :%s on|off", cmd)
+			return false
+		}
+		switch cmd {
+		case "timing":
+			sh.timing = on
+		case "explain":
+			sh.explain = on
+			if on {
+				sh.profile = false
+			}
+		case "profile":
+			sh.profile = on
+			if on {
+				sh.explain = false
+			}
+		case "tracing":
+			sh.tracing = on
+		}
+	case "history":
+		n := 20
+		if arg != "" {
+			if v, err := strconv.Atoi(arg); err == nil && v > 0 {
+				n = v
+			}
+		}
+		start := max(len(sh.history)-n, 0)
+		for i := start; i < len(sh.history); i++ {
+			fmt.Fprintf(sh.out, "%4d  %s\n", i+1, sh.history[i])
+		}
+	case "status":
+		sh.printStatus()
+	default:
+		sh.errorf("perintah :%s tidak dikenal (ketik :help)", cmd)
+	}
+	return false
+}
+
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseParamValue membaca nilai JSON (angka bulat menjadi int64); selain
+// JSON nilai dipakai apa adanya sebagai teks.
+func parseParamValue(s string) interface{} {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return s
+	}
+	return normalizeJSON(v)
+}
+
+func normalizeJSON(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeJSON(t[i])
+		}
+	case map[string]interface{}:
+		for k := range t {
+			t[k] = normalizeJSON(t[k])
+		}
+	}
+	return v
+}
+
+func parseToggle(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "true", "1":
+		return true, nil
+	case "off", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("nilai %q bukan on/off", s)
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+func (sh *shell) errorf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+}
+
+func (sh *shell) printParams() {
+	if len(sh.params) == 0 {
+		fmt.Fprintln(sh.out, "(belum ada parameter)")
+		return
+	}
+	names := make([]string, 0, len(sh.params))
+	for name := range sh.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, _ := json.Marshal(sh.params[name])
+		fmt.Fprintf(sh.out, "  %s => %s\n", name, v)
+	}
+}
+
+func (sh *shell) printStatus() {
+	conn := func(err error) string {
+		if err != nil {
+			return "tidak terhubung (" + err.Error() + ")"
+		}
+		return "terhubung"
+	}
+	fmt.Fprintf(sh.out, "mode      : %s\n", sh.mode)
+	fmt.Fprintf(sh.out, "cassandra : %s\n", conn(sh.errCassandra))
+	fmt.Fprintf(sh.out, "neo4j     : %s\n", conn(sh.errNeo4j))
+	fmt.Fprintf(sh.out, "format    : %s, page %d, timing %s\n", sh.view.Format, sh.pageSize, onOff(sh.timing))
+	fmt.Fprintf(sh.out, "cypher    : explain %s, profile %s\n", onOff(sh.explain), onOff(sh.profile))
+	fmt.Fprintf(sh.out, "cql       : tracing %s\n", onOff(sh.tracing))
+	fmt.Fprintf(sh.out, "parameter : %d\n", len(sh.params))
+}
+
+// ===============================================
+//   EKSEKUSI & PAGING
+// ===============================================
+
+// pager mengumpulkan baris dan menampilkannya per halaman. Pada format
+// mesin (json/jsonl/csv) atau input non-interaktif semua baris ditampilkan
+// sekaligus agar output tetap satu dokumen.
+type pager struct {
+	sh      *shell
+	columns []string
+	rows    [][]interface{}
+	total   int
+	waited  time.Duration // waktu menunggu pengguna, tidak dihitung timing
+}
+
+func (p *pager) paged() bool {
+	f := p.sh.view.Format
+	return p.sh.interactive && p.sh.pageSize > 0 && (f == render.Table || f == render.Markdown)
+}
+
+func (p *pager) add(columns []string, row []interface{}) error {
+	p.columns = columns
+	p.rows = append(p.rows, row)
+	p.total++
+	if !p.paged() || len(p.rows) < p.sh.pageSize {
+		return nil
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	answer, err := p.sh.in.Prompt(fmt.Sprintf("-- %d baris ditampilkan; Enter = lanjut, q = berhenti -- ", p.total))
+	p.waited += time.Since(start)
+	if err != nil || strings.EqualFold(strings.TrimSpace(answer), "q") {
+		return errStopPaging
+	}
+	return nil
+}
+
+func (p *pager) flush() error {
+	if len(p.rows) == 0 && (p.total > 0 || len(p.columns) == 0) {
+		return nil
+	}
+	res := render.Result{Empty: "(0 baris)"}
+	for _, c := range p.columns {
+		res.Columns = append(res.Columns, render.Column{Key: c, Width: 60})
+	}
+	res.Rows = p.rows
+	p.rows = nil
+	return render.Render(p.sh.out, res, p.sh.view)
+}
+
+func (sh *shell) run(d script.Dialect, stmt string) {
+	p := &pager{sh: sh}
+	start := time.Now()
+	var err error
+	var footer []string
+
+	if d == script.Cypher {
+		footer, err = sh.runCypher(stmt, p)
+	} else {
+		footer, err = sh.runCQL(stmt, p)
+	}
+	elapsed := time.Since(start) - p.waited
+
+	stopped := errors.Is(err, errStopPaging)
+	if err != nil && !stopped {
+		sh.errorf("%v", err)
+		return
+	}
+	if !stopped {
+		if err := p.flush(); err != nil {
+			sh.errorf("%v", err)
+			return
+		}
+	}
+
+	for _, line := range footer {
+		fmt.Fprintln(sh.out, line)
+	}
+	if sh.timing && (sh.view.Format == render.Table || sh.view.Format == render.Markdown) {
+		note := fmt.Sprintf("(%d baris, %.3f ms)", p.total, float64(elapsed.Microseconds())/1000)
+		if stopped {
+			note = fmt.Sprintf("(paging dihentikan setelah %d baris, %.3f ms)", p.total, float64(elapsed.Microseconds())/1000)
+		}
+		fmt.Fprintln(sh.out, note)
+	}
+}
+
+var cypherPrefix = regexp.MustCompile(`(?i)^\s*(EXPLAIN|PROFILE)\b`)
+
+func (sh *shell) runCypher(stmt string, p *pager) ([]string, error) {
+	if sh.errNeo4j != nil {
+		return nil, fmt.Errorf("Neo4j tidak terhubung: %v", sh.errNeo4j)
+	}
+	if !cypherPrefix.MatchString(stmt) {
+		if sh.explain {
+			stmt = "EXPLAIN " + stmt
+		} else if sh.profile {
+			stmt = "PROFILE " + stmt
+		}
+	}
+
+	summary, err := neo4j.StreamNeo4j(stmt, sh.params, p.add)
+	if err != nil {
+		return nil, err
+	}
+
+	var footer []string
+	for _, c := range summary.Counters {
+		footer = append(footer, fmt.Sprintf("%s: %d", c.Name, c.Value))
+	}
+	if summary.Plan != nil {
+		footer = append(footer, planLines(summary.Plan, summary.Profiled, 0)...)
+	}
+	return footer, nil
+}
+
+func (sh *shell) runCQL(stmt string, p *pager) ([]string, error) {
+	if sh.errCassandra != nil {
+		return nil, fmt.Errorf("Cassandra tidak terhubung: %v", sh.errCassandra)
+	}
+	query, args, err := script.BindCQL(stmt, sh.params)
+	if err != nil {
+		return nil, fmt.Errorf("%v (pakai :param nama => nilai)", err)
+	}
+
+	var trace io.Writer
+	var traced strings.Builder
+	if sh.tracing {
+		trace = &traced
+	}
+
+	fetch := sh.pageSize
+	if fetch <= 0 {
+		fetch = 5000
+	}
+	columns, err := cassandra.StreamCassandra(query, args, fetch, trace, p.add)
+	if p.columns == nil {
+		p.columns = columns
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var footer []string
+	if traced.Len() > 0 {
+		footer = append(footer, strings.Split(strings.TrimRight(traced.String(), "\n"), "\n")...)
+	}
+	return footer, nil
+}
+
+// planLines menulis plan EXPLAIN/PROFILE sebagai pohon berindentasi.
+func planLines(p *neo4j.Plan, profiled bool, depth int) []string {
+	line := strings.Repeat("  ", depth) + "+ " + p.Operator
+	if details, ok := p.Arguments["Details"].(string); ok && details != "" {
+		line += "  " + details
+	}
+	if est, ok := p.Arguments["EstimatedRows"].(float64); ok {
+		line += fmt.Sprintf("  est=%.0f", est)
+	}
+	if profiled {
+		line += fmt.Sprintf("  rows=%d dbHits=%d", p.Rows, p.DbHits)
+	}
+
+	lines := []string{line}
+	if depth == 0 {
+		title := "Plan (EXPLAIN):"
+		if profiled {
+			title = "Plan (PROFILE):"
+		}
+		lines = append([]string{title}, lines...)
+	}
+	for _, child := range p.Children {
+		lines = append(lines, planLines(child, profiled, depth+1)...)
+	}
+	return lines
+}
@@ -0,0 +1,626 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"src/apotek"
+	"src/layanan"
+	"src/queries"
+	"src/render"
+)
+
+// ===============================================
+//   rs insert ...
+// ===============================================
+
+var insertGroup = &group{
+	Name:    "insert",
+	Summary: "tambah data master ke Neo4j",
+	Commands: []*command{
+		{Name: "patient", Summary: "tambah node Pasien dari flag atau --file JSON", Stores: useNeo4j, Setup: insertPatient},
+		{Name: "registered-patient", Summary: "beri label :PasienTerdaftar pada pasien bernama --nama", Stores: useNeo4j, Setup: insertRegisteredPatient},
+		{Name: "hospital", Summary: "tambah node RumahSakit dari flag atau --file JSON", Stores: useNeo4j, Setup: insertHospital},
+		{Name: "department", Summary: "tambah Departemen pada rumah sakit dari flag atau --file JSON", Stores: useNeo4j, Setup: insertDepartment},
+	},
+}
+
+// insertEach menjalankan insert untuk setiap record dan berhenti pada
+// kegagalan pertama; record sebelumnya sudah tersimpan.
+func insertEach[T any](e *env, records []T, insert func(T) (*T, error)) ([]*T, error) {
+	var saved []*T
+	err := e.measure(func() error {
+		for i, r := range records {
+			out, err := insert(r)
+			if err != nil {
+				if len(records) > 1 {
+					return fmt.Errorf("record #%d: %v (%d record sudah ditambahkan)", i+1, err, len(saved))
+				}
+				return err
+			}
+			saved = append(saved, out)
+		}
+		return nil
+	})
+	return saved, err
+}
+
+var pasienFields = []recordField[queries.Pasien]{
+	{"email", "email pasien (wajib)", func(p *queries.Pasien) *string { return &p.Email }},
+	{"nik", "NIK 16 digit (opsional, harus cocok dengan tanggal lahir)", func(p *queries.Pasien) *string { return &p.NIK }},
+	{"kata-sandi", "kata sandi (wajib)", func(p *queries.Pasien) *string { return &p.KataSandi }},
+	{"nama", "nama lengkap (wajib)", func(p *queries.Pasien) *string { return &p.NamaLengkap }},
+	{"jenis-kelamin", "jenis kelamin: L atau P", func(p *queries.Pasien) *string { return &p.JenisKelamin }},
+	{"tanggal-lahir", "tanggal lahir YYYY-MM-DD (wajib)", func(p *queries.Pasien) *string { return &p.TanggalLahir }},
+	{"telepon", "nomor telepon", func(p *queries.Pasien) *string { return &p.NomorTelepon }},
+	{"provinsi", "provinsi", func(p *queries.Pasien) *string { return &p.Provinsi }},
+	{"kota", "kota", func(p *queries.Pasien) *string { return &p.Kota }},
+	{"kecamatan", "kecamatan", func(p *queries.Pasien) *string { return &p.Kecamatan }},
+	{"jalan", "alamat jalan", func(p *queries.Pasien) *string { return &p.Jalan }},
+}
+
+func insertPatient(fs *flag.FlagSet) action {
+	load := bindRecords(fs, pasienFields)
+	var records []queries.Pasien
+	return action{
+		Check: func() (err error) {
+			records, err = load()
+			return err
+		},
+		Run: func(e *env) error {
+			saved, err := insertEach(e, records, queries.InsertPasien)
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: "INSERT: Menambahkan Pasien Baru",
+				Columns: []render.Column{
+					{Key: "email", Header: "Email"},
+					{Key: "nama_lengkap", Header: "Nama Lengkap"},
+				},
+				Notes: []string{fmt.Sprintf("✓ %d pasien berhasil ditambahkan!", len(saved))},
+			}
+			for _, p := range saved {
+				res.Add(p.Email, p.NamaLengkap)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+func insertRegisteredPatient(fs *flag.FlagSet) action {
+	nama := fs.String("nama", "", "nama lengkap pasien yang sudah ada (wajib)")
+	return action{
+		Check: func() error {
+			if strings.TrimSpace(*nama) == "" {
+				return fmt.Errorf("--nama wajib diisi")
+			}
+			return nil
+		},
+		Run: func(e *env) error {
+			var email string
+			err := e.measure(func() (err error) {
+				email, err = queries.DaftarkanPasien(*nama)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title:   "INSERT: Tambah Pasien Berdasarkan Nama dari User",
+				Columns: []render.Column{{Key: "email", Header: "Email Pasien"}},
+				Notes: []string{
+					"✓ Pasien berhasil ditambahkan berdasarkan user yang ada!",
+					"   (Di Neo4j: menambahkan label :PasienTerdaftar pada node yang sudah ada)",
+				},
+			}
+			res.Add(email)
+			return e.render(res)
+		},
+	}
+}
+
+var rumahSakitFields = []recordField[queries.RumahSakit]{
+	{"id-rs", "ID rumah sakit, mis. RS101 (wajib)", func(r *queries.RumahSakit) *string { return &r.IdRS }},
+	{"email", "email rumah sakit", func(r *queries.RumahSakit) *string { return &r.Email }},
+	{"nama", "nama rumah sakit (wajib)", func(r *queries.RumahSakit) *string { return &r.NamaRumahSakit }},
+	{"telepon", "nomor telepon", func(r *queries.RumahSakit) *string { return &r.NoTelepon }},
+	{"provinsi", "provinsi", func(r *queries.RumahSakit) *string { return &r.Provinsi }},
+	{"kota", "kota (wajib)", func(r *queries.RumahSakit) *string { return &r.Kota }},
+	{"kecamatan", "kecamatan", func(r *queries.RumahSakit) *string { return &r.Kecamatan }},
+	{"jalan", "alamat jalan", func(r *queries.RumahSakit) *string { return &r.Jalan }},
+}
+
+func insertHospital(fs *flag.FlagSet) action {
+	load := bindRecords(fs, rumahSakitFields)
+	var records []queries.RumahSakit
+	return action{
+		Check: func() (err error) {
+			records, err = load()
+			return err
+		},
+		Run: func(e *env) error {
+			saved, err := insertEach(e, records, queries.InsertRumahSakit)
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: "INSERT: Menambah Rumah Sakit Baru",
+				Columns: []render.Column{
+					{Key: "id_rs", Header: "ID RS"},
+					{Key: "nama_rumah_sakit", Header: "Nama Rumah Sakit"},
+				},
+				Notes: []string{fmt.Sprintf("✓ %d rumah sakit berhasil ditambahkan!", len(saved))},
+			}
+			for _, rs := range saved {
+				res.Add(rs.IdRS, rs.NamaRumahSakit)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+var departemenFields = []recordField[queries.DepartemenRS]{
+	{"nama", "nama departemen (wajib)", func(d *queries.DepartemenRS) *string { return &d.NamaDepartemen }},
+	{"gedung", "gedung", func(d *queries.DepartemenRS) *string { return &d.Gedung }},
+	{"id-rs", "ID rumah sakit tujuan", func(d *queries.DepartemenRS) *string { return &d.IdRS }},
+	{"rumah-sakit", "nama rumah sakit tujuan (dipakai bila --id-rs kosong)", func(d *queries.DepartemenRS) *string { return &d.RumahSakit }},
+}
+
+func insertDepartment(fs *flag.FlagSet) action {
+	load := bindRecords(fs, departemenFields)
+	var records []queries.DepartemenRS
+	return action{
+		Check: func() (err error) {
+			records, err = load()
+			return err
+		},
+		Run: func(e *env) error {
+			saved, err := insertEach(e, records, queries.InsertDepartemen)
+			if err != nil {
+				return err
+			}
+
+			res := render.Result{
+				Title: "INSERT: Menambah Departemen pada RS",
+				Columns: []render.Column{
+					{Key: "nama_departemen", Header: "Nama Departemen"},
+					{Key: "gedung", Header: "Gedung"},
+					{Key: "rumah_sakit", Header: "Rumah Sakit"},
+					{Key: "id_rs", Header: "ID RS"},
+				},
+				Notes: []string{fmt.Sprintf("✓ %d departemen berhasil ditambahkan!", len(saved))},
+			}
+			for _, d := range saved {
+				res.Add(d.NamaDepartemen, d.Gedung, d.RumahSakit, d.IdRS)
+			}
+			return e.render(res)
+		},
+	}
+}
+
+// ===============================================
+//   rs update ...
+// ===============================================
+
+var updateGroup = &group{
+	Name:    "update",
+	Summary: "ubah data yang sudah ada",
+	Commands: []*command{
+		{Name: "expire-orders", Summary: "batalkan pesanan obat tertua yang belum dibayar lebih lama dari --older-than", Stores: useCassandra, Setup: updateExpireOrders},
+		{Name: "transfer-staff", Summary: "pindahtugaskan tenaga medis ke --departemen", Stores: useNeo4j, Setup: updateTransferStaff},
+		{Name: "cancel-service-order", Summary: "batalkan pemesanan layanan --id (default: satu yang masih aktif)", Stores: useCassandra, Setup: updateCancelServiceOrder},
+	},
+}
+
+func updateExpireOrders(fs *flag.FlagSet) action {
+	olderThan := mustAge("2d")
+	fs.Var(olderThan, "older-than", "umur minimum pesanan 'belum dibayar' (mis. 48h, 2d, 1w)")
+	maxOrders := fs.Int("max", 5, "jumlah maksimum pesanan yang dibatalkan (0 = semua)")
+	g := bindGuard(fs)
+	return action{
+		Check: func() error {
+			if err := olderThan.positive("older-than"); err != nil {
+				return err
+			}
+			if err := atLeast("max", *maxOrders, 0); err != nil {
+				return err
+			}
+			return g.check()
+		},
+		Run: func(e *env) error { return expireOrders(e, g, olderThan, *maxOrders) },
+	}
+}
+
+func expireOrders(e *env, g *guard, olderThan *age, maxOrders int) error {
+	orders, err := queries.ExpiredOrders(olderThan.Before(time.Now()), maxOrders)
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("UPDATE: Batalkan Pesanan Obat Tertua yang Expired (belum dibayar > %s)", olderThan)
+	p := plan{
+		Title:  title,
+		Action: "pesanan obat akan diubah menjadi 'dibatalkan'",
+		Preview: render.Result{Columns: []render.Column{
+			{Key: "id_pesanan", Header: "ID Pesanan"},
+			{Key: "waktu_pemesanan", Header: "Waktu Pemesanan"},
+			{Key: "status_pemesanan", Header: "Status"},
+		}},
+	}
+	for _, order := range orders {
+		p.Preview.Add(order.IdPesanan, order.WaktuPemesanan, order.StatusPemesanan)
+	}
+	if ok, err := g.approve(e, p); !ok || err != nil {
+		return err
+	}
+
+	var hasil []apotek.Batal
+	e.measure(func() error {
+		hasil, _ = apotek.BatalkanExpired(context.Background(), orders)
+		return nil
+	})
+	statusBaru := map[string]string{}
+	var updated int
+	for _, b := range hasil {
+		if b.Applied {
+			updated++
+			statusBaru[b.IdPesanan] = b.Status
+		} else {
+			statusBaru[b.IdPesanan] = b.Status + " (dilewati)"
+		}
+	}
+
+	limit := "tanpa LIMIT"
+	if maxOrders > 0 {
+		limit = fmt.Sprintf("LIMIT %d", maxOrders)
+	}
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "id_pesanan", Header: "ID Pesanan"},
+			{Key: "waktu_pemesanan", Header: "Waktu Pemesanan"},
+			{Key: "status_lama", Header: "Status Lama"},
+			{Key: "status_baru", Header: "Status Baru"},
+		},
+		Empty: "Tidak ada pesanan yang expired.",
+		Notes: []string{
+			fmt.Sprintf("Total pesanan expired yang diupdate: %d dari %d (%s)", updated, len(orders), limit),
+			"Stok obat pesanan yang dibatalkan dikembalikan. Pesanan yang statusnya berubah sejak dibaca (mis. baru dibayar) dilewati.",
+			"Pesanan yang sudah masuk tagihan yang belum dibatalkan tidak ikut dibatalkan; statusnya mengikuti pembayaran tagihan.",
+			"",
+			"⚠️  LIMITATION CASSANDRA:",
+			"   - Tidak support time-based filtering (NOW() - INTERVAL) di WHERE clause",
+			"   - Tidak support LIMIT di UPDATE statement",
+			"   - Tidak support ORDER BY di query UPDATE",
+			"   - Harus: SELECT → filter & sort di aplikasi → UPDATE satu per satu",
+		},
+	}
+	for _, order := range orders {
+		baru, ok := statusBaru[order.IdPesanan]
+		if !ok {
+			baru = "gagal (lihat log)"
+		}
+		res.Add(order.IdPesanan, order.WaktuPemesanan, order.StatusPemesanan, baru)
+	}
+	return e.render(res)
+}
+
+func updateTransferStaff(fs *flag.FlagSet) action {
+	email := fs.String("email", "", "email tenaga medis (default: tenaga medis pertama yang ditemukan)")
+	departemen := fs.String("departemen", "", "nama departemen tujuan (wajib; dibuat bila belum ada)")
+	return action{
+		Check: func() error {
+			if strings.TrimSpace(*departemen) == "" {
+				return fmt.Errorf("--departemen wajib diisi")
+			}
+			if *email != "" {
+				return queries.ValidEmail(*email)
+			}
+			return nil
+		},
+		Run: func(e *env) error { return transferStaff(e, *email, *departemen) },
+	}
+}
+
+func transferStaff(e *env, email, departemenBaru string) error {
+	before, err := queries.DepartemenTenagaMedis(email)
+	if err != nil {
+		return err
+	}
+	email = fmt.Sprintf("%v", before["email"])
+
+	if err := e.measure(func() error { return queries.PindahTenagaMedis(email, departemenBaru) }); err != nil {
+		return fmt.Errorf("gagal memindahkan tenaga medis: %v", err)
+	}
+
+	after, err := queries.DepartemenTenagaMedis(email)
+	if err != nil {
+		return fmt.Errorf("gagal membaca data setelah pindah: %v", err)
+	}
+
+	res := render.Result{
+		Title: "UPDATE: Pindahtugaskan Tenaga Medis",
+		Columns: []render.Column{
+			{Key: "email", Header: "Email"},
+			{Key: "departemen_lama", Header: "Departemen Lama"},
+			{Key: "departemen_baru", Header: "Departemen Baru"},
+		},
+	}
+	res.Add(email, before["departemen"], after["departemen"])
+	return e.render(res)
+}
+
+func updateCancelServiceOrder(fs *flag.FlagSet) action {
+	id := fs.String("id", "", "ID pemesanan layanan (default: satu pemesanan yang belum dibatalkan)")
+	g := bindGuard(fs)
+	return action{
+		Check: g.check,
+		Run:   func(e *env) error { return cancelServiceOrder(e, g, *id) },
+	}
+}
+
+func cancelServiceOrder(e *env, g *guard, idPesanan string) error {
+	var status string
+	var err error
+	if idPesanan == "" {
+		idPesanan, status, err = queries.PemesananLayananAktif()
+	} else if status, err = queries.StatusPemesananLayanan(idPesanan); err == nil && status == "dibatalkan" {
+		err = fmt.Errorf("pemesanan layanan %s sudah dibatalkan", idPesanan)
+	}
+	if err != nil {
+		return err
+	}
+
+	res := render.Result{
+		Title: "UPDATE: Batalkan Pemesanan Layanan",
+		Columns: []render.Column{
+			{Key: "id_pesanan", Header: "ID Pesanan"},
+			{Key: "status_lama", Header: "Status Lama"},
+			{Key: "status_baru", Header: "Status Baru"},
+		},
+		Empty: "Tidak ditemukan pemesanan layanan yang belum dibatalkan. Tidak ada yang diubah.",
+	}
+	if idPesanan == "" && !g.dryRun {
+		return e.render(res)
+	}
+
+	p := plan{
+		Title:  res.Title,
+		Action: "pemesanan layanan akan diubah menjadi 'dibatalkan'",
+		Preview: render.Result{Columns: []render.Column{
+			{Key: "id_pesanan", Header: "ID Pesanan"},
+			{Key: "status_pemesanan", Header: "Status"},
+		}},
+	}
+	if idPesanan != "" {
+		p.Preview.Add(idPesanan, status)
+	}
+	if ok, err := g.approve(e, p); !ok || err != nil {
+		return err
+	}
+
+	var batal *queries.PemesananLayanan
+	if err := e.measure(func() (err error) {
+		batal, err = layanan.Batal(idPesanan)
+		return err
+	}); err != nil {
+		return err
+	}
+	res.Add(idPesanan, status, batal.StatusPemesanan)
+	return e.render(res)
+}
+
+// ===============================================
+//   rs delete ...
+// ===============================================
+
+var deleteGroup = &group{
+	Name:    "delete",
+	Summary: "hapus data yang sudah tidak diperlukan",
+	Commands: []*command{
+		{Name: "cancelled-orders", Summary: "hapus semua pemesanan obat yang dibatalkan", Stores: useCassandra, Setup: deleteCancelledOrders},
+		{Name: "old-logs", Summary: "hapus log aktivitas Baymin yang lebih tua dari --older-than (default 6mo)", Stores: useCassandra, Setup: deleteOldLogs},
+		{Name: "stale-appointments", Summary: "hapus janji temu lebih tua dari --older-than (default 30d) tanpa resep", Stores: useNeo4j, Setup: deleteStaleAppointments},
+	},
+}
+
+// deleteReport adalah laporan seragam perintah delete.
+func deleteReport(title, target string, deleted, remaining int) render.Result {
+	res := render.Result{
+		Title: title,
+		Columns: []render.Column{
+			{Key: "target", Header: "Target"},
+			{Key: "dihapus", Header: "Dihapus"},
+			{Key: "sisa", Header: "Sisa"},
+		},
+	}
+	res.Add(target, deleted, remaining)
+	return res
+}
+
+func deleteCancelledOrders(fs *flag.FlagSet) action {
+	g := bindGuard(fs)
+	a := bindArchive(fs)
+	return action{Check: g.check, Run: func(e *env) error { return cancelledOrders(e, g, a) }}
+}
+
+func cancelledOrders(e *env, g *guard, a *archiver) error {
+	orders, err := queries.PemesananObatDibatalkan()
+	if err != nil {
+		return err
+	}
+
+	const title = "DELETE: Pemesanan Obat yang Dibatalkan"
+	p := plan{
+		Title:  title,
+		Action: "baris pemesanan_obat akan dihapus",
+		Preview: render.Result{Columns: []render.Column{
+			{Key: "id_pesanan", Header: "ID Pesanan"},
+			{Key: "email_pemesan", Header: "Email Pemesan", Width: 30},
+			{Key: "waktu_pemesanan", Header: "Waktu Pemesanan"},
+		}},
+	}
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		ids[i] = o.IdPesanan
+		p.Preview.Add(o.IdPesanan, o.EmailPemesan, o.WaktuPemesanan)
+	}
+	if ok, err := g.approve(e, p); !ok || err != nil {
+		return err
+	}
+
+	var archived, deleted int
+	err = e.measure(func() (err error) {
+		keys := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
+			keys[i] = map[string]interface{}{"id_pesanan": id}
+		}
+		if archived, err = a.save("pemesanan_obat", "delete cancelled-orders", keys); err != nil {
+			return err
+		}
+		deleted, err = queries.HapusPemesananObat(ids)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("gagal hapus pesanan dibatalkan (%d terhapus): %v", deleted, err)
+	}
+
+	after, err := queries.PemesananObatDibatalkan()
+	if err != nil {
+		return err
+	}
+	res := deleteReport(title, "pemesanan_obat (status dibatalkan)", deleted, len(after))
+	res.Notes = append(res.Notes, a.note("pemesanan_obat", archived))
+	return e.render(res)
+}
+
+func deleteOldLogs(fs *flag.FlagSet) action {
+	olderThan := mustAge("6mo")
+	fs.Var(olderThan, "older-than", "umur minimum log yang dihapus (mis. 90d, 6mo, 1y)")
+	g := bindGuard(fs)
+	return action{
+		Check: func() error {
+			if err := olderThan.positive("older-than"); err != nil {
+				return err
+			}
+			return g.check()
+		},
+		Run: func(e *env) error { return oldLogs(e, g, olderThan.Before(time.Now())) },
+	}
+}
+
+func oldLogs(e *env, g *guard, batas time.Time) error {
+	logs, err := queries.LogAktivitasSebelum(batas)
+	if err != nil {
+		return err
+	}
+
+	const title = "DELETE: Log Aktivitas Baymin Lama"
+	target := "log_aktivitas sebelum " + batas.Format("2006-01-02")
+	p := plan{
+		Title:  title,
+		Action: "baris " + target + " akan dihapus",
+		Preview: render.Result{Columns: []render.Column{
+			{Key: "id_perangkat", Header: "ID Perangkat"},
+			{Key: "waktu_aktivitas", Header: "Waktu Aktivitas"},
+			{Key: "detail_aktivitas", Header: "Detail Aktivitas", Width: 40},
+		}},
+	}
+	for _, l := range logs {
+		p.Preview.Add(l.IDPerangkat, l.WaktuAktivitas, l.DetailAktivitas)
+	}
+	if ok, err := g.approve(e, p); !ok || err != nil {
+		return err
+	}
+
+	var deleted int
+	e.measure(func() error {
+		deleted = queries.HapusLogAktivitas(logs)
+		return nil
+	})
+
+	after, err := queries.LogAktivitasSebelum(batas)
+	if err != nil {
+		return err
+	}
+	return e.render(deleteReport(title, target, deleted, len(after)))
+}
+
+func deleteStaleAppointments(fs *flag.FlagSet) action {
+	olderThan := mustAge("30d")
+	fs.Var(olderThan, "older-than", "umur minimum janji temu yang dihapus (mis. 30d, 2w, 3mo)")
+	g := bindGuard(fs)
+	a := bindArchive(fs)
+	return action{
+		Check: func() error {
+			if err := olderThan.positive("older-than"); err != nil {
+				return err
+			}
+			return g.check()
+		},
+		Run: func(e *env) error {
+			return staleAppointments(e, g, a, olderThan.Before(time.Now()), olderThan.String())
+		},
+	}
+}
+
+func staleAppointments(e *env, g *guard, a *archiver, batas time.Time, umur string) error {
+	appointments, err := queries.JanjiTemuLama(batas)
+	if err != nil {
+		return err
+	}
+
+	const title = "DELETE: Janji Temu Lama tanpa Resep"
+	p := plan{
+		Title:  title,
+		Action: "node JanjiTemu akan dihapus (DETACH DELETE)",
+		Preview: render.Result{Columns: []render.Column{
+			{Key: "id_janji_temu", Header: "ID Janji Temu"},
+			{Key: "waktu_pelaksanaan", Header: "Waktu Pelaksanaan"},
+			{Key: "status", Header: "Status"},
+			{Key: "relasi", Header: "Relasi"},
+		}},
+	}
+	ids := make([]string, len(appointments))
+	relasi := 0
+	for i, j := range appointments {
+		ids[i] = j.IDJanjiTemu
+		relasi += j.Relasi
+		p.Preview.Add(j.IDJanjiTemu, j.WaktuPelaksanaan, j.Status, j.Relasi)
+	}
+	p.Notes = []string{fmt.Sprintf("Relationship yang ikut terhapus: %d", relasi)}
+	if ok, err := g.approve(e, p); !ok || err != nil {
+		return err
+	}
+
+	var archived, deleted int
+	err = e.measure(func() (err error) {
+		keys := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
+			keys[i] = map[string]interface{}{"id_janji_temu": id}
+		}
+		if archived, err = a.save("janji_temu", "delete stale-appointments", keys); err != nil {
+			return err
+		}
+		deleted, err = queries.HapusJanjiTemu(ids)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	after, err := queries.JanjiTemuLama(batas)
+	if err != nil {
+		return err
+	}
+	res := deleteReport(title, "JanjiTemu > "+umur+" tanpa resep", deleted, len(after))
+	res.Notes = append(res.Notes, a.note("janji_temu", archived))
+	return e.render(res)
+}
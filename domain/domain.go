@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+// ===============================================
+//   ERROR & ID BERSAMA PAKET LAYANAN
+// ===============================================
+//
+// Paket layanan (booking, apotek, layanan, tagihan, pembayaran) menolak
+// permintaan dengan *Error. Kind menentukan status yang dipakai CLI dan API
+// (422/404/409/401); error lain dianggap berasal dari database atau gateway.
+
+// Kind membedakan penyebab Error.
+type Kind int
+
+const (
+	Invalid Kind = iota
+	NotFound
+	Conflict
+	Unauthorized
+)
+
+// Error adalah penolakan yang pesannya layak ditampilkan ke pengguna.
+type Error struct {
+	Kind    Kind
+	Message string
+	Details []string
+}
+
+func (e *Error) Error() string {
+	if len(e.Details) == 0 {
+		return e.Message
+	}
+	return e.Message + ": " + strings.Join(e.Details, "; ")
+}
+
+// Fail membuat Error dengan pesan terformat.
+func Fail(kind Kind, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewID membuat key acak, mis. JT-K7Q2M9XA. Prefix berbeda dari ID seeder
+// (JT00001) sehingga keduanya tidak pernah bertabrakan.
+func NewID(prefix string) string {
+	b := make([]byte, 5)
+	rand.Read(b)
+	return prefix + base32.StdEncoding.EncodeToString(b)
+}
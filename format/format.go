@@ -0,0 +1,19 @@
+package format
+
+import "unicode/utf8"
+
+// ===============================================
+//   HELPER FORMAT TAMPILAN
+// ===============================================
+
+// Truncate memotong s menjadi paling banyak maxLen karakter, diakhiri "..."
+// bila terpotong.
+func Truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return string([]rune(s)[:maxLen])
+	}
+	return string([]rune(s)[:maxLen-3]) + "..."
+}
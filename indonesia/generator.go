@@ -0,0 +1,197 @@
+package indonesia
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// ===============================================
+//   GENERATOR DATA BERLOKAL INDONESIA
+// ===============================================
+
+var (
+	namaDepanLaki = []string{
+		"Agus", "Budi", "Dedi", "Eko", "Fajar", "Hendra", "Joko", "Rudi", "Slamet", "Wahyu",
+		"Andi", "Bayu", "Dimas", "Rizky", "Arif", "Yusuf", "Ahmad", "Muhammad", "Putu", "Made",
+		"Asep", "Ujang", "Iwan", "Teguh", "Bambang", "Hadi", "Irfan", "Reza", "Taufik", "Gilang",
+	}
+	namaDepanPerempuan = []string{
+		"Siti", "Dewi", "Sri", "Ani", "Rina", "Wati", "Ayu", "Fitri", "Indah", "Lestari",
+		"Putri", "Nur", "Dian", "Maya", "Ratna", "Yuni", "Kartika", "Nadia", "Aisyah", "Ketut",
+		"Nining", "Euis", "Wulan", "Intan", "Sari", "Rahma", "Nabila", "Anisa", "Citra", "Larasati",
+	}
+	namaBelakang = []string{
+		"Santoso", "Wijaya", "Saputra", "Hidayat", "Setiawan", "Kusuma", "Pratama", "Nugroho", "Siregar", "Nasution",
+		"Lubis", "Harahap", "Simanjuntak", "Sihombing", "Tanjung", "Rahman", "Hakim", "Susanto", "Gunawan", "Kurniawan",
+		"Purnomo", "Wibowo", "Rahayu", "Handayani", "Permana", "Sembiring", "Ginting", "Pangaribuan", "Daulay", "Mahendra",
+	}
+	namaJalan = []string{
+		"Jend. Sudirman", "M.H. Thamrin", "Gatot Subroto", "Diponegoro", "Ahmad Yani", "Pemuda", "Veteran", "Merdeka",
+		"Gajah Mada", "Hayam Wuruk", "Pahlawan", "Imam Bonjol", "Teuku Umar", "Cut Nyak Dien", "R.A. Kartini",
+		"Dr. Sutomo", "Pattimura", "Ki Hajar Dewantara", "Siliwangi", "Asia Afrika", "Mawar", "Melati", "Kenanga", "Flamboyan",
+	}
+	// prefiks nomor seluler per operator
+	prefiksSeluler = []string{
+		"0811", "0812", "0813", "0821", "0822", "0823", "0852", "0853", // Telkomsel
+		"0814", "0815", "0816", "0855", "0856", "0857", "0858", // Indosat
+		"0817", "0818", "0819", "0859", "0877", "0878", // XL
+		"0831", "0832", "0833", "0838", // Axis
+		"0895", "0896", "0897", "0898", "0899", // Tri
+		"0881", "0882", "0883", "0887", "0888", "0889", // Smartfren
+	}
+	jenisRumahSakit = []string{"RSUD", "RS Umum", "RS Ibu dan Anak", "RS Islam", "RS Kristen", "RS Bhayangkara", "RS Khusus Jantung"}
+	namaRumahSakit  = []string{"Harapan Bunda", "Medika", "Sehat Sentosa", "Permata Hati", "Mitra Husada", "Bakti Husada", "Cahaya Medika", "Kasih Ibu", "Budi Mulia", "Sentra Medika"}
+	keluhan         = []string{"Kontrol rutin", "Demam tinggi", "Batuk berkepanjangan", "Sakit kepala", "Nyeri perut", "Pemeriksaan lanjutan", "Sesak napas", "Nyeri sendi", "Gatal-gatal", "Pusing dan mual"}
+	diagnosis       = []string{"Influenza", "Gastritis", "Hipertensi", "ISPA", "Migrain", "Diare akut", "Demam berdarah", "Tifoid", "Dermatitis", "Asma"}
+)
+
+// Orang adalah identitas penduduk yang saling konsisten: NIK dibentuk dari
+// kecamatan alamat, tanggal lahir dan jenis kelamin.
+type Orang struct {
+	NIK          string
+	Nama         string
+	JenisKelamin string
+	TanggalLahir time.Time
+	NomorHP      string
+	Wilayah      Wilayah
+	Jalan        string
+}
+
+// Generator membangkitkan data dari sumber random yang diberikan sehingga
+// hasilnya deterministik untuk seed yang sama.
+type Generator struct {
+	rng  *rand.Rand
+	urut map[string]int // nomor urut NIK per (kecamatan, tanggal lahir)
+}
+
+func New(rng *rand.Rand) *Generator {
+	return &Generator{rng: rng, urut: map[string]int{}}
+}
+
+func (g *Generator) pick(options []string) string {
+	return options[g.rng.Intn(len(options))]
+}
+
+// Wilayah memilih kecamatan secara acak bertingkat: provinsi dipilih
+// merata, lalu kota di provinsi itu, lalu kecamatannya. Jadi setiap
+// provinsi mendapat bagian yang sama dan kota di provinsi dengan sedikit
+// kota lebih sering muncul; kecamatan yang banyak tidak mendominasi.
+func (g *Generator) Wilayah() Wilayah {
+	p := Provinsis[g.rng.Intn(len(Provinsis))]
+	k := p.Kota[g.rng.Intn(len(p.Kota))]
+	i := g.rng.Intn(len(k.Kecamatan))
+	w, _ := WilayahByKode(fmt.Sprintf("%s%02d", k.Kode, i+1))
+	return w
+}
+
+// Jalan menghasilkan alamat jalan, mis. "Jl. Diponegoro No. 12".
+func (g *Generator) Jalan() string {
+	return fmt.Sprintf("Jl. %s No. %d", g.pick(namaJalan), g.rng.Intn(200)+1)
+}
+
+// JenisKelamin mengembalikan "L" atau "P".
+func (g *Generator) JenisKelamin() string {
+	if g.rng.Intn(2) == 0 {
+		return JenisKelaminLaki
+	}
+	return JenisKelaminPerempuan
+}
+
+// Nama menghasilkan nama lengkap sesuai jenis kelamin. Sebagian orang
+// Indonesia hanya memiliki satu nama.
+func (g *Generator) Nama(jenisKelamin string) string {
+	depan := namaDepanLaki
+	if jenisKelamin == JenisKelaminPerempuan {
+		depan = namaDepanPerempuan
+	}
+	parts := []string{g.pick(depan)}
+	switch r := g.rng.Float64(); {
+	case r < 0.15:
+		// satu nama saja
+	case r < 0.35:
+		parts = append(parts, g.pick(depan), g.pick(namaBelakang))
+	default:
+		parts = append(parts, g.pick(namaBelakang))
+	}
+	return strings.Join(parts, " ")
+}
+
+// TanggalLahir menghasilkan tanggal lahir dengan umur minUmur..maxUmur
+// tahun relatif terhadap now.
+func (g *Generator) TanggalLahir(now time.Time, minUmur, maxUmur int) time.Time {
+	latest := now.AddDate(-minUmur, 0, 0)
+	earliest := now.AddDate(-maxUmur, 0, 0)
+	days := int(latest.Sub(earliest).Hours() / 24)
+	d := earliest.AddDate(0, 0, g.rng.Intn(days+1))
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NomorHP menghasilkan nomor seluler dengan prefiks operator yang valid
+// (total 11-12 digit).
+func (g *Generator) NomorHP() string {
+	digits := 7 + g.rng.Intn(2)
+	n := g.rng.Int63n(pow10(digits))
+	return fmt.Sprintf("%s%0*d", g.pick(prefiksSeluler), digits, n)
+}
+
+// TeleponKantor menghasilkan nomor telepon tetap dengan kode area wilayah.
+func (g *Generator) TeleponKantor(w Wilayah) string {
+	digits := 8
+	if len(w.KodeArea) == 4 {
+		digits = 6
+	}
+	return fmt.Sprintf("%s-%0*d", w.KodeArea, digits, g.rng.Int63n(pow10(digits)))
+}
+
+// NIK menerbitkan NIK unik (dalam generator ini) untuk data yang diberikan.
+func (g *Generator) NIK(w Wilayah, lahir time.Time, jenisKelamin string) string {
+	key := NIK(w.Kode, lahir, jenisKelamin, 0)[:12]
+	g.urut[key]++
+	return NIK(w.Kode, lahir, jenisKelamin, g.urut[key])
+}
+
+// Orang menghasilkan identitas penduduk berumur minUmur..maxUmur tahun.
+func (g *Generator) Orang(now time.Time, minUmur, maxUmur int) Orang {
+	jk := g.JenisKelamin()
+	lahir := g.TanggalLahir(now, minUmur, maxUmur)
+	w := g.Wilayah()
+	return Orang{
+		NIK:          g.NIK(w, lahir, jk),
+		Nama:         g.Nama(jk),
+		JenisKelamin: jk,
+		TanggalLahir: lahir,
+		NomorHP:      g.NomorHP(),
+		Wilayah:      w,
+		Jalan:        g.Jalan(),
+	}
+}
+
+// NamaRumahSakit menghasilkan nama rumah sakit di wilayah tertentu, mis.
+// "RSUD Bandung" atau "RS Islam Mitra Husada Coblong".
+func (g *Generator) NamaRumahSakit(w Wilayah) string {
+	jenis := g.pick(jenisRumahSakit)
+	if jenis == "RSUD" {
+		return "RSUD " + w.Kota
+	}
+	return fmt.Sprintf("%s %s %s", jenis, g.pick(namaRumahSakit), w.Kecamatan)
+}
+
+// Keluhan mengembalikan alasan kunjungan ke dokter.
+func (g *Generator) Keluhan() string {
+	return g.pick(keluhan)
+}
+
+// Diagnosis mengembalikan nama penyakit untuk resep.
+func (g *Generator) Diagnosis() string {
+	return g.pick(diagnosis)
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,83 @@
+package indonesia
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ===============================================
+//   NIK (Nomor Induk Kependudukan)
+// ===============================================
+//
+// Struktur 16 digit:
+//   PPKKCC   kode wilayah kecamatan tempat NIK diterbitkan
+//   DDMMYY   tanggal lahir; untuk perempuan DD ditambah 40
+//   NNNN     nomor urut (0001-9999)
+
+const (
+	JenisKelaminLaki      = "L"
+	JenisKelaminPerempuan = "P"
+)
+
+// NIK membentuk NIK dari kode kecamatan, tanggal lahir, jenis kelamin dan
+// nomor urut.
+func NIK(kodeKecamatan string, lahir time.Time, jenisKelamin string, urut int) string {
+	day := lahir.Day()
+	if jenisKelamin == JenisKelaminPerempuan {
+		day += 40
+	}
+	return fmt.Sprintf("%s%02d%02d%02d%04d", kodeKecamatan, day, int(lahir.Month()), lahir.Year()%100, urut)
+}
+
+// InfoNIK adalah hasil penguraian NIK.
+type InfoNIK struct {
+	Wilayah      Wilayah
+	JenisKelamin string
+	Hari         int
+	Bulan        int
+	Tahun2Digit  int
+	Urut         int
+}
+
+// ParseNIK menguraikan dan memvalidasi struktur NIK.
+func ParseNIK(nik string) (InfoNIK, error) {
+	var info InfoNIK
+	if len(nik) != 16 {
+		return info, fmt.Errorf("NIK harus 16 digit, bukan %d", len(nik))
+	}
+	if _, err := strconv.ParseUint(nik, 10, 64); err != nil {
+		return info, fmt.Errorf("NIK hanya boleh berisi angka")
+	}
+
+	w, ok := WilayahByKode(nik[:6])
+	if !ok {
+		return info, fmt.Errorf("kode wilayah %s tidak dikenal", nik[:6])
+	}
+	info.Wilayah = w
+
+	info.Hari, _ = strconv.Atoi(nik[6:8])
+	info.Bulan, _ = strconv.Atoi(nik[8:10])
+	info.Tahun2Digit, _ = strconv.Atoi(nik[10:12])
+	info.Urut, _ = strconv.Atoi(nik[12:])
+
+	info.JenisKelamin = JenisKelaminLaki
+	if info.Hari > 40 {
+		info.JenisKelamin = JenisKelaminPerempuan
+		info.Hari -= 40
+	}
+	// Tahun hanya 2 digit, jadi 29 Februari selalu diterima (tahun 2000
+	// kabisat).
+	if info.Bulan < 1 || info.Bulan > 12 || info.Hari < 1 || info.Hari > time.Date(2000, time.Month(info.Bulan)+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return info, fmt.Errorf("tanggal lahir di NIK tidak valid: %s", nik[6:12])
+	}
+	if info.Urut == 0 {
+		return info, fmt.Errorf("nomor urut NIK tidak boleh 0000")
+	}
+	return info, nil
+}
+
+// CocokLahir melaporkan apakah tanggal lahir sesuai dengan NIK.
+func (i InfoNIK) CocokLahir(lahir time.Time) bool {
+	return i.Hari == lahir.Day() && i.Bulan == int(lahir.Month()) && i.Tahun2Digit == lahir.Year()%100
+}
@@ -0,0 +1,104 @@
+package indonesia
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNIK(t *testing.T) {
+	lahir := time.Date(1995, 4, 7, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		jenisKelamin string
+		urut         int
+		want         string
+	}{
+		{JenisKelaminLaki, 1, "1275010704950001"},
+		{JenisKelaminPerempuan, 1, "1275014704950001"},
+		{JenisKelaminLaki, 9999, "1275010704959999"},
+	}
+	for _, tt := range tests {
+		if got := NIK("127501", lahir, tt.jenisKelamin, tt.urut); got != tt.want {
+			t.Errorf("NIK(%s, %d) = %s, ingin %s", tt.jenisKelamin, tt.urut, got, tt.want)
+		}
+	}
+}
+
+func TestParseNIK(t *testing.T) {
+	tests := []struct {
+		nik          string
+		jenisKelamin string
+		hari, bulan  int
+		tahun, urut  int
+	}{
+		{"1275010704950001", JenisKelaminLaki, 7, 4, 95, 1},
+		{"1275014704950001", JenisKelaminPerempuan, 7, 4, 95, 1},
+		{"1275013112000042", JenisKelaminLaki, 31, 12, 0, 42},
+		{"1275017112000042", JenisKelaminPerempuan, 31, 12, 0, 42},
+		{"1275014101059999", JenisKelaminPerempuan, 1, 1, 5, 9999},
+		{"1275012902040001", JenisKelaminLaki, 29, 2, 4, 1},
+		{"1275016902040001", JenisKelaminPerempuan, 29, 2, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nik, func(t *testing.T) {
+			info, err := ParseNIK(tt.nik)
+			if err != nil {
+				t.Fatalf("ParseNIK: %v", err)
+			}
+			if info.JenisKelamin != tt.jenisKelamin || info.Hari != tt.hari || info.Bulan != tt.bulan ||
+				info.Tahun2Digit != tt.tahun || info.Urut != tt.urut {
+				t.Fatalf("ParseNIK = %+v", info)
+			}
+			if info.Wilayah.Kode != "127501" || info.Wilayah.Kota != "Medan" {
+				t.Fatalf("wilayah = %+v", info.Wilayah)
+			}
+		})
+	}
+}
+
+func TestParseNIKInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		nik  string
+	}{
+		{"terlalu pendek", "127501070495001"},
+		{"terlalu panjang", "12750107049500011"},
+		{"bukan angka", "1275010704950O01"},
+		{"tanda plus", "+275010704950001"},
+		{"wilayah tidak dikenal", "9999010704950001"},
+		{"hari nol", "1275010004950001"},
+		{"hari 32", "1275013204950001"},
+		{"hari 40", "1275014004950001"},
+		{"hari 72 (perempuan 32)", "1275017204950001"},
+		{"bulan nol", "1275010700950001"},
+		{"bulan 13", "1275010713950001"},
+		{"31 April", "1275013104950001"},
+		{"30 Februari", "1275013002950001"},
+		{"70 Februari (perempuan 30)", "1275017002950001"},
+		{"urut nol", "1275010704950000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if info, err := ParseNIK(tt.nik); err == nil {
+				t.Fatalf("ParseNIK(%s) = %+v, ingin error", tt.nik, info)
+			}
+		})
+	}
+}
+
+// TestNIKRoundTrip memastikan NIK yang dibentuk generator selalu lolos
+// ParseNIK dan cocok dengan tanggal lahirnya.
+func TestNIKRoundTrip(t *testing.T) {
+	w := SemuaWilayah()[0]
+	for _, jk := range []string{JenisKelaminLaki, JenisKelaminPerempuan} {
+		for d := time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() < 2001; d = d.AddDate(0, 0, 1) {
+			nik := NIK(w.Kode, d, jk, 1)
+			info, err := ParseNIK(nik)
+			if err != nil {
+				t.Fatalf("ParseNIK(%s) untuk %s %s: %v", nik, jk, d.Format("2006-01-02"), err)
+			}
+			if info.JenisKelamin != jk || !info.CocokLahir(d) {
+				t.Fatalf("ParseNIK(%s) = %+v, ingin %s lahir %s", nik, info, jk, d.Format("2006-01-02"))
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package indonesia
+
+import "fmt"
+
+// ===============================================
+//   WILAYAH: provinsi -> kota -> kecamatan
+// ===============================================
+//
+// Kode provinsi dan kota mengikuti kode wilayah Kemendagri (2 dan 4 digit).
+// Kode kecamatan dibentuk dari kode kota + nomor urut kecamatan di tabel
+// ini, sehingga 6 digit pertama NIK selalu konsisten dengan alamatnya.
+
+// Provinsi adalah satu provinsi beserta kota di dalamnya.
+type Provinsi struct {
+	Kode string
+	Nama string
+	Kota []Kota
+}
+
+// Kota adalah kota/kabupaten beserta kecamatannya.
+type Kota struct {
+	Kode      string
+	Nama      string
+	KodeArea  string // kode area telepon rumah/kantor
+	Kecamatan []string
+}
+
+// Wilayah menunjuk satu kecamatan lengkap dengan induknya.
+type Wilayah struct {
+	Provinsi  string
+	Kota      string
+	Kecamatan string
+	Kode      string // 6 digit: PPKKCC
+	KodeArea  string
+}
+
+var Provinsis = []Provinsi{
+	{"12", "Sumatera Utara", []Kota{
+		{"1275", "Medan", "061", []string{"Medan Baru", "Medan Petisah", "Medan Kota", "Medan Helvetia", "Medan Johor", "Medan Sunggal", "Medan Tembung"}},
+	}},
+	{"13", "Sumatera Barat", []Kota{
+		{"1371", "Padang", "0751", []string{"Padang Barat", "Padang Timur", "Padang Utara", "Koto Tangah", "Kuranji", "Lubuk Begalung"}},
+	}},
+	{"31", "DKI Jakarta", []Kota{
+		{"3171", "Jakarta Selatan", "021", []string{"Jagakarsa", "Pasar Minggu", "Cilandak", "Pesanggrahan", "Kebayoran Lama", "Kebayoran Baru", "Mampang Prapatan", "Pancoran", "Tebet", "Setiabudi"}},
+		{"3172", "Jakarta Timur", "021", []string{"Matraman", "Pulo Gadung", "Jatinegara", "Kramat Jati", "Pasar Rebo", "Cakung", "Duren Sawit", "Makasar", "Ciracas", "Cipayung"}},
+		{"3173", "Jakarta Pusat", "021", []string{"Tanah Abang", "Menteng", "Senen", "Johar Baru", "Cempaka Putih", "Kemayoran", "Sawah Besar", "Gambir"}},
+	}},
+	{"32", "Jawa Barat", []Kota{
+		{"3271", "Bogor", "0251", []string{"Bogor Selatan", "Bogor Timur", "Bogor Utara", "Bogor Tengah", "Bogor Barat", "Tanah Sareal"}},
+		{"3273", "Bandung", "022", []string{"Coblong", "Sukajadi", "Cicendo", "Andir", "Lengkong", "Buahbatu", "Antapani", "Arcamanik", "Bandung Wetan", "Sumur Bandung"}},
+		{"3275", "Bekasi", "021", []string{"Bekasi Timur", "Bekasi Barat", "Bekasi Utara", "Bekasi Selatan", "Rawalumbu", "Pondok Gede", "Jatiasih", "Medan Satria"}},
+		{"3276", "Depok", "021", []string{"Beji", "Pancoran Mas", "Sukmajaya", "Cimanggis", "Sawangan", "Limo", "Cinere", "Cilodong"}},
+	}},
+	{"33", "Jawa Tengah", []Kota{
+		{"3372", "Surakarta", "0271", []string{"Laweyan", "Serengan", "Pasar Kliwon", "Jebres", "Banjarsari"}},
+		{"3374", "Semarang", "024", []string{"Semarang Tengah", "Semarang Utara", "Semarang Selatan", "Candisari", "Banyumanik", "Tembalang", "Gajahmungkur", "Ngaliyan"}},
+	}},
+	{"34", "DI Yogyakarta", []Kota{
+		{"3471", "Yogyakarta", "0274", []string{"Gondokusuman", "Jetis", "Tegalrejo", "Umbulharjo", "Kotagede", "Mergangsan", "Danurejan", "Gedongtengen"}},
+	}},
+	{"35", "Jawa Timur", []Kota{
+		{"3573", "Malang", "0341", []string{"Klojen", "Blimbing", "Lowokwaru", "Sukun", "Kedungkandang"}},
+		{"3578", "Surabaya", "031", []string{"Gubeng", "Tegalsari", "Genteng", "Wonokromo", "Sukolilo", "Rungkut", "Mulyorejo", "Tambaksari", "Wiyung", "Sawahan"}},
+	}},
+	{"36", "Banten", []Kota{
+		{"3671", "Tangerang", "021", []string{"Tangerang", "Karawaci", "Cipondoh", "Ciledug", "Batuceper", "Benda", "Pinang"}},
+		{"3673", "Serang", "0254", []string{"Serang", "Cipocok Jaya", "Kasemen", "Taktakan", "Walantaka", "Curug"}},
+		{"3674", "Tangerang Selatan", "021", []string{"Serpong", "Serpong Utara", "Pondok Aren", "Ciputat", "Ciputat Timur", "Pamulang", "Setu"}},
+	}},
+	{"51", "Bali", []Kota{
+		{"5171", "Denpasar", "0361", []string{"Denpasar Barat", "Denpasar Timur", "Denpasar Selatan", "Denpasar Utara"}},
+	}},
+	{"64", "Kalimantan Timur", []Kota{
+		{"6471", "Balikpapan", "0542", []string{"Balikpapan Kota", "Balikpapan Selatan", "Balikpapan Utara", "Balikpapan Timur", "Balikpapan Barat", "Balikpapan Tengah"}},
+		{"6472", "Samarinda", "0541", []string{"Samarinda Ulu", "Samarinda Ilir", "Samarinda Kota", "Sungai Kunjang", "Sambutan"}},
+	}},
+	{"73", "Sulawesi Selatan", []Kota{
+		{"7371", "Makassar", "0411", []string{"Mariso", "Mamajang", "Makassar", "Ujung Pandang", "Wajo", "Panakkukang", "Tamalate", "Rappocini", "Biringkanaya"}},
+	}},
+}
+
+// semuaWilayah berisi seluruh kecamatan, diindeks saat init.
+var (
+	semuaWilayah  []Wilayah
+	wilayahByKode = map[string]Wilayah{}
+)
+
+func init() {
+	for _, p := range Provinsis {
+		for _, k := range p.Kota {
+			for i, kec := range k.Kecamatan {
+				w := Wilayah{
+					Provinsi:  p.Nama,
+					Kota:      k.Nama,
+					Kecamatan: kec,
+					Kode:      fmt.Sprintf("%s%02d", k.Kode, i+1),
+					KodeArea:  k.KodeArea,
+				}
+				semuaWilayah = append(semuaWilayah, w)
+				wilayahByKode[w.Kode] = w
+			}
+		}
+	}
+}
+
+// SemuaWilayah mengembalikan seluruh kecamatan yang dikenal.
+func SemuaWilayah() []Wilayah {
+	return append([]Wilayah(nil), semuaWilayah...)
+}
+
+// WilayahByKode mencari kecamatan berdasarkan kode 6 digit.
+func WilayahByKode(kode string) (Wilayah, bool) {
+	w, ok := wilayahByKode[kode]
+	return w, ok
+}
+
+// CariWilayah mencari kecamatan berdasarkan nama provinsi, kota dan
+// kecamatan.
+func CariWilayah(provinsi, kota, kecamatan string) (Wilayah, bool) {
+	for _, w := range semuaWilayah {
+		if w.Provinsi == provinsi && w.Kota == kota && w.Kecamatan == kecamatan {
+			return w, true
+		}
+	}
+	return Wilayah{}, false
+}
@@ -0,0 +1,184 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ===============================================
+//   JADWAL CRON
+// ===============================================
+//
+// Format lima kolom cron standar, dievaluasi pada zona waktu lokal:
+//
+//	menit jam tanggal bulan hari     mis. "*/15 * * * *", "0 2 * * 1-5"
+//
+// Tiap kolom menerima *, angka, rentang a-b, daftar a,b dan langkah /n.
+// Hari 0-6 dimulai Minggu (7 juga Minggu). Bila tanggal dan hari sama-sama
+// dibatasi, cukup salah satu yang cocok (seperti cron). Singkatan @hourly,
+// @daily, @weekly, @monthly dan "@every 10m" juga diterima.
+
+// Jadwal menentukan kapan job berikutnya dijalankan.
+type Jadwal interface {
+	// Next mengembalikan waktu run pertama yang lebih besar dari t.
+	Next(t time.Time) time.Time
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"menit", 0, 59},
+	{"jam", 0, 23},
+	{"tanggal", 1, 31},
+	{"bulan", 1, 12},
+	{"hari", 0, 7},
+}
+
+var cronAlias = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// cron adalah jadwal lima kolom; tiap kolom adalah bitset nilai yang cocok.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// every menjalankan job setiap interval tetap sejak awal menit.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(e)).Add(time.Duration(e))
+}
+
+// ParseJadwal membaca jadwal cron atau @every.
+func ParseJadwal(spec string) (Jadwal, error) {
+	spec = strings.TrimSpace(spec)
+	if alias, ok := cronAlias[spec]; ok {
+		spec = alias
+	}
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("jadwal %q: @every butuh durasi >= 1m, mis. @every 15m", spec)
+		}
+		return every(d), nil
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("jadwal %q harus berisi 5 kolom (menit jam tanggal bulan hari) atau @daily/@every 15m", spec)
+	}
+	sets := make([]uint64, len(parts))
+	for i, part := range parts {
+		set, err := parseField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("jadwal %q: %v", spec, err)
+		}
+		sets[i] = set
+	}
+	c := &cron{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domStar: parts[2] == "*", dowStar: parts[4] == "*",
+	}
+	// 7 dan 0 sama-sama Minggu.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("jadwal %q tidak pernah terjadi", spec)
+	}
+	return c, nil
+}
+
+func parseField(s string, f cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("langkah %q pada kolom %s tidak valid", item, f.name)
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var errA, errB error
+			lo, errA = strconv.Atoi(a)
+			hi, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil || lo > hi {
+				return 0, fmt.Errorf("rentang %q pada kolom %s tidak valid", item, f.name)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("nilai %q pada kolom %s bukan angka", item, f.name)
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+		if lo < f.min || hi > f.max {
+			return 0, fmt.Errorf("nilai %q pada kolom %s harus %d-%d", item, f.name, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dow
+	case c.dowStar:
+		return dom
+	}
+	return dom || dow
+}
+
+// Next melompati bulan, hari dan jam yang tidak cocok sekaligus sehingga
+// jadwal jarang (mis. tanggal 29 Februari) tetap cepat dihitung.
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.In(time.Local).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.Local)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.Local)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.Local)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
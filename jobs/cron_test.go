@@ -0,0 +1,128 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func lokal(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseJadwalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"kolom kurang", "0 0 * *"},
+		{"kolom lebih", "0 0 * * * *"},
+		{"menit di luar batas", "60 * * * *"},
+		{"jam di luar batas", "0 24 * * *"},
+		{"tanggal nol", "0 0 0 * *"},
+		{"bulan 13", "0 0 1 13 *"},
+		{"hari 8", "0 0 * * 8"},
+		{"rentang terbalik", "0 5-1 * * *"},
+		{"rentang bukan angka", "0 a-b * * *"},
+		{"langkah nol", "*/0 * * * *"},
+		{"langkah negatif", "*/-5 * * * *"},
+		{"nilai bukan angka", "x * * * *"},
+		{"30 Februari", "0 0 30 2 *"},
+		{"31 April", "0 0 31 4 *"},
+		{"every terlalu cepat", "@every 30s"},
+		{"every tidak valid", "@every sebentar"},
+		{"kosong", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJadwal(tt.spec); err == nil {
+				t.Fatalf("ParseJadwal(%q) = nil error, ingin error", tt.spec)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		dari string
+		want []string // run berturut-turut setelah dari
+	}{
+		{"tiap menit", "* * * * *", "2025-01-06 09:00",
+			[]string{"2025-01-06 09:01", "2025-01-06 09:02"}},
+		{"langkah 15 menit", "*/15 * * * *", "2025-01-06 09:07",
+			[]string{"2025-01-06 09:15", "2025-01-06 09:30", "2025-01-06 09:45", "2025-01-06 10:00"}},
+		{"tepat pada jadwal dilewati", "*/15 * * * *", "2025-01-06 09:15",
+			[]string{"2025-01-06 09:30"}},
+		{"rentang jam", "0 8-10 * * *", "2025-01-06 09:30",
+			[]string{"2025-01-06 10:00", "2025-01-07 08:00", "2025-01-07 09:00"}},
+		{"rentang dengan langkah", "0 1-9/4 * * *", "2025-01-06 00:00",
+			[]string{"2025-01-06 01:00", "2025-01-06 05:00", "2025-01-06 09:00", "2025-01-07 01:00"}},
+		{"angka dengan langkah sampai batas", "50/5 * * * *", "2025-01-06 09:00",
+			[]string{"2025-01-06 09:50", "2025-01-06 09:55", "2025-01-06 10:50"}},
+		{"daftar", "0,30 12 * * *", "2025-01-06 12:10",
+			[]string{"2025-01-06 12:30", "2025-01-07 12:00"}},
+		{"hari kerja", "0 2 * * 1-5", "2025-01-10 03:00", // Jumat
+			[]string{"2025-01-13 02:00", "2025-01-14 02:00"}},
+		{"hari 7 adalah Minggu", "0 0 * * 7", "2025-01-06 00:00",
+			[]string{"2025-01-12 00:00", "2025-01-19 00:00"}},
+		{"hari 0 adalah Minggu", "0 0 * * 0", "2025-01-06 00:00",
+			[]string{"2025-01-12 00:00"}},
+		{"tanggal atau hari", "0 0 15 * 1", "2025-01-06 00:00", // Senin atau tanggal 15
+			[]string{"2025-01-13 00:00", "2025-01-15 00:00", "2025-01-20 00:00"}},
+		{"tanggal 31 melompati bulan pendek", "0 0 31 * *", "2025-01-31 00:00",
+			[]string{"2025-03-31 00:00", "2025-05-31 00:00"}},
+		{"29 Februari", "0 0 29 2 *", "2025-01-01 00:00",
+			[]string{"2028-02-29 00:00"}},
+		{"pergantian tahun", "0 0 1 1 *", "2025-12-31 23:59",
+			[]string{"2026-01-01 00:00", "2027-01-01 00:00"}},
+		{"alias daily", "@daily", "2025-01-06 09:00",
+			[]string{"2025-01-07 00:00", "2025-01-08 00:00"}},
+		{"alias weekly", "@weekly", "2025-01-06 09:00",
+			[]string{"2025-01-12 00:00"}},
+		{"alias monthly", "@monthly", "2025-01-06 09:00",
+			[]string{"2025-02-01 00:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := ParseJadwal(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseJadwal(%q): %v", tt.spec, err)
+			}
+			now := lokal(tt.dari)
+			for _, w := range tt.want {
+				got := j.Next(now)
+				if want := lokal(w); !got.Equal(want) {
+					t.Fatalf("Next(%s) = %s, ingin %s", now.Format("2006-01-02 15:04"), got.Format("2006-01-02 15:04"), w)
+				}
+				now = got
+			}
+		})
+	}
+}
+
+func TestCronNextDetikDibulatkan(t *testing.T) {
+	j, err := ParseJadwal("*/15 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := lokal("2025-01-06 09:14").Add(59*time.Second + 500*time.Millisecond)
+	if got, want := j.Next(now), lokal("2025-01-06 09:15"); !got.Equal(want) {
+		t.Fatalf("Next = %s, ingin %s", got, want)
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	j, err := ParseJadwal("@every 10m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 1, 6, 9, 7, 30, 0, time.UTC)
+	if got, want := j.Next(now), time.Date(2025, 1, 6, 9, 10, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Next = %s, ingin %s", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"src/apotek"
+	"src/pembayaran"
+	"src/queries"
+	"src/retensi"
+)
+
+// ===============================================
+//   JOB PEMELIHARAAN
+// ===============================================
+//
+// Job adalah tugas pemeliharaan yang dulu hanya bisa dijalankan manual
+// lewat rs update/delete. Ambang umur expire-orders sama dengan default
+// update1; pembersihan delete1-delete3 kini diatur berkas kebijakan retensi
+// (lihat paket retensi). Yang bisa diatur di sini adalah jadwal dan jeda
+// (job_konfigurasi).
+//
+//   expire-orders     update1: batalkan pesanan obat belum dibayar > 2 hari
+//                     yang belum masuk tagihan terbuka
+//   expire-payments   kedaluwarsakan pembayaran tagihan yang lewat batas
+//   retention         jalankan aturan aktif berkas kebijakan retensi
+
+// MaksPesananExpired membatasi pesanan yang dibatalkan per run agar satu
+// run tidak berjalan terlalu lama; sisanya diambil run berikutnya.
+const MaksPesananExpired = 500
+
+// Hasil adalah ringkasan satu run job.
+type Hasil struct {
+	Jumlah int    // baris/node yang terdampak
+	Pesan  string // ringkasan untuk riwayat
+}
+
+// Job adalah satu tugas terjadwal.
+type Job struct {
+	Nama      string
+	Ringkasan string
+	// JadwalBawaan dipakai bila job_konfigurasi tidak berisi jadwal.
+	JadwalBawaan string
+	// Neo4j berarti job membutuhkan koneksi Neo4j selain Cassandra.
+	Neo4j bool
+	// Run harus berhenti di antara batch begitu ctx dibatalkan (lease
+	// hilang) dan mengembalikan hasil sejauh itu.
+	Run func(ctx context.Context, now time.Time) (Hasil, error)
+}
+
+// Semua adalah daftar job, urut nama.
+var Semua = []*Job{
+	{
+		Nama:         "expire-orders",
+		Ringkasan:    "batalkan pesanan obat belum dibayar lebih dari 2 hari (update1)",
+		JadwalBawaan: "*/30 * * * *",
+		Run:          batalkanPesananExpired,
+	},
+	{
+		Nama:         "expire-payments",
+		Ringkasan:    "kedaluwarsakan pembayaran yang lewat batas; batalkan tagihan yang lewat jatuh tempo",
+		JadwalBawaan: "*/15 * * * *",
+		Run:          kedaluwarsakanPembayaran,
+	},
+	{
+		Nama:         "retention",
+		Ringkasan:    "jalankan aturan aktif kebijakan retensi (env RS_RETENSI atau retensi.json)",
+		JadwalBawaan: "0 3 * * *",
+		Neo4j:        true,
+		Run:          jalankanRetensi,
+	},
+}
+
+// Cari mengembalikan job bernama nama.
+func Cari(nama string) (*Job, error) {
+	for _, j := range Semua {
+		if j.Nama == nama {
+			return j, nil
+		}
+	}
+	names := make([]string, len(Semua))
+	for i, j := range Semua {
+		names[i] = j.Nama
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("job %q tidak dikenal (pilihan: %v)", nama, names)
+}
+
+func batalkanPesananExpired(ctx context.Context, now time.Time) (Hasil, error) {
+	orders, err := queries.ExpiredOrders(now.Add(-48*time.Hour), MaksPesananExpired)
+	if err != nil {
+		return Hasil{}, err
+	}
+	hasil, err := apotek.BatalkanExpired(ctx, orders)
+	var n int
+	for _, b := range hasil {
+		if b.Applied {
+			n++
+		}
+	}
+	h := Hasil{Jumlah: n, Pesan: fmt.Sprintf("%d dari %d pesanan obat belum dibayar dibatalkan, stok dikembalikan", n, len(orders))}
+	if err != nil {
+		return h, err
+	}
+	if len(hasil) < len(orders) {
+		return h, fmt.Errorf("%d pesanan gagal dibatalkan", len(orders)-len(hasil))
+	}
+	return h, nil
+}
+
+func kedaluwarsakanPembayaran(ctx context.Context, now time.Time) (Hasil, error) {
+	list, err := pembayaran.Kedaluwarsakan(ctx, now, pembayaran.MaksKedaluwarsa)
+	var pesanan int
+	for _, h := range list {
+		pesanan += len(h.Pesanan)
+	}
+	h := Hasil{Jumlah: len(list), Pesan: fmt.Sprintf("%d pembayaran kedaluwarsa, %d pesanan dibatalkan", len(list), pesanan)}
+	return h, err
+}
+
+// jalankanRetensi membaca ulang berkas kebijakan setiap run agar perubahan
+// aturan berlaku tanpa me-restart worker.
+func jalankanRetensi(ctx context.Context, now time.Time) (Hasil, error) {
+	k, err := retensi.Muat(retensi.BerkasKebijakan())
+	if err != nil {
+		return Hasil{}, err
+	}
+	laporan, err := k.Jalankan(ctx, retensi.Opsi{Now: now})
+	if err != nil && laporan == nil {
+		return Hasil{}, err
+	}
+	var h Hasil
+	var pesan, gagal []string
+	for _, l := range laporan {
+		h.Jumlah += l.Diproses
+		pesan = append(pesan, fmt.Sprintf("%s: %d/%d %s", l.Aturan, l.Diproses, l.Cocok, l.Aksi))
+		if l.Err != nil {
+			gagal = append(gagal, fmt.Sprintf("%s: %v", l.Aturan, l.Err))
+		}
+	}
+	h.Pesan = strings.Join(pesan, "; ")
+	if h.Pesan == "" {
+		h.Pesan = "tidak ada aturan aktif"
+	}
+	if len(gagal) > 0 {
+		return h, fmt.Errorf("aturan gagal: %s", strings.Join(gagal, "; "))
+	}
+	return h, err
+}
@@ -0,0 +1,256 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"src/domain"
+	"src/queries"
+)
+
+// ===============================================
+//   RUNNER & WORKER
+// ===============================================
+//
+// Setiap run (terjadwal maupun manual) lebih dulu mengambil lease job di
+// job_lease dengan INSERT ... IF NOT EXISTS USING TTL. Lease diperpanjang
+// selama job berjalan dan dilepas setelahnya; bila instance mati, TTL
+// membuat lease hilang sendiri. Bila perpanjangan gagal karena lease sudah
+// dipegang instance lain, ctx job dibatalkan dan job berhenti di batch
+// berikutnya. Hasil run dicatat di job_riwayat.
+
+// Status run di job_riwayat.
+const (
+	StatusSukses = "sukses"
+	StatusGagal  = "gagal"
+)
+
+// Pemicu run di job_riwayat.
+const (
+	PemicuJadwal = "jadwal"
+	PemicuManual = "manual"
+)
+
+// SimpanRiwayat adalah umur baris job_riwayat (TTL).
+const SimpanRiwayat = 90 * 24 * time.Hour
+
+// ErrLeaseHilang adalah penyebab pembatalan ctx job ketika lease tidak
+// bisa diperpanjang.
+var ErrLeaseHilang = errors.New("lease job hilang, run dihentikan")
+
+// LeaseDipegang berarti job sedang dijalankan instance lain.
+type LeaseDipegang struct {
+	Lease queries.LeaseJob
+}
+
+func (e *LeaseDipegang) Error() string {
+	return fmt.Sprintf("job %s sedang dijalankan %s (lease sampai %s)",
+		e.Lease.Nama, e.Lease.Pemilik, e.Lease.Sampai.Local().Format("2006-01-02 15:04:05"))
+}
+
+// Runner menjalankan job dengan lease atas nama satu instance.
+type Runner struct {
+	ID    string        // pemilik lease, mis. host-pid
+	Lease time.Duration // lama lease; diperpanjang tiap Lease/3
+	Logf  func(format string, args ...interface{})
+}
+
+// DefaultID adalah identitas instance bawaan: hostname-pid.
+func DefaultID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "rs"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Jalankan menjalankan job sekali bila lease bisa diambil, lalu mencatat
+// riwayatnya. Error *LeaseDipegang berarti job tidak dijalankan.
+func (r *Runner) Jalankan(j *Job, pemicu string) (queries.RunJob, error) {
+	mulai := time.Now()
+	applied, lama, err := queries.AmbilLease(j.Nama, r.ID, mulai.Add(r.Lease), r.Lease)
+	if err != nil {
+		return queries.RunJob{}, err
+	}
+	if !applied {
+		return queries.RunJob{}, &LeaseDipegang{Lease: lama}
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.perpanjang(j.Nama, stop, cancel)
+	}()
+
+	hasil, runErr := runSafely(ctx, j, mulai)
+	if cause := context.Cause(ctx); cause != nil && (runErr == nil || errors.Is(runErr, context.Canceled)) {
+		runErr = cause
+	}
+
+	close(stop)
+	wg.Wait()
+	cancel(nil)
+	if err := queries.LepasLease(j.Nama, r.ID); err != nil {
+		r.Logf("%v; lease hilang sendiri setelah %s\n", err, r.Lease)
+	}
+
+	selesai := time.Now()
+	run := queries.RunJob{
+		Nama:     j.Nama,
+		Mulai:    mulai.UTC().Truncate(time.Millisecond),
+		IdRun:    domain.NewID("RUN-"),
+		Selesai:  selesai.UTC().Truncate(time.Millisecond),
+		DurasiMs: int(selesai.Sub(mulai) / time.Millisecond),
+		Status:   StatusSukses,
+		Jumlah:   hasil.Jumlah,
+		Pesan:    hasil.Pesan,
+		Pemilik:  r.ID,
+		Pemicu:   pemicu,
+	}
+	if runErr != nil {
+		run.Status = StatusGagal
+		if run.Pesan != "" {
+			run.Pesan += "; "
+		}
+		run.Pesan += runErr.Error()
+	}
+	if err := queries.CatatRunJob(run, SimpanRiwayat); err != nil {
+		r.Logf("%v\n", err)
+	}
+	return run, runErr
+}
+
+// runSafely mengubah panic job menjadi error agar worker tetap hidup.
+func runSafely(ctx context.Context, j *Job, now time.Time) (h Hasil, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return j.Run(ctx, now)
+}
+
+// perpanjang memperpanjang lease tiap Lease/3 sampai stop ditutup. Bila
+// lease sudah dipegang instance lain, ctx job dibatalkan dengan
+// ErrLeaseHilang.
+func (r *Runner) perpanjang(nama string, stop <-chan struct{}, cancel context.CancelCauseFunc) {
+	t := time.NewTicker(r.Lease / 3)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-t.C:
+			ok, err := queries.PerpanjangLease(nama, r.ID, now.Add(r.Lease), r.Lease)
+			switch {
+			case err != nil:
+				r.Logf("%v\n", err)
+			case !ok:
+				r.Logf("lease job %s sudah tidak dipegang %s; run dihentikan\n", nama, r.ID)
+				cancel(ErrLeaseHilang)
+				return
+			}
+		}
+	}
+}
+
+// Worker menjalankan daftar job sesuai jadwalnya sampai ctx selesai, lalu
+// menunggu run yang sedang berjalan. Konfigurasi (jadwal, jeda) dibaca
+// ulang setiap cek sehingga rs job schedule/pause/resume berlaku tanpa
+// restart. Run pertama terjadi pada jadwal berikutnya setelah start.
+func (r *Runner) Worker(ctx context.Context, daftar []*Job, cek time.Duration) error {
+	type state struct {
+		spec   string
+		jadwal Jadwal
+		next   time.Time
+	}
+	states := map[string]*state{}
+	var mu sync.Mutex
+	running := map[string]bool{}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	tick := func(now time.Time) {
+		konf, err := queries.SemuaKonfigurasiJob()
+		if err != nil {
+			r.Logf("%v; dicoba lagi dalam %s\n", err, cek)
+			return
+		}
+		for _, j := range daftar {
+			k := konf[j.Nama]
+			spec := k.Jadwal
+			if spec == "" {
+				spec = j.JadwalBawaan
+			}
+			st := states[j.Nama]
+			if st == nil || st.spec != spec {
+				jadwal, err := ParseJadwal(spec)
+				if err != nil {
+					r.Logf("job %s tidak dijadwalkan: %v\n", j.Nama, err)
+					states[j.Nama] = &state{spec: spec}
+					continue
+				}
+				st = &state{spec: spec, jadwal: jadwal, next: jadwal.Next(now)}
+				states[j.Nama] = st
+				r.Logf("job %s: jadwal %q, run berikutnya %s\n", j.Nama, spec, st.next.Format("2006-01-02 15:04"))
+				continue
+			}
+			if st.jadwal == nil || now.Before(st.next) {
+				continue
+			}
+			st.next = st.jadwal.Next(now)
+			if k.Dijeda {
+				r.Logf("job %s dilewati: dijeda\n", j.Nama)
+				continue
+			}
+			mu.Lock()
+			busy := running[j.Nama]
+			running[j.Nama] = true
+			mu.Unlock()
+			if busy {
+				r.Logf("job %s dilewati: run sebelumnya belum selesai\n", j.Nama)
+				continue
+			}
+
+			wg.Add(1)
+			go func(j *Job, next time.Time) {
+				defer wg.Done()
+				defer func() {
+					mu.Lock()
+					delete(running, j.Nama)
+					mu.Unlock()
+				}()
+				run, err := r.Jalankan(j, PemicuJadwal)
+				var held *LeaseDipegang
+				switch {
+				case errors.As(err, &held):
+					r.Logf("job %s dilewati: %v\n", j.Nama, err)
+				case err != nil:
+					r.Logf("job %s gagal setelah %dms: %s\n", j.Nama, run.DurasiMs, run.Pesan)
+				default:
+					r.Logf("job %s selesai dalam %dms: %s; run berikutnya %s\n", j.Nama, run.DurasiMs, run.Pesan, next.Format("2006-01-02 15:04"))
+				}
+			}(j, st.next)
+		}
+	}
+
+	t := time.NewTicker(cek)
+	defer t.Stop()
+	tick(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			r.Logf("worker %s berhenti; menunggu job yang sedang berjalan\n", r.ID)
+			return nil
+		case now := <-t.C:
+			tick(now)
+		}
+	}
+}
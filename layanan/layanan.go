@@ -0,0 +1,302 @@
+package layanan
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/queries"
+)
+
+// ===============================================
+//   PEMESANAN LAYANAN MEDIS
+// ===============================================
+//
+// Alur pesan: pastikan pasien ada → baca (id_rs, id_layanan) dari
+// lokasi_layanan (harus ada) → ambil satu kuota pada tanggal pelaksanaan
+// di kuota_layanan dengan LWT (ditolak bila kapasitas harian penuh) →
+// tulis pemesanan_layanan beserta biaya saat itu dan kedua tabel query
+// dalam satu LOGGED BATCH. Bila penulisan gagal, kuota dikembalikan.
+// Batal mengubah status dengan LWT lalu mengembalikan kuota.
+//
+// Dipakai oleh rs service ... dan endpoint layanan di paket api.
+
+// KapasitasDefault dipakai untuk lokasi_layanan tanpa kapasitas_harian.
+const KapasitasDefault = 20
+
+// MaksHariKeDepan membatasi seberapa jauh jadwal boleh dipesan.
+const MaksHariKeDepan = 90
+
+// StatusBaru adalah status pemesanan layanan yang baru dibuat, sama
+// seperti pesanan obat.
+const StatusBaru = "belum dibayar"
+
+const casRetry = 5
+
+// Tanggal adalah kunci kuota harian: tanggal jadwal pelaksanaan dalam
+// queries.ZonaJadwal.
+func Tanggal(t time.Time) string {
+	return queries.TanggalJadwal(t)
+}
+
+// Permintaan adalah pemesanan satu layanan di satu rumah sakit.
+type Permintaan struct {
+	IdPesanan   string // kosong = dibuat otomatis
+	EmailPasien string
+	IdRS        string
+	IdLayanan   string
+	Jadwal      time.Time
+}
+
+func (p Permintaan) validate(now time.Time) error {
+	var details []string
+	if err := queries.ValidEmail(p.EmailPasien); err != nil {
+		details = append(details, "email_pasien: "+err.Error())
+	}
+	if strings.TrimSpace(p.IdRS) == "" {
+		details = append(details, "id_rs wajib diisi")
+	}
+	if strings.TrimSpace(p.IdLayanan) == "" {
+		details = append(details, "id_layanan wajib diisi")
+	}
+	switch {
+	case p.Jadwal.IsZero():
+		details = append(details, "jadwal wajib diisi")
+	case !p.Jadwal.After(now):
+		details = append(details, "jadwal harus di masa depan")
+	case p.Jadwal.After(now.AddDate(0, 0, MaksHariKeDepan)):
+		details = append(details, fmt.Sprintf("jadwal paling jauh %d hari ke depan", MaksHariKeDepan))
+	}
+	if len(details) > 0 {
+		return &domain.Error{Kind: domain.Invalid, Message: "pemesanan layanan tidak valid", Details: details}
+	}
+	return nil
+}
+
+// Pesan membuat pemesanan layanan dan mengembalikannya.
+func Pesan(p Permintaan, now time.Time) (*queries.PemesananLayanan, error) {
+	if err := p.validate(now); err != nil {
+		return nil, err
+	}
+	ada, err := queries.PasienAda(p.EmailPasien)
+	if err != nil {
+		return nil, err
+	}
+	if !ada {
+		return nil, domain.Fail(domain.NotFound, "pasien %s tidak ditemukan", p.EmailPasien)
+	}
+	penawaran, err := queries.Penawaran(p.IdRS, p.IdLayanan)
+	if err != nil {
+		return nil, err
+	}
+	if penawaran == nil {
+		return nil, domain.Fail(domain.NotFound, "rumah sakit %s tidak menawarkan layanan %s", p.IdRS, p.IdLayanan)
+	}
+	kapasitas := penawaran.KapasitasHarian
+	if kapasitas <= 0 {
+		kapasitas = KapasitasDefault
+	}
+
+	tanggal := Tanggal(p.Jadwal)
+	if err := ambilKuota(p.IdRS, p.IdLayanan, tanggal, kapasitas); err != nil {
+		return nil, err
+	}
+	if p.IdPesanan == "" {
+		p.IdPesanan = domain.NewID("PL-")
+	}
+	pesanan := queries.PemesananLayanan{
+		IdPesanan:         p.IdPesanan,
+		EmailPemesan:      p.EmailPasien,
+		IdRS:              p.IdRS,
+		IdLayanan:         p.IdLayanan,
+		NamaLayanan:       penawaran.NamaLayanan,
+		BiayaLayanan:      penawaran.BiayaLayanan,
+		WaktuPemesanan:    now.UTC().Truncate(time.Millisecond),
+		JadwalPelaksanaan: p.Jadwal.UTC().Truncate(time.Second),
+		StatusPemesanan:   StatusBaru,
+	}
+	if err := queries.SimpanPemesananLayanan(pesanan); err != nil {
+		kembalikanKuota(p.IdRS, p.IdLayanan, tanggal)
+		return nil, err
+	}
+	return &pesanan, nil
+}
+
+// ambilKuota menaikkan jumlah terpakai pada tanggal bila masih di bawah
+// kapasitas.
+func ambilKuota(idRS, idLayanan, tanggal string, kapasitas int) error {
+	terpakai, ada, err := queries.KuotaTerpakai(idRS, idLayanan, tanggal)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < casRetry; i++ {
+		if !ada {
+			applied, err := queries.MulaiKuota(idRS, idLayanan, tanggal)
+			if err != nil {
+				return err
+			}
+			if applied {
+				return nil
+			}
+			// Pemesanan lain membuat baris kuota lebih dulu; baca ulang.
+			if terpakai, ada, err = queries.KuotaTerpakai(idRS, idLayanan, tanggal); err != nil {
+				return err
+			}
+			continue
+		}
+		if terpakai >= kapasitas {
+			return &domain.Error{Kind: domain.Conflict, Message: "kapasitas layanan penuh",
+				Details: []string{fmt.Sprintf("%s di %s pada %s sudah %d dari %d pemesanan", idLayanan, idRS, tanggal, terpakai, kapasitas)}}
+		}
+		applied, sekarang, err := queries.UbahKuota(idRS, idLayanan, tanggal, terpakai, terpakai+1)
+		if err != nil {
+			return err
+		}
+		if applied {
+			return nil
+		}
+		terpakai = sekarang
+	}
+	return domain.Fail(domain.Conflict, "kuota %s di %s pada %s sedang diubah pemesanan lain, coba lagi", idLayanan, idRS, tanggal)
+}
+
+// kembalikanKuota menurunkan jumlah terpakai. Kegagalan hanya dicatat.
+func kembalikanKuota(idRS, idLayanan, tanggal string) {
+	terpakai, ada, err := queries.KuotaTerpakai(idRS, idLayanan, tanggal)
+	for i := 0; err == nil && ada && terpakai > 0 && i < casRetry; i++ {
+		var applied bool
+		applied, terpakai, err = queries.UbahKuota(idRS, idLayanan, tanggal, terpakai, terpakai-1)
+		if applied {
+			return
+		}
+	}
+	if err != nil || (ada && terpakai > 0) {
+		log.Printf("gagal mengembalikan kuota %s di %s pada %s: %v", idLayanan, idRS, tanggal, err)
+	}
+}
+
+// Batal membatalkan pemesanan layanan yang belum berlangsung dan
+// mengembalikan kuota hariannya.
+func Batal(id string) (*queries.PemesananLayanan, error) {
+	p, err := queries.DetailPemesananLayanan(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, domain.Fail(domain.NotFound, "pemesanan layanan %s tidak ditemukan", id)
+	}
+	for i := 0; ; i++ {
+		if !bisaDibatalkan(p.StatusPemesanan) {
+			return nil, domain.Fail(domain.Conflict, "pemesanan layanan %s sudah %s", id, p.StatusPemesanan)
+		}
+		applied, sekarang, err := queries.UbahStatusLayanan(id, p.StatusPemesanan, "dibatalkan")
+		if err != nil {
+			return nil, err
+		}
+		if applied {
+			break
+		}
+		if i == casRetry {
+			return nil, domain.Fail(domain.Conflict, "status pemesanan layanan %s sedang diubah, coba lagi", id)
+		}
+		p.StatusPemesanan = sekarang
+	}
+	p.StatusPemesanan = "dibatalkan"
+	lepasKuota(p)
+	return p, nil
+}
+
+// bisaDibatalkan melaporkan apakah pemesanan berstatus status belum
+// berlangsung, selesai atau dibatalkan.
+func bisaDibatalkan(status string) bool {
+	switch status {
+	case "dibatalkan", "selesai", "sedang berlangsung":
+		return false
+	}
+	return true
+}
+
+// LepasKuota mengembalikan kuota harian pesanan yang dibatalkan di luar
+// Batal (mis. pembayaran kedaluwarsa).
+func LepasKuota(id string) error {
+	p, err := queries.DetailPemesananLayanan(id)
+	if err != nil || p == nil {
+		return err
+	}
+	lepasKuota(p)
+	return nil
+}
+
+// lepasKuota melewati pesanan lama tanpa RS/layanan karena tidak tercatat
+// di kuota.
+func lepasKuota(p *queries.PemesananLayanan) {
+	if p.IdRS != "" && p.IdLayanan != "" {
+		kembalikanKuota(p.IdRS, p.IdLayanan, Tanggal(p.JadwalPelaksanaan))
+	}
+}
+
+// PesananPasien mengembalikan paling banyak limit (0 = semua) pemesanan
+// layanan pasien beserta statusnya, jadwal terbaru lebih dulu.
+func PesananPasien(email string, limit int) ([]queries.PemesananLayanan, error) {
+	if err := queries.ValidEmail(email); err != nil {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: "email pasien tidak valid", Details: []string{err.Error()}}
+	}
+	list, err := queries.PemesananLayananPasien(email, limit)
+	if err != nil {
+		return nil, err
+	}
+	return withStatus(list)
+}
+
+// JadwalRS mengembalikan paling banyak limit (0 = semua) pemesanan layanan
+// RS dengan jadwal dalam [dari, dari+hari) yang belum dibatalkan, urut
+// jadwal. Jadwal dibaca per hari (satu partition, dibatasi kapasitas
+// harian) agar pesanan yang dibatalkan tidak mengurangi jumlah hasil.
+func JadwalRS(idRS string, dari time.Time, hari, limit int) ([]queries.PemesananLayanan, error) {
+	sampai := dari.AddDate(0, 0, hari)
+	var out []queries.PemesananLayanan
+	for mulai := dari; mulai.Before(sampai); {
+		y, m, d := mulai.In(queries.ZonaJadwal).Date()
+		akhir := time.Date(y, m, d+1, 0, 0, 0, 0, queries.ZonaJadwal)
+		if akhir.After(sampai) {
+			akhir = sampai
+		}
+		list, err := queries.JadwalLayananRS(idRS, mulai, akhir, 0)
+		if err != nil {
+			return nil, err
+		}
+		list, err = withStatus(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range list {
+			if p.StatusPemesanan == "dibatalkan" {
+				continue
+			}
+			out = append(out, p)
+			if limit > 0 && len(out) == limit {
+				return out, nil
+			}
+		}
+		mulai = akhir
+	}
+	return out, nil
+}
+
+// withStatus melengkapi status dari pemesanan_layanan.
+func withStatus(list []queries.PemesananLayanan) ([]queries.PemesananLayanan, error) {
+	ids := make([]string, len(list))
+	for i, p := range list {
+		ids[i] = p.IdPesanan
+	}
+	status, err := queries.StatusPemesananLayananPerID(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].StatusPemesanan = status[list[i].IdPesanan]
+	}
+	return list, nil
+}
@@ -0,0 +1,76 @@
+package layanan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTanggal(t *testing.T) {
+	tests := []struct {
+		jadwal string
+		want   string
+	}{
+		{"2025-01-05T16:59:00Z", "2025-01-05"},
+		{"2025-01-05T17:00:00Z", "2025-01-06"},
+		{"2025-01-06T00:30:00+07:00", "2025-01-06"},
+		{"2025-01-06T00:30:00+09:00", "2025-01-05"},
+	}
+	for _, tt := range tests {
+		jadwal, err := time.Parse(time.RFC3339, tt.jadwal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Zona mesin tidak boleh memengaruhi kunci kuota.
+		for _, zona := range []*time.Location{time.UTC, time.FixedZone("X", -10*60*60)} {
+			if got := Tanggal(jadwal.In(zona)); got != tt.want {
+				t.Errorf("Tanggal(%s di %s) = %s, ingin %s", tt.jadwal, zona, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestPermintaanValidate(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	valid := Permintaan{EmailPasien: "pasien1@mail.com", IdRS: "RS001", IdLayanan: "L001", Jadwal: now.Add(time.Hour)}
+	tests := []struct {
+		name    string
+		ubah    func(p *Permintaan)
+		wantErr bool
+	}{
+		{"valid", func(p *Permintaan) {}, false},
+		{"email tidak valid", func(p *Permintaan) { p.EmailPasien = "pasien" }, true},
+		{"tanpa rs", func(p *Permintaan) { p.IdRS = " " }, true},
+		{"tanpa layanan", func(p *Permintaan) { p.IdLayanan = "" }, true},
+		{"tanpa jadwal", func(p *Permintaan) { p.Jadwal = time.Time{} }, true},
+		{"jadwal sekarang", func(p *Permintaan) { p.Jadwal = now }, true},
+		{"jadwal batas terjauh", func(p *Permintaan) { p.Jadwal = now.AddDate(0, 0, MaksHariKeDepan) }, false},
+		{"jadwal terlalu jauh", func(p *Permintaan) { p.Jadwal = now.AddDate(0, 0, MaksHariKeDepan).Add(time.Minute) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.ubah(&p)
+			if err := p.validate(now); (err != nil) != tt.wantErr {
+				t.Fatalf("validate = %v, ingin error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBisaDibatalkan(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"belum dibayar", true},
+		{"dijadwalkan", true},
+		{"sedang berlangsung", false},
+		{"selesai", false},
+		{"dibatalkan", false},
+	}
+	for _, tt := range tests {
+		if got := bisaDibatalkan(tt.status); got != tt.want {
+			t.Errorf("bisaDibatalkan(%q) = %v, ingin %v", tt.status, got, tt.want)
+		}
+	}
+}
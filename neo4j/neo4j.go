@@ -2,11 +2,14 @@ package neo4j
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
 )
 
 var (
@@ -14,22 +17,47 @@ var (
 	ctx    = context.Background()
 )
 
+// Config berisi parameter koneksi Neo4j.
+type Config struct {
+	URI      string
+	User     string
+	Password string
+}
+
+// ConfigFromEnv membaca NEO4J_URI, NEO4J_USER dan NEO4J_PASSWORD dengan
+// nilai default untuk docker-compose.
+func ConfigFromEnv() Config {
+	return Config{
+		URI:      getEnv("NEO4J_URI", "bolt://127.0.0.1:7687"),
+		User:     getEnv("NEO4J_USER", "neo4j"),
+		Password: getEnv("NEO4J_PASSWORD", "password123"),
+	}
+}
+
+// DefaultConfig dipakai InitNeo4j. CLI dapat menimpanya dengan nilai dari
+// flag atau file konfigurasi sebelum seeder/simulator terhubung.
+var DefaultConfig = ConfigFromEnv()
+
 // ====================================
 // Init Neo4j connection
 // ====================================
 func InitNeo4j() {
-	uri := getEnv("NEO4J_URI", "bolt://127.0.0.1:7687")
-	user := getEnv("NEO4J_USER", "neo4j")
-	pass := getEnv("NEO4J_PASSWORD", "password123")
-
-	var err error
-	driver, err = neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(user, pass, ""))
-	if err != nil {
+	if err := Connect(DefaultConfig); err != nil {
 		log.Fatalf("Failed to connect to Neo4j: %v", err)
 	}
 	fmt.Println("Connected to Neo4j")
 }
 
+// Connect membuat driver global dengan konfigurasi yang diberikan.
+func Connect(cfg Config) error {
+	d, err := neo4j.NewDriverWithContext(cfg.URI, neo4j.BasicAuth(cfg.User, cfg.Password, ""))
+	if err != nil {
+		return err
+	}
+	driver = d
+	return nil
+}
+
 // Close driver
 func CloseNeo4j() {
 	if driver != nil {
@@ -104,6 +132,207 @@ func DeleteNeo4j(query string, params map[string]interface{}) error {
 	return runWrite(query, params)
 }
 
+// Write many rows in one transaction. The query receives the rows as $rows
+// and is expected to start with UNWIND $rows AS row.
+func WriteBatchNeo4j(query string, rows []map[string]interface{}) error {
+	return runWrite(query, map[string]interface{}{"rows": rows})
+}
+
+// ExplainNeo4j runs the query with EXPLAIN (planned, never executed) and returns
+// the column keys it would produce. Syntax and parameter errors surface here.
+func ExplainNeo4j(query string, params map[string]interface{}) ([]string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	res, err := session.Run(ctx, "EXPLAIN "+query, params)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := res.Keys()
+	if err != nil {
+		return nil, err
+	}
+	_, err = res.Consume(ctx)
+	return keys, err
+}
+
+// ====================================
+// Ad-hoc statements (rs shell, rs script)
+// ====================================
+
+// Plan is one operator of an EXPLAIN or PROFILE plan. DbHits and Rows are
+// only filled for PROFILE.
+type Plan struct {
+	Operator    string
+	Arguments   map[string]interface{}
+	Identifiers []string
+	DbHits      int64
+	Rows        int64
+	Children    []*Plan
+}
+
+// Counter is one non-zero update counter, e.g. {"nodes created", 2}.
+type Counter struct {
+	Name  string
+	Value int
+}
+
+// Summary describes a finished statement.
+type Summary struct {
+	Counters []Counter
+	Plan     *Plan // nil unless the statement was EXPLAINed or PROFILEd
+	Profiled bool
+	// AvailableAfter is the server time until the first record was ready.
+	AvailableAfter time.Duration
+}
+
+// StreamNeo4j runs a statement in an auto-commit transaction (so schema
+// commands and CALL {} IN TRANSACTIONS work too) and calls fn for every
+// record with the column keys and plain Go values (see Plain). Returning an
+// error from fn stops reading and discards the remaining records.
+func StreamNeo4j(query string, params map[string]interface{}, fn func(keys []string, values []interface{}) error) (Summary, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	res, err := session.Run(ctx, query, params)
+	if err != nil {
+		return Summary{}, err
+	}
+	keys, err := res.Keys()
+	if err != nil {
+		return Summary{}, err
+	}
+
+	for res.Next(ctx) {
+		values := res.Record().Values
+		plain := make([]interface{}, len(values))
+		for i, v := range values {
+			plain[i] = Plain(v)
+		}
+		if err := fn(keys, plain); err != nil {
+			res.Consume(ctx)
+			return Summary{}, err
+		}
+	}
+	if err := res.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	rs, err := res.Consume(ctx)
+	if err != nil {
+		return Summary{}, err
+	}
+	return summarize(rs), nil
+}
+
+// Plain converts driver values (nodes, relationships, paths, temporal and
+// spatial types) into maps, slices and strings that render as table cells
+// and marshal cleanly to JSON.
+func Plain(v interface{}) interface{} {
+	switch t := v.(type) {
+	case dbtype.Node:
+		return map[string]interface{}{"labels": t.Labels, "properties": plainMap(t.Props)}
+	case dbtype.Relationship:
+		return map[string]interface{}{"type": t.Type, "properties": plainMap(t.Props)}
+	case dbtype.Path:
+		nodes := make([]interface{}, len(t.Nodes))
+		for i, n := range t.Nodes {
+			nodes[i] = Plain(n)
+		}
+		rels := make([]interface{}, len(t.Relationships))
+		for i, r := range t.Relationships {
+			rels[i] = Plain(r)
+		}
+		return map[string]interface{}{"nodes": nodes, "relationships": rels}
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			out[i] = Plain(item)
+		}
+		return out
+	case map[string]interface{}:
+		return plainMap(t)
+	case dbtype.Date, dbtype.LocalDateTime, dbtype.LocalTime, dbtype.Time, dbtype.Duration,
+		dbtype.Point2D, dbtype.Point3D:
+		return fmt.Sprint(t)
+	}
+	return v
+}
+
+func plainMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = Plain(v)
+	}
+	return out
+}
+
+func summarize(rs neo4j.ResultSummary) Summary {
+	s := Summary{AvailableAfter: rs.ResultAvailableAfter()}
+
+	c := rs.Counters()
+	for _, counter := range []Counter{
+		{"nodes created", c.NodesCreated()},
+		{"nodes deleted", c.NodesDeleted()},
+		{"relationships created", c.RelationshipsCreated()},
+		{"relationships deleted", c.RelationshipsDeleted()},
+		{"properties set", c.PropertiesSet()},
+		{"labels added", c.LabelsAdded()},
+		{"labels removed", c.LabelsRemoved()},
+		{"indexes added", c.IndexesAdded()},
+		{"indexes removed", c.IndexesRemoved()},
+		{"constraints added", c.ConstraintsAdded()},
+		{"constraints removed", c.ConstraintsRemoved()},
+		{"system updates", c.SystemUpdates()},
+	} {
+		if counter.Value != 0 {
+			s.Counters = append(s.Counters, counter)
+		}
+	}
+
+	if p := rs.Profile(); p != nil {
+		s.Plan, s.Profiled = profiledPlan(p), true
+	} else if p := rs.Plan(); p != nil {
+		s.Plan = plan(p)
+	}
+	return s
+}
+
+func plan(p neo4j.Plan) *Plan {
+	out := &Plan{Operator: p.Operator(), Arguments: p.Arguments(), Identifiers: p.Identifiers()}
+	for _, child := range p.Children() {
+		out.Children = append(out.Children, plan(child))
+	}
+	return out
+}
+
+func profiledPlan(p neo4j.ProfiledPlan) *Plan {
+	out := &Plan{
+		Operator:    p.Operator(),
+		Arguments:   p.Arguments(),
+		Identifiers: p.Identifiers(),
+		DbHits:      p.DbHits(),
+		Rows:        p.Records(),
+	}
+	for _, child := range p.Children() {
+		out.Children = append(out.Children, profiledPlan(child))
+	}
+	return out
+}
+
+// IsTransient reports whether err is a temporary failure (deadlock,
+// leader switch, connectivity) that is safe to retry.
+func IsTransient(err error) bool {
+	return err != nil && (neo4j.IsRetryable(err) || neo4j.IsConnectivityError(err))
+}
+
+// IsConstraintViolation reports whether err was caused by a uniqueness (or
+// other schema) constraint, e.g. creating a node with an existing key.
+func IsConstraintViolation(err error) bool {
+	var nerr *neo4j.Neo4jError
+	return errors.As(err, &nerr) && nerr.Code == "Neo.ClientError.Schema.ConstraintValidationFailed"
+}
+
 // --- Internal Helper ---
 func runWrite(query string, params map[string]interface{}) error {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
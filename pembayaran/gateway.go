@@ -0,0 +1,231 @@
+package pembayaran
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/domain"
+	"src/queries"
+	"src/uang"
+)
+
+// ===============================================
+//   GATEWAY PEMBAYARAN
+// ===============================================
+//
+// Gateway adalah penyedia pembayaran: ia membuat payment intent yang
+// dibayar pasien lewat URL bayar, lalu mengirim hasilnya (dibayar, gagal,
+// kedaluwarsa) ke webhook kita. Gateway baru cukup memenuhi interface
+// Gateway dan didaftarkan dengan Daftarkan.
+//
+// Lokal adalah gateway palsu untuk pengembangan: intent hanya dicatat di
+// tabel pembayaran, dan event webhook dipicu manual lewat rs payment
+// simulate, ditandatangani HMAC-SHA256 seperti gateway sungguhan. Karena
+// siapa pun yang tahu rahasianya bisa menandai tagihan lunas, Lokal tidak
+// pernah terpasang otomatis: pemanggil memasangnya dengan PasangLokal bila
+// diaktifkan secara eksplisit, dan rahasia wajib diisi.
+
+// Intent adalah data yang dikirim ke gateway saat membuat payment intent.
+type Intent struct {
+	IdPembayaran string
+	NomorTagihan string
+	EmailPasien  string
+	Jumlah       uang.Rupiah
+	Kedaluwarsa  time.Time
+}
+
+// Event adalah isi webhook gateway setelah diverifikasi.
+type Event struct {
+	ID           string      `json:"id"`
+	Jenis        string      `json:"jenis"` // dibayar, gagal atau kedaluwarsa
+	Ref          string      `json:"ref"`   // id intent di gateway
+	IdPembayaran string      `json:"id_pembayaran"`
+	Jumlah       uang.Rupiah `json:"jumlah"`
+	Alasan       string      `json:"alasan,omitempty"`
+	Waktu        time.Time   `json:"waktu"`
+}
+
+// Gateway adalah penyedia pembayaran yang bisa dipasang.
+type Gateway interface {
+	// Nama dipakai di kolom pembayaran.gateway dan path webhook.
+	Nama() string
+	// BuatIntent mendaftarkan pembayaran di gateway dan mengembalikan id
+	// intent serta URL yang dibuka pasien untuk membayar.
+	BuatIntent(i Intent) (ref, urlBayar string, err error)
+	// BacaWebhook memverifikasi tanda tangan webhook dan membaca event.
+	BacaWebhook(body []byte, header http.Header) (Event, error)
+}
+
+var gateways = map[string]Gateway{}
+
+// Daftarkan memasang gateway; gateway dengan nama sama diganti.
+func Daftarkan(g Gateway) {
+	gateways[g.Nama()] = g
+}
+
+// Cari mengembalikan gateway bernama nama.
+func Cari(nama string) (Gateway, error) {
+	if g, ok := gateways[nama]; ok {
+		return g, nil
+	}
+	if len(gateways) == 0 {
+		return nil, domain.Fail(domain.NotFound, "gateway pembayaran %q tidak terpasang (belum ada gateway yang diaktifkan)", nama)
+	}
+	return nil, domain.Fail(domain.NotFound, "gateway pembayaran %q tidak dikenal (pilihan: %s)", nama, strings.Join(NamaGateway(), ", "))
+}
+
+// NamaGateway mengembalikan nama semua gateway yang terpasang, urut nama.
+func NamaGateway() []string {
+	names := make([]string, 0, len(gateways))
+	for nama := range gateways {
+		names = append(names, nama)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ===============================================
+//   GATEWAY LOKAL (PALSU)
+// ===============================================
+
+// NamaLokal adalah nama gateway palsu.
+const NamaLokal = "lokal"
+
+// HeaderTandaTangan berisi HMAC-SHA256 (hex) body webhook gateway lokal.
+const HeaderTandaTangan = "X-Rs-Signature"
+
+// Env gateway lokal: EnvLokal mengaktifkannya (mis. "1") dan EnvRahasia
+// berisi rahasia HMAC bersama pengirim dan penerima webhook.
+const (
+	EnvLokal   = "RS_PAYMENT_LOKAL"
+	EnvRahasia = "RS_PAYMENT_SECRET"
+)
+
+// LokalDiaktifkan melaporkan apakah env EnvLokal bernilai benar.
+func LokalDiaktifkan() bool {
+	aktif, _ := strconv.ParseBool(os.Getenv(EnvLokal))
+	return aktif
+}
+
+// Lokal adalah gateway palsu yang menandatangani webhook dengan rahasia
+// bersama.
+type Lokal struct {
+	rahasia []byte
+	client  *http.Client
+}
+
+// NewLokal membuat gateway lokal. Rahasia kosong ditolak; tidak ada
+// rahasia bawaan.
+func NewLokal(rahasia string) (*Lokal, error) {
+	if rahasia == "" {
+		return nil, domain.Fail(domain.Invalid, "gateway %s butuh rahasia webhook yang tidak kosong (env %s)", NamaLokal, EnvRahasia)
+	}
+	return &Lokal{rahasia: []byte(rahasia), client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// PasangLokal membuat gateway lokal dengan rahasia lalu mendaftarkannya.
+func PasangLokal(rahasia string) (*Lokal, error) {
+	l, err := NewLokal(rahasia)
+	if err != nil {
+		return nil, err
+	}
+	Daftarkan(l)
+	return l, nil
+}
+
+func (l *Lokal) Nama() string { return NamaLokal }
+
+func (l *Lokal) BuatIntent(i Intent) (string, string, error) {
+	ref := domain.NewID("pi_")
+	return ref, "http://localhost/bayar/" + ref, nil
+}
+
+func (l *Lokal) BacaWebhook(body []byte, header http.Header) (Event, error) {
+	var ev Event
+	got, err := hex.DecodeString(header.Get(HeaderTandaTangan))
+	if err != nil || !hmac.Equal(got, l.tandaTangan(body)) {
+		return ev, domain.Fail(domain.Unauthorized, "tanda tangan webhook %s tidak valid", NamaLokal)
+	}
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return ev, domain.Fail(domain.Invalid, "body webhook bukan event yang valid: %v", err)
+	}
+	return ev, nil
+}
+
+func (l *Lokal) tandaTangan(body []byte) []byte {
+	mac := hmac.New(sha256.New, l.rahasia)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Picu membuat event jenis untuk pembayaran p seperti yang akan dikirim
+// gateway. Event dengan id yang sama dikirim ulang oleh Kirim untuk
+// mensimulasikan webhook ganda.
+func (l *Lokal) Picu(p queries.Pembayaran, jenis, alasan string, waktu time.Time) (Event, error) {
+	if p.Gateway != NamaLokal {
+		return Event{}, domain.Fail(domain.Invalid, "pembayaran %s memakai gateway %s, bukan %s", p.IdPembayaran, p.Gateway, NamaLokal)
+	}
+	if err := cekJenis(jenis); err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:           domain.NewID("evt_"),
+		Jenis:        jenis,
+		Ref:          p.RefGateway,
+		IdPembayaran: p.IdPembayaran,
+		Jumlah:       p.Jumlah,
+		Alasan:       alasan,
+		Waktu:        waktu,
+	}, nil
+}
+
+// Kirim menandatangani event dan mengirimnya ke url webhook (POST). url
+// kosong berarti diproses langsung di proses ini tanpa HTTP.
+func (l *Lokal) Kirim(ev Event, url string, now time.Time) (*Hasil, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(HeaderTandaTangan, hex.EncodeToString(l.tandaTangan(body)))
+	if url == "" {
+		return Terima(NamaLokal, body, header, now)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengirim webhook ke %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca balasan webhook %s: %v", url, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("webhook %s membalas %s: %s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	var out struct {
+		Data Hasil `json:"data"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("balasan webhook %s bukan JSON yang dikenal: %v", url, err)
+	}
+	return &out.Data, nil
+}
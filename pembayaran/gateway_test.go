@@ -0,0 +1,90 @@
+package pembayaran
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+
+	"src/domain"
+)
+
+func TestNewLokalTanpaRahasia(t *testing.T) {
+	if _, err := NewLokal(""); err == nil {
+		t.Fatal("NewLokal(\"\") = nil error, ingin error")
+	}
+	if _, err := PasangLokal(""); err == nil {
+		t.Fatal("PasangLokal(\"\") = nil error, ingin error")
+	}
+	if _, err := Cari(NamaLokal); err == nil {
+		t.Fatal("gateway lokal terpasang tanpa rahasia")
+	}
+}
+
+func TestLokalDiaktifkan(t *testing.T) {
+	tests := []struct {
+		env  string
+		want bool
+	}{
+		{"", false},
+		{"0", false},
+		{"false", false},
+		{"ya", false},
+		{"1", true},
+		{"true", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(EnvLokal, tt.env)
+			if got := LokalDiaktifkan(); got != tt.want {
+				t.Fatalf("LokalDiaktifkan() dengan %s=%q = %v, ingin %v", EnvLokal, tt.env, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLokalBacaWebhook(t *testing.T) {
+	l, err := NewLokal("rahasia-uji")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lain, err := NewLokal("rahasia-lain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"id": "evt_1", "jenis": "dibayar", "id_pembayaran": "PAY-1"}`)
+	tests := []struct {
+		name    string
+		body    []byte
+		tanda   string
+		wantErr bool
+		kind    domain.Kind
+	}{
+		{"tanda tangan benar", body, hex.EncodeToString(l.tandaTangan(body)), false, 0},
+		{"tanpa tanda tangan", body, "", true, domain.Unauthorized},
+		{"bukan hex", body, "zz", true, domain.Unauthorized},
+		{"rahasia lain", body, hex.EncodeToString(lain.tandaTangan(body)), true, domain.Unauthorized},
+		{"body diubah", []byte(`{"id": "evt_1", "jenis": "dibayar", "id_pembayaran": "PAY-2"}`), hex.EncodeToString(l.tandaTangan(body)), true, domain.Unauthorized},
+		{"body bukan event", []byte(`[]`), hex.EncodeToString(l.tandaTangan([]byte(`[]`))), true, domain.Invalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(HeaderTandaTangan, tt.tanda)
+			ev, err := l.BacaWebhook(tt.body, header)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("BacaWebhook: %v", err)
+				}
+				if ev.ID != "evt_1" || ev.IdPembayaran != "PAY-1" {
+					t.Fatalf("event = %+v", ev)
+				}
+				return
+			}
+			var e *domain.Error
+			if !errors.As(err, &e) || e.Kind != tt.kind {
+				t.Fatalf("BacaWebhook error = %v, ingin Error kind %d", err, tt.kind)
+			}
+		})
+	}
+}
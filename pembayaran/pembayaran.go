@@ -0,0 +1,410 @@
+package pembayaran
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"src/apotek"
+	"src/domain"
+	"src/layanan"
+	"src/queries"
+	"src/tagihan"
+)
+
+// ===============================================
+//   PEMBAYARAN TAGIHAN
+// ===============================================
+//
+// Alur bayar: tagihan "belum dibayar" → klaim pembayaran_aktif (IF NOT
+// EXISTS, satu pembayaran berjalan per tagihan) → buat intent di gateway
+// → simpan pembayaran "menunggu". Intent berlaku sampai jatuh tempo
+// tagihan kecuali --berlaku lebih pendek.
+//
+// Alur webhook: verifikasi tanda tangan → catat event di
+// webhook_pembayaran (IF NOT EXISTS) → ubah status pembayaran dari
+// "menunggu" dengan LWT → terapkan ke tagihan dan pesanannya:
+//
+//   dibayar      tagihan → lunas, pesanan belum dibayar → dijadwalkan
+//   gagal        klaim pembayaran_aktif dilepas; tagihan bisa dibayar lagi
+//   kedaluwarsa  sebelum jatuh tempo tagihan: sama seperti gagal; setelah
+//                jatuh tempo: tagihan dan pesanan belum dibayar →
+//                dibatalkan, stok obat dan kuota layanan dikembalikan
+//
+// Setiap langkah memakai LWT dari status yang diharapkan, jadi webhook yang
+// dikirim ulang (event sama) atau proses yang terputus di tengah bisa
+// diulang tanpa efek ganda: langkah yang sudah terjadi dilewati.
+//
+// Dipakai oleh rs payment, job expire-payments dan endpoint pembayaran di
+// paket api.
+
+// Status pembayaran, sekaligus jenis event webhook.
+const (
+	StatusMenunggu    = "menunggu"
+	StatusDibayar     = "dibayar"
+	StatusGagal       = "gagal"
+	StatusKedaluwarsa = "kedaluwarsa"
+)
+
+// Jenis adalah jenis event webhook yang dikenal.
+var Jenis = []string{StatusDibayar, StatusGagal, StatusKedaluwarsa}
+
+// Status tagihan dan pesanan setelah pembayaran.
+const (
+	TagihanLunas   = "lunas"
+	TagihanBatal   = "dibatalkan"
+	PesananDibayar = "dijadwalkan"
+	PesananBatal   = "dibatalkan"
+)
+
+// MaksKedaluwarsa membatasi pembayaran yang dikedaluwarsakan per run.
+const MaksKedaluwarsa = 500
+
+func cekJenis(jenis string) error {
+	for _, j := range Jenis {
+		if j == jenis {
+			return nil
+		}
+	}
+	return domain.Fail(domain.Invalid, "jenis event %q tidak dikenal (pilihan: %s)", jenis, strings.Join(Jenis, ", "))
+}
+
+// Permintaan adalah permintaan membayar satu tagihan.
+type Permintaan struct {
+	NomorTagihan string
+	Gateway      string
+	Berlaku      time.Duration // 0 = sampai jatuh tempo tagihan
+}
+
+// Buat membuat payment intent untuk tagihan dan menyimpannya sebagai
+// pembayaran "menunggu".
+func Buat(p Permintaan, now time.Time) (*queries.Pembayaran, error) {
+	if strings.TrimSpace(p.NomorTagihan) == "" {
+		return nil, domain.Fail(domain.Invalid, "nomor tagihan wajib diisi")
+	}
+	if p.Berlaku < 0 {
+		return nil, domain.Fail(domain.Invalid, "masa berlaku tidak boleh negatif")
+	}
+	g, err := Cari(p.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	t, err := tagihan.Lihat(p.NomorTagihan)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != tagihan.StatusBaru {
+		return nil, domain.Fail(domain.Conflict, "tagihan %s sudah %s", t.Nomor, t.Status)
+	}
+	if t.Total <= 0 {
+		return nil, domain.Fail(domain.Invalid, "total tagihan %s %s, tidak ada yang perlu dibayar", t.Nomor, t.Total)
+	}
+	kedaluwarsa := t.JatuhTempo
+	if p.Berlaku > 0 && now.Add(p.Berlaku).Before(kedaluwarsa) {
+		kedaluwarsa = now.Add(p.Berlaku)
+	}
+	if !kedaluwarsa.After(now) {
+		return nil, domain.Fail(domain.Conflict, "tagihan %s sudah lewat jatuh tempo (%s)", t.Nomor, t.JatuhTempo.Local().Format("2006-01-02 15:04"))
+	}
+
+	bayar := queries.Pembayaran{
+		IdPembayaran: domain.NewID("PAY-"),
+		NomorTagihan: t.Nomor,
+		EmailPasien:  t.EmailPasien,
+		Gateway:      g.Nama(),
+		Jumlah:       t.Total,
+		Status:       StatusMenunggu,
+		Dibuat:       now,
+		Kedaluwarsa:  kedaluwarsa,
+		Diperbarui:   now,
+	}
+	if err := klaim(t.Nomor, bayar.IdPembayaran); err != nil {
+		return nil, err
+	}
+	bayar.RefGateway, bayar.URLBayar, err = g.BuatIntent(Intent{
+		IdPembayaran: bayar.IdPembayaran,
+		NomorTagihan: bayar.NomorTagihan,
+		EmailPasien:  bayar.EmailPasien,
+		Jumlah:       bayar.Jumlah,
+		Kedaluwarsa:  bayar.Kedaluwarsa,
+	})
+	if err == nil {
+		err = queries.SimpanPembayaran(bayar)
+	}
+	if err != nil {
+		lepas(t.Nomor, bayar.IdPembayaran)
+		return nil, err
+	}
+	return &bayar, nil
+}
+
+// klaim menandai id sebagai pembayaran berjalan tagihan. Klaim milik
+// pembayaran yang sudah gagal (pelepasannya terputus) diambil alih.
+func klaim(nomor, id string) error {
+	for i := 0; i < 2; i++ {
+		applied, sekarang, err := queries.KlaimPembayaranAktif(nomor, id)
+		if err != nil || applied {
+			return err
+		}
+		lama, err := queries.DetailPembayaran(sekarang)
+		if err != nil {
+			return err
+		}
+		if lama != nil && lama.Status != StatusGagal {
+			return &domain.Error{Kind: domain.Conflict, Message: fmt.Sprintf("tagihan %s sudah punya pembayaran %s", nomor, sekarang),
+				Details: []string{fmt.Sprintf("status %s, url bayar %s", lama.Status, lama.URLBayar)}}
+		}
+		if err := queries.LepasPembayaranAktif(nomor, sekarang); err != nil {
+			return err
+		}
+	}
+	return domain.Fail(domain.Conflict, "pembayaran tagihan %s sedang dibuat, coba lagi", nomor)
+}
+
+// lepas menghapus klaim pembayaran berjalan. Kegagalan hanya dicatat;
+// klaim milik pembayaran gagal diambil alih oleh pembayaran berikutnya.
+func lepas(nomor, id string) {
+	if err := queries.LepasPembayaranAktif(nomor, id); err != nil {
+		log.Printf("%v; hapus baris pembayaran_aktif %s secara manual", err, nomor)
+	}
+}
+
+// Lihat membaca satu pembayaran.
+func Lihat(id string) (*queries.Pembayaran, error) {
+	p, err := queries.DetailPembayaran(id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, domain.Fail(domain.NotFound, "pembayaran %s tidak ditemukan", id)
+	}
+	return p, nil
+}
+
+// DaftarTagihan membaca semua pembayaran satu tagihan, terbaru lebih dulu.
+func DaftarTagihan(nomor string) ([]queries.Pembayaran, error) {
+	if _, err := tagihan.Lihat(nomor); err != nil {
+		return nil, err
+	}
+	return queries.PembayaranTagihan(nomor)
+}
+
+// ===============================================
+//   WEBHOOK
+// ===============================================
+
+// Perubahan adalah perubahan status satu pesanan karena pembayaran.
+type Perubahan struct {
+	IdPesanan  string `json:"id_pesanan"`
+	Jenis      string `json:"jenis"`
+	StatusLama string `json:"status_lama"`
+	StatusBaru string `json:"status_baru"`
+}
+
+// Hasil adalah ringkasan pemrosesan satu event webhook.
+type Hasil struct {
+	IdEvent       string      `json:"id_event"`
+	Jenis         string      `json:"jenis"`
+	IdPembayaran  string      `json:"id_pembayaran"`
+	NomorTagihan  string      `json:"nomor_tagihan"`
+	Status        string      `json:"status"` // status pembayaran setelah diproses
+	StatusTagihan string      `json:"status_tagihan"`
+	Duplikat      bool        `json:"duplikat"` // event ini sudah pernah diterima
+	Pesanan       []Perubahan `json:"pesanan"`
+	Catatan       []string    `json:"catatan,omitempty"`
+}
+
+// Terima memverifikasi dan memproses webhook dari gateway. Event yang
+// sudah pernah diterima diproses ulang tanpa efek ganda dan ditandai
+// Duplikat.
+func Terima(gateway string, body []byte, header http.Header, now time.Time) (*Hasil, error) {
+	g, err := Cari(gateway)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := g.BacaWebhook(body, header)
+	if err != nil {
+		return nil, err
+	}
+	return proses(g.Nama(), ev, now)
+}
+
+func proses(gateway string, ev Event, now time.Time) (*Hasil, error) {
+	if ev.ID == "" || ev.IdPembayaran == "" {
+		return nil, domain.Fail(domain.Invalid, "event webhook tanpa id atau id_pembayaran")
+	}
+	if err := cekJenis(ev.Jenis); err != nil {
+		return nil, err
+	}
+	p, err := Lihat(ev.IdPembayaran)
+	if err != nil {
+		return nil, err
+	}
+	if p.Gateway != gateway || (ev.Ref != "" && ev.Ref != p.RefGateway) {
+		return nil, domain.Fail(domain.Invalid, "event %s tidak cocok dengan pembayaran %s (gateway %s, ref %s)", ev.ID, p.IdPembayaran, p.Gateway, p.RefGateway)
+	}
+	if ev.Jenis == StatusDibayar && ev.Jumlah != p.Jumlah {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: fmt.Sprintf("jumlah dibayar untuk %s tidak sama dengan tagihan", p.IdPembayaran),
+			Details: []string{fmt.Sprintf("dibayar %s, tagihan %s", ev.Jumlah, p.Jumlah)}}
+	}
+
+	baru, err := queries.CatatWebhook(gateway, ev.ID, p.IdPembayaran, ev.Jenis, now)
+	if err != nil {
+		return nil, err
+	}
+	h := &Hasil{IdEvent: ev.ID, Jenis: ev.Jenis, IdPembayaran: p.IdPembayaran, NomorTagihan: p.NomorTagihan, Duplikat: !baru}
+
+	status := p.Status
+	if status == StatusMenunggu {
+		_, status, err = queries.UbahStatusPembayaran(p.IdPembayaran, StatusMenunggu, ev.Jenis, ev.Alasan, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	h.Status = status
+	if status != ev.Jenis {
+		// Pembayaran sudah selesai dengan hasil lain (mis. dibayar setelah
+		// kedaluwarsa); gateway tetap mendapat 200 agar tidak mengirim ulang.
+		h.Catatan = append(h.Catatan, fmt.Sprintf("pembayaran sudah %s; event %s diabaikan", status, ev.Jenis))
+		if ev.Jenis == StatusDibayar {
+			h.Catatan = append(h.Catatan, "dana diterima untuk pembayaran yang sudah selesai, kembalikan dana secara manual")
+		}
+		h.StatusTagihan, err = statusTagihan(p.NomorTagihan)
+		return h, err
+	}
+	return h, terapkan(h, p, now)
+}
+
+// terapkan meneruskan hasil pembayaran ke tagihan dan pesanannya.
+func terapkan(h *Hasil, p *queries.Pembayaran, now time.Time) error {
+	t, err := tagihan.Lihat(p.NomorTagihan)
+	if err != nil {
+		return err
+	}
+	h.StatusTagihan = t.Status
+
+	var statusTagihan, statusPesanan string
+	switch h.Status {
+	case StatusGagal:
+		lepas(p.NomorTagihan, p.IdPembayaran)
+		h.Catatan = append(h.Catatan, "tagihan bisa dibayar lagi dengan pembayaran baru")
+		return nil
+	case StatusDibayar:
+		statusTagihan, statusPesanan = TagihanLunas, PesananDibayar
+	case StatusKedaluwarsa:
+		if !lewatJatuhTempo(t, now) {
+			lepas(p.NomorTagihan, p.IdPembayaran)
+			h.Catatan = append(h.Catatan, fmt.Sprintf("tagihan belum jatuh tempo (%s), bisa dibayar lagi dengan pembayaran baru", t.JatuhTempo.Local().Format("2006-01-02")))
+			return nil
+		}
+		statusTagihan, statusPesanan = TagihanBatal, PesananBatal
+	}
+
+	if t.Status == tagihan.StatusBaru {
+		if _, h.StatusTagihan, err = queries.UbahStatusTagihan(t.Nomor, tagihan.StatusBaru, statusTagihan); err != nil {
+			return err
+		}
+	}
+	if h.StatusTagihan != statusTagihan {
+		h.Catatan = append(h.Catatan, fmt.Sprintf("tagihan %s sudah %s, tidak diubah", t.Nomor, h.StatusTagihan))
+	}
+
+	jenis := map[string]string{}
+	for _, b := range t.Baris {
+		if b.Jenis != tagihan.JenisPenyesuaian {
+			jenis[b.IdPesanan] = b.Jenis
+		}
+	}
+	for _, id := range t.Pesanan() {
+		applied, sekarang, err := ubahPesanan(jenis[id], id, statusPesanan)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			if sekarang != statusPesanan && h.Status == StatusDibayar {
+				h.Catatan = append(h.Catatan, fmt.Sprintf("pesanan %s sudah %s sebelum dibayar, periksa pengembalian dana", id, sekarang))
+			}
+			continue
+		}
+		h.Pesanan = append(h.Pesanan, Perubahan{IdPesanan: id, Jenis: jenis[id], StatusLama: tagihan.StatusBaru, StatusBaru: statusPesanan})
+		if statusPesanan == PesananBatal {
+			kembalikan(jenis[id], id)
+		}
+	}
+	return nil
+}
+
+// lewatJatuhTempo menentukan apakah pembayaran kedaluwarsa ikut
+// membatalkan tagihan. Sebelum jatuh tempo intent yang kedaluwarsa hanya
+// melepas klaim pembayaran_aktif, sama seperti pembayaran gagal.
+func lewatJatuhTempo(t *tagihan.Tagihan, now time.Time) bool {
+	return !now.Before(t.JatuhTempo)
+}
+
+// ubahPesanan mengubah pesanan yang masih belum dibayar ke status baru.
+// applied false berarti pesanan sudah berstatus lain (sekarang).
+func ubahPesanan(jenis, id, baru string) (bool, string, error) {
+	if jenis == tagihan.JenisLayanan {
+		return queries.UbahStatusLayanan(id, tagihan.StatusBaru, baru)
+	}
+	return queries.UbahStatusObat(id, tagihan.StatusBaru, baru)
+}
+
+// kembalikan melepas stok obat atau kuota layanan pesanan yang baru saja
+// dibatalkan. Kegagalan hanya dicatat karena pembatalan sudah tersimpan.
+func kembalikan(jenis, id string) {
+	var err error
+	if jenis == tagihan.JenisLayanan {
+		err = layanan.LepasKuota(id)
+	} else {
+		err = apotek.KembalikanStok(id)
+	}
+	if err != nil {
+		log.Printf("gagal mengembalikan stok/kuota pesanan %s: %v", id, err)
+	}
+}
+
+func statusTagihan(nomor string) (string, error) {
+	t, err := tagihan.Lihat(nomor)
+	if err != nil {
+		return "", err
+	}
+	return t.Status, nil
+}
+
+// Kedaluwarsakan memproses pembayaran "menunggu" yang sudah lewat batas
+// sebagai event kedaluwarsa, untuk gateway yang tidak mengirim webhook
+// kedaluwarsa (termasuk gateway lokal). Id event diturunkan dari id
+// pembayaran sehingga run yang diulang tetap idempoten.
+func Kedaluwarsakan(ctx context.Context, now time.Time, limit int) ([]Hasil, error) {
+	list, err := queries.PembayaranMenungguSebelum(now, limit)
+	if err != nil {
+		return nil, err
+	}
+	var out []Hasil
+	var gagal []string
+	for _, m := range list {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		h, err := proses(m.Gateway, Event{
+			ID:           "kedaluwarsa-" + m.IdPembayaran,
+			Jenis:        StatusKedaluwarsa,
+			IdPembayaran: m.IdPembayaran,
+			Alasan:       "lewat batas " + m.Kedaluwarsa.Local().Format("2006-01-02 15:04"),
+			Waktu:        now,
+		}, now)
+		if err != nil {
+			gagal = append(gagal, fmt.Sprintf("%s: %v", m.IdPembayaran, err))
+			continue
+		}
+		out = append(out, *h)
+	}
+	if len(gagal) > 0 {
+		return out, &domain.Error{Kind: domain.Conflict, Message: fmt.Sprintf("%d dari %d pembayaran gagal dikedaluwarsakan", len(gagal), len(list)), Details: gagal}
+	}
+	return out, nil
+}
@@ -0,0 +1,26 @@
+package pembayaran
+
+import (
+	"testing"
+	"time"
+
+	"src/tagihan"
+)
+
+func TestLewatJatuhTempo(t *testing.T) {
+	t0 := &tagihan.Tagihan{JatuhTempo: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"intent kedaluwarsa sebelum jatuh tempo", t0.JatuhTempo.Add(-24 * time.Hour), false},
+		{"tepat jatuh tempo", t0.JatuhTempo, true},
+		{"setelah jatuh tempo", t0.JatuhTempo.Add(time.Minute), true},
+	}
+	for _, tt := range tests {
+		if got := lewatJatuhTempo(t0, tt.now); got != tt.want {
+			t.Errorf("%s: lewatJatuhTempo = %v, ingin %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/uang"
+)
+
+// ===============================================
+//   PESAN OBAT (APOTEK)
+// ===============================================
+
+// ObatPesanan adalah data obat yang dibutuhkan untuk membuat pesanan.
+type ObatPesanan struct {
+	IdObat string
+	Nama   string
+	Harga  uang.Rupiah
+	Stok   int
+}
+
+// PemesananObat adalah satu pesanan obat: header di pemesanan_obat dan
+// daftar obat di detail_pesanan_obat.
+type PemesananObat struct {
+	IdPesanan       string
+	EmailPemesan    string
+	WaktuPemesanan  time.Time
+	StatusPemesanan string
+	TotalHarga      uang.Rupiah
+	DaftarObat      map[string]int
+	// StokDipesan true bila stok obat pesanan ini sudah dipotong saat
+	// pesan. Pesanan tanpa tanda ini (mis. dari seeder) tidak mengembalikan
+	// stok saat dibatalkan.
+	StokDipesan bool
+}
+
+var (
+	qObatUntukPesanan  = use("obat.untuk_pesanan").with("ids").returns("id_obat", "nama", "harga", "stok")
+	qUbahStokObat      = use("obat.ubah_stok").with("stok_baru", "id_obat", "stok_lama")
+	qBuatPemesananObat = use("pemesanan_obat.buat").with("id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan", "total_harga", "stok_dipesan")
+	qBuatDetailPesanan = use("detail_pesanan_obat.buat").with("id_pesanan", "daftar_obat")
+	qRingkasanPesanan  = use("pemesanan_obat.ringkasan").with("id_pesanan").returns("id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan", "total_harga", "stok_dipesan")
+)
+
+// ObatUntukPesanan membaca obat dengan id yang diminta. Id yang tidak ada
+// di tabel obat tidak muncul di map hasil.
+func ObatUntukPesanan(ids []string) (map[string]ObatPesanan, error) {
+	iter, err := cassandra.SelectCassandra(qObatUntukPesanan.text(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca obat: %v", err)
+	}
+	out := make(map[string]ObatPesanan, len(ids))
+	var o ObatPesanan
+	for iter.Scan(&o.IdObat, &o.Nama, &o.Harga, &o.Stok) {
+		out[o.IdObat] = o
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca obat: %v", err)
+	}
+	return out, nil
+}
+
+// UbahStokObat mengganti stok obat dari lama ke baru dengan lightweight
+// transaction. Bila stok sudah berubah, applied false dan sekarang berisi
+// stok terbaru sehingga pemanggil bisa mencoba lagi.
+func UbahStokObat(idObat string, lama, baru int) (applied bool, sekarang int, err error) {
+	applied, prev, err := cassandra.CASCassandra(qUbahStokObat.text(), baru, idObat, lama)
+	if err != nil {
+		return false, 0, fmt.Errorf("gagal mengubah stok %s: %v", idObat, err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	stok, _ := prev["stok"].(int)
+	return false, stok, nil
+}
+
+// SimpanPemesananObat menulis header dan detail pesanan dalam satu
+// LOGGED BATCH.
+func SimpanPemesananObat(p PemesananObat) error {
+	err := cassandra.LoggedBatchCassandra(
+		cassandra.Statement{Query: qBuatPemesananObat.text(), Params: []interface{}{
+			p.IdPesanan, p.EmailPemesan, p.WaktuPemesanan, p.StatusPemesanan, p.TotalHarga, p.StokDipesan,
+		}},
+		cassandra.Statement{Query: qBuatDetailPesanan.text(), Params: []interface{}{p.IdPesanan, p.DaftarObat}},
+	)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan pesanan %s: %v", p.IdPesanan, err)
+	}
+	return nil
+}
+
+// RingkasanPemesananObat membaca header dan detail satu pesanan. Hasil nil
+// berarti pesanan tidak ada. TotalHarga 0 untuk pesanan yang dibuat sebelum
+// kolom total_harga ada.
+func RingkasanPemesananObat(id string) (*PemesananObat, error) {
+	iter, err := cassandra.SelectCassandra(qRingkasanPesanan.text(), id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pesanan %s: %v", id, err)
+	}
+	var p PemesananObat
+	found := iter.Scan(&p.IdPesanan, &p.EmailPemesan, &p.WaktuPemesanan, &p.StatusPemesanan, &p.TotalHarga, &p.StokDipesan)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pesanan %s: %v", id, err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	iter, err = cassandra.SelectCassandra(qDaftarObat.text(), id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca detail pesanan %s: %v", id, err)
+	}
+	iter.Scan(&p.DaftarObat)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca detail pesanan %s: %v", id, err)
+	}
+	return &p, nil
+}
+
+var qPasienAda = use("pasien.ada").with("email").returns("email")
+
+// PasienAda melaporkan apakah node Pasien dengan email tersebut ada.
+func PasienAda(email string) (bool, error) {
+	records, err := neo4j.ReadNeo4j(qPasienAda.text(), map[string]interface{}{"email": email})
+	if err != nil {
+		return false, fmt.Errorf("gagal membaca pasien %s: %v", email, err)
+	}
+	return len(records) > 0, nil
+}
+
+// ===============================================
+//   TEBUS RESEP
+// ===============================================
+
+// ResepPesanan adalah resep beserta pasien dan baris obatnya.
+type ResepPesanan struct {
+	IdResep     string
+	Penyakit    string
+	IdJanjiTemu string
+	EmailPasien string // kosong bila janji temu/pasien tidak terhubung
+	LamaHari    int    // 0 bila Resep tidak punya lama_hari
+	Detail      []BarisResep
+}
+
+// BarisResep adalah satu DetailResep.
+type BarisResep struct {
+	IdObat   string
+	Dosis    string
+	LamaHari int // 0 bila DetailResep tidak punya lama_hari
+}
+
+// TautanResep adalah pesanan obat hasil tebus satu resep.
+type TautanResep struct {
+	IdResep     string
+	IdPesanan   string
+	EmailPasien string
+	WaktuDibuat time.Time
+}
+
+var (
+	qResepUntukPesanan = use("resep.untuk_pesanan").with("id_resep").returns("id_resep", "penyakit", "id_janji_temu", "email_pasien", "lama_hari", "detail")
+	qKlaimResep        = use("resep_pemesanan_obat.klaim").with("id_resep", "id_pesanan", "email_pasien", "waktu_dibuat")
+	qLepasResep        = use("resep_pemesanan_obat.lepas").with("id_resep", "id_pesanan")
+	qTautanResep       = use("resep_pemesanan_obat.per_resep").with("id_resep").returns("id_resep", "id_pesanan", "email_pasien", "waktu_dibuat")
+)
+
+// ResepUntukPesanan membaca resep beserta pasien dan detailnya. Hasil nil
+// berarti resep tidak ada.
+func ResepUntukPesanan(idResep string) (*ResepPesanan, error) {
+	records, err := neo4j.ReadNeo4j(qResepUntukPesanan.text(), map[string]interface{}{"id_resep": idResep})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca resep %s: %v", idResep, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	rec := records[0]
+	lama, _ := rec["lama_hari"].(int64)
+	r := &ResepPesanan{
+		IdResep:     stringValue(rec, "id_resep"),
+		Penyakit:    stringValue(rec, "penyakit"),
+		IdJanjiTemu: stringValue(rec, "id_janji_temu"),
+		EmailPasien: stringValue(rec, "email_pasien"),
+		LamaHari:    int(lama),
+	}
+	items, _ := rec["detail"].([]interface{})
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		lama, _ := m["lama_hari"].(int64)
+		r.Detail = append(r.Detail, BarisResep{
+			IdObat:   stringValue(m, "id_obat"),
+			Dosis:    stringValue(m, "dosis"),
+			LamaHari: int(lama),
+		})
+	}
+	return r, nil
+}
+
+// KlaimResep menautkan resep ke pesanan bila resep belum pernah ditebus.
+// Bila sudah, applied false dan lama berisi tautan yang ada.
+func KlaimResep(t TautanResep) (applied bool, lama *TautanResep, err error) {
+	applied, prev, err := cassandra.CASCassandra(qKlaimResep.text(), t.IdResep, t.IdPesanan, t.EmailPasien, t.WaktuDibuat)
+	if err != nil {
+		return false, nil, fmt.Errorf("gagal menautkan resep %s: %v", t.IdResep, err)
+	}
+	if applied {
+		return true, nil, nil
+	}
+	lama = &TautanResep{IdResep: t.IdResep}
+	lama.IdPesanan, _ = prev["id_pesanan"].(string)
+	lama.EmailPasien, _ = prev["email_pasien"].(string)
+	lama.WaktuDibuat, _ = prev["waktu_dibuat"].(time.Time)
+	return false, lama, nil
+}
+
+// LepasResep menghapus tautan resep yang masih menunjuk idPesanan.
+func LepasResep(idResep, idPesanan string) error {
+	if _, _, err := cassandra.CASCassandra(qLepasResep.text(), idResep, idPesanan); err != nil {
+		return fmt.Errorf("gagal melepas tautan resep %s: %v", idResep, err)
+	}
+	return nil
+}
+
+// TautanResepPesanan membaca pesanan hasil tebus resep; nil bila resep
+// belum ditebus.
+func TautanResepPesanan(idResep string) (*TautanResep, error) {
+	iter, err := cassandra.SelectCassandra(qTautanResep.text(), idResep)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca tautan resep %s: %v", idResep, err)
+	}
+	var t TautanResep
+	found := iter.Scan(&t.IdResep, &t.IdPesanan, &t.EmailPasien, &t.WaktuDibuat)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca tautan resep %s: %v", idResep, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &t, nil
+}
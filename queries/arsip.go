@@ -0,0 +1,146 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/uang"
+)
+
+// ===============================================
+//   ARSIP
+// ===============================================
+//
+// Akses data untuk paket arsip: salinan lengkap entitas sebelum dihapus
+// (baris apa adanya, atau node beserta relationship dan kunci tetangganya)
+// dan pemulihannya. Pemulihan tidak pernah menimpa data yang sudah ada.
+
+var (
+	qPulihkanPesananObat = use("pemesanan_obat.pulihkan").with("id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan", "total_harga")
+	qPulihkanLayanan     = use("pemesanan_layanan.pulihkan").with("id_pesanan", "email_pemesan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan")
+	qPulihkanLog         = use("log_aktivitas.pulihkan").with("id_perangkat", "waktu_aktivitas", "detail_aktivitas")
+	qArsipJanjiTemu      = use("janji_temu.arsip").with("ids").returns("id_janji_temu", "label", "properti", "relasi")
+	qPulihkanJanjiTemu   = use("janji_temu.pulihkan").with("properti", "relasi").returns("dibuat", "relasi")
+)
+
+// PesananObatLengkap adalah header pesanan obat beserta daftar obatnya.
+type PesananObatLengkap struct {
+	PesananObat
+	StatusPemesanan string
+	TotalHarga      uang.Rupiah
+	DaftarObat      map[string]int
+}
+
+// SalinanPesananObat membaca baris pemesanan_obat dan detail_pesanan_obat
+// satu pesanan apa adanya. Header nil berarti pesanan sudah tidak ada.
+func SalinanPesananObat(id string) (header, detail map[string]interface{}, err error) {
+	rows, _, err := cassandra.PageCassandra(qRingkasanPesanan.text(), []interface{}{id}, 1, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca pesanan obat %s: %v", id, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+	daftar, _, err := cassandra.PageCassandra(qDaftarObat.text(), []interface{}{id}, 1, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca daftar obat %s: %v", id, err)
+	}
+	if len(daftar) > 0 {
+		detail = map[string]interface{}{"id_pesanan": id, "daftar_obat": daftar[0]["daftar_obat"]}
+	}
+	return rows[0], detail, nil
+}
+
+// PulihkanPesananObat membuat ulang pesanan obat beserta daftar obatnya.
+// Mengembalikan false bila pesanan dengan id yang sama sudah ada.
+func PulihkanPesananObat(p PesananObatLengkap) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qPulihkanPesananObat.text(),
+		p.IdPesanan, p.EmailPemesan, p.WaktuPemesanan, p.StatusPemesanan, p.TotalHarga)
+	if err != nil {
+		return false, fmt.Errorf("gagal memulihkan pesanan obat %s: %v", p.IdPesanan, err)
+	}
+	if !applied || p.DaftarObat == nil {
+		return applied, nil
+	}
+	if err := cassandra.InsertCassandra(qBuatDetailPesanan.text(), p.IdPesanan, p.DaftarObat); err != nil {
+		return true, fmt.Errorf("pesanan obat %s dipulihkan tanpa daftar obat: %v", p.IdPesanan, err)
+	}
+	return true, nil
+}
+
+// PulihkanPemesananLayanan membuat ulang pemesanan layanan beserta baris
+// tabel query per pasien dan per RS. Kuota harian tidak dipesan ulang.
+func PulihkanPemesananLayanan(p PemesananLayanan) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qPulihkanLayanan.text(),
+		p.IdPesanan, p.EmailPemesan, p.IdRS, p.IdLayanan, p.NamaLayanan, p.BiayaLayanan,
+		p.WaktuPemesanan, p.JadwalPelaksanaan, p.StatusPemesanan)
+	if err != nil {
+		return false, fmt.Errorf("gagal memulihkan pemesanan layanan %s: %v", p.IdPesanan, err)
+	}
+	if !applied {
+		return false, nil
+	}
+	var statements []cassandra.Statement
+	if p.EmailPemesan != "" {
+		statements = append(statements, cassandra.Statement{Query: qBuatLayananPasien.text(),
+			Params: []interface{}{p.EmailPemesan, p.JadwalPelaksanaan, p.IdPesanan, p.IdRS, p.IdLayanan, p.NamaLayanan, p.BiayaLayanan}})
+	}
+	if p.IdRS != "" {
+		statements = append(statements, barisJadwalRS(p))
+	}
+	if len(statements) == 0 {
+		return true, nil
+	}
+	if err := cassandra.LoggedBatchCassandra(statements...); err != nil {
+		return true, fmt.Errorf("pemesanan layanan %s dipulihkan tanpa tabel query: %v", p.IdPesanan, err)
+	}
+	return true, nil
+}
+
+// PulihkanLogAktivitas membuat ulang satu log aktivitas.
+func PulihkanLogAktivitas(l LogAktivitas) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qPulihkanLog.text(), l.IDPerangkat, l.WaktuAktivitas, l.DetailAktivitas)
+	if err != nil {
+		return false, fmt.Errorf("gagal memulihkan log %s %s: %v", l.IDPerangkat, l.WaktuAktivitas.Format(time.RFC3339), err)
+	}
+	return applied, nil
+}
+
+// SalinanJanjiTemu membaca node janji temu dengan id tertentu beserta
+// relationship-nya. Setiap relasi berisi tipe, keluar (arah dari janji
+// temu), properti, serta label, kunci dan nilai kunci tetangga.
+func SalinanJanjiTemu(ids []string) ([]map[string]interface{}, error) {
+	records, err := neo4j.ReadNeo4j(qArsipJanjiTemu.text(), map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca salinan janji temu: %v", err)
+	}
+	for _, rec := range records {
+		for k, v := range rec {
+			rec[k] = neo4j.Plain(v)
+		}
+	}
+	return records, nil
+}
+
+// PulihkanJanjiTemu membuat ulang node janji temu dan relationship ke
+// tetangga yang masih ada. Mengembalikan false bila janji temu dengan id
+// yang sama sudah ada, serta jumlah relationship yang dibuat.
+func PulihkanJanjiTemu(properti map[string]interface{}, relasi []map[string]interface{}) (bool, int, error) {
+	list := make([]interface{}, len(relasi))
+	for i, r := range relasi {
+		list[i] = r
+	}
+	records, err := neo4j.CreateAndReturnNeo4j(qPulihkanJanjiTemu.text(),
+		map[string]interface{}{"properti": properti, "relasi": list})
+	if err != nil {
+		return false, 0, fmt.Errorf("gagal memulihkan janji temu %v: %v", properti["id_janji_temu"], err)
+	}
+	if len(records) == 0 {
+		return false, 0, nil
+	}
+	dibuat, _ := records[0]["dibuat"].(int64)
+	n, _ := records[0]["relasi"].(int64)
+	return dibuat > 0, int(n), nil
+}
@@ -0,0 +1,304 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/neo4j"
+)
+
+// ===============================================
+//   BOOKING: Jadwal praktik
+// ===============================================
+
+// JadwalPraktik adalah satu rentang jam praktik pada satu hari. Hari
+// ditulis senin..minggu, jam HH:MM dan DurasiSlot dalam menit.
+type JadwalPraktik struct {
+	Hari       string `json:"hari"`
+	JamMulai   string `json:"jam_mulai"`
+	JamSelesai string `json:"jam_selesai"`
+	DurasiSlot int    `json:"durasi_slot"`
+}
+
+// TenagaMedisJadwal adalah tenaga medis beserta tempat kerjanya dan jadwal
+// praktik yang tercatat di setiap tingkat (dokter, departemen, rumah sakit).
+type TenagaMedisJadwal struct {
+	Email            string
+	Nama             string
+	Departemen       string
+	IdRS             string
+	JadwalDokter     []JadwalPraktik
+	JadwalDepartemen []JadwalPraktik
+	JadwalRS         []JadwalPraktik
+}
+
+var (
+	qJadwalTenagaMedis   = use("tenaga_medis.jadwal_praktik").with("email").returns("email", "nama", "departemen", "id_rs", "jadwal_dokter", "jadwal_departemen", "jadwal_rs")
+	qSimpanJadwalPraktik = use("jadwal_praktik.simpan").with("pemilik", "kunci", "hari", "jam_mulai", "jam_selesai", "durasi_slot").returns("dibuat")
+	qHapusJadwalPraktik  = use("jadwal_praktik.hapus").with("pemilik", "kunci", "hari").returns("pemilik", "dihapus")
+)
+
+// Pemilik jadwal praktik.
+const (
+	PemilikDokter     = "dokter"
+	PemilikDepartemen = "departemen"
+	PemilikRumahSakit = "rumah_sakit"
+)
+
+// JadwalTenagaMedis membaca tenaga medis dan jadwal praktiknya. Mengembalikan
+// nil tanpa error bila tenaga medis tidak ditemukan.
+func JadwalTenagaMedis(email string) (*TenagaMedisJadwal, error) {
+	records, err := neo4j.ReadNeo4j(qJadwalTenagaMedis.text(), map[string]interface{}{"email": email})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca jadwal praktik: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	rec := records[0]
+	return &TenagaMedisJadwal{
+		Email:            stringValue(rec, "email"),
+		Nama:             stringValue(rec, "nama"),
+		Departemen:       stringValue(rec, "departemen"),
+		IdRS:             stringValue(rec, "id_rs"),
+		JadwalDokter:     jadwalList(rec["jadwal_dokter"]),
+		JadwalDepartemen: jadwalList(rec["jadwal_departemen"]),
+		JadwalRS:         jadwalList(rec["jadwal_rs"]),
+	}, nil
+}
+
+func jadwalList(v interface{}) []JadwalPraktik {
+	items, _ := v.([]interface{})
+	out := make([]JadwalPraktik, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		durasi, _ := m["durasi_slot"].(int64)
+		out = append(out, JadwalPraktik{
+			Hari:       stringValue(m, "hari"),
+			JamMulai:   stringValue(m, "jam_mulai"),
+			JamSelesai: stringValue(m, "jam_selesai"),
+			DurasiSlot: int(durasi),
+		})
+	}
+	return out
+}
+
+// SimpanJadwalPraktik mengganti jadwal praktik pemilik pada hari-hari
+// tersebut dan mengembalikan jumlah jadwal yang dibuat (0 berarti pemilik
+// tidak ditemukan).
+func SimpanJadwalPraktik(pemilik, kunci string, hari []string, jamMulai, jamSelesai string, durasiSlot int) (int, error) {
+	params := map[string]interface{}{
+		"pemilik":     pemilik,
+		"kunci":       kunci,
+		"hari":        hari,
+		"jam_mulai":   jamMulai,
+		"jam_selesai": jamSelesai,
+		"durasi_slot": durasiSlot,
+	}
+	records, err := neo4j.CreateAndReturnNeo4j(qSimpanJadwalPraktik.text(), params)
+	if err != nil {
+		return 0, fmt.Errorf("gagal menyimpan jadwal praktik: %v", err)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+	dibuat, _ := records[0]["dibuat"].(int64)
+	return int(dibuat), nil
+}
+
+// HapusJadwalPraktik menghapus jadwal praktik pemilik pada hari-hari
+// tersebut. found false berarti pemilik tidak ditemukan.
+func HapusJadwalPraktik(pemilik, kunci string, hari []string) (found bool, dihapus int, err error) {
+	params := map[string]interface{}{"pemilik": pemilik, "kunci": kunci, "hari": hari}
+	records, err := neo4j.CreateAndReturnNeo4j(qHapusJadwalPraktik.text(), params)
+	if err != nil {
+		return false, 0, fmt.Errorf("gagal menghapus jadwal praktik: %v", err)
+	}
+	if len(records) == 0 {
+		return false, 0, nil
+	}
+	n, _ := records[0]["pemilik"].(int64)
+	deleted, _ := records[0]["dihapus"].(int64)
+	return n > 0, int(deleted), nil
+}
+
+// ===============================================
+//   BOOKING: Janji temu
+// ===============================================
+
+// JanjiTemu disimpan dengan waktu lokal tanpa zona.
+const (
+	FormatWaktuJanji = "2006-01-02 15:04:05"
+	formatBatasJanji = "2006-01-02T15:04:05"
+)
+
+// JanjiTerjadwal adalah janji temu yang menempati jadwal dokter.
+// DurasiMenit 0 berarti janji temu dibuat sebelum durasi dicatat.
+type JanjiTerjadwal struct {
+	IDJanjiTemu      string
+	WaktuPelaksanaan string
+	DurasiMenit      int
+	Status           string
+}
+
+// Booking adalah satu janji temu beserta pihak-pihaknya.
+type Booking struct {
+	IDJanjiTemu      string `json:"id_janji_temu"`
+	WaktuPelaksanaan string `json:"waktu_pelaksanaan"`
+	DurasiMenit      int    `json:"durasi_menit,omitempty"`
+	Status           string `json:"status"`
+	Alasan           string `json:"alasan,omitempty"`
+	EmailPasien      string `json:"email_pasien"`
+	EmailDokter      string `json:"email_dokter"`
+	IdRS             string `json:"id_rs"`
+}
+
+// PesanJanji adalah parameter janji_temu.pesan.
+type PesanJanji struct {
+	ID            string
+	EmailPasien   string
+	EmailDokter   string
+	IdRS          string
+	Waktu         string // FormatWaktuJanji
+	Durasi        int    // menit
+	DurasiDefault int    // durasi janji temu lama tanpa durasi_menit
+	Alasan        string
+}
+
+// Bentrok adalah janji temu lain yang beririsan dengan waktu yang diminta.
+// Peran "dokter" atau "pasien" menunjukkan jadwal siapa yang bentrok.
+type Bentrok struct {
+	IDJanjiTemu      string
+	WaktuPelaksanaan string
+	Peran            string
+}
+
+var (
+	qJanjiTerjadwal    = use("janji_temu.terjadwal").with("email_dokter", "dari", "sampai").returns("id_janji_temu", "waktu_pelaksanaan", "durasi_menit", "status")
+	qBookingJanjiTemu  = use("janji_temu.booking").with("id").returns("id_janji_temu", "waktu_pelaksanaan", "durasi_menit", "status", "alasan", "email_pasien", "email_dokter", "id_rs")
+	qPesanJanjiTemu    = use("janji_temu.pesan").with("id", "email_pasien", "email_dokter", "id_rs", "waktu", "durasi", "durasi_default", "alasan").returns("id_janji_temu")
+	qJadwalUlangJanji  = use("janji_temu.jadwal_ulang").with("id", "waktu", "durasi", "durasi_default").returns("id_janji_temu")
+	qBentrokJanjiTemu  = use("janji_temu.bentrok").with("id", "email_dokter", "email_pasien", "waktu", "durasi", "durasi_default").returns("id_janji_temu", "waktu_pelaksanaan", "peran")
+	qBatalkanJanjiTemu = use("janji_temu.batalkan").with("id").returns("id_janji_temu")
+)
+
+// JanjiTemuTerjadwal mengembalikan janji temu dokter yang tidak dibatalkan
+// dan dimulai dalam [dari, sampai), urut waktu.
+func JanjiTemuTerjadwal(emailDokter string, dari, sampai time.Time) ([]JanjiTerjadwal, error) {
+	params := map[string]interface{}{
+		"email_dokter": emailDokter,
+		"dari":         dari.Format(formatBatasJanji),
+		"sampai":       sampai.Format(formatBatasJanji),
+	}
+	records, err := neo4j.ReadNeo4j(qJanjiTerjadwal.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca janji temu dokter: %v", err)
+	}
+
+	result := make([]JanjiTerjadwal, 0, len(records))
+	for _, rec := range records {
+		durasi, _ := rec["durasi_menit"].(int64)
+		result = append(result, JanjiTerjadwal{
+			IDJanjiTemu:      stringValue(rec, "id_janji_temu"),
+			WaktuPelaksanaan: stringValue(rec, "waktu_pelaksanaan"),
+			DurasiMenit:      int(durasi),
+			Status:           stringValue(rec, "status"),
+		})
+	}
+	return result, nil
+}
+
+// BookingJanjiTemu membaca satu janji temu. Mengembalikan nil tanpa error
+// bila tidak ditemukan.
+func BookingJanjiTemu(id string) (*Booking, error) {
+	records, err := neo4j.ReadNeo4j(qBookingJanjiTemu.text(), map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca janji temu: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	rec := records[0]
+	durasi, _ := rec["durasi_menit"].(int64)
+	return &Booking{
+		IDJanjiTemu:      stringValue(rec, "id_janji_temu"),
+		WaktuPelaksanaan: stringValue(rec, "waktu_pelaksanaan"),
+		DurasiMenit:      int(durasi),
+		Status:           stringValue(rec, "status"),
+		Alasan:           stringValue(rec, "alasan"),
+		EmailPasien:      stringValue(rec, "email_pasien"),
+		EmailDokter:      stringValue(rec, "email_dokter"),
+		IdRS:             stringValue(rec, "id_rs"),
+	}, nil
+}
+
+// PesanJanjiTemu membuat janji temu dalam satu transaksi. false berarti
+// tidak ada yang dibuat: jadwal bentrok atau pasien/dokter/RS tidak ada.
+func PesanJanjiTemu(p PesanJanji) (bool, error) {
+	params := map[string]interface{}{
+		"id":             p.ID,
+		"email_pasien":   p.EmailPasien,
+		"email_dokter":   p.EmailDokter,
+		"id_rs":          p.IdRS,
+		"waktu":          p.Waktu,
+		"durasi":         p.Durasi,
+		"durasi_default": p.DurasiDefault,
+		"alasan":         nullable(p.Alasan),
+	}
+	records, err := neo4j.CreateAndReturnNeo4j(qPesanJanjiTemu.text(), params)
+	if err != nil {
+		return false, fmt.Errorf("gagal membuat janji temu: %v", err)
+	}
+	return len(records) > 0, nil
+}
+
+// JadwalUlangJanjiTemu memindahkan janji temu ke waktu baru dalam satu
+// transaksi. false berarti tidak ada yang diubah: jadwal bentrok atau
+// janji temu sudah selesai/dibatalkan.
+func JadwalUlangJanjiTemu(id, waktu string, durasi, durasiDefault int) (bool, error) {
+	params := map[string]interface{}{"id": id, "waktu": waktu, "durasi": durasi, "durasi_default": durasiDefault}
+	records, err := neo4j.CreateAndReturnNeo4j(qJadwalUlangJanji.text(), params)
+	if err != nil {
+		return false, fmt.Errorf("gagal menjadwalkan ulang janji temu: %v", err)
+	}
+	return len(records) > 0, nil
+}
+
+// BentrokJanjiTemu mencari janji temu dokter atau pasien yang beririsan
+// dengan waktu yang diminta, selain janji temu id.
+func BentrokJanjiTemu(id, emailDokter, emailPasien, waktu string, durasi, durasiDefault int) ([]Bentrok, error) {
+	params := map[string]interface{}{
+		"id":             id,
+		"email_dokter":   emailDokter,
+		"email_pasien":   emailPasien,
+		"waktu":          waktu,
+		"durasi":         durasi,
+		"durasi_default": durasiDefault,
+	}
+	records, err := neo4j.ReadNeo4j(qBentrokJanjiTemu.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memeriksa bentrok janji temu: %v", err)
+	}
+	result := make([]Bentrok, 0, len(records))
+	for _, rec := range records {
+		result = append(result, Bentrok{
+			IDJanjiTemu:      stringValue(rec, "id_janji_temu"),
+			WaktuPelaksanaan: stringValue(rec, "waktu_pelaksanaan"),
+			Peran:            stringValue(rec, "peran"),
+		})
+	}
+	return result, nil
+}
+
+// BatalkanJanjiTemu mengubah status janji temu menjadi 'dibatalkan'. false
+// berarti janji temu tidak ada atau sudah selesai/dibatalkan.
+func BatalkanJanjiTemu(id string) (bool, error) {
+	records, err := neo4j.CreateAndReturnNeo4j(qBatalkanJanjiTemu.text(), map[string]interface{}{"id": id})
+	if err != nil {
+		return false, fmt.Errorf("gagal membatalkan janji temu: %v", err)
+	}
+	return len(records) > 0, nil
+}
@@ -0,0 +1,669 @@
+package queries
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// ===============================================
+//   KATALOG QUERY
+// ===============================================
+//
+// cassandra_cql_queries.cql dan neo4j_browser_queries.cypher adalah sumber
+// semua statement yang dijalankan package ini. Setiap entri diawali header
+// komentar:
+//
+//	-- name: obat.stok_menipis            (// untuk Cypher)
+//	-- doc: Obat dengan stok di bawah batas.
+//	-- param: max_stok int 55             (nama, tipe, contoh nilai)
+//	-- columns: id_obat, nama, label, stok
+//	SELECT ... WHERE stok < ? ALLOW FILTERING;
+//
+// Parameter CQL ditulis sebagai ? sesuai urutan baris param; parameter
+// Cypher ditulis $nama. Tipe param: text, int, double, boolean, timestamp
+// (RFC3339), list<text> (dipisah koma), map<text,int> (kunci=nilai
+// dipisah koma) dan json (map/list Cypher, contoh ditulis sebagai JSON).
+// Statement tanpa header name ditolak sehingga file tidak bisa lagi berisi
+// query salinan yang tidak pernah dijalankan.
+//
+// Katalog di-embed ke binary; RS_QUERY_DIR dapat menunjuk direktori berisi
+// kedua file untuk mencoba perubahan tanpa build ulang. Saat dimuat, setiap
+// query yang dipakai kode Go (lihat use) dicocokkan nama, param dan
+// kolomnya dengan entri katalog.
+
+//go:embed cassandra_cql_queries.cql neo4j_browser_queries.cypher
+var catalogFiles embed.FS
+
+// CatalogDirEnv adalah variabel lingkungan untuk memuat katalog dari disk.
+const CatalogDirEnv = "RS_QUERY_DIR"
+
+const (
+	StoreCassandra = "cassandra"
+	StoreNeo4j     = "neo4j"
+)
+
+var catalogSources = []struct {
+	File, Store, Comment string
+}{
+	{"cassandra_cql_queries.cql", StoreCassandra, "--"},
+	{"neo4j_browser_queries.cypher", StoreNeo4j, "//"},
+}
+
+// Param adalah parameter bernama sebuah statement beserta contoh nilainya.
+type Param struct {
+	Name    string
+	Type    string
+	Example string
+}
+
+// Value mengubah contoh nilai menjadi nilai Go sesuai tipe param.
+func (p Param) Value() (interface{}, error) {
+	switch p.Type {
+	case "text":
+		return p.Example, nil
+	case "int":
+		return strconv.Atoi(p.Example)
+	case "double":
+		return strconv.ParseFloat(p.Example, 64)
+	case "boolean":
+		return strconv.ParseBool(p.Example)
+	case "timestamp":
+		return time.Parse(time.RFC3339, p.Example)
+	case "list<text>":
+		items := strings.Split(p.Example, ",")
+		for i := range items {
+			items[i] = strings.TrimSpace(items[i])
+		}
+		return items, nil
+	case "map<text,int>":
+		m := map[string]int{}
+		for _, item := range strings.Split(p.Example, ",") {
+			k, v, ok := strings.Cut(item, "=")
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if !ok || err != nil {
+				return nil, fmt.Errorf("contoh %q harus berbentuk kunci=angka", item)
+			}
+			m[strings.TrimSpace(k)] = n
+		}
+		return m, nil
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(p.Example), &v); err != nil {
+			return nil, fmt.Errorf("contoh %q bukan JSON: %v", p.Example, err)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("tipe param %q tidak dikenal (text, int, double, boolean, timestamp, list<text>, map<text,int>, json)", p.Type)
+}
+
+// Statement adalah satu entri katalog.
+type Statement struct {
+	Name    string
+	Store   string // StoreCassandra atau StoreNeo4j
+	Doc     string
+	Params  []Param
+	Columns []string // kolom hasil; kosong untuk statement tanpa hasil
+	Text    string   // tanpa ; penutup
+	Source  string   // file:baris header name
+	// Used berarti statement dijalankan oleh kode Go di package ini.
+	Used bool
+}
+
+var writeKeyword = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|CREATE|MERGE|SET|REMOVE)\b`)
+
+// Write melaporkan apakah statement mengubah data.
+func (s Statement) Write() bool {
+	return writeKeyword.MatchString(stripLiterals(s.Text))
+}
+
+// ExampleArgs mengembalikan contoh nilai param sesuai urutan (untuk CQL).
+func (s Statement) ExampleArgs() ([]interface{}, error) {
+	args := make([]interface{}, len(s.Params))
+	for i, p := range s.Params {
+		v, err := p.Value()
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %v", p.Name, err)
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// ExampleParams mengembalikan contoh nilai param sebagai map (untuk Cypher).
+func (s Statement) ExampleParams() (map[string]interface{}, error) {
+	args, err := s.ExampleArgs()
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]interface{}, len(args))
+	for i, p := range s.Params {
+		params[p.Name] = args[i]
+	}
+	return params, nil
+}
+
+// Inline mengembalikan statement dengan contoh nilai menggantikan
+// placeholder, siap di-copy ke cqlsh atau Neo4j Browser.
+func (s Statement) Inline() string {
+	literal := func(p Param) string {
+		switch p.Type {
+		case "int", "double", "boolean":
+			return p.Example
+		case "timestamp":
+			t, _ := time.Parse(time.RFC3339, p.Example)
+			return quote(t.Format("2006-01-02 15:04:05-0700"), s.Store)
+		case "list<text>":
+			v, _ := p.Value()
+			items := v.([]string)
+			for i, item := range items {
+				items[i] = quote(item, s.Store)
+			}
+			if s.Store == StoreCassandra {
+				return "(" + strings.Join(items, ", ") + ")"
+			}
+			return "[" + strings.Join(items, ", ") + "]"
+		case "map<text,int>":
+			v, err := p.Value()
+			if err != nil {
+				return p.Example
+			}
+			m := v.(map[string]int)
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			items := make([]string, len(keys))
+			for i, k := range keys {
+				items[i] = fmt.Sprintf("%s: %d", quote(k, s.Store), m[k])
+			}
+			return "{" + strings.Join(items, ", ") + "}"
+		case "json":
+			v, err := p.Value()
+			if err != nil {
+				return p.Example
+			}
+			return literalJSON(v, s.Store)
+		}
+		return quote(p.Example, s.Store)
+	}
+
+	var out strings.Builder
+	next := 0
+	scanPlaceholders(s.Text, s.Store, func(text string, name string, isParam bool) {
+		if !isParam {
+			out.WriteString(text)
+			return
+		}
+		var p *Param
+		if s.Store == StoreCassandra {
+			if next < len(s.Params) {
+				p = &s.Params[next]
+			}
+			next++
+		} else {
+			for i := range s.Params {
+				if s.Params[i].Name == name {
+					p = &s.Params[i]
+				}
+			}
+		}
+		if p == nil {
+			out.WriteString(text)
+			return
+		}
+		out.WriteString(literal(*p))
+	})
+	return out.String() + ";"
+}
+
+// Check menjalankan statement terhadap database tanpa mengubah data dan
+// mencocokkan kolom hasilnya dengan katalog. Cypher dijalankan dengan
+// EXPLAIN; CQL read diambil satu baris, CQL write hanya di-prepare.
+// Mengembalikan kolom yang dilaporkan database.
+func (s Statement) Check() ([]string, error) {
+	var columns []string
+	var err error
+
+	switch {
+	case s.Store == StoreNeo4j:
+		var params map[string]interface{}
+		if params, err = s.ExampleParams(); err != nil {
+			return nil, err
+		}
+		columns, err = neo4j.ExplainNeo4j(s.Text, params)
+	case s.Write():
+		var args []interface{}
+		if args, err = s.ExampleArgs(); err != nil {
+			return nil, err
+		}
+		err = cassandra.PrepareCassandra(s.Text, args...)
+	default:
+		var args []interface{}
+		if args, err = s.ExampleArgs(); err != nil {
+			return nil, err
+		}
+		columns, err = cassandra.ColumnsCassandra(s.Text, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameNames(columns, s.Columns) {
+		return columns, fmt.Errorf("kolom hasil [%s], katalog mencatat [%s]",
+			strings.Join(columns, ", "), strings.Join(s.Columns, ", "))
+	}
+	return columns, nil
+}
+
+// ===============================================
+//   QUERY YANG DIPAKAI KODE GO
+// ===============================================
+
+// query adalah referensi kode Go ke entri katalog. Param dan kolom yang
+// diharapkan kode dicatat agar perubahan urutan atau nama di file
+// terdeteksi saat katalog dimuat, bukan saat Scan menghasilkan data salah.
+type query struct {
+	name    string
+	params  []string
+	columns []string
+}
+
+var usedQueries []*query
+
+// use mendaftarkan entri katalog yang dijalankan oleh kode Go.
+func use(name string) *query {
+	q := &query{name: name}
+	usedQueries = append(usedQueries, q)
+	return q
+}
+
+// with mencatat nama param sesuai urutan argumen yang dikirim kode.
+func (q *query) with(params ...string) *query {
+	q.params = params
+	return q
+}
+
+// returns mencatat kolom hasil yang dibaca kode (urutan Scan untuk CQL).
+func (q *query) returns(columns ...string) *query {
+	q.columns = columns
+	return q
+}
+
+// text mengembalikan statement dari katalog. Katalog yang tidak valid
+// menghentikan program karena tidak ada query yang bisa dijalankan.
+func (q *query) text() string {
+	c, err := loadedCatalog()
+	if err != nil {
+		log.Fatalf("katalog query: %v", err)
+	}
+	return c.entries[c.index[q.name]].Text
+}
+
+// ===============================================
+//   LOADER
+// ===============================================
+
+type catalog struct {
+	entries []Statement
+	index   map[string]int
+}
+
+var (
+	catalogOnce   sync.Once
+	catalogLoaded *catalog
+	catalogErr    error
+)
+
+func loadedCatalog() (*catalog, error) {
+	catalogOnce.Do(func() {
+		var fsys fs.FS = catalogFiles
+		if dir := os.Getenv(CatalogDirEnv); dir != "" {
+			fsys = os.DirFS(dir)
+		}
+		catalogLoaded, catalogErr = loadCatalog(fsys)
+	})
+	return catalogLoaded, catalogErr
+}
+
+// Catalog mengembalikan semua entri katalog sesuai urutan file.
+func Catalog() ([]Statement, error) {
+	c, err := loadedCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return c.entries, nil
+}
+
+// Lookup mencari entri katalog berdasarkan nama.
+func Lookup(name string) (Statement, error) {
+	c, err := loadedCatalog()
+	if err != nil {
+		return Statement{}, err
+	}
+	i, ok := c.index[name]
+	if !ok {
+		return Statement{}, fmt.Errorf("query %q tidak ada di katalog", name)
+	}
+	return c.entries[i], nil
+}
+
+func loadCatalog(fsys fs.FS) (*catalog, error) {
+	c := &catalog{index: map[string]int{}}
+	for _, src := range catalogSources {
+		data, err := fs.ReadFile(fsys, src.File)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := parseCatalog(src.File, src.Store, src.Comment, string(data))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range entries {
+			if i, dup := c.index[s.Name]; dup {
+				return nil, fmt.Errorf("%s: nama %q sudah dipakai di %s", s.Source, s.Name, c.entries[i].Source)
+			}
+			c.index[s.Name] = len(c.entries)
+			c.entries = append(c.entries, s)
+		}
+	}
+
+	for _, q := range usedQueries {
+		i, ok := c.index[q.name]
+		if !ok {
+			return nil, fmt.Errorf("query %q dipakai kode tetapi tidak ada di katalog", q.name)
+		}
+		s := &c.entries[i]
+		s.Used = true
+
+		names := make([]string, len(s.Params))
+		for j, p := range s.Params {
+			names[j] = p.Name
+		}
+		if !sameNames(names, q.params) {
+			return nil, fmt.Errorf("%s: param [%s] tidak cocok dengan kode [%s]",
+				s.Source, strings.Join(names, ", "), strings.Join(q.params, ", "))
+		}
+		if !sameNames(s.Columns, q.columns) {
+			return nil, fmt.Errorf("%s: kolom [%s] tidak cocok dengan kode [%s]",
+				s.Source, strings.Join(s.Columns, ", "), strings.Join(q.columns, ", "))
+		}
+	}
+	return c, nil
+}
+
+var (
+	catalogName      = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+	catalogParamName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// parseCatalog membaca entri dari satu file katalog. Baris komentar di luar
+// entri (banner, catatan) diabaikan; komentar di dalam statement dibuang.
+func parseCatalog(file, store, comment, src string) ([]Statement, error) {
+	var entries []Statement
+	var cur *Statement
+	var body []string
+
+	finish := func() error {
+		cur.Text = strings.TrimSuffix(strings.TrimSpace(strings.Join(body, "\n")), ";")
+		cur.Text = strings.TrimSpace(cur.Text)
+		if err := validateStatement(cur); err != nil {
+			return fmt.Errorf("%s: %v", cur.Source, err)
+		}
+		entries = append(entries, *cur)
+		cur, body = nil, nil
+		return nil
+	}
+
+	for n, line := range strings.Split(src, "\n") {
+		pos := fmt.Sprintf("%s:%d", file, n+1)
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, comment) {
+			key, value, ok := directive(strings.TrimSpace(strings.TrimPrefix(trimmed, comment)))
+			if !ok {
+				continue
+			}
+			if key == "name" {
+				if cur != nil {
+					return nil, fmt.Errorf("%s: entri %q belum ditutup dengan ;", cur.Source, cur.Name)
+				}
+				if !catalogName.MatchString(value) {
+					return nil, fmt.Errorf("%s: nama %q tidak valid (huruf kecil, angka, _ dan .)", pos, value)
+				}
+				cur = &Statement{Name: value, Store: store, Source: pos}
+				continue
+			}
+			if cur == nil {
+				return nil, fmt.Errorf("%s: %s: tanpa -- name: sebelumnya", pos, key)
+			}
+			if len(body) > 0 {
+				return nil, fmt.Errorf("%s: %s: harus ditulis sebelum statement", pos, key)
+			}
+			switch key {
+			case "doc":
+				cur.Doc = strings.TrimSpace(cur.Doc + " " + value)
+			case "param":
+				fields := strings.SplitN(value, " ", 3)
+				if len(fields) < 3 {
+					return nil, fmt.Errorf("%s: param harus berbentuk \"nama tipe contoh\"", pos)
+				}
+				cur.Params = append(cur.Params, Param{Name: fields[0], Type: fields[1], Example: strings.TrimSpace(fields[2])})
+			case "columns":
+				for _, col := range strings.Split(value, ",") {
+					if col = strings.TrimSpace(col); col != "" {
+						cur.Columns = append(cur.Columns, col)
+					}
+				}
+			}
+			continue
+		}
+
+		if trimmed == "" {
+			continue
+		}
+		if cur == nil {
+			return nil, fmt.Errorf("%s: statement tanpa header %s name:", pos, comment)
+		}
+		body = append(body, strings.TrimRight(line, " \t"))
+		if strings.HasSuffix(trimmed, ";") {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if cur != nil {
+		return nil, fmt.Errorf("%s: entri %q belum ditutup dengan ;", cur.Source, cur.Name)
+	}
+	return entries, nil
+}
+
+// directive memecah "name: nilai" menjadi key dan nilai. Komentar biasa
+// (termasuk "Catatan: ...") bukan direktif.
+func directive(s string) (string, string, bool) {
+	key, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", "", false
+	}
+	switch key {
+	case "name", "doc", "param", "columns":
+		return key, strings.TrimSpace(value), true
+	}
+	return "", "", false
+}
+
+// validateStatement memastikan param yang dideklarasikan sama dengan
+// placeholder di statement dan contoh nilainya dapat dipakai.
+func validateStatement(s *Statement) error {
+	if s.Text == "" {
+		return fmt.Errorf("entri %q tidak punya statement", s.Name)
+	}
+	if s.Doc == "" {
+		return fmt.Errorf("entri %q tidak punya doc:", s.Name)
+	}
+
+	declared := map[string]bool{}
+	for _, p := range s.Params {
+		if !catalogParamName.MatchString(p.Name) {
+			return fmt.Errorf("nama param %q tidak valid", p.Name)
+		}
+		if declared[p.Name] {
+			return fmt.Errorf("param %s dideklarasikan dua kali", p.Name)
+		}
+		declared[p.Name] = true
+		if _, err := p.Value(); err != nil {
+			return fmt.Errorf("param %s: contoh %q: %v", p.Name, p.Example, err)
+		}
+	}
+
+	markers := 0
+	used := map[string]bool{}
+	scanPlaceholders(s.Text, s.Store, func(_ string, name string, isParam bool) {
+		if isParam {
+			markers++
+			used[name] = true
+		}
+	})
+
+	if s.Store == StoreCassandra {
+		if markers != len(s.Params) {
+			return fmt.Errorf("statement punya %d placeholder ? tetapi %d param", markers, len(s.Params))
+		}
+		return nil
+	}
+	for name := range used {
+		if !declared[name] {
+			return fmt.Errorf("$%s dipakai tetapi tidak dideklarasikan", name)
+		}
+	}
+	for _, p := range s.Params {
+		if !used[p.Name] {
+			return fmt.Errorf("param %s dideklarasikan tetapi tidak dipakai", p.Name)
+		}
+	}
+	return nil
+}
+
+// scanPlaceholders memanggil fn untuk setiap potongan teks: placeholder
+// (? untuk CQL, $nama untuk Cypher) atau teks biasa. Isi literal string
+// tidak pernah dianggap placeholder.
+func scanPlaceholders(text, store string, fn func(text, name string, isParam bool)) {
+	start := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && store == StoreNeo4j {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '?' && store == StoreCassandra:
+			fn(text[start:i], "", false)
+			fn("?", "", true)
+			start = i + 1
+		case ch == '$' && store == StoreNeo4j:
+			j := i + 1
+			for j < len(text) && (text[j] == '_' || text[j] >= 'a' && text[j] <= 'z' || text[j] >= 'A' && text[j] <= 'Z' || text[j] >= '0' && text[j] <= '9') {
+				j++
+			}
+			if j == i+1 {
+				continue
+			}
+			fn(text[start:i], "", false)
+			fn(text[i:j], text[i+1:j], true)
+			start = j
+			i = j - 1
+		}
+	}
+	fn(text[start:], "", false)
+}
+
+// stripLiterals mengosongkan isi literal string agar kata kunci di dalam
+// data (mis. 'dibatalkan SET') tidak memengaruhi deteksi write.
+func stripLiterals(text string) string {
+	var out strings.Builder
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+				out.WriteByte(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+			out.WriteByte(ch)
+		default:
+			out.WriteByte(ch)
+		}
+	}
+	return out.String()
+}
+
+// quote menulis literal string: CQL menggandakan ', Cypher memakai \'.
+func quote(s, store string) string {
+	if store == StoreNeo4j {
+		return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// literalJSON menulis nilai JSON sebagai literal map/list Cypher. Kunci map
+// diurutkan agar hasil Inline stabil; string ditulis lewat quote.
+func literalJSON(v interface{}, store string) string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = k + ": " + literalJSON(t[k], store)
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case []interface{}:
+		items := make([]string, len(t))
+		for i, item := range t {
+			items[i] = literalJSON(item, store)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case string:
+		return quote(t, store)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
+
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// Test di file ini tidak butuh koneksi database: katalog hanya diparse dan
+// dicocokkan dengan query yang didaftarkan kode lewat use.
+func TestCatalogValid(t *testing.T) {
+	c, err := loadCatalog(catalogFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range usedQueries {
+		if _, ok := c.index[q.name]; !ok {
+			t.Errorf("use(%q) tidak ada di katalog", q.name)
+		}
+	}
+	for _, s := range c.entries {
+		if !s.Used {
+			t.Errorf("%s: %s tidak dipakai kode", s.Source, s.Name)
+		}
+	}
+}
+
+func TestLoadCatalogDitolak(t *testing.T) {
+	cql, err := catalogFiles.ReadFile("cassandra_cql_queries.cql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cypher, err := catalogFiles.ReadFile("neo4j_browser_queries.cypher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dup := "\n// name: obat.harga\n// doc: Nama sama dengan entri CQL.\nMATCH (o:Obat) RETURN o;\n"
+
+	tests := []struct {
+		name   string
+		cql    string
+		cypher string
+		want   string
+	}{
+		{"nama ganda antar file", string(cql), string(cypher) + dup, "sudah dipakai"},
+		{"query kode hilang", "", string(cypher), "dipakai kode tetapi tidak ada di katalog"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"cassandra_cql_queries.cql":    {Data: []byte(tt.cql)},
+				"neo4j_browser_queries.cypher": {Data: []byte(tt.cypher)},
+			}
+			_, err := loadCatalog(fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("loadCatalog = %v, ingin error memuat %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	tests := []struct {
+		name  string
+		store string
+		src   string
+		want  string // kosong berarti valid
+	}{
+		{"cql valid", StoreCassandra,
+			"-- name: obat.uji\n-- doc: Uji.\n-- param: id_obat text O0001\n-- columns: nama\nSELECT nama FROM obat\nWHERE id_obat = ?;\n", ""},
+		{"cypher valid", StoreNeo4j,
+			"// name: obat.uji\n// doc: Uji.\n// param: id_obat text O0001\nMATCH (o:Obat {id_obat: $id_obat}) RETURN o.nama AS nama;\n", ""},
+		{"statement tanpa header", StoreCassandra, "SELECT * FROM obat;\n", "tanpa header"},
+		{"belum ditutup", StoreCassandra, "-- name: obat.uji\n-- doc: Uji.\nSELECT * FROM obat\n", "belum ditutup"},
+		{"nama tidak valid", StoreCassandra, "-- name: Obat-Uji\n", "tidak valid"},
+		{"doc setelah statement", StoreCassandra, "-- name: obat.uji\nSELECT *\n-- doc: Uji.\nFROM obat;\n", "sebelum statement"},
+		{"tanpa doc", StoreCassandra, "-- name: obat.uji\nSELECT * FROM obat;\n", "tidak punya doc"},
+		{"param tanpa contoh", StoreCassandra, "-- name: obat.uji\n-- doc: Uji.\n-- param: id_obat text\nSELECT * FROM obat WHERE id_obat = ?;\n", "nama tipe contoh"},
+		{"placeholder kurang", StoreCassandra,
+			"-- name: obat.uji\n-- doc: Uji.\n-- param: id_obat text O0001\nSELECT * FROM obat;\n", "0 placeholder"},
+		{"contoh tidak cocok tipe", StoreCassandra,
+			"-- name: obat.uji\n-- doc: Uji.\n-- param: limit int sepuluh\nSELECT * FROM obat LIMIT ?;\n", "contoh \"sepuluh\""},
+		{"param cypher tidak dideklarasikan", StoreNeo4j,
+			"// name: obat.uji\n// doc: Uji.\nMATCH (o:Obat {id_obat: $id_obat}) RETURN o;\n", "$id_obat dipakai"},
+		{"param cypher tidak dipakai", StoreNeo4j,
+			"// name: obat.uji\n// doc: Uji.\n// param: id_obat text O0001\nMATCH (o:Obat) RETURN o;\n", "tidak dipakai"},
+		{"param ganda", StoreNeo4j,
+			"// name: obat.uji\n// doc: Uji.\n// param: id_obat text O0001\n// param: id_obat text O0002\nMATCH (o:Obat {id_obat: $id_obat}) RETURN o;\n", "dua kali"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := "--"
+			if tt.store == StoreNeo4j {
+				comment = "//"
+			}
+			entries, err := parseCatalog("uji", tt.store, comment, tt.src)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("parseCatalog = %v, ingin nil", err)
+				}
+				if len(entries) != 1 || entries[0].Name != "obat.uji" || strings.HasSuffix(entries[0].Text, ";") {
+					t.Fatalf("entri = %+v", entries)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("parseCatalog = %v, ingin error memuat %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package queries
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// ===============================================
+//   DELETE 1: Pemesanan obat yang dibatalkan
+// ===============================================
+
+type PesananObat struct {
+	IdPesanan      string
+	EmailPemesan   string
+	WaktuPemesanan time.Time
+}
+
+var (
+	qPesananDibatalkan = use("pemesanan_obat.dibatalkan").returns("id_pesanan", "email_pemesan", "waktu_pemesanan")
+	qHapusPesanan      = use("pemesanan_obat.hapus").with("id_pesanan")
+)
+
+// PemesananObatDibatalkan mengembalikan pesanan berstatus 'dibatalkan'.
+func PemesananObatDibatalkan() ([]PesananObat, error) {
+	iter, err := cassandra.SelectCassandra(qPesananDibatalkan.text())
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []PesananObat
+	var o PesananObat
+	for iter.Scan(&o.IdPesanan, &o.EmailPemesan, &o.WaktuPemesanan) {
+		orders = append(orders, o)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// HapusPemesananObat menghapus pesanan satu per satu berdasarkan primary
+// key dan mengembalikan jumlah yang terhapus sebelum kegagalan pertama.
+func HapusPemesananObat(ids []string) (int, error) {
+	for i, pid := range ids {
+		if err := cassandra.DeleteCassandra(qHapusPesanan.text(), pid); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// ===============================================
+//   DELETE 2: Log aktivitas Baymin yang sudah lama
+// ===============================================
+
+type LogAktivitas struct {
+	IDPerangkat     string
+	WaktuAktivitas  time.Time
+	DetailAktivitas string
+}
+
+var (
+	qLogSebelum = use("log_aktivitas.sebelum").with("batas").returns("id_perangkat", "waktu_aktivitas", "detail_aktivitas")
+	qHapusLog   = use("log_aktivitas.hapus").with("id_perangkat", "waktu_aktivitas")
+)
+
+// LogAktivitasSebelum mengembalikan log dengan waktu sebelum batas.
+func LogAktivitasSebelum(batas time.Time) ([]LogAktivitas, error) {
+	iter, err := cassandra.SelectCassandra(qLogSebelum.text(), batas)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []LogAktivitas
+	var l LogAktivitas
+	for iter.Scan(&l.IDPerangkat, &l.WaktuAktivitas, &l.DetailAktivitas) {
+		logs = append(logs, l)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// HapusLogAktivitas menghapus log tertentu berdasarkan primary key
+// (hasil LogAktivitasSebelum) dan mengembalikan jumlah yang terhapus.
+// Kegagalan per baris hanya dicatat.
+func HapusLogAktivitas(logs []LogAktivitas) int {
+	deleted := 0
+	for _, l := range logs {
+		if err := cassandra.DeleteCassandra(qHapusLog.text(), l.IDPerangkat, l.WaktuAktivitas); err != nil {
+			log.Printf("Gagal hapus log untuk %s: %v\n", l.IDPerangkat, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
+
+// ===============================================
+//   DELETE 3: Janji temu lama yang tidak menghasilkan resep
+// ===============================================
+
+var (
+	qJanjiTemuLama  = use("janji_temu.lama").with("batas").returns("id", "waktu", "status", "relasi")
+	qHapusJanjiTemu = use("janji_temu.hapus").with("ids").returns("deleted")
+)
+
+// janjiTemuBatas memformat batas waktu seperti waktu_pelaksanaan (waktu
+// lokal tanpa zona).
+func janjiTemuBatas(batas time.Time) map[string]interface{} {
+	return map[string]interface{}{"batas": batas.Format("2006-01-02T15:04:05")}
+}
+
+type JanjiTemuTanpaResep struct {
+	IDJanjiTemu      string
+	WaktuPelaksanaan string
+	Status           string
+	// Relasi adalah jumlah relationship yang ikut terhapus oleh DETACH DELETE.
+	Relasi int
+}
+
+// JanjiTemuLama mengembalikan janji temu yang dilaksanakan sebelum batas
+// dan tidak menghasilkan resep, urut dari yang tertua.
+func JanjiTemuLama(batas time.Time) ([]JanjiTemuTanpaResep, error) {
+	records, err := neo4j.ReadNeo4j(qJanjiTemuLama.text(), janjiTemuBatas(batas))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca janji temu lama: %v", err)
+	}
+
+	result := make([]JanjiTemuTanpaResep, 0, len(records))
+	for _, rec := range records {
+		relasi, _ := rec["relasi"].(int64)
+		result = append(result, JanjiTemuTanpaResep{
+			IDJanjiTemu:      stringValue(rec, "id"),
+			WaktuPelaksanaan: stringValue(rec, "waktu"),
+			Status:           stringValue(rec, "status"),
+			Relasi:           int(relasi),
+		})
+	}
+	return result, nil
+}
+
+// HapusJanjiTemu menghapus (DETACH DELETE) janji temu dengan id tertentu.
+// Janji temu yang ternyata sudah menghasilkan resep tetap dilewati.
+func HapusJanjiTemu(ids []string) (int, error) {
+	records, err := neo4j.CreateAndReturnNeo4j(qHapusJanjiTemu.text(), map[string]interface{}{"ids": ids})
+	if err != nil {
+		return 0, fmt.Errorf("gagal hapus janji temu: %v", err)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+	return int(records[0]["deleted"].(int64)), nil
+}
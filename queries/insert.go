@@ -0,0 +1,182 @@
+package queries
+
+import (
+	"fmt"
+
+	"src/neo4j"
+)
+
+// ===============================================
+//   INSERT 1: Pasien baru
+// ===============================================
+
+// Pasien memakai nama properti node sebagai tag JSON sehingga record dapat
+// dibaca langsung dari file (rs insert patient --file).
+type Pasien struct {
+	Email        string `json:"email"`
+	NIK          string `json:"nik,omitempty"`
+	KataSandi    string `json:"kata_sandi"`
+	NamaLengkap  string `json:"nama_lengkap"`
+	JenisKelamin string `json:"jenis_kelamin,omitempty"`
+	TanggalLahir string `json:"tanggal_lahir"`
+	NomorTelepon string `json:"nomor_telepon,omitempty"`
+	Provinsi     string `json:"provinsi,omitempty"`
+	Kota         string `json:"kota,omitempty"`
+	Kecamatan    string `json:"kecamatan,omitempty"`
+	Jalan        string `json:"jalan,omitempty"`
+}
+
+var qBuatPasien = use("pasien.buat").
+	with("email", "nik", "kata_sandi", "nama_lengkap", "jenis_kelamin", "tanggal_lahir",
+		"nomor_telepon", "provinsi", "kota", "kecamatan", "jalan").
+	returns("email", "nama")
+
+func InsertPasien(p Pasien) (*Pasien, error) {
+	params := map[string]interface{}{
+		"email":         p.Email,
+		"nik":           nullable(p.NIK),
+		"kata_sandi":    p.KataSandi,
+		"nama_lengkap":  p.NamaLengkap,
+		"jenis_kelamin": nullable(p.JenisKelamin),
+		"tanggal_lahir": p.TanggalLahir,
+		"nomor_telepon": nullable(p.NomorTelepon),
+		"provinsi":      nullable(p.Provinsi),
+		"kota":          nullable(p.Kota),
+		"kecamatan":     nullable(p.Kecamatan),
+		"jalan":         nullable(p.Jalan),
+	}
+
+	results, err := neo4j.CreateAndReturnNeo4j(qBuatPasien.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menambahkan pasien: %v", err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("tidak ada data yang dikembalikan")
+	}
+
+	return &Pasien{
+		Email:       stringValue(results[0], "email"),
+		NamaLengkap: stringValue(results[0], "nama"),
+	}, nil
+}
+
+// nullable mengubah string kosong menjadi nil agar properti opsional tidak
+// disimpan sebagai "" di Neo4j.
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// ===============================================
+//   INSERT 2: Tandai user yang ada sebagai pasien terdaftar
+// ===============================================
+
+var qDaftarkanPasien = use("pasien.daftarkan").with("nama_lengkap").returns("email")
+
+// DaftarkanPasien menambahkan label :PasienTerdaftar pada pasien pertama
+// dengan nama lengkap tersebut (padanan INSERT INTO pasien SELECT ... di SQL).
+func DaftarkanPasien(namaLengkap string) (string, error) {
+	params := map[string]interface{}{"nama_lengkap": namaLengkap}
+
+	results, err := neo4j.CreateAndReturnNeo4j(qDaftarkanPasien.text(), params)
+	if err != nil {
+		return "", fmt.Errorf("gagal menambahkan pasien: %v", err)
+	}
+
+	if len(results) == 0 {
+		return "", fmt.Errorf("user dengan nama %q tidak ditemukan", namaLengkap)
+	}
+
+	return stringValue(results[0], "email"), nil
+}
+
+// ===============================================
+//   INSERT 3: Rumah sakit baru
+// ===============================================
+
+type RumahSakit struct {
+	IdRS           string `json:"id_rs"`
+	Email          string `json:"email,omitempty"`
+	NamaRumahSakit string `json:"nama_rumah_sakit"`
+	NoTelepon      string `json:"no_telepon,omitempty"`
+	Provinsi       string `json:"provinsi,omitempty"`
+	Kota           string `json:"kota"`
+	Kecamatan      string `json:"kecamatan,omitempty"`
+	Jalan          string `json:"jalan,omitempty"`
+}
+
+var qBuatRumahSakit = use("rumah_sakit.buat").
+	with("id_rs", "email", "nama_rumah_sakit", "no_telepon", "provinsi", "kota", "kecamatan", "jalan").
+	returns("id_rs", "nama")
+
+func InsertRumahSakit(rs RumahSakit) (*RumahSakit, error) {
+	params := map[string]interface{}{
+		"id_rs":            rs.IdRS,
+		"email":            nullable(rs.Email),
+		"nama_rumah_sakit": rs.NamaRumahSakit,
+		"no_telepon":       nullable(rs.NoTelepon),
+		"provinsi":         nullable(rs.Provinsi),
+		"kota":             rs.Kota,
+		"kecamatan":        nullable(rs.Kecamatan),
+		"jalan":            nullable(rs.Jalan),
+	}
+
+	results, err := neo4j.CreateAndReturnNeo4j(qBuatRumahSakit.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menambahkan rumah sakit: %v", err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("tidak ada data yang dikembalikan")
+	}
+
+	return &RumahSakit{
+		IdRS:           stringValue(results[0], "id_rs"),
+		NamaRumahSakit: stringValue(results[0], "nama"),
+	}, nil
+}
+
+// ===============================================
+//   INSERT 4: Departemen baru pada rumah sakit
+// ===============================================
+
+type DepartemenRS struct {
+	NamaDepartemen string `json:"nama_departemen"`
+	Gedung         string `json:"gedung,omitempty"`
+	RumahSakit     string `json:"nama_rumah_sakit,omitempty"`
+	IdRS           string `json:"id_rs,omitempty"`
+}
+
+var qBuatDepartemen = use("departemen.buat").
+	with("id_rs", "nama_rumah_sakit", "nama_departemen", "gedung").
+	returns("departemen", "rumah_sakit", "id_rs")
+
+// InsertDepartemen membuat departemen dan menghubungkannya ke rumah sakit
+// d.IdRS, atau rumah sakit pertama bernama d.RumahSakit bila IdRS kosong.
+func InsertDepartemen(d DepartemenRS) (*DepartemenRS, error) {
+	params := map[string]interface{}{
+		"id_rs":            d.IdRS,
+		"nama_rumah_sakit": d.RumahSakit,
+		"nama_departemen":  d.NamaDepartemen,
+		"gedung":           nullable(d.Gedung),
+	}
+
+	results, err := neo4j.CreateAndReturnNeo4j(qBuatDepartemen.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menambahkan departemen: %v", err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("tidak ada data yang dikembalikan atau rumah sakit tidak ditemukan")
+	}
+
+	return &DepartemenRS{
+		NamaDepartemen: stringValue(results[0], "departemen"),
+		Gedung:         d.Gedung,
+		RumahSakit:     stringValue(results[0], "rumah_sakit"),
+		IdRS:           stringValue(results[0], "id_rs"),
+	}, nil
+}
@@ -0,0 +1,194 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/cassandra"
+)
+
+// ===============================================
+//   JOB RUNNER
+// ===============================================
+
+// KonfigurasiJob adalah jadwal dan status jeda satu job. Jadwal kosong
+// berarti jadwal bawaan job.
+type KonfigurasiJob struct {
+	Nama   string
+	Jadwal string
+	Dijeda bool
+	Diubah time.Time
+}
+
+// LeaseJob adalah lease yang sedang dipegang satu instance.
+type LeaseJob struct {
+	Nama    string
+	Pemilik string
+	Sampai  time.Time
+}
+
+// RunJob adalah satu baris riwayat run job.
+type RunJob struct {
+	Nama     string    `json:"nama"`
+	Mulai    time.Time `json:"mulai"`
+	IdRun    string    `json:"id_run"`
+	Selesai  time.Time `json:"selesai"`
+	DurasiMs int       `json:"durasi_ms"`
+	Status   string    `json:"status"`
+	Jumlah   int       `json:"jumlah"`
+	Pesan    string    `json:"pesan"`
+	Pemilik  string    `json:"pemilik"`
+	Pemicu   string    `json:"pemicu"`
+}
+
+var (
+	qKonfigurasiJob  = use("job_konfigurasi.semua").returns("nama", "jadwal", "dijeda", "diubah")
+	qAturJadwalJob   = use("job_konfigurasi.atur_jadwal").with("jadwal", "diubah", "nama")
+	qAturJedaJob     = use("job_konfigurasi.atur_jeda").with("dijeda", "diubah", "nama")
+	qAmbilLease      = use("job_lease.ambil").with("nama", "pemilik", "sampai", "ttl")
+	qPerpanjangLease = use("job_lease.perpanjang").with("ttl", "pemilik", "sampai", "nama", "pemilik_lama")
+	qLepasLease      = use("job_lease.lepas").with("nama", "pemilik")
+	qLeaseJob        = use("job_lease.semua").returns("nama", "pemilik", "sampai")
+	qCatatRun        = use("job_riwayat.catat").with("nama", "mulai", "id_run", "selesai", "durasi_ms", "status", "jumlah", "pesan", "pemilik", "pemicu", "ttl")
+	qRiwayatJob      = use("job_riwayat.per_job").with("nama").returns("mulai", "id_run", "selesai", "durasi_ms", "status", "jumlah", "pesan", "pemilik", "pemicu")
+	qRunTerakhir     = use("job_riwayat.terakhir").with("nama").returns("nama", "mulai", "status", "jumlah")
+)
+
+// SemuaKonfigurasiJob membaca konfigurasi semua job, per nama.
+func SemuaKonfigurasiJob() (map[string]KonfigurasiJob, error) {
+	iter, err := cassandra.SelectCassandra(qKonfigurasiJob.text())
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca konfigurasi job: %v", err)
+	}
+	out := map[string]KonfigurasiJob{}
+	var k KonfigurasiJob
+	for iter.Scan(&k.Nama, &k.Jadwal, &k.Dijeda, &k.Diubah) {
+		out[k.Nama] = k
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca konfigurasi job: %v", err)
+	}
+	return out, nil
+}
+
+// AturJadwalJob mengganti jadwal job; jadwal kosong kembali ke bawaan.
+func AturJadwalJob(nama, jadwal string, now time.Time) error {
+	if err := cassandra.UpdateCassandra(qAturJadwalJob.text(), jadwal, now, nama); err != nil {
+		return fmt.Errorf("gagal mengubah jadwal job %s: %v", nama, err)
+	}
+	return nil
+}
+
+// AturJedaJob menjeda (true) atau melanjutkan (false) job.
+func AturJedaJob(nama string, dijeda bool, now time.Time) error {
+	if err := cassandra.UpdateCassandra(qAturJedaJob.text(), dijeda, now, nama); err != nil {
+		return fmt.Errorf("gagal mengubah jeda job %s: %v", nama, err)
+	}
+	return nil
+}
+
+// AmbilLease mencoba memegang lease job sampai waktu sampai. Bila lease
+// dipegang instance lain, applied false dan lama berisi pemegangnya.
+func AmbilLease(nama, pemilik string, sampai time.Time, ttl time.Duration) (applied bool, lama LeaseJob, err error) {
+	applied, prev, err := cassandra.CASCassandra(qAmbilLease.text(), nama, pemilik, sampai, ttlDetik(ttl))
+	if err != nil {
+		return false, LeaseJob{}, fmt.Errorf("gagal mengambil lease job %s: %v", nama, err)
+	}
+	if applied {
+		return true, LeaseJob{}, nil
+	}
+	lama = LeaseJob{Nama: nama}
+	lama.Pemilik, _ = prev["pemilik"].(string)
+	lama.Sampai, _ = prev["sampai"].(time.Time)
+	return false, lama, nil
+}
+
+// PerpanjangLease memperpanjang lease yang masih dipegang pemilik; false
+// bila lease sudah hilang atau berpindah.
+func PerpanjangLease(nama, pemilik string, sampai time.Time, ttl time.Duration) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qPerpanjangLease.text(), ttlDetik(ttl), pemilik, sampai, nama, pemilik)
+	if err != nil {
+		return false, fmt.Errorf("gagal memperpanjang lease job %s: %v", nama, err)
+	}
+	return applied, nil
+}
+
+// LepasLease melepas lease bila masih dipegang pemilik.
+func LepasLease(nama, pemilik string) error {
+	if _, _, err := cassandra.CASCassandra(qLepasLease.text(), nama, pemilik); err != nil {
+		return fmt.Errorf("gagal melepas lease job %s: %v", nama, err)
+	}
+	return nil
+}
+
+// SemuaLeaseJob membaca lease yang sedang dipegang, per nama job.
+func SemuaLeaseJob() (map[string]LeaseJob, error) {
+	iter, err := cassandra.SelectCassandra(qLeaseJob.text())
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca lease job: %v", err)
+	}
+	out := map[string]LeaseJob{}
+	var l LeaseJob
+	for iter.Scan(&l.Nama, &l.Pemilik, &l.Sampai) {
+		out[l.Nama] = l
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca lease job: %v", err)
+	}
+	return out, nil
+}
+
+// CatatRunJob menyimpan satu run; baris hilang sendiri setelah simpan.
+func CatatRunJob(r RunJob, simpan time.Duration) error {
+	err := cassandra.InsertCassandra(qCatatRun.text(), r.Nama, r.Mulai, r.IdRun, r.Selesai, r.DurasiMs,
+		r.Status, r.Jumlah, r.Pesan, r.Pemilik, r.Pemicu, ttlDetik(simpan))
+	if err != nil {
+		return fmt.Errorf("gagal mencatat riwayat job %s: %v", r.Nama, err)
+	}
+	return nil
+}
+
+// RiwayatJob membaca paling banyak limit (0 = semua) run job, terbaru
+// lebih dulu.
+func RiwayatJob(nama string, limit int) ([]RunJob, error) {
+	iter, err := cassandra.SelectCassandra(qRiwayatJob.text(), nama)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca riwayat job %s: %v", nama, err)
+	}
+	var out []RunJob
+	r := RunJob{Nama: nama}
+	for (limit <= 0 || len(out) < limit) && iter.Scan(&r.Mulai, &r.IdRun, &r.Selesai, &r.DurasiMs, &r.Status, &r.Jumlah, &r.Pesan, &r.Pemilik, &r.Pemicu) {
+		out = append(out, r)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca riwayat job %s: %v", nama, err)
+	}
+	return out, nil
+}
+
+// RunTerakhir membaca run terakhir tiap job (tanpa pesan), per nama.
+func RunTerakhir(nama []string) (map[string]RunJob, error) {
+	out := map[string]RunJob{}
+	if len(nama) == 0 {
+		return out, nil
+	}
+	iter, err := cassandra.SelectCassandra(qRunTerakhir.text(), nama)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca riwayat job: %v", err)
+	}
+	var r RunJob
+	for iter.Scan(&r.Nama, &r.Mulai, &r.Status, &r.Jumlah) {
+		out[r.Nama] = r
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca riwayat job: %v", err)
+	}
+	return out, nil
+}
+
+func ttlDetik(d time.Duration) int {
+	if d < time.Second {
+		return 1
+	}
+	return int(d / time.Second)
+}
@@ -0,0 +1,283 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/cassandra"
+	"src/uang"
+)
+
+// ===============================================
+//   PESAN LAYANAN
+// ===============================================
+
+// PenawaranLayanan adalah satu baris lokasi_layanan.
+type PenawaranLayanan struct {
+	IdRS            string
+	IdLayanan       string
+	NamaLayanan     string
+	BiayaLayanan    uang.Rupiah
+	KapasitasHarian int // 0 bila belum diatur
+}
+
+// PemesananLayanan adalah satu baris pemesanan_layanan. IdRS dan
+// IdLayanan kosong untuk pesanan yang dibuat sebelum kolomnya ada.
+type PemesananLayanan struct {
+	IdPesanan         string      `json:"id_pesanan"`
+	EmailPemesan      string      `json:"email_pemesan"`
+	IdRS              string      `json:"id_rs"`
+	IdLayanan         string      `json:"id_layanan"`
+	NamaLayanan       string      `json:"nama_layanan"`
+	BiayaLayanan      uang.Rupiah `json:"biaya_layanan"`
+	WaktuPemesanan    time.Time   `json:"waktu_pemesanan"`
+	JadwalPelaksanaan time.Time   `json:"jadwal_pelaksanaan"`
+	StatusPemesanan   string      `json:"status_pemesanan"`
+}
+
+var (
+	qPenawaranLayanan  = use("lokasi_layanan.penawaran").with("id_rs", "id_layanan").returns("nama_layanan", "biaya_layanan", "kapasitas_harian")
+	qKuotaTerpakai     = use("kuota_layanan.terpakai").with("id_rs", "id_layanan", "tanggal").returns("terpakai")
+	qKuotaMulai        = use("kuota_layanan.mulai").with("id_rs", "id_layanan", "tanggal")
+	qKuotaUbah         = use("kuota_layanan.ubah").with("terpakai_baru", "id_rs", "id_layanan", "tanggal", "terpakai_lama")
+	qBuatLayanan       = use("pemesanan_layanan.buat").with("id_pesanan", "email_pemesan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan")
+	qBuatLayananPasien = use("pemesanan_layanan_per_pasien.buat").with("email_pemesan", "jadwal_pelaksanaan", "id_pesanan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan")
+	qBuatLayananRS     = use("pemesanan_layanan_per_rs.buat").with("id_rs", "tanggal", "jadwal_pelaksanaan", "id_pesanan", "email_pemesan", "id_layanan", "nama_layanan")
+	qDetailLayanan     = use("pemesanan_layanan.detail").with("id_pesanan").returns("id_pesanan", "email_pemesan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan")
+	qUbahStatusLayanan = use("pemesanan_layanan.ubah_status").with("status_baru", "id_pesanan", "status_lama")
+	qStatusPerID       = use("pemesanan_layanan.status_per_id").with("ids").returns("id_pesanan", "status_pemesanan")
+	qLayananPasien     = use("pemesanan_layanan_per_pasien.daftar").with("email_pemesan").returns("jadwal_pelaksanaan", "id_pesanan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan")
+	qLayananRS         = use("pemesanan_layanan_per_rs.mendatang").with("id_rs", "tanggal", "dari", "sampai").returns("jadwal_pelaksanaan", "id_pesanan", "email_pemesan", "id_layanan", "nama_layanan")
+	qSemuaLayanan      = use("pemesanan_layanan.semua").returns("id_pesanan", "email_pemesan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan")
+)
+
+// ZonaJadwal adalah zona waktu tanggal jadwal layanan (WIB). Tanggal
+// dipakai sebagai partition dan kunci kuota, jadi tidak boleh bergantung
+// pada zona mesin yang menjalankan rs.
+var ZonaJadwal = time.FixedZone("WIB", 7*60*60)
+
+// TanggalJadwal adalah tanggal (YYYY-MM-DD, ZonaJadwal) jadwal pelaksanaan:
+// bucket partition pemesanan_layanan_per_rs dan kunci kuota_layanan.
+func TanggalJadwal(t time.Time) string {
+	return t.In(ZonaJadwal).Format("2006-01-02")
+}
+
+// barisJadwalRS adalah statement baris pemesanan_layanan_per_rs untuk p.
+func barisJadwalRS(p PemesananLayanan) cassandra.Statement {
+	return cassandra.Statement{Query: qBuatLayananRS.text(), Params: []interface{}{
+		p.IdRS, TanggalJadwal(p.JadwalPelaksanaan), p.JadwalPelaksanaan, p.IdPesanan, p.EmailPemesan, p.IdLayanan, p.NamaLayanan,
+	}}
+}
+
+// Penawaran membaca layanan yang ditawarkan RS; nil bila RS tidak
+// menawarkan layanan tersebut.
+func Penawaran(idRS, idLayanan string) (*PenawaranLayanan, error) {
+	iter, err := cassandra.SelectCassandra(qPenawaranLayanan.text(), idRS, idLayanan)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca lokasi_layanan: %v", err)
+	}
+	p := PenawaranLayanan{IdRS: idRS, IdLayanan: idLayanan}
+	found := iter.Scan(&p.NamaLayanan, &p.BiayaLayanan, &p.KapasitasHarian)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca lokasi_layanan: %v", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// KuotaTerpakai membaca jumlah pemesanan aktif pada tanggal (YYYY-MM-DD).
+// ada false berarti belum ada pemesanan sama sekali pada tanggal itu.
+func KuotaTerpakai(idRS, idLayanan, tanggal string) (terpakai int, ada bool, err error) {
+	iter, err := cassandra.SelectCassandra(qKuotaTerpakai.text(), idRS, idLayanan, tanggal)
+	if err != nil {
+		return 0, false, fmt.Errorf("gagal membaca kuota layanan: %v", err)
+	}
+	ada = iter.Scan(&terpakai)
+	if err := iter.Close(); err != nil {
+		return 0, false, fmt.Errorf("gagal membaca kuota layanan: %v", err)
+	}
+	return terpakai, ada, nil
+}
+
+// MulaiKuota mencatat pemesanan pertama pada tanggal; applied false bila
+// baris kuota sudah dibuat pemesanan lain.
+func MulaiKuota(idRS, idLayanan, tanggal string) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qKuotaMulai.text(), idRS, idLayanan, tanggal)
+	if err != nil {
+		return false, fmt.Errorf("gagal mengubah kuota layanan: %v", err)
+	}
+	return applied, nil
+}
+
+// UbahKuota mengganti jumlah terpakai dari lama ke baru dengan LWT. Bila
+// sudah berubah, applied false dan sekarang berisi nilai terbaru.
+func UbahKuota(idRS, idLayanan, tanggal string, lama, baru int) (applied bool, sekarang int, err error) {
+	applied, prev, err := cassandra.CASCassandra(qKuotaUbah.text(), baru, idRS, idLayanan, tanggal, lama)
+	if err != nil {
+		return false, 0, fmt.Errorf("gagal mengubah kuota layanan: %v", err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	sekarang, _ = prev["terpakai"].(int)
+	return false, sekarang, nil
+}
+
+// SimpanPemesananLayanan menulis pesanan dan kedua tabel query-nya dalam
+// satu LOGGED BATCH.
+func SimpanPemesananLayanan(p PemesananLayanan) error {
+	err := cassandra.LoggedBatchCassandra(
+		cassandra.Statement{Query: qBuatLayanan.text(), Params: []interface{}{
+			p.IdPesanan, p.EmailPemesan, p.IdRS, p.IdLayanan, p.NamaLayanan,
+			p.BiayaLayanan, p.WaktuPemesanan, p.JadwalPelaksanaan, p.StatusPemesanan,
+		}},
+		cassandra.Statement{Query: qBuatLayananPasien.text(), Params: []interface{}{
+			p.EmailPemesan, p.JadwalPelaksanaan, p.IdPesanan, p.IdRS, p.IdLayanan, p.NamaLayanan, p.BiayaLayanan,
+		}},
+		barisJadwalRS(p),
+	)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan pemesanan layanan %s: %v", p.IdPesanan, err)
+	}
+	return nil
+}
+
+// DetailPemesananLayanan membaca satu pemesanan layanan; nil bila tidak ada.
+func DetailPemesananLayanan(id string) (*PemesananLayanan, error) {
+	iter, err := cassandra.SelectCassandra(qDetailLayanan.text(), id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pemesanan layanan %s: %v", id, err)
+	}
+	var p PemesananLayanan
+	found := iter.Scan(&p.IdPesanan, &p.EmailPemesan, &p.IdRS, &p.IdLayanan, &p.NamaLayanan,
+		&p.BiayaLayanan, &p.WaktuPemesanan, &p.JadwalPelaksanaan, &p.StatusPemesanan)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pemesanan layanan %s: %v", id, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// UbahStatusLayanan mengganti status pesanan dari lama ke baru dengan LWT.
+// Bila status sudah berubah, applied false dan sekarang berisi status
+// terbaru.
+func UbahStatusLayanan(id, lama, baru string) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qUbahStatusLayanan.text(), baru, id, lama)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal mengubah status pemesanan layanan %s: %v", id, err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	sekarang, _ = prev["status_pemesanan"].(string)
+	return false, sekarang, nil
+}
+
+// StatusPemesananLayananPerID membaca status beberapa pesanan sekaligus.
+func StatusPemesananLayananPerID(ids []string) (map[string]string, error) {
+	out := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	iter, err := cassandra.SelectCassandra(qStatusPerID.text(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca status pemesanan layanan: %v", err)
+	}
+	var id, status string
+	for iter.Scan(&id, &status) {
+		out[id] = status
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca status pemesanan layanan: %v", err)
+	}
+	return out, nil
+}
+
+// PemesananLayananPasien membaca paling banyak limit (0 = semua) pesanan
+// satu pasien, jadwal terbaru lebih dulu. Status belum diisi.
+func PemesananLayananPasien(email string, limit int) ([]PemesananLayanan, error) {
+	iter, err := cassandra.SelectCassandra(qLayananPasien.text(), email)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pemesanan layanan %s: %v", email, err)
+	}
+	var out []PemesananLayanan
+	p := PemesananLayanan{EmailPemesan: email}
+	for (limit <= 0 || len(out) < limit) && iter.Scan(&p.JadwalPelaksanaan, &p.IdPesanan, &p.IdRS, &p.IdLayanan, &p.NamaLayanan, &p.BiayaLayanan) {
+		out = append(out, p)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pemesanan layanan %s: %v", email, err)
+	}
+	return out, nil
+}
+
+// JadwalLayananRS membaca paling banyak limit (0 = semua) pesanan satu RS
+// dengan jadwal dalam [dari, sampai), urut jadwal. Partition dibaca per
+// tanggal ZonaJadwal mulai tanggal dari. Status dan biaya belum diisi.
+func JadwalLayananRS(idRS string, dari, sampai time.Time, limit int) ([]PemesananLayanan, error) {
+	var out []PemesananLayanan
+	y, m, d := dari.In(ZonaJadwal).Date()
+	for hari := time.Date(y, m, d, 0, 0, 0, 0, ZonaJadwal); hari.Before(sampai); hari = hari.AddDate(0, 0, 1) {
+		tanggal := TanggalJadwal(hari)
+		iter, err := cassandra.SelectCassandra(qLayananRS.text(), idRS, tanggal, dari, sampai)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca jadwal layanan %s %s: %v", idRS, tanggal, err)
+		}
+		p := PemesananLayanan{IdRS: idRS}
+		for (limit <= 0 || len(out) < limit) && iter.Scan(&p.JadwalPelaksanaan, &p.IdPesanan, &p.EmailPemesan, &p.IdLayanan, &p.NamaLayanan) {
+			out = append(out, p)
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("gagal membaca jadwal layanan %s %s: %v", idRS, tanggal, err)
+		}
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// HalamanPemesananLayanan membaca satu halaman pemesanan_layanan mulai
+// paging state (nil = awal); state berikutnya nil bila sudah habis.
+func HalamanPemesananLayanan(state []byte, n int) ([]map[string]interface{}, []byte, error) {
+	return halaman(qSemuaLayanan, "pemesanan_layanan", state, n)
+}
+
+// IsiUlangJadwalLayananRS menulis ulang pemesanan_layanan_per_rs dari
+// pemesanan_layanan, mis. setelah tabelnya dibuat ulang dengan primary key
+// baru. Pesanan tanpa RS dilewati.
+func IsiUlangJadwalLayananRS() (int, error) {
+	n := 0
+	var state []byte
+	for {
+		rows, next, err := HalamanPemesananLayanan(state, 500)
+		if err != nil {
+			return n, err
+		}
+		for _, row := range rows {
+			var p PemesananLayanan
+			p.IdPesanan, _ = row["id_pesanan"].(string)
+			p.EmailPemesan, _ = row["email_pemesan"].(string)
+			p.IdRS, _ = row["id_rs"].(string)
+			p.IdLayanan, _ = row["id_layanan"].(string)
+			p.NamaLayanan, _ = row["nama_layanan"].(string)
+			p.JadwalPelaksanaan, _ = row["jadwal_pelaksanaan"].(time.Time)
+			if p.IdRS == "" {
+				continue
+			}
+			stmt := barisJadwalRS(p)
+			if err := cassandra.InsertCassandra(stmt.Query, stmt.Params...); err != nil {
+				return n, fmt.Errorf("gagal mengisi jadwal layanan %s: %v", p.IdPesanan, err)
+			}
+			n++
+		}
+		if len(next) == 0 {
+			return n, nil
+		}
+		state = next
+	}
+}
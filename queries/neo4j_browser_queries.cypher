@@ -1,46 +1,216 @@
 // ========================================
-// Neo4j Browser Queries - Copy & Paste
+// Katalog Query Neo4j (Cypher)
 // ========================================
-// Buka Neo4j Browser di http://localhost:7474
-// Login: neo4j / password123
-// Copy-paste query di bawah satu per satu
+// File ini dibaca oleh package queries saat runtime: setiap statement di
+// bawah adalah query yang benar-benar dijalankan kode Go. Jangan menyalin
+// query ke kode; ubah di sini lalu jalankan `rs catalog check`.
+//
+// Format entri:
+//   // name: <nama unik, mis. pasien.buat>
+//   // doc: <penjelasan; boleh beberapa baris doc:>
+//   // param: <nama> <tipe> <contoh nilai>   (dipakai sebagai $nama)
+//   // columns: <kolom hasil RETURN, dipisah koma>
+//   <statement diakhiri ;>
+//
+// Tipe param: text, int, timestamp (RFC3339), list<text> (dipisah koma),
+// json (map/list, contoh ditulis sebagai JSON).
+//
+// Untuk Neo4j Browser (http://localhost:7474): `rs catalog show --name
+// <nama>` mencetak statement dengan contoh nilai menggantikan $nama, atau
+// set parameter di Browser dengan :param nama => nilai.
 
 
 // ========================================
-// INSERT 1: Menambahkan Pengguna Baru
+// READ 3: Perangkat Baymin milik pasien
 // ========================================
+
+// name: baymin.perangkat_pasien
+// doc: Perangkat Baymin dan nama pasien pemiliknya; log dibaca dari Cassandra.
+// param: email text pasien1@mail.com
+// columns: id_perangkat, nama
+MATCH (p:Pasien {email: $email})-[:memiliki_perangkat]->(b:Baymin)
+RETURN b.id_perangkat AS id_perangkat, p.nama_lengkap AS nama;
+
+
+// ========================================
+// READ 4: Jumlah janji temu per tenaga medis
+// ========================================
+
+// name: tenaga_medis.jumlah_janji_temu
+// doc: Jumlah janji temu yang ditangani setiap tenaga medis.
+// columns: email, nama, profesi, jumlah_janji_temu
+MATCH (tm:TenagaMedis)<-[:dengan_dokter]-(jt:JanjiTemu)
+RETURN tm.email AS email, tm.nama_lengkap AS nama, tm.profesi AS profesi, COUNT(jt) AS jumlah_janji_temu
+ORDER BY jumlah_janji_temu DESC, email;
+
+
+// ========================================
+// READ 5: Detail resep sebuah janji temu
+// ========================================
+
+// name: janji_temu.detail_resep
+// doc: Penyakit, obat dan dosis pada resep hasil satu janji temu; nama dan
+// doc: label obat dilengkapi dari Cassandra (obat.nama_label).
+// param: id_janji_temu text JT00011
+// columns: id_janji_temu, penyakit, id_obat, dosis
+MATCH (jt:JanjiTemu {id_janji_temu: $id_janji_temu})
+      -[:menghasilkan_resep]->
+      (r:Resep)-[:memiliki_detail]->(dr:DetailResep)
+RETURN jt.id_janji_temu AS id_janji_temu,
+       r.penyakit AS penyakit,
+       dr.id_obat AS id_obat,
+       dr.dosis AS dosis;
+
+
+// ========================================
+// READ 7: Layanan medis yang paling sering dipesan
+// ========================================
+
+// name: layanan_medis.terpopuler
+// doc: Jumlah janji temu di rumah sakit yang menawarkan setiap layanan.
+// columns: nama_layanan, jumlah_pesanan
+MATCH (l:LayananMedis)<-[:menawarkan_layanan]-(rs:RumahSakit)<-[:di_rs]-(j:JanjiTemu)
+WITH l, COUNT(j) as jumlah_pesanan
+RETURN l.nama_layanan AS nama_layanan,
+       jumlah_pesanan
+ORDER BY jumlah_pesanan DESC;
+
+
+// ========================================
+// READ 8 & 9: Peringkat rumah sakit
+// ========================================
+
+// name: rumah_sakit.top_janji_temu
+// doc: Rumah sakit diurutkan berdasarkan jumlah janji temu.
+// columns: nama_rumah_sakit, jumlah
+MATCH (rs:RumahSakit)<-[:di_rs]-(j:JanjiTemu)
+WITH rs, COUNT(j) as jumlah
+RETURN rs.nama_rumah_sakit AS nama_rumah_sakit,
+       jumlah
+ORDER BY jumlah DESC;
+
+// name: rumah_sakit.top_tenaga_medis
+// doc: Rumah sakit diurutkan berdasarkan jumlah tenaga medis (lewat departemen).
+// columns: nama_rumah_sakit, jumlah
+MATCH (rs:RumahSakit)-[:memiliki_departemen]->(d:Departemen)<-[:bekerja_di]-(t:TenagaMedis)
+WITH rs, COUNT(DISTINCT t) as jumlah
+RETURN rs.nama_rumah_sakit AS nama_rumah_sakit,
+       jumlah
+ORDER BY jumlah DESC;
+
+
+// ========================================
+// READ 10: Pasien dengan janji temu tanpa resep
+// ========================================
+
+// name: pasien.tanpa_resep
+// doc: Pasien yang punya janji temu yang tidak menghasilkan resep.
+// columns: email, nama_lengkap, jumlah_janji_temu
+MATCH (p:Pasien)<-[:memiliki_janji]-(j:JanjiTemu)
+WHERE NOT (j)-[:menghasilkan_resep]->(:Resep)
+WITH p, COUNT(DISTINCT j) as jumlah_janji_temu
+RETURN p.email AS email,
+       p.nama_lengkap AS nama_lengkap,
+       jumlah_janji_temu
+ORDER BY jumlah_janji_temu DESC, p.nama_lengkap ASC;
+
+
+// ========================================
+// SPECIAL GRAPH: Cari Dokter Spesialis
+// Keunggulan Graph: Relationship Traversal
+// ========================================
+
+// name: tenaga_medis.spesialis_di_kota
+// doc: Tenaga medis dengan profesi tertentu di rumah sakit suatu kota.
+// param: profesi text Dokter Spesialis Anak
+// param: kota text Bandung
+// param: limit int 50
+// columns: nama_dokter, telepon, departemen, rumah_sakit, alamat
+MATCH (tm:TenagaMedis {profesi: $profesi})-[:bekerja_di]->(d:Departemen)
+      <-[:memiliki_departemen]-(rs:RumahSakit {kota: $kota})
+RETURN
+    tm.nama_lengkap AS nama_dokter,
+    tm.nomor_telepon AS telepon,
+    d.nama_departemen AS departemen,
+    rs.nama_rumah_sakit AS rumah_sakit,
+    rs.jalan AS alamat
+ORDER BY rs.nama_rumah_sakit, tm.nama_lengkap
+LIMIT $limit;
+
+
+// ========================================
+// INSERT 1: Menambahkan Pasien Baru
+// ========================================
+
+// name: pasien.buat
+// doc: Buat node Pasien; properti opsional dikirim null bila kosong.
+// param: email text andi@example.com
+// param: nik text 3273012104950001
+// param: kata_sandi text hashed_password
+// param: nama_lengkap text Andi Setiawan
+// param: jenis_kelamin text L
+// param: tanggal_lahir text 1995-04-21
+// param: nomor_telepon text 08123456789
+// param: provinsi text Jawa Barat
+// param: kota text Bandung
+// param: kecamatan text Coblong
+// param: jalan text Jl. Merdeka 123
+// columns: email, nama
 CREATE (p:Pasien {
-    email: 'andi@example.com',
-    kata_sandi: 'hashed_password',
-    nama_lengkap: 'Andi Setiawan',
-    tanggal_lahir: '1995-04-21',
-    nomor_telepon: '08123456789',
-    provinsi: 'Jawa Barat',
-    kota: 'Bandung',
-    jalan: 'Jl. Merdeka 123'
+    email: $email,
+    nik: $nik,
+    kata_sandi: $kata_sandi,
+    nama_lengkap: $nama_lengkap,
+    jenis_kelamin: $jenis_kelamin,
+    tanggal_lahir: $tanggal_lahir,
+    nomor_telepon: $nomor_telepon,
+    provinsi: $provinsi,
+    kota: $kota,
+    kecamatan: $kecamatan,
+    jalan: $jalan
 })
 RETURN p.email AS email, p.nama_lengkap AS nama;
 
 
 // ========================================
-// INSERT 2: Cari Pasien Berdasarkan Nama
+// INSERT 2: Tandai Pasien Terdaftar
 // ========================================
-MATCH (p:Pasien {nama_lengkap: 'Andi Setiawan'})
-WITH p LIMIT 1
-RETURN p.email AS email, p.nama_lengkap AS nama, p.kota AS kota;
+
+// name: pasien.daftarkan
+// doc: Tambah label :PasienTerdaftar pada pasien pertama dengan nama tersebut
+// doc: (padanan INSERT INTO pasien SELECT ... di SQL).
+// param: nama_lengkap text Andi Setiawan
+// columns: email
+MATCH (u:Pasien {nama_lengkap: $nama_lengkap})
+WITH u LIMIT 1
+SET u:PasienTerdaftar
+RETURN u.email AS email;
 
 
 // ========================================
 // INSERT 3: Menambah Rumah Sakit Baru
 // ========================================
+
+// name: rumah_sakit.buat
+// doc: Buat node RumahSakit; properti opsional dikirim null bila kosong.
+// param: id_rs text RS999
+// param: email text rs@example.com
+// param: nama_rumah_sakit text RS Sehat Selalu
+// param: no_telepon text 0221234567
+// param: provinsi text Jawa Barat
+// param: kota text Bandung
+// param: kecamatan text Coblong
+// param: jalan text Jl. Kesehatan 10
+// columns: id_rs, nama
 CREATE (r:RumahSakit {
-    id_rs: 'RS999',
-    email: 'rs@example.com',
-    nama_rumah_sakit: 'RS Sehat Selalu',
-    no_telepon: '0221234567',
-    provinsi: 'Jawa Barat',
-    kota: 'Bandung',
-    jalan: 'Jl. Kesehatan 10'
+    id_rs: $id_rs,
+    email: $email,
+    nama_rumah_sakit: $nama_rumah_sakit,
+    no_telepon: $no_telepon,
+    provinsi: $provinsi,
+    kota: $kota,
+    kecamatan: $kecamatan,
+    jalan: $jalan
 })
 RETURN r.id_rs AS id_rs, r.nama_rumah_sakit AS nama;
 
@@ -48,67 +218,388 @@ RETURN r.id_rs AS id_rs, r.nama_rumah_sakit AS nama;
 // ========================================
 // INSERT 4: Menambah Departemen di RS
 // ========================================
-MATCH (rs:RumahSakit {nama_rumah_sakit: 'RS Sehat Selalu'})
+
+// name: departemen.buat
+// doc: Buat departemen pada rumah sakit $id_rs, atau rumah sakit pertama
+// doc: bernama $nama_rumah_sakit bila $id_rs kosong.
+// param: id_rs text RS999
+// param: nama_rumah_sakit text RS Sehat Selalu
+// param: nama_departemen text Kardiologi
+// param: gedung text Gedung A
+// columns: departemen, rumah_sakit, id_rs
+MATCH (rs:RumahSakit)
+WHERE ($id_rs <> '' AND rs.id_rs = $id_rs)
+   OR ($id_rs = '' AND rs.nama_rumah_sakit = $nama_rumah_sakit)
 WITH rs LIMIT 1
 CREATE (d:Departemen {
-    nama_departemen: 'Kardiologi',
-    gedung: 'Gedung A'
+    nama_departemen: $nama_departemen,
+    gedung: $gedung
 })
 CREATE (rs)-[:memiliki_departemen]->(d)
 RETURN d.nama_departemen AS departemen, rs.nama_rumah_sakit AS rumah_sakit, rs.id_rs AS id_rs;
 
 
 // ========================================
-// SPECIAL GRAPH: Cari Dokter Spesialis
-// Keunggulan Graph: Relationship Traversal
+// UPDATE 2: Pindahtugaskan tenaga medis
 // ========================================
-MATCH (tm:TenagaMedis {profesi: 'Dokter Spesialis Anak'})-[:bekerja_di]->(d:Departemen)
-      <-[:memiliki_departemen]-(rs:RumahSakit {kota: 'Bandung'})
-RETURN 
-    tm.nama_lengkap AS nama_dokter,
-    tm.nomor_telepon AS telepon,
-    d.nama_departemen AS departemen,
-    rs.nama_rumah_sakit AS rumah_sakit,
-    rs.jalan AS alamat
-ORDER BY rs.nama_rumah_sakit, tm.nama_lengkap
-LIMIT 50;
+
+// name: tenaga_medis.departemen
+// doc: Email dan departemen tenaga medis; email kosong berarti tenaga medis
+// doc: pertama yang ditemukan.
+// param: email text tm1@rs.com
+// columns: email, departemen
+MATCH (t:TenagaMedis)
+WHERE $email = '' OR t.email = $email
+OPTIONAL MATCH (t)-[:bekerja_di]->(d:Departemen)
+RETURN t.email AS email, d.nama_departemen AS departemen
+LIMIT 1;
+
+// name: tenaga_medis.pindah
+// doc: Ganti relasi bekerja_di tenaga medis ke departemen $dept (dibuat bila belum ada).
+// param: email text tm1@rs.com
+// param: dept text Kardiologi
+MATCH (t:TenagaMedis {email:$email})
+OPTIONAL MATCH (t)-[r:bekerja_di]->(d:Departemen)
+FOREACH (_ IN CASE WHEN r IS NULL THEN [] ELSE [1] END | DELETE r)
+WITH t
+MERGE (d2:Departemen {nama_departemen:$dept})
+MERGE (t)-[:bekerja_di]->(d2);
+
+
+// ========================================
+// DELETE 3: Janji temu lama tanpa resep
+// ========================================
+// $batas berformat waktu lokal tanpa zona (2006-01-02T15:04:05), sama
+// dengan waktu_pelaksanaan yang disimpan sebagai "2006-01-02 15:04:05".
+
+// name: janji_temu.lama
+// doc: Janji temu sebelum $batas yang tidak menghasilkan resep, beserta jumlah
+// doc: relationship yang ikut terhapus oleh DETACH DELETE (pratinjau).
+// param: batas text 2025-01-01T00:00:00
+// columns: id, waktu, status, relasi
+MATCH (j:JanjiTemu)
+WHERE localdatetime(replace(j.waktu_pelaksanaan, ' ', 'T')) < localdatetime($batas)
+      AND NOT (j)-[:menghasilkan_resep]->(:Resep)
+OPTIONAL MATCH (j)-[r]-()
+RETURN j.id_janji_temu AS id, j.waktu_pelaksanaan AS waktu, j.status AS status, count(r) AS relasi
+ORDER BY waktu;
+
+// name: janji_temu.hapus
+// doc: DETACH DELETE janji temu dengan id tertentu; yang ternyata sudah
+// doc: menghasilkan resep tetap dilewati.
+// param: ids list<text> JT00001,JT00002
+// columns: deleted
+MATCH (j:JanjiTemu)
+WHERE j.id_janji_temu IN $ids
+  AND NOT (j)-[:menghasilkan_resep]->(:Resep)
+DETACH DELETE j
+RETURN count(*) AS deleted;
 
 
 // ========================================
-// CLEANUP (Hati-hati!)
+// BOOKING: Jadwal praktik & janji temu
 // ========================================
+// Jadwal praktik adalah node :JadwalPraktik {hari, jam_mulai, jam_selesai,
+// durasi_slot} milik TenagaMedis, Departemen atau RumahSakit lewat
+// :memiliki_jadwal; jadwal dokter mengalahkan jadwal departemennya, yang
+// mengalahkan jadwal rumah sakitnya. hari: senin..minggu, jam HH:MM,
+// durasi_slot dalam menit.
+//
+// Janji temu baru menyimpan durasi_menit; janji temu lama tanpa properti
+// itu dianggap berdurasi $durasi_default. Pesan dan jadwal ulang mengunci
+// node dokter dan pasien (SET versi_jadwal) sebelum memeriksa bentrok,
+// sehingga dua booking bersamaan untuk dokter yang sama dijalankan
+// berurutan dan yang kedua melihat janji temu yang pertama.
+
+// name: tenaga_medis.jadwal_praktik
+// doc: Tenaga medis beserta departemen, rumah sakit dan jadwal praktik di
+// doc: ketiga tingkat (dipilih yang paling spesifik di kode Go).
+// param: email text tm1@rs.com
+// columns: email, nama, departemen, id_rs, jadwal_dokter, jadwal_departemen, jadwal_rs
+MATCH (t:TenagaMedis {email: $email})
+OPTIONAL MATCH (t)-[:bekerja_di]->(d:Departemen)
+OPTIONAL MATCH (d)<-[:memiliki_departemen]-(rs:RumahSakit)
+WITH t, d, rs LIMIT 1
+RETURN t.email AS email,
+       t.nama_lengkap AS nama,
+       d.nama_departemen AS departemen,
+       rs.id_rs AS id_rs,
+       [(t)-[:memiliki_jadwal]->(x:JadwalPraktik) | x {.hari, .jam_mulai, .jam_selesai, .durasi_slot}] AS jadwal_dokter,
+       CASE WHEN d IS NULL THEN [] ELSE [(d)-[:memiliki_jadwal]->(x:JadwalPraktik) | x {.hari, .jam_mulai, .jam_selesai, .durasi_slot}] END AS jadwal_departemen,
+       CASE WHEN rs IS NULL THEN [] ELSE [(rs)-[:memiliki_jadwal]->(x:JadwalPraktik) | x {.hari, .jam_mulai, .jam_selesai, .durasi_slot}] END AS jadwal_rs;
+
+// name: jadwal_praktik.simpan
+// doc: Ganti jadwal praktik pemilik ($pemilik: dokter, departemen atau
+// doc: rumah_sakit) pada hari-hari $hari dengan satu rentang jam yang sama.
+// param: pemilik text departemen
+// param: kunci text Kardiologi
+// param: hari list<text> senin,selasa,rabu,kamis,jumat
+// param: jam_mulai text 08:00
+// param: jam_selesai text 16:00
+// param: durasi_slot int 30
+// columns: dibuat
+CALL {
+    MATCH (o:TenagaMedis {email: $kunci}) WHERE $pemilik = 'dokter' RETURN o
+    UNION
+    MATCH (o:Departemen {nama_departemen: $kunci}) WHERE $pemilik = 'departemen' RETURN o
+    UNION
+    MATCH (o:RumahSakit {id_rs: $kunci}) WHERE $pemilik = 'rumah_sakit' RETURN o
+}
+OPTIONAL MATCH (o)-[:memiliki_jadwal]->(lama:JadwalPraktik)
+WHERE lama.hari IN $hari
+DETACH DELETE lama
+WITH DISTINCT o
+UNWIND $hari AS h
+CREATE (o)-[:memiliki_jadwal]->(:JadwalPraktik {hari: h, jam_mulai: $jam_mulai, jam_selesai: $jam_selesai, durasi_slot: $durasi_slot})
+RETURN count(*) AS dibuat;
+
+// name: jadwal_praktik.hapus
+// doc: Hapus jadwal praktik pemilik pada hari-hari $hari (hari libur).
+// param: pemilik text departemen
+// param: kunci text Kardiologi
+// param: hari list<text> sabtu,minggu
+// columns: pemilik, dihapus
+CALL {
+    MATCH (o:TenagaMedis {email: $kunci}) WHERE $pemilik = 'dokter' RETURN o
+    UNION
+    MATCH (o:Departemen {nama_departemen: $kunci}) WHERE $pemilik = 'departemen' RETURN o
+    UNION
+    MATCH (o:RumahSakit {id_rs: $kunci}) WHERE $pemilik = 'rumah_sakit' RETURN o
+}
+OPTIONAL MATCH (o)-[:memiliki_jadwal]->(lama:JadwalPraktik)
+WHERE lama.hari IN $hari
+DETACH DELETE lama
+RETURN count(DISTINCT o) AS pemilik, count(lama) AS dihapus;
+
+// name: janji_temu.terjadwal
+// doc: Janji temu dokter yang tidak dibatalkan dengan waktu mulai dalam
+// doc: [$dari, $sampai) (waktu lokal tanpa zona, format 2006-01-02T15:04:05).
+// param: email_dokter text tm1@rs.com
+// param: dari text 2025-01-06T00:00:00
+// param: sampai text 2025-01-13T00:00:00
+// columns: id_janji_temu, waktu_pelaksanaan, durasi_menit, status
+MATCH (:TenagaMedis {email: $email_dokter})<-[:dengan_dokter]-(j:JanjiTemu)
+WHERE coalesce(j.status, '') <> 'dibatalkan'
+  AND localdatetime(replace(j.waktu_pelaksanaan, ' ', 'T')) >= localdatetime($dari)
+  AND localdatetime(replace(j.waktu_pelaksanaan, ' ', 'T')) < localdatetime($sampai)
+RETURN j.id_janji_temu AS id_janji_temu, j.waktu_pelaksanaan AS waktu_pelaksanaan,
+       j.durasi_menit AS durasi_menit, j.status AS status
+ORDER BY waktu_pelaksanaan;
+
+// name: janji_temu.booking
+// doc: Satu janji temu beserta pasien, dokter dan rumah sakitnya.
+// param: id text JT00001
+// columns: id_janji_temu, waktu_pelaksanaan, durasi_menit, status, alasan, email_pasien, email_dokter, id_rs
+MATCH (j:JanjiTemu {id_janji_temu: $id})
+RETURN j.id_janji_temu AS id_janji_temu, j.waktu_pelaksanaan AS waktu_pelaksanaan,
+       j.durasi_menit AS durasi_menit, j.status AS status, j.alasan AS alasan,
+       head([(j)-[:memiliki_janji]->(p:Pasien) | p.email]) AS email_pasien,
+       head([(j)-[:dengan_dokter]->(t:TenagaMedis) | t.email]) AS email_dokter,
+       head([(j)-[:di_rs]->(rs:RumahSakit) | rs.id_rs]) AS id_rs;
+
+// name: janji_temu.pesan
+// doc: Buat janji temu berstatus 'dijadwalkan' bila dokter dan pasien tidak
+// doc: punya janji temu lain yang beririsan dengan [$waktu, $waktu + $durasi).
+// doc: Tidak mengembalikan baris bila bentrok atau node tidak ditemukan.
+// param: id text JT-CONTOH01
+// param: email_pasien text pasien1@mail.com
+// param: email_dokter text tm1@rs.com
+// param: id_rs text RS001
+// param: waktu text 2025-01-06 09:00:00
+// param: durasi int 30
+// param: durasi_default int 30
+// param: alasan text Demam
+// columns: id_janji_temu
+MATCH (t:TenagaMedis {email: $email_dokter})
+MATCH (p:Pasien {email: $email_pasien})
+MATCH (rs:RumahSakit {id_rs: $id_rs})
+SET t.versi_jadwal = coalesce(t.versi_jadwal, 0) + 1,
+    p.versi_jadwal = coalesce(p.versi_jadwal, 0) + 1
+WITH t, p, rs, localdatetime(replace($waktu, ' ', 'T')) AS mulai
+WITH t, p, rs, mulai, mulai + duration({minutes: $durasi}) AS selesai,
+     [(t)<-[:dengan_dokter]-(x:JanjiTemu) | x] + [(p)<-[:memiliki_janji]-(x:JanjiTemu) | x] AS lain
+WHERE none(x IN lain WHERE coalesce(x.status, '') <> 'dibatalkan'
+      AND localdatetime(replace(x.waktu_pelaksanaan, ' ', 'T')) < selesai
+      AND localdatetime(replace(x.waktu_pelaksanaan, ' ', 'T')) + duration({minutes: coalesce(x.durasi_menit, $durasi_default)}) > mulai)
+CREATE (j:JanjiTemu {id_janji_temu: $id, waktu_pelaksanaan: $waktu, durasi_menit: $durasi, alasan: $alasan, status: 'dijadwalkan'})
+CREATE (j)-[:memiliki_janji]->(p), (j)-[:dengan_dokter]->(t), (j)-[:di_rs]->(rs)
+RETURN j.id_janji_temu AS id_janji_temu;
+
+// name: janji_temu.jadwal_ulang
+// doc: Pindahkan janji temu yang belum selesai/dibatalkan ke $waktu dengan
+// doc: pemeriksaan bentrok yang sama seperti janji_temu.pesan.
+// param: id text JT00001
+// param: waktu text 2025-01-06 10:00:00
+// param: durasi int 30
+// param: durasi_default int 30
+// columns: id_janji_temu
+MATCH (j:JanjiTemu {id_janji_temu: $id})-[:dengan_dokter]->(t:TenagaMedis)
+MATCH (j)-[:memiliki_janji]->(p:Pasien)
+WHERE NOT coalesce(j.status, '') IN ['selesai', 'dibatalkan']
+SET t.versi_jadwal = coalesce(t.versi_jadwal, 0) + 1,
+    p.versi_jadwal = coalesce(p.versi_jadwal, 0) + 1
+WITH j, t, p, localdatetime(replace($waktu, ' ', 'T')) AS mulai
+WITH j, mulai, mulai + duration({minutes: $durasi}) AS selesai,
+     [(t)<-[:dengan_dokter]-(x:JanjiTemu) | x] + [(p)<-[:memiliki_janji]-(x:JanjiTemu) | x] AS lain
+WHERE none(x IN lain WHERE x <> j AND coalesce(x.status, '') <> 'dibatalkan'
+      AND localdatetime(replace(x.waktu_pelaksanaan, ' ', 'T')) < selesai
+      AND localdatetime(replace(x.waktu_pelaksanaan, ' ', 'T')) + duration({minutes: coalesce(x.durasi_menit, $durasi_default)}) > mulai)
+SET j.waktu_pelaksanaan = $waktu, j.durasi_menit = $durasi
+RETURN j.id_janji_temu AS id_janji_temu;
+
+// name: janji_temu.bentrok
+// doc: Janji temu dokter atau pasien yang beririsan dengan [$waktu, $waktu +
+// doc: $durasi), selain janji temu $id; dipakai untuk menjelaskan penolakan.
+// param: id text JT00001
+// param: email_dokter text tm1@rs.com
+// param: email_pasien text pasien1@mail.com
+// param: waktu text 2025-01-06 09:00:00
+// param: durasi int 30
+// param: durasi_default int 30
+// columns: id_janji_temu, waktu_pelaksanaan, peran
+CALL {
+    MATCH (:TenagaMedis {email: $email_dokter})<-[:dengan_dokter]-(x:JanjiTemu) RETURN x, 'dokter' AS peran
+    UNION
+    MATCH (:Pasien {email: $email_pasien})<-[:memiliki_janji]-(x:JanjiTemu) RETURN x, 'pasien' AS peran
+}
+WITH x, peran, localdatetime(replace($waktu, ' ', 'T')) AS mulai,
+     localdatetime(replace(x.waktu_pelaksanaan, ' ', 'T')) AS mulai_lain
+WHERE x.id_janji_temu <> $id AND coalesce(x.status, '') <> 'dibatalkan'
+  AND mulai_lain < mulai + duration({minutes: $durasi})
+  AND mulai_lain + duration({minutes: coalesce(x.durasi_menit, $durasi_default)}) > mulai
+RETURN x.id_janji_temu AS id_janji_temu, x.waktu_pelaksanaan AS waktu_pelaksanaan, peran
+ORDER BY waktu_pelaksanaan, peran;
+
+// name: janji_temu.batalkan
+// doc: Ubah status janji temu yang belum selesai/dibatalkan menjadi
+// doc: 'dibatalkan'; slotnya langsung bisa dipesan lagi.
+// param: id text JT00001
+// columns: id_janji_temu
+MATCH (j:JanjiTemu {id_janji_temu: $id})
+WHERE NOT coalesce(j.status, '') IN ['selesai', 'dibatalkan']
+SET j.status = 'dibatalkan'
+RETURN j.id_janji_temu AS id_janji_temu;
+
 
 // ========================================
-// HAPUS DATA INSERT 1-4 (untuk re-run queries)
+// APOTEK: Pemesan obat
 // ========================================
 
-// Hapus Pasien dengan email 'andi@example.com' (INSERT 1)
-MATCH (p:Pasien {email: 'andi@example.com'})
-DETACH DELETE p;
+// name: pasien.ada
+// doc: Email pasien bila node Pasien ada (validasi pemesan obat).
+// param: email text pasien1@mail.com
+// columns: email
+MATCH (p:Pasien {email: $email})
+RETURN p.email AS email;
+
+// name: resep.untuk_pesanan
+// doc: Resep beserta pasien (lewat JanjiTemu) dan baris DetailResep untuk
+// doc: dijadikan pesanan obat. lama_hari opsional pada Resep/DetailResep.
+// param: id_resep text R00001
+// columns: id_resep, penyakit, id_janji_temu, email_pasien, lama_hari, detail
+MATCH (r:Resep {id_resep: $id_resep})
+OPTIONAL MATCH (j:JanjiTemu)-[:menghasilkan_resep]->(r)
+OPTIONAL MATCH (j)-[:memiliki_janji]->(p:Pasien)
+RETURN r.id_resep AS id_resep,
+       r.penyakit AS penyakit,
+       j.id_janji_temu AS id_janji_temu,
+       p.email AS email_pasien,
+       r.lama_hari AS lama_hari,
+       [(r)-[:memiliki_detail]->(dr:DetailResep) |
+          {id_obat: dr.id_obat, dosis: dr.dosis, lama_hari: dr.lama_hari}] AS detail;
 
-// Hapus Rumah Sakit 'RS Sehat Selalu' beserta departemennya (INSERT 3 & 4)
-MATCH (rs:RumahSakit {id_rs: 'RS999'})
-OPTIONAL MATCH (rs)-[:memiliki_departemen]->(d:Departemen)
-DETACH DELETE rs, d;
 
-// Atau hapus hanya departemen 'Kardiologi' di RS tertentu (INSERT 4)
-MATCH (rs:RumahSakit {nama_rumah_sakit: 'RS Sehat Selalu'})-[:memiliki_departemen]->(d:Departemen {nama_departemen: 'Kardiologi'})
-DETACH DELETE d;
+// ========================================
+// RETENSI: Pindai dan anonimkan janji temu
+// ========================================
+// Dipakai mesin kebijakan retensi (rs retention). Halaman berikutnya
+// dimulai setelah id terakhir (keyset) sehingga node yang dihapus di
+// tengah jalan tidak menggeser halaman. Penghapusan memakai
+// janji_temu.hapus yang melewati janji temu dengan resep.
+
+// name: janji_temu.halaman
+// doc: Satu halaman janji temu urut id, mulai setelah $setelah.
+// param: setelah text JT00000
+// param: limit int 100
+// columns: id_janji_temu, waktu_pelaksanaan, alasan, status, punya_resep, dianonimkan
+MATCH (j:JanjiTemu)
+WHERE j.id_janji_temu > $setelah
+RETURN j.id_janji_temu AS id_janji_temu,
+       j.waktu_pelaksanaan AS waktu_pelaksanaan,
+       j.alasan AS alasan,
+       j.status AS status,
+       EXISTS { (j)-[:menghasilkan_resep]->(:Resep) } AS punya_resep,
+       coalesce(j.dianonimkan, false) AS dianonimkan
+ORDER BY id_janji_temu
+LIMIT $limit;
+
+// name: janji_temu.anonimkan
+// doc: Kosongkan alasan janji temu dan tandai dianonimkan.
+// param: ids list<text> JT00001,JT00002
+// columns: diubah
+MATCH (j:JanjiTemu)
+WHERE j.id_janji_temu IN $ids AND j.dianonimkan IS NULL
+SET j.alasan = null, j.dianonimkan = true
+RETURN count(j) AS diubah;
 
-// Hapus semua departemen yang tidak terhubung ke RS manapun
-MATCH (d:Departemen)
-WHERE NOT (d)<-[:memiliki_departemen]-()
-DELETE d;
 
 // ========================================
-// QUICK CLEANUP - Hapus semua data INSERT 1-4 sekaligus
+// ARSIP: Salinan janji temu sebelum dihapus dan pemulihannya
 // ========================================
-MATCH (p:Pasien {email: 'andi@example.com'})
-DETACH DELETE p;
+// Dipakai paket arsip (rs archive, delete stale-appointments, aksi archive
+// rs retention). Tetangga disimpan sebagai label + properti unik sesuai
+// constraint schema sehingga relationship bisa dibuat ulang. Pemulihan
+// memakai tipe relationship dinamis $(...) (Neo4j 5.26+).
+
+// name: janji_temu.arsip
+// doc: Node janji temu beserta semua relationship dan kunci tetangganya.
+// param: ids list<text> JT00001,JT00002
+// columns: id_janji_temu, label, properti, relasi
+MATCH (j:JanjiTemu)
+WHERE j.id_janji_temu IN $ids
+OPTIONAL MATCH (j)-[r]-(n)
+WITH j, r, n, head(labels(n)) AS label_n
+WITH j, r, n, label_n,
+     CASE label_n
+       WHEN 'Pasien' THEN 'email'
+       WHEN 'TenagaMedis' THEN 'email'
+       WHEN 'RumahSakit' THEN 'id_rs'
+       WHEN 'Departemen' THEN 'nama_departemen'
+       WHEN 'LayananMedis' THEN 'id_layanan'
+       WHEN 'Baymin' THEN 'id_perangkat'
+       WHEN 'JanjiTemu' THEN 'id_janji_temu'
+       WHEN 'Resep' THEN 'id_resep'
+       WHEN 'DetailResep' THEN 'id_detail_resep'
+     END AS kunci_n
+RETURN j.id_janji_temu AS id_janji_temu,
+       labels(j) AS label,
+       properties(j) AS properti,
+       collect(CASE WHEN r IS NULL THEN null ELSE {
+         tipe: type(r), keluar: startNode(r) = j, properti: properties(r),
+         label: label_n, kunci: kunci_n,
+         nilai: CASE WHEN kunci_n IS NULL THEN null ELSE n[kunci_n] END
+       } END) AS relasi
+ORDER BY id_janji_temu;
 
-MATCH (rs:RumahSakit {id_rs: 'RS999'})
-OPTIONAL MATCH (rs)-[r]->(d:Departemen)
-DETACH DELETE rs, d;
+// name: janji_temu.pulihkan
+// doc: Buat ulang janji temu yang diarsipkan beserta relationship ke tetangga
+// doc: yang masih ada. Janji temu yang sudah ada dilewati (dibuat = 0).
+// param: properti json {"id_janji_temu": "JT-ARSIP", "waktu_pelaksanaan": "2025-01-06 09:00:00", "status": "selesai"}
+// param: relasi json [{"tipe": "memiliki_janji", "keluar": true, "properti": {}, "label": "Pasien", "kunci": "email", "nilai": "pasien1@mail.com"}]
+// columns: dibuat, relasi
+OPTIONAL MATCH (ada:JanjiTemu {id_janji_temu: $properti.id_janji_temu})
+WITH ada WHERE ada IS NULL
+CREATE (j:JanjiTemu)
+SET j = $properti
+WITH j
+UNWIND CASE WHEN size($relasi) = 0 THEN [null] ELSE $relasi END AS r
+OPTIONAL MATCH (n)
+WHERE r IS NOT NULL AND r.label IN labels(n) AND n[r.kunci] = r.nilai
+FOREACH (_ IN CASE WHEN n IS NOT NULL AND r.keluar THEN [1] ELSE [] END |
+  CREATE (j)-[x:$(r.tipe)]->(n) SET x = r.properti)
+FOREACH (_ IN CASE WHEN n IS NOT NULL AND NOT r.keluar THEN [1] ELSE [] END |
+  CREATE (n)-[x:$(r.tipe)]->(j) SET x = r.properti)
+RETURN count(DISTINCT j) AS dibuat, count(n) AS relasi;
 
 
 // ========================================
@@ -118,4 +609,5 @@ DETACH DELETE rs, d;
 // 2. Klik node di visualization untuk lihat properties
 // 3. Gunakan PROFILE untuk detailed performance metrics
 // 4. Gunakan EXPLAIN untuk lihat execution plan tanpa run query
+//    (rs catalog check memakai EXPLAIN untuk semua entri di file ini)
 // 5. Double-click node untuk expand relationships
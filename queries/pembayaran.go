@@ -0,0 +1,237 @@
+package queries
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"src/cassandra"
+	"src/uang"
+)
+
+// ===============================================
+//   PEMBAYARAN
+// ===============================================
+
+// Pembayaran adalah satu payment intent untuk tagihan. Jumlah dalam sen di
+// database.
+type Pembayaran struct {
+	IdPembayaran string      `json:"id_pembayaran"`
+	NomorTagihan string      `json:"nomor_tagihan"`
+	EmailPasien  string      `json:"email_pasien"`
+	Gateway      string      `json:"gateway"`
+	RefGateway   string      `json:"ref_gateway"`
+	URLBayar     string      `json:"url_bayar"`
+	Jumlah       uang.Rupiah `json:"jumlah"`
+	Status       string      `json:"status"`
+	Alasan       string      `json:"alasan,omitempty"`
+	Dibuat       time.Time   `json:"dibuat"`
+	Kedaluwarsa  time.Time   `json:"kedaluwarsa"`
+	Diperbarui   time.Time   `json:"diperbarui"`
+}
+
+// PembayaranMenunggu adalah pembayaran yang belum mendapat hasil dari
+// gateway.
+type PembayaranMenunggu struct {
+	IdPembayaran string
+	Gateway      string
+	Kedaluwarsa  time.Time
+}
+
+var kolomPembayaran = []string{"id_pembayaran", "nomor_tagihan", "email_pasien", "gateway", "ref_gateway", "url_bayar",
+	"jumlah", "status", "alasan", "dibuat", "kedaluwarsa", "diperbarui"}
+
+var (
+	qKlaimPembayaranAktif = use("pembayaran_aktif.klaim").with("nomor_tagihan", "id_pembayaran")
+	qLepasPembayaranAktif = use("pembayaran_aktif.lepas").with("nomor_tagihan", "id_pembayaran")
+	qBuatPembayaran       = use("pembayaran.buat").with("id_pembayaran", "nomor_tagihan", "email_pasien", "gateway", "ref_gateway", "url_bayar", "jumlah", "status", "dibuat", "kedaluwarsa", "diperbarui")
+	qBuatPembayaranPerTgh = use("pembayaran_per_tagihan.buat").with("nomor_tagihan", "dibuat", "id_pembayaran")
+	qDetailPembayaran     = use("pembayaran.detail").with("id_pembayaran").returns(kolomPembayaran...)
+	qPembayaranPerID      = use("pembayaran.per_id").with("ids").returns(kolomPembayaran...)
+	qPembayaranTagihan    = use("pembayaran_per_tagihan.daftar").with("nomor_tagihan").returns("id_pembayaran")
+	qPembayaranMenunggu   = use("pembayaran.menunggu").returns("id_pembayaran", "gateway", "kedaluwarsa")
+	qUbahStatusPembayaran = use("pembayaran.ubah_status").with("status_baru", "alasan", "diperbarui", "id_pembayaran", "status_lama")
+	qCatatWebhook         = use("webhook_pembayaran.catat").with("gateway", "id_event", "id_pembayaran", "jenis", "diterima")
+	qUbahStatusTagihan    = use("tagihan.ubah_status").with("status_baru", "nomor", "status_lama")
+	qUbahStatusObat       = use("pemesanan_obat.ubah_status").with("status_baru", "id_pesanan", "status_lama")
+)
+
+// KlaimPembayaranAktif menandai pembayaran yang sedang berjalan untuk
+// tagihan. Bila tagihan sudah punya pembayaran berjalan, applied false dan
+// sekarang berisi id pembayaran tersebut.
+func KlaimPembayaranAktif(nomor, idPembayaran string) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qKlaimPembayaranAktif.text(), nomor, idPembayaran)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal menandai pembayaran tagihan %s: %v", nomor, err)
+	}
+	if applied {
+		return true, idPembayaran, nil
+	}
+	sekarang, _ = prev["id_pembayaran"].(string)
+	return false, sekarang, nil
+}
+
+// LepasPembayaranAktif menghapus tanda pembayaran berjalan bila masih
+// menunjuk idPembayaran.
+func LepasPembayaranAktif(nomor, idPembayaran string) error {
+	if _, _, err := cassandra.CASCassandra(qLepasPembayaranAktif.text(), nomor, idPembayaran); err != nil {
+		return fmt.Errorf("gagal melepas pembayaran %s dari tagihan %s: %v", idPembayaran, nomor, err)
+	}
+	return nil
+}
+
+// SimpanPembayaran menulis pembayaran dan tabel query per tagihan dalam
+// satu LOGGED BATCH.
+func SimpanPembayaran(p Pembayaran) error {
+	err := cassandra.LoggedBatchCassandra(
+		cassandra.Statement{Query: qBuatPembayaran.text(), Params: []interface{}{
+			p.IdPembayaran, p.NomorTagihan, p.EmailPasien, p.Gateway, p.RefGateway, p.URLBayar,
+			p.Jumlah, p.Status, p.Dibuat, p.Kedaluwarsa, p.Diperbarui,
+		}},
+		cassandra.Statement{Query: qBuatPembayaranPerTgh.text(), Params: []interface{}{p.NomorTagihan, p.Dibuat, p.IdPembayaran}},
+	)
+	if err != nil {
+		return fmt.Errorf("gagal menyimpan pembayaran %s: %v", p.IdPembayaran, err)
+	}
+	return nil
+}
+
+func scanPembayaran(scan func(dest ...interface{}) bool, p *Pembayaran) bool {
+	return scan(&p.IdPembayaran, &p.NomorTagihan, &p.EmailPasien, &p.Gateway, &p.RefGateway, &p.URLBayar,
+		&p.Jumlah, &p.Status, &p.Alasan, &p.Dibuat, &p.Kedaluwarsa, &p.Diperbarui)
+}
+
+// DetailPembayaran membaca satu pembayaran; nil bila tidak ada.
+func DetailPembayaran(id string) (*Pembayaran, error) {
+	iter, err := cassandra.SelectCassandra(qDetailPembayaran.text(), id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran %s: %v", id, err)
+	}
+	var p Pembayaran
+	found := scanPembayaran(iter.Scan, &p)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran %s: %v", id, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// PembayaranTagihan membaca semua pembayaran satu tagihan, terbaru lebih
+// dulu.
+func PembayaranTagihan(nomor string) ([]Pembayaran, error) {
+	iter, err := cassandra.SelectCassandra(qPembayaranTagihan.text(), nomor)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran tagihan %s: %v", nomor, err)
+	}
+	var ids []string
+	var id string
+	for iter.Scan(&id) {
+		ids = append(ids, id)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran tagihan %s: %v", nomor, err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	iter, err = cassandra.SelectCassandra(qPembayaranPerID.text(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran tagihan %s: %v", nomor, err)
+	}
+	byID := make(map[string]Pembayaran, len(ids))
+	var p Pembayaran
+	for scanPembayaran(iter.Scan, &p) {
+		byID[p.IdPembayaran] = p
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran tagihan %s: %v", nomor, err)
+	}
+	out := make([]Pembayaran, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// PembayaranMenungguSebelum mengembalikan paling banyak limit pembayaran
+// berstatus 'menunggu' yang kedaluwarsa sebelum batas, tertua lebih dulu.
+// Filter waktu dan urutan dilakukan di aplikasi.
+func PembayaranMenungguSebelum(batas time.Time, limit int) ([]PembayaranMenunggu, error) {
+	iter, err := cassandra.SelectCassandra(qPembayaranMenunggu.text())
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran menunggu: %v", err)
+	}
+	var out []PembayaranMenunggu
+	var p PembayaranMenunggu
+	for iter.Scan(&p.IdPembayaran, &p.Gateway, &p.Kedaluwarsa) {
+		if p.Kedaluwarsa.Before(batas) {
+			out = append(out, p)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pembayaran menunggu: %v", err)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kedaluwarsa.Before(out[j].Kedaluwarsa) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// UbahStatusPembayaran mengganti status pembayaran dari lama ke baru dengan
+// LWT. Bila status sudah berubah, applied false dan sekarang berisi status
+// terbaru.
+func UbahStatusPembayaran(id, lama, baru, alasan string, waktu time.Time) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qUbahStatusPembayaran.text(), baru, alasan, waktu, id, lama)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal mengubah status pembayaran %s: %v", id, err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	sekarang, _ = prev["status"].(string)
+	return false, sekarang, nil
+}
+
+// CatatWebhook mencatat event webhook. applied false berarti event dengan
+// id yang sama sudah pernah diterima.
+func CatatWebhook(gateway, idEvent, idPembayaran, jenis string, diterima time.Time) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qCatatWebhook.text(), gateway, idEvent, idPembayaran, jenis, diterima)
+	if err != nil {
+		return false, fmt.Errorf("gagal mencatat webhook %s: %v", idEvent, err)
+	}
+	return applied, nil
+}
+
+// UbahStatusTagihan mengganti status tagihan dari lama ke baru dengan LWT.
+func UbahStatusTagihan(nomor, lama, baru string) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qUbahStatusTagihan.text(), baru, nomor, lama)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal mengubah status tagihan %s: %v", nomor, err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	sekarang, _ = prev["status"].(string)
+	return false, sekarang, nil
+}
+
+// UbahStatusObat mengganti status pesanan obat dari lama ke baru dengan
+// LWT. Bila status sudah berubah, applied false dan sekarang berisi status
+// terbaru.
+func UbahStatusObat(id, lama, baru string) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qUbahStatusObat.text(), baru, id, lama)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal mengubah status pesanan obat %s: %v", id, err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	sekarang, _ = prev["status_pemesanan"].(string)
+	return false, sekarang, nil
+}
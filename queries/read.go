@@ -0,0 +1,486 @@
+package queries
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/uang"
+)
+
+// ===============================================
+//   READ 1: Jumlah pesanan obat per pasien
+// ===============================================
+
+type PatientOrderCount struct {
+	Email        string `json:"email"`
+	TotalPesanan int    `json:"total_pesanan"`
+}
+
+var qEmailPemesan = use("pemesanan_obat.email_pemesan").returns("email_pemesan")
+
+func PatientOrderCounts() ([]PatientOrderCount, error) {
+	iter, err := cassandra.SelectCassandra(qEmailPemesan.text())
+	if err != nil {
+		return nil, fmt.Errorf("Terjadi kesalahan: %v", err)
+	}
+
+	var email string
+	orderCountMap := make(map[string]int)
+
+	for iter.Scan(&email) {
+		orderCountMap[email]++
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca data: %v", err)
+	}
+
+	result := make([]PatientOrderCount, 0, len(orderCountMap))
+	for email, count := range orderCountMap {
+		result = append(result, PatientOrderCount{
+			Email:        email,
+			TotalPesanan: count,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalPesanan != result[j].TotalPesanan {
+			return result[i].TotalPesanan > result[j].TotalPesanan
+		}
+		return result[i].Email < result[j].Email
+	})
+
+	return result, nil
+}
+
+// ===============================================
+//   READ 2: Obat dengan stok menipis
+// ===============================================
+
+type MedicineStock struct {
+	IDObat string `json:"id_obat"`
+	Nama   string `json:"nama"`
+	Label  string `json:"label"`
+	Stok   int    `json:"stok"`
+}
+
+var qStokMenipis = use("obat.stok_menipis").with("max_stok").returns("id_obat", "nama", "label", "stok")
+
+func LowStockMedicines(maxStok int) ([]MedicineStock, error) {
+	iter, err := cassandra.SelectCassandra(qStokMenipis.text(), maxStok)
+	if err != nil {
+		return nil, fmt.Errorf("Terjadi kesalahan: %v", err)
+	}
+
+	var medicines []MedicineStock
+
+	var idObat, nama, label string
+	var stok int
+
+	for iter.Scan(&idObat, &nama, &label, &stok) {
+		medicines = append(medicines, MedicineStock{
+			IDObat: idObat,
+			Nama:   nama,
+			Label:  label,
+			Stok:   stok,
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca data: %v", err)
+	}
+
+	return medicines, nil
+}
+
+// ===============================================
+//   READ 3: Log aktivitas Baymin milik pasien
+// ===============================================
+
+type BayminLog struct {
+	Nama            string    `json:"nama"`
+	WaktuAktivitas  time.Time `json:"waktu_aktivitas"`
+	DetailAktivitas string    `json:"detail_aktivitas"`
+}
+
+var (
+	qPerangkatPasien = use("baymin.perangkat_pasien").with("email").returns("id_perangkat", "nama")
+	qLogPerangkat    = use("log_aktivitas.per_perangkat").with("id_perangkat").returns("waktu_aktivitas", "detail_aktivitas")
+)
+
+// BayminLogs mencari perangkat Baymin pasien di Neo4j lalu membaca lognya
+// dari Cassandra.
+func BayminLogs(email string) ([]BayminLog, error) {
+	idPerangkat, nama, err := bayminDevice(email)
+	if err != nil {
+		return nil, err
+	}
+	return bayminDeviceLogs(idPerangkat, nama)
+}
+
+func bayminDevice(email string) (string, string, error) {
+	records, err := neo4j.ReadNeo4j(qPerangkatPasien.text(), map[string]interface{}{"email": email})
+	if err != nil {
+		return "", "", fmt.Errorf("gagal membaca dari Neo4j: %v", err)
+	}
+
+	if len(records) == 0 {
+		return "", "", fmt.Errorf("tidak ditemukan Baymin untuk pasien dengan email %s", email)
+	}
+
+	idPerangkat := fmt.Sprintf("%v", records[0]["id_perangkat"])
+	nama := fmt.Sprintf("%v", records[0]["nama"])
+	return idPerangkat, nama, nil
+}
+
+func bayminDeviceLogs(idPerangkat string, namaPasien string) ([]BayminLog, error) {
+	iter, err := cassandra.SelectCassandra(qLogPerangkat.text(), idPerangkat)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca dari Cassandra: %v", err)
+	}
+
+	var logs []BayminLog
+	var waktu time.Time
+	var detail string
+
+	for iter.Scan(&waktu, &detail) {
+		logs = append(logs, BayminLog{
+			Nama:            namaPasien,
+			WaktuAktivitas:  waktu,
+			DetailAktivitas: detail,
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal menutup iterasi Cassandra: %v", err)
+	}
+
+	return logs, nil
+}
+
+// ===============================================
+//   READ 4: Jumlah janji temu per tenaga medis
+// ===============================================
+
+type MedikJanjiTemu struct {
+	Email           string `json:"email"`
+	Nama            string `json:"nama"`
+	Profesi         string `json:"profesi"`
+	JumlahJanjiTemu int    `json:"jumlah_janji_temu"`
+}
+
+var qJumlahJanjiTemu = use("tenaga_medis.jumlah_janji_temu").returns("email", "nama", "profesi", "jumlah_janji_temu")
+
+func MedikJanjiTemuCounts() ([]MedikJanjiTemu, error) {
+	records, err := neo4j.ReadNeo4j(qJumlahJanjiTemu.text(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca dari Neo4j: %v", err)
+	}
+
+	var results []MedikJanjiTemu
+	for _, record := range records {
+		results = append(results, MedikJanjiTemu{
+			Email:           fmt.Sprintf("%v", record["email"]),
+			Nama:            fmt.Sprintf("%v", record["nama"]),
+			Profesi:         fmt.Sprintf("%v", record["profesi"]),
+			JumlahJanjiTemu: int(record["jumlah_janji_temu"].(int64)),
+		})
+	}
+
+	return results, nil
+}
+
+// ===============================================
+//   READ 5: Detail resep sebuah janji temu
+// ===============================================
+
+type DetailResep struct {
+	IDJanjiTemu string `json:"id_janji_temu"`
+	Penyakit    string `json:"penyakit"`
+	NamaObat    string `json:"nama_obat"`
+	LabelObat   string `json:"label_obat"`
+	Dosis       string `json:"dosis"`
+}
+
+var (
+	qDetailResep = use("janji_temu.detail_resep").with("id_janji_temu").returns("id_janji_temu", "penyakit", "id_obat", "dosis")
+	qNamaObat    = use("obat.nama_label").with("id_obat").returns("nama", "label")
+)
+
+// DetailResepJanjiTemu membaca detail resep dari Neo4j dan melengkapi nama
+// serta label obat dari Cassandra ("-" bila obat tidak ditemukan).
+func DetailResepJanjiTemu(idJanjiTemu string) ([]DetailResep, error) {
+	params := map[string]interface{}{"id_janji_temu": idJanjiTemu}
+	records, err := neo4j.ReadNeo4j(qDetailResep.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca dari Neo4j: %v", err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("tidak ditemukan detail resep untuk janji temu %s", idJanjiTemu)
+	}
+
+	var results []DetailResep
+	for _, record := range records {
+		idObat := fmt.Sprintf("%v", record["id_obat"])
+		namaObat, labelObat := "-", "-"
+
+		iter, _ := cassandra.SelectCassandra(qNamaObat.text(), idObat)
+		iter.Scan(&namaObat, &labelObat)
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("gagal membaca obat %s: %v", idObat, err)
+		}
+
+		results = append(results, DetailResep{
+			IDJanjiTemu: fmt.Sprintf("%v", record["id_janji_temu"]),
+			Penyakit:    fmt.Sprintf("%v", record["penyakit"]),
+			NamaObat:    namaObat,
+			LabelObat:   labelObat,
+			Dosis:       fmt.Sprintf("%v", record["dosis"]),
+		})
+	}
+
+	return results, nil
+}
+
+// ===============================================
+//   READ 6: Pasien dengan biaya pemesanan obat terbesar
+// ===============================================
+
+type PatientOrderCost struct {
+	Email      string      `json:"email"`
+	TotalBiaya uang.Rupiah `json:"total_biaya"`
+}
+
+var (
+	qPesananEmail = use("pemesanan_obat.id_email").returns("id_pesanan", "email_pemesan")
+	qHargaObat    = use("obat.harga").returns("id_obat", "harga")
+	qDaftarObat   = use("detail_pesanan_obat.daftar_obat").with("id_pesanan").returns("daftar_obat")
+)
+
+func PatientOrderCosts() ([]PatientOrderCost, error) {
+	// Step 1: Get all orders
+	iter, err := cassandra.SelectCassandra(qPesananEmail.text())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pemesanan_obat: %v", err)
+	}
+
+	type orderInfo struct {
+		ID    string
+		Email string
+	}
+	orders := make([]orderInfo, 0)
+	var idPesanan, emailPemesan string
+	for iter.Scan(&idPesanan, &emailPemesan) {
+		orders = append(orders, orderInfo{ID: idPesanan, Email: emailPemesan})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read pemesanan_obat: %v", err)
+	}
+
+	// Step 2: Cache all medication prices once
+	priceCache := make(map[string]uang.Rupiah)
+	priceIter, err := cassandra.SelectCassandra(qHargaObat.text())
+	if err != nil {
+		return nil, err
+	}
+
+	var idObat string
+	var harga uang.Rupiah
+	for priceIter.Scan(&idObat, &harga) {
+		priceCache[idObat] = harga
+	}
+	if err := priceIter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read obat: %v", err)
+	}
+
+	// Step 3: Process orders using cached prices
+	patientMap := make(map[string]uang.Rupiah)
+	for _, order := range orders {
+		detailIter, err := cassandra.SelectCassandra(qDaftarObat.text(), order.ID)
+		if err != nil {
+			log.Printf("Error getting details for order %s: %v", order.ID, err)
+			continue
+		}
+
+		var daftarObat map[string]int
+		if detailIter.Scan(&daftarObat) {
+			for obatID, jumlah := range daftarObat {
+				patientMap[order.Email] += priceCache[obatID].Kali(jumlah)
+			}
+		}
+		if err := detailIter.Close(); err != nil {
+			log.Printf("Error getting details for order %s: %v", order.ID, err)
+		}
+	}
+
+	patients := make([]PatientOrderCost, 0, len(patientMap))
+	for email, totalBiaya := range patientMap {
+		patients = append(patients, PatientOrderCost{
+			Email:      email,
+			TotalBiaya: totalBiaya,
+		})
+	}
+
+	sort.Slice(patients, func(i, j int) bool {
+		return patients[i].TotalBiaya > patients[j].TotalBiaya
+	})
+
+	return patients, nil
+}
+
+// ===============================================
+//   READ 7: Layanan medis yang paling sering dipesan
+// ===============================================
+
+type LayananStats struct {
+	NamaLayanan   string `json:"nama_layanan"`
+	JumlahPesanan int    `json:"jumlah_pesanan"`
+}
+
+// Count appointments at hospitals that offer each service
+var qLayananTerpopuler = use("layanan_medis.terpopuler").returns("nama_layanan", "jumlah_pesanan")
+
+func MostOrderedServices() ([]LayananStats, error) {
+	results, err := neo4j.ReadNeo4j(qLayananTerpopuler.text(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Neo4j: %v", err)
+	}
+
+	services := make([]LayananStats, 0)
+	for _, record := range results {
+		services = append(services, LayananStats{
+			NamaLayanan:   stringValue(record, "nama_layanan"),
+			JumlahPesanan: int(record["jumlah_pesanan"].(int64)),
+		})
+	}
+
+	return services, nil
+}
+
+// ===============================================
+//   READ 8 & 9: Peringkat rumah sakit
+// ===============================================
+
+type RumahSakitStats struct {
+	NamaRumahSakit string `json:"nama_rumah_sakit"`
+	Jumlah         int    `json:"jumlah"`
+}
+
+var (
+	qTopJanjiTemu   = use("rumah_sakit.top_janji_temu").returns("nama_rumah_sakit", "jumlah")
+	qTopTenagaMedis = use("rumah_sakit.top_tenaga_medis").returns("nama_rumah_sakit", "jumlah")
+)
+
+// TopHospitalsByAppointments mengurutkan rumah sakit berdasarkan jumlah
+// janji temu.
+func TopHospitalsByAppointments() ([]RumahSakitStats, error) {
+	return hospitalStats(qTopJanjiTemu)
+}
+
+// HospitalsByMedicalStaff mengurutkan rumah sakit berdasarkan jumlah tenaga
+// medis (lewat departemen).
+func HospitalsByMedicalStaff() ([]RumahSakitStats, error) {
+	return hospitalStats(qTopTenagaMedis)
+}
+
+func hospitalStats(q *query) ([]RumahSakitStats, error) {
+	results, err := neo4j.ReadNeo4j(q.text(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Neo4j: %v", err)
+	}
+
+	hospitals := make([]RumahSakitStats, 0)
+	for _, record := range results {
+		hospitals = append(hospitals, RumahSakitStats{
+			NamaRumahSakit: stringValue(record, "nama_rumah_sakit"),
+			Jumlah:         int(record["jumlah"].(int64)),
+		})
+	}
+
+	return hospitals, nil
+}
+
+// ===============================================
+//   READ 10: Pasien dengan janji temu tanpa resep
+// ===============================================
+
+type PasienNoResep struct {
+	Email           string `json:"email"`
+	NamaLengkap     string `json:"nama_lengkap"`
+	JumlahJanjiTemu int    `json:"jumlah_janji_temu"`
+}
+
+// Find patients who have appointments but those appointments didn't produce prescriptions
+var qTanpaResep = use("pasien.tanpa_resep").returns("email", "nama_lengkap", "jumlah_janji_temu")
+
+func PatientsWithoutPrescriptions() ([]PasienNoResep, error) {
+	results, err := neo4j.ReadNeo4j(qTanpaResep.text(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Neo4j: %v", err)
+	}
+
+	patients := make([]PasienNoResep, 0)
+	for _, record := range results {
+		patients = append(patients, PasienNoResep{
+			Email:           stringValue(record, "email"),
+			NamaLengkap:     stringValue(record, "nama_lengkap"),
+			JumlahJanjiTemu: int(record["jumlah_janji_temu"].(int64)),
+		})
+	}
+
+	return patients, nil
+}
+
+// ===============================================
+//   SPECIAL GRAPH: Dokter spesialis di suatu kota
+// ===============================================
+
+type DokterSpesialis struct {
+	NamaDokter string `json:"nama_dokter"`
+	Telepon    string `json:"telepon"`
+	Departemen string `json:"departemen"`
+	RumahSakit string `json:"rumah_sakit"`
+	Alamat     string `json:"alamat"`
+}
+
+var qSpesialis = use("tenaga_medis.spesialis_di_kota").with("profesi", "kota", "limit").
+	returns("nama_dokter", "telepon", "departemen", "rumah_sakit", "alamat")
+
+func DokterSpesialisDiKota(profesi, kota string, limit int) ([]DokterSpesialis, error) {
+	params := map[string]interface{}{
+		"profesi": profesi,
+		"kota":    kota,
+		"limit":   limit,
+	}
+
+	results, err := neo4j.ReadNeo4j(qSpesialis.text(), params)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencari dokter spesialis: %v", err)
+	}
+
+	var dokters []DokterSpesialis
+	for _, record := range results {
+		dokters = append(dokters, DokterSpesialis{
+			NamaDokter: stringValue(record, "nama_dokter"),
+			Telepon:    stringValue(record, "telepon"),
+			Departemen: stringValue(record, "departemen"),
+			RumahSakit: stringValue(record, "rumah_sakit"),
+			Alamat:     stringValue(record, "alamat"),
+		})
+	}
+
+	return dokters, nil
+}
+
+// --- Helper ---
+func stringValue(record map[string]interface{}, key string) string {
+	if val, ok := record[key]; ok && val != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return ""
+}
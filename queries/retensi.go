@@ -0,0 +1,145 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// ===============================================
+//   RETENSI
+// ===============================================
+//
+// Akses data untuk mesin kebijakan retensi (paket retensi). Halaman*
+// mengembalikan baris apa adanya (nama kolom -> nilai) agar bisa dievaluasi
+// terhadap kondisi aturan dan diarsipkan tanpa kehilangan kolom.
+
+var (
+	qSemuaPemesananObat    = use("pemesanan_obat.semua").returns("id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan", "total_harga")
+	qHapusDetailPesanan    = use("detail_pesanan_obat.hapus").with("id_pesanan")
+	qAnonimkanPesananObat  = use("pemesanan_obat.anonimkan").with("email_pemesan", "id_pesanan")
+	qHapusLayanan          = use("pemesanan_layanan.hapus").with("id_pesanan")
+	qHapusLayananPasien    = use("pemesanan_layanan_per_pasien.hapus").with("email_pemesan", "jadwal_pelaksanaan", "id_pesanan")
+	qHapusLayananRS        = use("pemesanan_layanan_per_rs.hapus").with("id_rs", "tanggal", "jadwal_pelaksanaan", "id_pesanan")
+	qAnonimkanLayanan      = use("pemesanan_layanan.anonimkan").with("email_pemesan", "id_pesanan")
+	qAnonimkanLayananRS    = use("pemesanan_layanan_per_rs.anonimkan").with("email_pemesan", "id_rs", "tanggal", "jadwal_pelaksanaan", "id_pesanan")
+	qSemuaLogAktivitas     = use("log_aktivitas.semua").returns("id_perangkat", "waktu_aktivitas", "detail_aktivitas")
+	qAnonimkanLogAktivitas = use("log_aktivitas.anonimkan").with("detail_aktivitas", "id_perangkat", "waktu_aktivitas")
+	qHalamanJanjiTemu      = use("janji_temu.halaman").with("setelah", "limit").returns("id_janji_temu", "waktu_pelaksanaan", "alasan", "status", "punya_resep", "dianonimkan")
+	qAnonimkanJanjiTemu    = use("janji_temu.anonimkan").with("ids").returns("diubah")
+)
+
+// HalamanPemesananObat membaca satu halaman pemesanan_obat mulai paging
+// state (nil = awal); state berikutnya nil bila sudah habis.
+func HalamanPemesananObat(state []byte, n int) ([]map[string]interface{}, []byte, error) {
+	return halaman(qSemuaPemesananObat, "pemesanan_obat", state, n)
+}
+
+// HalamanLogAktivitas membaca satu halaman log_aktivitas.
+func HalamanLogAktivitas(state []byte, n int) ([]map[string]interface{}, []byte, error) {
+	return halaman(qSemuaLogAktivitas, "log_aktivitas", state, n)
+}
+
+func halaman(q *query, tabel string, state []byte, n int) ([]map[string]interface{}, []byte, error) {
+	rows, next, err := cassandra.PageCassandra(q.text(), nil, n, state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca %s: %v", tabel, err)
+	}
+	return rows, next, nil
+}
+
+// HapusPesananObatLengkap menghapus pesanan obat beserta daftar obatnya
+// dalam satu LOGGED BATCH.
+func HapusPesananObatLengkap(id string) error {
+	err := cassandra.LoggedBatchCassandra(
+		cassandra.Statement{Query: qHapusPesanan.text(), Params: []interface{}{id}},
+		cassandra.Statement{Query: qHapusDetailPesanan.text(), Params: []interface{}{id}},
+	)
+	if err != nil {
+		return fmt.Errorf("gagal hapus pesanan obat %s: %v", id, err)
+	}
+	return nil
+}
+
+// AnonimkanPemesananObat mengganti email pemesan pesanan obat.
+func AnonimkanPemesananObat(id, pseudonim string) error {
+	if err := cassandra.UpdateCassandra(qAnonimkanPesananObat.text(), pseudonim, id); err != nil {
+		return fmt.Errorf("gagal anonimkan pesanan obat %s: %v", id, err)
+	}
+	return nil
+}
+
+// HapusPemesananLayanan menghapus pemesanan layanan beserta baris tabel
+// query per pasien dan per RS dalam satu LOGGED BATCH.
+func HapusPemesananLayanan(p PemesananLayanan) error {
+	statements := []cassandra.Statement{{Query: qHapusLayanan.text(), Params: []interface{}{p.IdPesanan}}}
+	if p.EmailPemesan != "" {
+		statements = append(statements, cassandra.Statement{Query: qHapusLayananPasien.text(),
+			Params: []interface{}{p.EmailPemesan, p.JadwalPelaksanaan, p.IdPesanan}})
+	}
+	if p.IdRS != "" {
+		statements = append(statements, cassandra.Statement{Query: qHapusLayananRS.text(),
+			Params: []interface{}{p.IdRS, TanggalJadwal(p.JadwalPelaksanaan), p.JadwalPelaksanaan, p.IdPesanan}})
+	}
+	if err := cassandra.LoggedBatchCassandra(statements...); err != nil {
+		return fmt.Errorf("gagal hapus pemesanan layanan %s: %v", p.IdPesanan, err)
+	}
+	return nil
+}
+
+// AnonimkanPemesananLayanan mengganti email pemesan di pemesanan_layanan
+// dan tabel query-nya. Baris per pasien dipindah ke partition pseudonim
+// karena email adalah partition key-nya.
+func AnonimkanPemesananLayanan(p PemesananLayanan, pseudonim string) error {
+	statements := []cassandra.Statement{{Query: qAnonimkanLayanan.text(), Params: []interface{}{pseudonim, p.IdPesanan}}}
+	if p.EmailPemesan != "" {
+		statements = append(statements,
+			cassandra.Statement{Query: qHapusLayananPasien.text(),
+				Params: []interface{}{p.EmailPemesan, p.JadwalPelaksanaan, p.IdPesanan}},
+			cassandra.Statement{Query: qBuatLayananPasien.text(),
+				Params: []interface{}{pseudonim, p.JadwalPelaksanaan, p.IdPesanan, p.IdRS, p.IdLayanan, p.NamaLayanan, p.BiayaLayanan}},
+		)
+	}
+	if p.IdRS != "" {
+		statements = append(statements, cassandra.Statement{Query: qAnonimkanLayananRS.text(),
+			Params: []interface{}{pseudonim, p.IdRS, TanggalJadwal(p.JadwalPelaksanaan), p.JadwalPelaksanaan, p.IdPesanan}})
+	}
+	if err := cassandra.LoggedBatchCassandra(statements...); err != nil {
+		return fmt.Errorf("gagal anonimkan pemesanan layanan %s: %v", p.IdPesanan, err)
+	}
+	return nil
+}
+
+// AnonimkanLogAktivitas mengganti detail satu log aktivitas.
+func AnonimkanLogAktivitas(idPerangkat string, waktu time.Time, detail string) error {
+	if err := cassandra.UpdateCassandra(qAnonimkanLogAktivitas.text(), detail, idPerangkat, waktu); err != nil {
+		return fmt.Errorf("gagal anonimkan log %s: %v", idPerangkat, err)
+	}
+	return nil
+}
+
+// HalamanJanjiTemu membaca paling banyak n janji temu dengan id setelah
+// setelah ("" = awal), urut id.
+func HalamanJanjiTemu(setelah string, n int) ([]map[string]interface{}, error) {
+	records, err := neo4j.ReadNeo4j(qHalamanJanjiTemu.text(), map[string]interface{}{"setelah": setelah, "limit": n})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca janji temu: %v", err)
+	}
+	return records, nil
+}
+
+// AnonimkanJanjiTemu mengosongkan alasan janji temu dengan id tertentu dan
+// mengembalikan jumlah node yang berubah.
+func AnonimkanJanjiTemu(ids []string) (int, error) {
+	records, err := neo4j.CreateAndReturnNeo4j(qAnonimkanJanjiTemu.text(), map[string]interface{}{"ids": ids})
+	if err != nil {
+		return 0, fmt.Errorf("gagal anonimkan janji temu: %v", err)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+	n, _ := records[0]["diubah"].(int64)
+	return int(n), nil
+}
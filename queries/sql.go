@@ -0,0 +1,55 @@
+package queries
+
+import (
+	_ "embed"
+	"regexp"
+	"strings"
+)
+
+// ===============================================
+//   SQL EQUIVALENTS
+// ===============================================
+//
+// sql_equivalents.sql adalah versi SQL relasional dari query read1-read10
+// dan special_graph. File ini di-embed agar benchmark dapat menjalankan
+// statement yang sama persis dengan yang didokumentasikan.
+
+//go:embed sql_equivalents.sql
+var sqlEquivalents string
+
+var sqlHeading = regexp.MustCompile(`^--\s*([0-9]+)\.\s`)
+
+// SQLEquivalents mengembalikan statement SQL per nama query lama
+// ("read1".."read10", "special_graph"). Statement pertama setelah heading
+// "-- N. ..." menjadi readN; statement setelah banner SPECIAL GRAPH menjadi
+// special_graph. Baris komentar (termasuk Cypher yang dikomentari) diabaikan.
+func SQLEquivalents() map[string]string {
+	statements := map[string]string{}
+	var key string
+	var buf strings.Builder
+
+	for _, line := range strings.Split(sqlEquivalents, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") {
+			if m := sqlHeading.FindStringSubmatch(trimmed); m != nil {
+				key = "read" + m[1]
+			} else if strings.Contains(trimmed, "SPECIAL GRAPH") {
+				key = "special_graph"
+			}
+			continue
+		}
+		if trimmed == "" && buf.Len() == 0 {
+			continue
+		}
+
+		buf.WriteString(strings.TrimRight(line, " \t"))
+		buf.WriteByte('\n')
+		if strings.HasSuffix(trimmed, ";") {
+			if _, seen := statements[key]; key != "" && !seen {
+				statements[key] = strings.TrimSpace(buf.String())
+			}
+			buf.Reset()
+		}
+	}
+	return statements
+}
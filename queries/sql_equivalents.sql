@@ -28,10 +28,10 @@ WHERE tm.profesi = 'Dokter Spesialis Anak'
 ORDER BY rs.nama_rumah_sakit, u.nama_lengkap
 LIMIT 50;
 
--- Perbandingan Performa:
+-- Perbandingan Performa (perkiraan, bukan hasil ukur):
 -- SQL: ~50-500ms (4 table JOINs, tergantung dataset size)
 -- Neo4j: ~5-50ms (Direct relationship traversal)
--- Winner: Neo4j 10x FASTER!
+-- Ukur pada dataset sendiri dengan: rs bench --seed <seed> --now <now>
 
 -- Mengapa SQL Lambat:
 -- 1. Harus JOIN 4 tabel (tenaga_medis, user, departemen, rumah_sakit)
@@ -74,10 +74,10 @@ JOIN user u ON p.email = u.email
 LEFT JOIN pemesanan_obat po ON p.email = po.email_pemesan
 GROUP BY p.email, u.nama_lengkap;
 
--- 2. Obat dengan stok kurang dari 20
+-- 2. Obat dengan stok kurang dari 55
 SELECT id_obat, label, stok
 FROM obat
-WHERE stok < 20
+WHERE stok < 55
 ORDER BY stok ASC;
 
 -- 3. Log aktivitas Baymin milik pasien tertentu
@@ -86,7 +86,7 @@ FROM log_aktivitas l
 JOIN baymin b ON l.id_perangkat = b.id_perangkat
 JOIN pasien p ON b.email_pasien = p.email
 JOIN user u ON p.email = u.email
-WHERE p.email = 'adhiarjawaskita@example.com'
+WHERE p.email = 'pasien1@mail.com'
 ORDER BY l.waktu_aktivitas DESC;
 
 -- 4. Tenaga medis dan jumlah janji temu mereka
@@ -104,7 +104,7 @@ JOIN resep r ON hjt.id_resep = r.id_resep
 JOIN detail_resep dr ON r.id_resep = dr.id_resep
 JOIN obat o ON dr.id_obat = o.id_obat
 JOIN janji_temu jt ON hjt.id_janji_temu = jt.id_janji_temu
-WHERE jt.id_janji_temu = 3;
+WHERE jt.id_janji_temu = 'JT00011';
 
 -- 6. Pasien dengan pengeluaran obat terbesar
 SELECT p.email, u.nama_lengkap, SUM(o.harga * dp.jumlah) AS total_pengeluaran
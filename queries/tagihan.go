@@ -0,0 +1,260 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"src/cassandra"
+	"src/uang"
+)
+
+// ===============================================
+//   TAGIHAN
+// ===============================================
+
+// Tagihan adalah header satu tagihan. Nominal dalam sen di database.
+type Tagihan struct {
+	Nomor            string
+	EmailPasien      string
+	Dibuat           time.Time
+	JatuhTempo       time.Time
+	Status           string
+	Subtotal         uang.Rupiah
+	Diskon           uang.Rupiah
+	KeteranganDiskon string
+	DPP              uang.Rupiah
+	TarifPPN         uang.Persen
+	PPN              uang.Rupiah
+	Total            uang.Rupiah
+}
+
+// BarisTagihan adalah satu baris tagihan: satu obat dalam pesanan obat,
+// satu pemesanan layanan, atau penyesuaian harga pesanan.
+type BarisTagihan struct {
+	Urutan    int
+	Jenis     string
+	IdPesanan string
+	Kode      string
+	Deskripsi string
+	Jumlah    int
+	Harga     uang.Rupiah
+	Subtotal  uang.Rupiah
+}
+
+var (
+	qPesananObatPasien = use("pemesanan_obat.per_pasien").with("email_pemesan").returns("id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan", "total_harga")
+	qTagihanPesanan    = use("tagihan_pesanan.per_id").with("ids").returns("id_pesanan", "nomor")
+	qKlaimPesanan      = use("tagihan_pesanan.klaim").with("id_pesanan", "nomor", "jenis", "waktu_dibuat")
+	qLepasPesanan      = use("tagihan_pesanan.lepas").with("id_pesanan", "nomor")
+	qNomorTerakhir     = use("nomor_tagihan.terakhir").with("periode").returns("terakhir")
+	qNomorMulai        = use("nomor_tagihan.mulai").with("periode")
+	qNomorUbah         = use("nomor_tagihan.ubah").with("terakhir_baru", "periode", "terakhir_lama")
+	qBuatTagihan       = use("tagihan.buat").with("nomor", "email_pasien", "dibuat", "jatuh_tempo", "status", "subtotal", "diskon", "keterangan_diskon", "dpp", "tarif_ppn", "ppn", "total")
+	qBuatBarisTagihan  = use("tagihan_baris.buat").with("nomor", "urutan", "jenis", "id_pesanan", "kode", "deskripsi", "jumlah", "harga", "subtotal")
+	qBuatTagihanPasien = use("tagihan_per_pasien.buat").with("email_pasien", "dibuat", "nomor", "total")
+	qDetailTagihan     = use("tagihan.detail").with("nomor").returns("nomor", "email_pasien", "dibuat", "jatuh_tempo", "status", "subtotal", "diskon", "keterangan_diskon", "dpp", "tarif_ppn", "ppn", "total")
+	qBarisTagihan      = use("tagihan_baris.per_tagihan").with("nomor").returns("urutan", "jenis", "id_pesanan", "kode", "deskripsi", "jumlah", "harga", "subtotal")
+	qTagihanPasien     = use("tagihan_per_pasien.daftar").with("email_pasien").returns("dibuat", "nomor", "total")
+	qStatusTagihan     = use("tagihan.status_per_nomor").with("nomor").returns("nomor", "status")
+)
+
+// PemesananObatPasien membaca semua header pesanan obat satu pasien.
+func PemesananObatPasien(email string) ([]PemesananObat, error) {
+	iter, err := cassandra.SelectCassandra(qPesananObatPasien.text(), email)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pesanan obat %s: %v", email, err)
+	}
+	var out []PemesananObat
+	var p PemesananObat
+	for iter.Scan(&p.IdPesanan, &p.EmailPemesan, &p.WaktuPemesanan, &p.StatusPemesanan, &p.TotalHarga) {
+		out = append(out, p)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca pesanan obat %s: %v", email, err)
+	}
+	return out, nil
+}
+
+// TagihanPesanan membaca nomor tagihan yang sudah memuat pesanan dengan id
+// tertentu. Pesanan yang belum ditagih tidak muncul di map hasil.
+func TagihanPesanan(ids []string) (map[string]string, error) {
+	out := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	iter, err := cassandra.SelectCassandra(qTagihanPesanan.text(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca tagihan_pesanan: %v", err)
+	}
+	var id, nomor string
+	for iter.Scan(&id, &nomor) {
+		out[id] = nomor
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca tagihan_pesanan: %v", err)
+	}
+	return out, nil
+}
+
+// KlaimPesanan menautkan pesanan ke tagihan. Bila pesanan sudah masuk
+// tagihan lain, applied false dan nomor berisi tagihan tersebut.
+func KlaimPesanan(idPesanan, nomor, jenis string, waktu time.Time) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qKlaimPesanan.text(), idPesanan, nomor, jenis, waktu)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal menautkan pesanan %s ke tagihan: %v", idPesanan, err)
+	}
+	if applied {
+		return true, nomor, nil
+	}
+	sekarang, _ = prev["nomor"].(string)
+	return false, sekarang, nil
+}
+
+// LepasPesanan menghapus tautan pesanan bila masih menunjuk nomor.
+func LepasPesanan(idPesanan, nomor string) error {
+	if _, _, err := cassandra.CASCassandra(qLepasPesanan.text(), idPesanan, nomor); err != nil {
+		return fmt.Errorf("gagal melepas pesanan %s dari tagihan %s: %v", idPesanan, nomor, err)
+	}
+	return nil
+}
+
+// NomorTerakhir membaca nomor urut terakhir pada periode (YYYYMM). ada
+// false berarti belum ada tagihan pada periode itu.
+func NomorTerakhir(periode string) (terakhir int, ada bool, err error) {
+	iter, err := cassandra.SelectCassandra(qNomorTerakhir.text(), periode)
+	if err != nil {
+		return 0, false, fmt.Errorf("gagal membaca nomor tagihan: %v", err)
+	}
+	ada = iter.Scan(&terakhir)
+	if err := iter.Close(); err != nil {
+		return 0, false, fmt.Errorf("gagal membaca nomor tagihan: %v", err)
+	}
+	return terakhir, ada, nil
+}
+
+// MulaiNomor mencatat tagihan pertama pada periode; applied false bila
+// baris periode sudah dibuat tagihan lain.
+func MulaiNomor(periode string) (bool, error) {
+	applied, _, err := cassandra.CASCassandra(qNomorMulai.text(), periode)
+	if err != nil {
+		return false, fmt.Errorf("gagal mengambil nomor tagihan: %v", err)
+	}
+	return applied, nil
+}
+
+// UbahNomor menaikkan nomor urut dari lama ke baru dengan LWT. Bila sudah
+// berubah, applied false dan sekarang berisi nilai terbaru.
+func UbahNomor(periode string, lama, baru int) (applied bool, sekarang int, err error) {
+	applied, prev, err := cassandra.CASCassandra(qNomorUbah.text(), baru, periode, lama)
+	if err != nil {
+		return false, 0, fmt.Errorf("gagal mengambil nomor tagihan: %v", err)
+	}
+	if applied {
+		return true, baru, nil
+	}
+	sekarang, _ = prev["terakhir"].(int)
+	return false, sekarang, nil
+}
+
+// SimpanTagihan menulis header, semua baris dan tabel query per pasien
+// dalam satu LOGGED BATCH.
+func SimpanTagihan(t Tagihan, baris []BarisTagihan) error {
+	statements := []cassandra.Statement{
+		{Query: qBuatTagihan.text(), Params: []interface{}{
+			t.Nomor, t.EmailPasien, t.Dibuat, t.JatuhTempo, t.Status, t.Subtotal, t.Diskon,
+			t.KeteranganDiskon, t.DPP, int(t.TarifPPN), t.PPN, t.Total,
+		}},
+		{Query: qBuatTagihanPasien.text(), Params: []interface{}{t.EmailPasien, t.Dibuat, t.Nomor, t.Total}},
+	}
+	for _, b := range baris {
+		statements = append(statements, cassandra.Statement{Query: qBuatBarisTagihan.text(), Params: []interface{}{
+			t.Nomor, b.Urutan, b.Jenis, b.IdPesanan, b.Kode, b.Deskripsi, b.Jumlah, b.Harga, b.Subtotal,
+		}})
+	}
+	if err := cassandra.LoggedBatchCassandra(statements...); err != nil {
+		return fmt.Errorf("gagal menyimpan tagihan %s: %v", t.Nomor, err)
+	}
+	return nil
+}
+
+// DetailTagihan membaca header dan baris satu tagihan; nil bila tidak ada.
+func DetailTagihan(nomor string) (*Tagihan, []BarisTagihan, error) {
+	iter, err := cassandra.SelectCassandra(qDetailTagihan.text(), nomor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca tagihan %s: %v", nomor, err)
+	}
+	var t Tagihan
+	var tarif int
+	found := iter.Scan(&t.Nomor, &t.EmailPasien, &t.Dibuat, &t.JatuhTempo, &t.Status, &t.Subtotal, &t.Diskon,
+		&t.KeteranganDiskon, &t.DPP, &tarif, &t.PPN, &t.Total)
+	if err := iter.Close(); err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca tagihan %s: %v", nomor, err)
+	}
+	if !found {
+		return nil, nil, nil
+	}
+	t.TarifPPN = uang.Persen(tarif)
+
+	iter, err = cassandra.SelectCassandra(qBarisTagihan.text(), nomor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca baris tagihan %s: %v", nomor, err)
+	}
+	var baris []BarisTagihan
+	var b BarisTagihan
+	for iter.Scan(&b.Urutan, &b.Jenis, &b.IdPesanan, &b.Kode, &b.Deskripsi, &b.Jumlah, &b.Harga, &b.Subtotal) {
+		baris = append(baris, b)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca baris tagihan %s: %v", nomor, err)
+	}
+	return &t, baris, nil
+}
+
+// TagihanPasien membaca paling banyak limit (0 = semua) tagihan satu
+// pasien, terbaru lebih dulu, beserta statusnya. Hanya Nomor, EmailPasien, Dibuat, Total
+// dan Status yang diisi.
+func TagihanPasien(email string, limit int) ([]Tagihan, error) {
+	iter, err := cassandra.SelectCassandra(qTagihanPasien.text(), email)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca tagihan %s: %v", email, err)
+	}
+	var out []Tagihan
+	var nomor []string
+	t := Tagihan{EmailPasien: email}
+	for (limit <= 0 || len(out) < limit) && iter.Scan(&t.Dibuat, &t.Nomor, &t.Total) {
+		out = append(out, t)
+		nomor = append(nomor, t.Nomor)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca tagihan %s: %v", email, err)
+	}
+	status, err := StatusTagihan(nomor)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Status = status[out[i].Nomor]
+	}
+	return out, nil
+}
+
+// StatusTagihan membaca status beberapa tagihan. Nomor yang tidak ada
+// tidak muncul di map hasil.
+func StatusTagihan(nomor []string) (map[string]string, error) {
+	status := make(map[string]string, len(nomor))
+	if len(nomor) == 0 {
+		return status, nil
+	}
+	iter, err := cassandra.SelectCassandra(qStatusTagihan.text(), nomor)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca status tagihan: %v", err)
+	}
+	var n, s string
+	for iter.Scan(&n, &s) {
+		status[n] = s
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca status tagihan: %v", err)
+	}
+	return status, nil
+}
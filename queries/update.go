@@ -0,0 +1,201 @@
+package queries
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// ===============================================
+//   UPDATE 1: Batalkan pesanan obat yang kedaluwarsa
+// ===============================================
+
+type PesananExpired struct {
+	IdPesanan       string
+	WaktuPemesanan  time.Time
+	StatusPemesanan string
+}
+
+var (
+	qBelumDibayar    = use("pemesanan_obat.belum_dibayar").returns("id_pesanan", "waktu_pemesanan", "status_pemesanan")
+	qBatalkanPesanan = use("pemesanan_obat.batalkan").with("id_pesanan")
+)
+
+// tagihanPerBaca membatasi jumlah id pada satu query IN saat memeriksa
+// tagihan pesanan expired.
+const tagihanPerBaca = 100
+
+// ExpiredOrders mengembalikan paling banyak limit pesanan 'belum dibayar'
+// yang dibuat sebelum batas, urut dari yang tertua. Pesanan yang sudah
+// masuk tagihan yang belum dibatalkan dilewati: tagihan baru jatuh tempo
+// setelah beberapa hari (bawaan 7) dan pembayarannya masih bisa datang,
+// jadi pesanan itu mengikuti status tagihannya, bukan umur pesanan.
+func ExpiredOrders(batas time.Time, limit int) ([]PesananExpired, error) {
+	iter, err := cassandra.SelectCassandra(qBelumDibayar.text())
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil data pesanan: %v", err)
+	}
+
+	var result []PesananExpired
+	var id, status string
+	var waktu time.Time
+
+	for iter.Scan(&id, &waktu, &status) {
+		// Filter di aplikasi karena status bukan bagian primary key
+		if waktu.Before(batas) {
+			result = append(result, PesananExpired{
+				IdPesanan:       id,
+				WaktuPemesanan:  waktu,
+				StatusPemesanan: status,
+			})
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("gagal membaca data: %v", err)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].WaktuPemesanan.Before(result[j].WaktuPemesanan)
+	})
+
+	return tanpaTagihanTerbuka(result, limit)
+}
+
+// tanpaTagihanTerbuka membuang pesanan yang tertaut (tagihan_pesanan) ke
+// tagihan yang belum dibatalkan, sampai terkumpul limit pesanan (0 =
+// semua). Tautan ke tagihan yang tidak ada (pembuatan tagihan gagal)
+// diabaikan.
+func tanpaTagihanTerbuka(orders []PesananExpired, limit int) ([]PesananExpired, error) {
+	var out []PesananExpired
+	for i := 0; i < len(orders) && (limit <= 0 || len(out) < limit); i += tagihanPerBaca {
+		chunk := orders[i:min(i+tagihanPerBaca, len(orders))]
+		ids := make([]string, len(chunk))
+		for j, o := range chunk {
+			ids[j] = o.IdPesanan
+		}
+		tertaut, err := TagihanPesanan(ids)
+		if err != nil {
+			return nil, err
+		}
+		nomor := make([]string, 0, len(tertaut))
+		for _, n := range tertaut {
+			nomor = append(nomor, n)
+		}
+		status, err := StatusTagihan(nomor)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range chunk {
+			if limit > 0 && len(out) >= limit {
+				break
+			}
+			if n, ok := tertaut[o.IdPesanan]; ok {
+				if s, ada := status[n]; ada && s != "dibatalkan" {
+					continue
+				}
+			}
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+// CancelOrder mengubah status pesanan menjadi 'dibatalkan' bila masih
+// 'belum dibayar' (LWT). Bila status sudah berubah, applied false dan
+// sekarang berisi status terbaru. Cassandra tidak mendukung UPDATE dengan
+// LIMIT/ORDER BY, jadi pesanan dibatalkan satu per satu.
+func CancelOrder(idPesanan string) (applied bool, sekarang string, err error) {
+	applied, prev, err := cassandra.CASCassandra(qBatalkanPesanan.text(), idPesanan)
+	if err != nil {
+		return false, "", fmt.Errorf("gagal membatalkan pesanan %s: %v", idPesanan, err)
+	}
+	if applied {
+		return true, "dibatalkan", nil
+	}
+	sekarang, _ = prev["status_pemesanan"].(string)
+	return false, sekarang, nil
+}
+
+// ===============================================
+//   UPDATE 2: Pindahtugaskan tenaga medis
+// ===============================================
+
+var (
+	qDepartemenTenagaMedis = use("tenaga_medis.departemen").with("email").returns("email", "departemen")
+	qPindahTenagaMedis     = use("tenaga_medis.pindah").with("email", "dept")
+)
+
+// DepartemenTenagaMedis mengembalikan email dan departemen tenaga medis.
+// Email kosong berarti tenaga medis pertama yang ditemukan.
+func DepartemenTenagaMedis(email string) (map[string]interface{}, error) {
+	records, err := neo4j.ReadNeo4j(qDepartemenTenagaMedis.text(), map[string]interface{}{"email": email})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca data: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("tidak ditemukan node TenagaMedis di database")
+	}
+	return records[0], nil
+}
+
+func PindahTenagaMedis(email string, departemenBaru string) error {
+	params := map[string]interface{}{"email": email, "dept": departemenBaru}
+	return neo4j.UpdateNeo4j(qPindahTenagaMedis.text(), params)
+}
+
+// ===============================================
+//   UPDATE 3: Batalkan pemesanan layanan
+// ===============================================
+
+var (
+	qStatusLayananSemua = use("pemesanan_layanan.status_semua").returns("id_pesanan", "status_pemesanan")
+	qStatusLayanan      = use("pemesanan_layanan.status").with("id_pesanan").returns("status_pemesanan")
+)
+
+// PemesananLayananAktif mencari satu pemesanan layanan yang masih bisa
+// dibatalkan (belum dibatalkan, berlangsung atau selesai). Mengembalikan ""
+// bila tidak ada.
+func PemesananLayananAktif() (string, string, error) {
+	iter, err := cassandra.SelectCassandra(qStatusLayananSemua.text())
+	if err != nil {
+		return "", "", fmt.Errorf("gagal membaca data: %v", err)
+	}
+
+	var idPesanan, status string
+	found := false
+	for iter.Scan(&idPesanan, &status) {
+		if status != "dibatalkan" && status != "sedang berlangsung" && status != "selesai" {
+			found = true
+			break
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return "", "", fmt.Errorf("gagal membaca data: %v", err)
+	}
+	if !found {
+		return "", "", nil
+	}
+	return idPesanan, status, nil
+}
+
+// StatusPemesananLayanan membaca status terkini sebuah pemesanan layanan.
+func StatusPemesananLayanan(idPesanan string) (string, error) {
+	iter, err := cassandra.SelectCassandra(qStatusLayanan.text(), idPesanan)
+	if err != nil {
+		return "", err
+	}
+	var status string
+	found := iter.Scan(&status)
+	if err := iter.Close(); err != nil {
+		return "", fmt.Errorf("gagal membaca data: %v", err)
+	}
+	if !found {
+		return "", fmt.Errorf("pemesanan layanan %s tidak ditemukan", idPesanan)
+	}
+	return status, nil
+}
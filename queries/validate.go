@@ -0,0 +1,137 @@
+package queries
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"src/indonesia"
+)
+
+// ===============================================
+//   VALIDASI RECORD INSERT
+// ===============================================
+
+var (
+	teleponPattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+	idRSPattern    = regexp.MustCompile(`^RS[0-9]+$`)
+)
+
+// ValidEmail memeriksa bahwa s adalah alamat email polos (tanpa nama).
+func ValidEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return fmt.Errorf("email %q tidak valid", s)
+	}
+	return nil
+}
+
+func validTelepon(field, s string) error {
+	if !teleponPattern.MatchString(strings.NewReplacer("-", "", " ", "").Replace(s)) {
+		return fmt.Errorf("%s %q harus 8-15 digit angka (boleh diawali +)", field, s)
+	}
+	return nil
+}
+
+func wajib(field, s string) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("%s wajib diisi", field)
+	}
+	return nil
+}
+
+// Validate memeriksa field wajib dan format Pasien. NIK bersifat opsional,
+// tetapi bila diisi harus konsisten dengan tanggal lahir dan jenis kelamin.
+func (p Pasien) Validate() error {
+	var errs []error
+	add := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := wajib("email", p.Email); err != nil {
+		add(err)
+	} else {
+		add(ValidEmail(p.Email))
+	}
+	add(wajib("kata_sandi", p.KataSandi))
+	add(wajib("nama_lengkap", p.NamaLengkap))
+
+	var lahir time.Time
+	if err := wajib("tanggal_lahir", p.TanggalLahir); err != nil {
+		add(err)
+	} else if t, err := time.Parse("2006-01-02", p.TanggalLahir); err != nil {
+		add(fmt.Errorf("tanggal_lahir %q harus berformat YYYY-MM-DD", p.TanggalLahir))
+	} else if t.After(time.Now()) {
+		add(fmt.Errorf("tanggal_lahir %s ada di masa depan", p.TanggalLahir))
+	} else {
+		lahir = t
+	}
+
+	if p.NomorTelepon != "" {
+		add(validTelepon("nomor_telepon", p.NomorTelepon))
+	}
+	if p.JenisKelamin != "" && p.JenisKelamin != indonesia.JenisKelaminLaki && p.JenisKelamin != indonesia.JenisKelaminPerempuan {
+		add(fmt.Errorf("jenis_kelamin harus %s atau %s, bukan %q",
+			indonesia.JenisKelaminLaki, indonesia.JenisKelaminPerempuan, p.JenisKelamin))
+	}
+	if p.NIK != "" {
+		info, err := indonesia.ParseNIK(p.NIK)
+		switch {
+		case err != nil:
+			add(err)
+		case !lahir.IsZero() && !info.CocokLahir(lahir):
+			add(fmt.Errorf("NIK %s tidak cocok dengan tanggal_lahir %s", p.NIK, p.TanggalLahir))
+		case p.JenisKelamin != "" && info.JenisKelamin != p.JenisKelamin:
+			add(fmt.Errorf("NIK %s tidak cocok dengan jenis_kelamin %s", p.NIK, p.JenisKelamin))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate memeriksa field wajib dan format RumahSakit.
+func (rs RumahSakit) Validate() error {
+	var errs []error
+	if err := wajib("id_rs", rs.IdRS); err != nil {
+		errs = append(errs, err)
+	} else if !idRSPattern.MatchString(rs.IdRS) {
+		errs = append(errs, fmt.Errorf("id_rs %q harus berformat RS diikuti angka (mis. RS001)", rs.IdRS))
+	}
+	if err := wajib("nama_rumah_sakit", rs.NamaRumahSakit); err != nil {
+		errs = append(errs, err)
+	}
+	if err := wajib("kota", rs.Kota); err != nil {
+		errs = append(errs, err)
+	}
+	if rs.Email != "" {
+		if err := ValidEmail(rs.Email); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if rs.NoTelepon != "" {
+		if err := validTelepon("no_telepon", rs.NoTelepon); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate memeriksa DepartemenRS. Rumah sakit tujuan boleh dirujuk lewat
+// id_rs atau nama_rumah_sakit.
+func (d DepartemenRS) Validate() error {
+	var errs []error
+	if err := wajib("nama_departemen", d.NamaDepartemen); err != nil {
+		errs = append(errs, err)
+	}
+	if d.IdRS == "" && strings.TrimSpace(d.RumahSakit) == "" {
+		errs = append(errs, fmt.Errorf("id_rs atau nama_rumah_sakit wajib diisi"))
+	}
+	if d.IdRS != "" && !idRSPattern.MatchString(d.IdRS) {
+		errs = append(errs, fmt.Errorf("id_rs %q harus berformat RS diikuti angka (mis. RS001)", d.IdRS))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,505 @@
+package render
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/format"
+)
+
+// ===============================================
+//   RENDER: tampilkan hasil query dalam berbagai format
+// ===============================================
+//
+// Perintah query mengisi Result dengan nilai mentah (string, int, float64,
+// time.Time, ...). Format teks (table, markdown) memakai Column.Format dan
+// Column.Width; format mesin (json, jsonl, csv) memakai nilai mentah apa
+// adanya sehingga aman di-pipe ke tool lain.
+
+const (
+	Table    = "table"
+	JSON     = "json"
+	JSONL    = "jsonl"
+	CSV      = "csv"
+	Markdown = "markdown"
+)
+
+var Formats = []string{Table, JSON, JSONL, CSV, Markdown}
+
+// Column mendeskripsikan satu kolom hasil.
+type Column struct {
+	Key    string                     // nama kolom untuk json/csv, --columns dan --sort
+	Header string                     // judul kolom di table/markdown (default: Key)
+	Width  int                        // lebar maksimum di table (0 = tanpa batas)
+	Format func(v interface{}) string // tampilan di table/markdown (default: %v)
+}
+
+// Result adalah hasil satu perintah.
+type Result struct {
+	Title    string
+	Columns  []Column
+	Rows     [][]interface{}
+	Numbered bool     // tambahkan kolom "No" di table/markdown
+	Empty    string   // pesan bila tidak ada baris (table/markdown)
+	Notes    []string // catatan setelah tabel (table/markdown)
+	Limit    int      // default jumlah baris yang ditampilkan (0 = semua)
+}
+
+// Add menambah satu baris; jumlah nilai harus sama dengan jumlah kolom.
+func (r *Result) Add(values ...interface{}) {
+	r.Rows = append(r.Rows, values)
+}
+
+// SortKey adalah satu kunci pengurutan dari --sort.
+type SortKey struct {
+	Key  string
+	Desc bool
+}
+
+// Options adalah pilihan tampilan dari flag.
+type Options struct {
+	Format  string
+	Columns []string
+	Sort    []SortKey
+	Limit   int // -1 = pakai Result.Limit
+}
+
+// Bind mendaftarkan --output, --columns, --sort dan --limit.
+func (o *Options) Bind(fs *flag.FlagSet) {
+	o.Format = Table
+	o.Limit = -1
+	fs.StringVar(&o.Format, "output", Table, "format hasil: "+strings.Join(Formats, ", "))
+	fs.Func("columns", "kolom yang ditampilkan, dipisah koma (mis. email,total_biaya)", func(s string) error {
+		o.Columns = splitList(s)
+		return nil
+	})
+	fs.Func("sort", "urutkan berdasarkan kolom, mis. total_biaya:desc,email", func(s string) error {
+		keys, err := ParseSort(s)
+		o.Sort = keys
+		return err
+	})
+	fs.IntVar(&o.Limit, "limit", -1, "jumlah baris maksimum (0 = semua; default tergantung perintah)")
+}
+
+// Validate memeriksa format output.
+func (o Options) Validate() error {
+	for _, f := range Formats {
+		if o.Format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("--output %q tidak dikenal (pilihan: %s)", o.Format, strings.Join(Formats, ", "))
+}
+
+// ParseSort mengurai "kolom[:asc|desc],..." menjadi daftar SortKey.
+func ParseSort(s string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range splitList(s) {
+		key, dir, _ := strings.Cut(part, ":")
+		k := SortKey{Key: key}
+		switch strings.ToLower(dir) {
+		case "", "asc":
+		case "desc":
+			k.Desc = true
+		default:
+			return nil, fmt.Errorf("arah sort %q tidak dikenal untuk kolom %s (asc atau desc)", dir, key)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// Render menulis res ke w sesuai opts.
+func Render(w io.Writer, res Result, opts Options) error {
+	res, err := apply(res, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case "", Table:
+		return writeTable(w, res)
+	case Markdown:
+		return writeMarkdown(w, res)
+	case JSON:
+		return writeJSON(w, res, false)
+	case JSONL:
+		return writeJSON(w, res, true)
+	case CSV:
+		return writeCSV(w, res)
+	}
+	return opts.Validate()
+}
+
+// apply menerapkan pengurutan, limit dan pemilihan kolom.
+func apply(res Result, opts Options) (Result, error) {
+	index := map[string]int{}
+	for i, c := range res.Columns {
+		index[c.Key] = i
+	}
+	keys := func() []string {
+		var ks []string
+		for _, c := range res.Columns {
+			ks = append(ks, c.Key)
+		}
+		return ks
+	}
+
+	rows := append([][]interface{}(nil), res.Rows...)
+	if len(opts.Sort) > 0 {
+		for _, k := range opts.Sort {
+			if _, ok := index[k.Key]; !ok {
+				return res, fmt.Errorf("kolom sort %q tidak ada (pilihan: %s)", k.Key, strings.Join(keys(), ", "))
+			}
+		}
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, k := range opts.Sort {
+				c := compare(rows[i][index[k.Key]], rows[j][index[k.Key]])
+				if c == 0 {
+					continue
+				}
+				if k.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	limit := res.Limit
+	if opts.Limit >= 0 {
+		limit = opts.Limit
+	}
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	if len(opts.Columns) > 0 {
+		var cols []Column
+		var picks []int
+		for _, name := range opts.Columns {
+			i, ok := index[name]
+			if !ok {
+				return res, fmt.Errorf("kolom %q tidak ada (pilihan: %s)", name, strings.Join(keys(), ", "))
+			}
+			cols = append(cols, res.Columns[i])
+			picks = append(picks, i)
+		}
+		picked := make([][]interface{}, len(rows))
+		for r, row := range rows {
+			picked[r] = make([]interface{}, len(picks))
+			for c, i := range picks {
+				picked[r][c] = row[i]
+			}
+		}
+		res.Columns, rows = cols, picked
+	}
+
+	res.Rows = rows
+	return res, nil
+}
+
+// compare membandingkan dua nilai sejenis; angka dibandingkan secara numerik.
+func compare(a, b interface{}) int {
+	if fa, ok := number(a); ok {
+		if fb, ok := number(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case interface{ Float() float64 }: // mis. uang.Rupiah
+		return n.Float(), true
+	}
+	return 0, false
+}
+
+// ===============================================
+//   FORMAT TEKS
+// ===============================================
+
+func (c Column) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+	return c.Key
+}
+
+func (c Column) text(v interface{}) string {
+	if c.Format != nil {
+		return c.Format(v)
+	}
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return t.Format("2006-01-02 15:04:05")
+	case float64:
+		return fmt.Sprintf("%.2f", t)
+	}
+	return fmt.Sprint(v)
+}
+
+// cells mengembalikan header dan isi sel dalam bentuk teks; truncate
+// memotong sel sesuai Column.Width.
+func cells(res Result, truncate bool) ([]string, [][]string, []bool) {
+	var headers []string
+	var numeric []bool
+	if res.Numbered {
+		headers = append(headers, "No")
+		numeric = append(numeric, true)
+	}
+	for i, c := range res.Columns {
+		headers = append(headers, c.header())
+		isNum := len(res.Rows) > 0
+		for _, row := range res.Rows {
+			if _, ok := number(row[i]); !ok {
+				isNum = false
+				break
+			}
+		}
+		numeric = append(numeric, isNum)
+	}
+
+	rows := make([][]string, len(res.Rows))
+	for r, row := range res.Rows {
+		if res.Numbered {
+			rows[r] = append(rows[r], fmt.Sprint(r+1))
+		}
+		for i, c := range res.Columns {
+			s := c.text(row[i])
+			if truncate && c.Width > 0 {
+				s = format.Truncate(s, c.Width)
+			}
+			rows[r] = append(rows[r], s)
+		}
+	}
+	return headers, rows, numeric
+}
+
+func writeTable(w io.Writer, res Result) error {
+	headers, rows, numeric := cells(res, true)
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = runeLen(h)
+	}
+	for _, row := range rows {
+		for i, s := range row {
+			widths[i] = max(widths[i], runeLen(s))
+		}
+	}
+	total := len(widths) - 1
+	for _, wd := range widths {
+		total += wd
+	}
+	total = max(total, runeLen(res.Title)+5, 40)
+
+	line := func(cols []string) {
+		parts := make([]string, len(cols))
+		for i, s := range cols {
+			pad := strings.Repeat(" ", widths[i]-runeLen(s))
+			if numeric[i] {
+				parts[i] = pad + s
+			} else {
+				parts[i] = s + pad
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, " "), " "))
+	}
+
+	if res.Title != "" {
+		fmt.Fprintln(w, "\n"+strings.Repeat("=", total))
+		fmt.Fprintln(w, "     "+res.Title)
+		fmt.Fprintln(w, strings.Repeat("=", total))
+	}
+	line(headers)
+	fmt.Fprintln(w, strings.Repeat("-", total))
+	if len(rows) == 0 {
+		fmt.Fprintln(w, emptyMessage(res))
+	}
+	for _, row := range rows {
+		line(row)
+	}
+	fmt.Fprintln(w, strings.Repeat("=", total))
+	for _, n := range res.Notes {
+		fmt.Fprintln(w, n)
+	}
+	return nil
+}
+
+func writeMarkdown(w io.Writer, res Result) error {
+	headers, rows, numeric := cells(res, false)
+	escape := func(s string) string { return strings.ReplaceAll(s, "|", `\|`) }
+
+	if res.Title != "" {
+		fmt.Fprintf(w, "### %s\n\n", res.Title)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(w, emptyMessage(res))
+	} else {
+		sep := make([]string, len(headers))
+		for i := range headers {
+			headers[i] = escape(headers[i])
+			sep[i] = "---"
+			if numeric[i] {
+				sep[i] = "---:"
+			}
+		}
+		fmt.Fprintf(w, "| %s |\n|%s|\n", strings.Join(headers, " | "), strings.Join(sep, "|"))
+		for _, row := range rows {
+			for i := range row {
+				row[i] = escape(row[i])
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+		}
+	}
+	if len(res.Notes) > 0 {
+		fmt.Fprintln(w)
+		for _, n := range res.Notes {
+			fmt.Fprintln(w, strings.TrimSpace(n)+"  ")
+		}
+	}
+	return nil
+}
+
+func emptyMessage(res Result) string {
+	if res.Empty != "" {
+		return res.Empty
+	}
+	return "Tidak ada data."
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+// ===============================================
+//   FORMAT MESIN
+// ===============================================
+
+func writeJSON(w io.Writer, res Result, lines bool) error {
+	objects := make([][]byte, len(res.Rows))
+	for r, row := range res.Rows {
+		var b bytes.Buffer
+		b.WriteByte('{')
+		for i, c := range res.Columns {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(c.Key)
+			val, err := json.Marshal(row[i])
+			if err != nil {
+				return fmt.Errorf("kolom %s: %v", c.Key, err)
+			}
+			b.Write(key)
+			b.WriteByte(':')
+			b.Write(val)
+		}
+		b.WriteByte('}')
+		objects[r] = b.Bytes()
+	}
+
+	if lines {
+		for _, o := range objects {
+			if _, err := fmt.Fprintf(w, "%s\n", o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(objects) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+	fmt.Fprintln(w, "[")
+	for i, o := range objects {
+		sep := ","
+		if i == len(objects)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(w, "  %s%s\n", o, sep)
+	}
+	_, err := fmt.Fprintln(w, "]")
+	return err
+}
+
+func writeCSV(w io.Writer, res Result) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(res.Columns))
+	for i, c := range res.Columns {
+		header[i] = c.Key
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range res.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = raw(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// raw memformat nilai mentah untuk CSV (waktu dalam RFC3339, float tanpa
+// notasi eksponen).
+func raw(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return t.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case encoding.TextMarshaler: // mis. uang.Rupiah sebagai angka desimal
+		b, _ := t.MarshalText()
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"src/uang"
+)
+
+var update = flag.Bool("update", false, "tulis ulang file golden di testdata")
+
+func contoh() Result {
+	res := Result{
+		Title: "Pasien dengan Biaya Terbesar",
+		Columns: []Column{
+			{Key: "email", Header: "Email"},
+			{Key: "nama", Header: "Nama", Width: 12},
+			{Key: "total_biaya", Header: "Total Biaya", Format: func(v interface{}) string { return v.(uang.Rupiah).String() }},
+			{Key: "kunjungan", Header: "Kunjungan"},
+			{Key: "terakhir", Header: "Terakhir"},
+		},
+		Numbered: true,
+		Notes:    []string{"Biaya dihitung dari pemesanan layanan yang tidak dibatalkan."},
+	}
+	res.Add("pasien1@mail.com", "Siti Nurhaliza Rahmawati", uang.DariFloat(1250000.5), 3, time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC))
+	res.Add("pasien2@mail.com", "Budi, \"Bud\"", uang.DariFloat(87500), 12, nil)
+	res.Add("pasien3@mail.com", "Ayu | Ratna", uang.DariFloat(0), 1, time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC))
+	return res
+}
+
+func TestRenderGolden(t *testing.T) {
+	kosong := contoh()
+	kosong.Rows = nil
+	kosong.Empty = "Belum ada pemesanan."
+
+	tests := []struct {
+		name string
+		res  Result
+		opts Options
+	}{
+		{"table", contoh(), Options{Format: Table, Limit: -1}},
+		{"table_kosong", kosong, Options{Format: Table, Limit: -1}},
+		{"table_sort_limit", contoh(), Options{Format: Table, Sort: []SortKey{{Key: "kunjungan", Desc: true}}, Limit: 2}},
+		{"markdown", contoh(), Options{Format: Markdown, Limit: -1}},
+		{"csv", contoh(), Options{Format: CSV, Limit: -1}},
+		{"csv_kolom", contoh(), Options{Format: CSV, Columns: []string{"total_biaya", "email"}, Limit: -1}},
+		{"json", contoh(), Options{Format: JSON, Limit: -1}},
+		{"json_kosong", kosong, Options{Format: JSON, Limit: -1}},
+		{"jsonl", contoh(), Options{Format: JSONL, Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.res, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (jalankan go test ./render -update)", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("output %s tidak sama dengan %s\n--- dapat ---\n%s\n--- ingin ---\n%s", tt.name, golden, got, want)
+			}
+		})
+	}
+}
+
+func TestRenderKolomTidakAda(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"columns", Options{Format: Table, Columns: []string{"umur"}, Limit: -1}},
+		{"sort", Options{Format: CSV, Sort: []SortKey{{Key: "umur"}}, Limit: -1}},
+		{"format", Options{Format: "xml", Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Render(&bytes.Buffer{}, contoh(), tt.opts); err == nil {
+				t.Fatalf("Render dengan %+v = nil error, ingin error", tt.opts)
+			}
+		})
+	}
+}
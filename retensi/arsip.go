@@ -0,0 +1,37 @@
+package retensi
+
+import (
+	"time"
+
+	"src/arsip"
+)
+
+// ===============================================
+//   ARSIP SEBELUM HAPUS
+// ===============================================
+//
+// Aksi archive menyimpan record ke Pengarsip lebih dulu; record baru
+// dihapus bila penyimpanan berhasil.
+
+// Pengarsip menyimpan salinan record sebelum dihapus.
+type Pengarsip interface {
+	Arsipkan(e *Entitas, aturan string, recs []Record, now time.Time) error
+}
+
+// ArsipBerkas menyimpan salinan lengkap record lewat paket arsip ke
+// direktori Dir, sehingga bisa dipulihkan dengan rs archive restore.
+type ArsipBerkas struct {
+	Dir string
+}
+
+func (a ArsipBerkas) Arsipkan(e *Entitas, aturan string, recs []Record, now time.Time) error {
+	rows := make([]map[string]interface{}, len(recs))
+	for i, r := range recs {
+		rows[i] = r
+	}
+	entri, err := arsip.Salin(e.Nama, "retention "+aturan, rows, now)
+	if err != nil {
+		return err
+	}
+	return arsip.Berkas{Dir: a.Dir}.Simpan(entri)
+}
@@ -0,0 +1,266 @@
+package retensi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"src/booking"
+	"src/layanan"
+	"src/queries"
+	"src/uang"
+)
+
+// ===============================================
+//   ENTITAS YANG BISA DIATUR RETENSINYA
+// ===============================================
+//
+// Setiap entitas tahu cara membaca seluruh datanya per halaman, kolom mana
+// yang boleh dipakai kondisi dan umur, serta cara menghapus dan
+// menganonimkan satu batch record. Aturan di berkas kebijakan hanya boleh
+// menyebut entitas dan kolom di sini.
+
+// TeksAnonim menggantikan teks bebas yang dianonimkan.
+const TeksAnonim = "[dianonimkan]"
+
+// Record adalah satu baris/node apa adanya (nama kolom -> nilai).
+type Record map[string]interface{}
+
+// Entitas adalah satu jenis data yang bisa diatur retensinya.
+type Entitas struct {
+	Nama      string
+	Store     string // queries.StoreCassandra atau queries.StoreNeo4j
+	Ringkasan string
+	Kunci     []string // kolom identitas record
+	Waktu     []string // kolom waktu untuk umur; yang pertama bawaan
+	Kolom     []string // kolom yang boleh dipakai kondisi
+	Anonim    string   // yang dilakukan aksi anonymize
+
+	// pindai membaca satu halaman mulai cursor (nil = awal); cursor
+	// berikutnya nil bila data sudah habis.
+	pindai func(cursor []byte, n int) ([]Record, []byte, error)
+	// hapus dan anonimkan mengembalikan jumlah record yang benar-benar
+	// berubah; sisanya dilewati (mis. janji temu yang punya resep).
+	hapus     func(recs []Record, now time.Time) (int, error)
+	anonimkan func(recs []Record) (int, error)
+	// dianonimkan melaporkan record yang sudah dianonimkan run sebelumnya.
+	dianonimkan func(Record) bool
+}
+
+// Daftar adalah semua entitas, urut nama.
+var Daftar = []*Entitas{
+	{
+		Nama:      "janji_temu",
+		Store:     queries.StoreNeo4j,
+		Ringkasan: "node JanjiTemu (DETACH DELETE; janji temu yang punya resep selalu dilewati)",
+		Kunci:     []string{"id_janji_temu"},
+		Waktu:     []string{"waktu_pelaksanaan"},
+		Kolom:     []string{"status", "punya_resep"},
+		Anonim:    "alasan dikosongkan, node ditandai dianonimkan",
+		pindai: func(cursor []byte, n int) ([]Record, []byte, error) {
+			rows, err := queries.HalamanJanjiTemu(string(cursor), n)
+			if err != nil || len(rows) < n {
+				return records(rows), nil, err
+			}
+			last, _ := rows[len(rows)-1]["id_janji_temu"].(string)
+			return records(rows), []byte(last), nil
+		},
+		hapus: func(recs []Record, _ time.Time) (int, error) {
+			return queries.HapusJanjiTemu(teks(recs, "id_janji_temu"))
+		},
+		anonimkan: func(recs []Record) (int, error) {
+			return queries.AnonimkanJanjiTemu(teks(recs, "id_janji_temu"))
+		},
+		dianonimkan: func(r Record) bool { return r["dianonimkan"] == true },
+	},
+	{
+		Nama:      "log_aktivitas",
+		Store:     queries.StoreCassandra,
+		Ringkasan: "log aktivitas perangkat Baymin",
+		Kunci:     []string{"id_perangkat", "waktu_aktivitas"},
+		Waktu:     []string{"waktu_aktivitas"},
+		Kolom:     []string{"id_perangkat"},
+		Anonim:    "detail_aktivitas diganti " + TeksAnonim,
+		pindai:    cassandraPage(queries.HalamanLogAktivitas),
+		hapus: func(recs []Record, _ time.Time) (int, error) {
+			logs := make([]queries.LogAktivitas, len(recs))
+			for i, r := range recs {
+				logs[i] = queries.LogAktivitas{IDPerangkat: str(r["id_perangkat"]), WaktuAktivitas: r["waktu_aktivitas"].(time.Time)}
+			}
+			n := queries.HapusLogAktivitas(logs)
+			if n < len(logs) {
+				return n, fmt.Errorf("%d log gagal dihapus", len(logs)-n)
+			}
+			return n, nil
+		},
+		anonimkan: perRecord(func(r Record) error {
+			return queries.AnonimkanLogAktivitas(str(r["id_perangkat"]), r["waktu_aktivitas"].(time.Time), TeksAnonim)
+		}),
+		dianonimkan: func(r Record) bool { return r["detail_aktivitas"] == TeksAnonim },
+	},
+	{
+		Nama:      "pemesanan_layanan",
+		Store:     queries.StoreCassandra,
+		Ringkasan: "pemesanan layanan beserta tabel query per pasien dan per RS; kuota pesanan aktif dikembalikan",
+		Kunci:     []string{"id_pesanan"},
+		Waktu:     []string{"jadwal_pelaksanaan", "waktu_pemesanan"},
+		Kolom:     []string{"status_pemesanan", "id_rs", "id_layanan", "email_pemesan"},
+		Anonim:    "email_pemesan diganti pseudonim anonim-<hash>",
+		pindai:    cassandraPage(queries.HalamanPemesananLayanan),
+		hapus: func(recs []Record, now time.Time) (int, error) {
+			return perRecord(func(r Record) error {
+				p := pemesananLayanan(r)
+				if aktif(p.StatusPemesanan) && p.JadwalPelaksanaan.After(now) {
+					if err := layanan.LepasKuota(p.IdPesanan); err != nil {
+						return err
+					}
+				}
+				return queries.HapusPemesananLayanan(p)
+			})(recs)
+		},
+		anonimkan: perRecord(func(r Record) error {
+			p := pemesananLayanan(r)
+			return queries.AnonimkanPemesananLayanan(p, Pseudonim(p.EmailPemesan))
+		}),
+		dianonimkan: func(r Record) bool { return strings.HasPrefix(str(r["email_pemesan"]), prefixPseudonim) },
+	},
+	{
+		Nama:      "pemesanan_obat",
+		Store:     queries.StoreCassandra,
+		Ringkasan: "pesanan obat beserta detail_pesanan_obat",
+		Kunci:     []string{"id_pesanan"},
+		Waktu:     []string{"waktu_pemesanan"},
+		Kolom:     []string{"status_pemesanan", "email_pemesan"},
+		Anonim:    "email_pemesan diganti pseudonim anonim-<hash>",
+		pindai:    cassandraPage(queries.HalamanPemesananObat),
+		hapus: func(recs []Record, _ time.Time) (int, error) {
+			return perRecord(func(r Record) error { return queries.HapusPesananObatLengkap(str(r["id_pesanan"])) })(recs)
+		},
+		anonimkan: perRecord(func(r Record) error {
+			return queries.AnonimkanPemesananObat(str(r["id_pesanan"]), Pseudonim(str(r["email_pemesan"])))
+		}),
+		dianonimkan: func(r Record) bool { return strings.HasPrefix(str(r["email_pemesan"]), prefixPseudonim) },
+	},
+}
+
+// Cari mengembalikan entitas bernama nama.
+func Cari(nama string) (*Entitas, error) {
+	for _, e := range Daftar {
+		if e.Nama == nama {
+			return e, nil
+		}
+	}
+	names := make([]string, len(Daftar))
+	for i, e := range Daftar {
+		names[i] = e.Nama
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("entitas %q tidak dikenal (pilihan: %v)", nama, names)
+}
+
+// KunciRecord memformat kunci record untuk laporan, mis. "BAYMIN-0001/2025-01-01 09:00:00".
+func (e *Entitas) KunciRecord(r Record) string {
+	parts := make([]string, len(e.Kunci))
+	for i, k := range e.Kunci {
+		if t, ok := r[k].(time.Time); ok {
+			parts[i] = t.Local().Format("2006-01-02 15:04:05")
+		} else {
+			parts[i] = fmt.Sprint(r[k])
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+const prefixPseudonim = "anonim-"
+
+// Pseudonim mengganti email dengan "anonim-" dan 10 digit hex SHA-256
+// email tersebut: pesanan pasien yang sama tetap bisa dikelompokkan, tetapi
+// email aslinya tidak disimpan lagi.
+func Pseudonim(email string) string {
+	if email == "" || strings.HasPrefix(email, prefixPseudonim) {
+		return email
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return prefixPseudonim + hex.EncodeToString(sum[:])[:10]
+}
+
+func cassandraPage(page func(state []byte, n int) ([]map[string]interface{}, []byte, error)) func([]byte, int) ([]Record, []byte, error) {
+	return func(cursor []byte, n int) ([]Record, []byte, error) {
+		rows, next, err := page(cursor, n)
+		return records(rows), next, err
+	}
+}
+
+// perRecord menjalankan fn untuk tiap record dan berhenti pada kegagalan
+// pertama, seperti queries.HapusPemesananObat.
+func perRecord(fn func(Record) error) func([]Record) (int, error) {
+	return func(recs []Record) (int, error) {
+		for i, r := range recs {
+			if err := fn(r); err != nil {
+				return i, err
+			}
+		}
+		return len(recs), nil
+	}
+}
+
+func records(rows []map[string]interface{}) []Record {
+	out := make([]Record, len(rows))
+	for i, r := range rows {
+		out[i] = Record(r)
+	}
+	return out
+}
+
+func teks(recs []Record, kolom string) []string {
+	out := make([]string, len(recs))
+	for i, r := range recs {
+		out[i] = str(r[kolom])
+	}
+	return out
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// waktu membaca kolom waktu: timestamp Cassandra atau teks waktu lokal
+// Neo4j seperti "2025-01-06 09:00:00".
+func waktu(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, !t.IsZero()
+	case string:
+		parsed, err := booking.ParseWaktu(t)
+		return parsed, err == nil
+	}
+	return time.Time{}, false
+}
+
+func aktif(status string) bool {
+	switch status {
+	case "dibatalkan", "selesai":
+		return false
+	}
+	return true
+}
+
+func pemesananLayanan(r Record) queries.PemesananLayanan {
+	p := queries.PemesananLayanan{
+		IdPesanan:       str(r["id_pesanan"]),
+		EmailPemesan:    str(r["email_pemesan"]),
+		IdRS:            str(r["id_rs"]),
+		IdLayanan:       str(r["id_layanan"]),
+		NamaLayanan:     str(r["nama_layanan"]),
+		StatusPemesanan: str(r["status_pemesanan"]),
+	}
+	biaya, _ := r["biaya_layanan"].(float64)
+	p.BiayaLayanan = uang.DariFloat(biaya)
+	p.WaktuPemesanan, _ = r["waktu_pemesanan"].(time.Time)
+	p.JadwalPelaksanaan, _ = r["jadwal_pelaksanaan"].(time.Time)
+	return p
+}
@@ -0,0 +1,244 @@
+package retensi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"src/arsip"
+)
+
+// ===============================================
+//   BERKAS KEBIJAKAN
+// ===============================================
+//
+// Kebijakan retensi ditulis sebagai JSON, mis. retensi.json:
+//
+//	{
+//	  "batch": 100, "per_detik": 500, "arsip": "data/arsip",
+//	  "aturan": [
+//	    {"nama": "log-aktivitas-lama", "entitas": "log_aktivitas",
+//	     "umur": "6mo", "aksi": "delete"},
+//	    {"nama": "janji-temu-tanpa-resep", "entitas": "janji_temu",
+//	     "kondisi": {"punya_resep": false}, "umur": "30d", "aksi": "archive"}
+//	  ]
+//	}
+//
+// Kondisi adalah kesamaan kolom (semua harus cocok); nilai berupa array
+// berarti salah satu nilainya. Umur dihitung dari kolom waktu entitas
+// ("waktu" memilih kolom lain bila entitas punya beberapa). Batch,
+// per_detik dan maks per aturan menimpa nilai di tingkat berkas.
+
+// Aksi aturan.
+const (
+	AksiHapus     = "delete"
+	AksiArsip     = "archive"
+	AksiAnonimkan = "anonymize"
+)
+
+// BerkasBawaan adalah berkas kebijakan bila RS_RETENSI tidak diisi.
+const BerkasBawaan = "retensi.json"
+
+// Batas nilai batch.
+const (
+	BatchBawaan = 100
+	MaksBatch   = 1000
+)
+
+// Kebijakan adalah isi berkas kebijakan retensi.
+type Kebijakan struct {
+	Batch    int      `json:"batch,omitempty"`
+	PerDetik int      `json:"per_detik,omitempty"` // record per detik; 0 = tanpa batas
+	Arsip    string   `json:"arsip,omitempty"`     // direktori arsip aksi archive; kosong = arsip.Dir()
+	Aturan   []Aturan `json:"aturan"`
+}
+
+// Aturan adalah satu baris kebijakan.
+type Aturan struct {
+	Nama       string                 `json:"nama"`
+	Keterangan string                 `json:"keterangan,omitempty"`
+	Entitas    string                 `json:"entitas"`
+	Kondisi    map[string]interface{} `json:"kondisi,omitempty"`
+	Umur       Umur                   `json:"umur"`
+	Waktu      string                 `json:"waktu,omitempty"`
+	Aksi       string                 `json:"aksi"`
+	Batch      int                    `json:"batch,omitempty"`
+	PerDetik   int                    `json:"per_detik,omitempty"`
+	Maks       int                    `json:"maks,omitempty"` // record per run; 0 = tanpa batas
+	Nonaktif   bool                   `json:"nonaktif,omitempty"`
+}
+
+// BerkasKebijakan mengembalikan path berkas kebijakan: env RS_RETENSI
+// atau BerkasBawaan.
+func BerkasKebijakan() string {
+	if v := os.Getenv("RS_RETENSI"); v != "" {
+		return v
+	}
+	return BerkasBawaan
+}
+
+// Muat membaca dan memvalidasi berkas kebijakan.
+func Muat(path string) (*Kebijakan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca kebijakan retensi: %v", err)
+	}
+	k, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("kebijakan retensi %s: %v", path, err)
+	}
+	return k, nil
+}
+
+// Parse membaca kebijakan dari JSON. Field yang tidak dikenal ditolak agar
+// salah ketik (mis. "kondis") tidak diam-diam melebarkan aturan.
+func Parse(data []byte) (*Kebijakan, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var k Kebijakan
+	if err := dec.Decode(&k); err != nil {
+		return nil, fmt.Errorf("JSON tidak valid: %v", err)
+	}
+	if k.Batch == 0 {
+		k.Batch = BatchBawaan
+	}
+	if k.Arsip == "" {
+		k.Arsip = arsip.Dir()
+	}
+	if errs := k.validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return &k, nil
+}
+
+func (k *Kebijakan) validate() []string {
+	var errs []string
+	if k.Batch < 1 || k.Batch > MaksBatch {
+		errs = append(errs, fmt.Sprintf("batch harus 1-%d", MaksBatch))
+	}
+	if k.PerDetik < 0 {
+		errs = append(errs, "per_detik tidak boleh negatif")
+	}
+	if len(k.Aturan) == 0 {
+		errs = append(errs, "tidak ada aturan")
+	}
+	seen := map[string]bool{}
+	for i, a := range k.Aturan {
+		label := fmt.Sprintf("aturan #%d", i+1)
+		if a.Nama == "" {
+			errs = append(errs, label+": nama wajib diisi")
+		} else {
+			label = "aturan " + a.Nama
+			if seen[a.Nama] {
+				errs = append(errs, label+": nama ganda")
+			}
+			seen[a.Nama] = true
+		}
+		for _, msg := range a.validate() {
+			errs = append(errs, label+": "+msg)
+		}
+	}
+	return errs
+}
+
+func (a *Aturan) validate() []string {
+	var errs []string
+	switch a.Aksi {
+	case AksiHapus, AksiArsip, AksiAnonimkan:
+	default:
+		errs = append(errs, fmt.Sprintf("aksi %q harus %s, %s atau %s", a.Aksi, AksiHapus, AksiArsip, AksiAnonimkan))
+	}
+	if a.Umur.String() == "" {
+		errs = append(errs, `umur wajib diisi (pakai "0d" untuk semua umur)`)
+	}
+	if a.Batch < 0 || a.Batch > MaksBatch {
+		errs = append(errs, fmt.Sprintf("batch harus 1-%d", MaksBatch))
+	}
+	if a.PerDetik < 0 || a.Maks < 0 {
+		errs = append(errs, "per_detik dan maks tidak boleh negatif")
+	}
+	e, err := Cari(a.Entitas)
+	if err != nil {
+		return append(errs, err.Error())
+	}
+	if a.Waktu != "" && !contains(e.Waktu, a.Waktu) {
+		errs = append(errs, fmt.Sprintf("waktu %q bukan kolom waktu %s (pilihan: %v)", a.Waktu, e.Nama, e.Waktu))
+	}
+	for kolom, v := range a.Kondisi {
+		if !contains(e.Kolom, kolom) {
+			errs = append(errs, fmt.Sprintf("kondisi %q bukan kolom %s yang bisa difilter (pilihan: %v)", kolom, e.Nama, e.Kolom))
+		}
+		if list, ok := v.([]interface{}); ok {
+			if len(list) == 0 {
+				errs = append(errs, fmt.Sprintf("kondisi %q: daftar nilai kosong", kolom))
+			}
+			for _, item := range list {
+				if !scalar(item) {
+					errs = append(errs, fmt.Sprintf("kondisi %q: daftar hanya boleh berisi teks, angka atau boolean", kolom))
+					break
+				}
+			}
+		} else if !scalar(v) {
+			errs = append(errs, fmt.Sprintf("kondisi %q harus teks, angka, boolean atau daftar", kolom))
+		}
+	}
+	return errs
+}
+
+// entitas mengembalikan entitas aturan (sudah divalidasi saat Parse).
+func (a *Aturan) entitas() *Entitas {
+	e, _ := Cari(a.Entitas)
+	return e
+}
+
+// KolomWaktu mengembalikan kolom waktu yang dipakai umur.
+func (a *Aturan) KolomWaktu() string {
+	if a.Waktu != "" {
+		return a.Waktu
+	}
+	return a.entitas().Waktu[0]
+}
+
+// cocok melaporkan apakah record memenuhi semua kondisi aturan.
+func (a *Aturan) cocok(r Record) bool {
+	for kolom, want := range a.Kondisi {
+		got := fmt.Sprint(r[kolom])
+		if r[kolom] == nil {
+			got = ""
+		}
+		list, ok := want.([]interface{})
+		if !ok {
+			list = []interface{}{want}
+		}
+		match := false
+		for _, w := range list {
+			if w == nil && got == "" || w != nil && fmt.Sprint(w) == got {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+func scalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, string, float64, bool:
+		return true
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
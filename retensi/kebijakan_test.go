@@ -0,0 +1,71 @@
+package retensi
+
+import (
+	"strings"
+	"testing"
+
+	"src/arsip"
+)
+
+// TestBerkasBawaan memastikan retensi.json valid dan aturan pengganti
+// delete1/delete3 mengarsipkan data sebelum dihapus, ke entitas yang bisa
+// dikembalikan dengan rs archive restore.
+func TestBerkasBawaan(t *testing.T) {
+	k, err := Muat("../retensi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aksi := map[string]string{}
+	for _, a := range k.Aturan {
+		aksi[a.Nama] = a.Aksi
+		if a.Aksi == AksiArsip {
+			if _, err := arsip.Cari(a.Entitas); err != nil {
+				t.Errorf("aturan %s: entitas tidak bisa dipulihkan: %v", a.Nama, err)
+			}
+		}
+	}
+	for _, nama := range []string{"pesanan-obat-dibatalkan", "janji-temu-tanpa-resep"} {
+		if aksi[nama] != AksiArsip {
+			t.Errorf("aturan %s: aksi = %q, ingin %q", nama, aksi[nama], AksiArsip)
+		}
+	}
+}
+
+// TestEntitasBisaDiarsipkan memastikan setiap entitas retensi punya jenis
+// arsip, sehingga aksi archive tidak gagal saat dijalankan.
+func TestEntitasBisaDiarsipkan(t *testing.T) {
+	for _, e := range Daftar {
+		if _, err := arsip.Cari(e.Nama); err != nil {
+			t.Errorf("entitas %s: %v", e.Nama, err)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"tanpa aturan", `{"aturan": []}`, "tidak ada aturan"},
+		{"field tidak dikenal", `{"aturan": [{"nama": "a", "entitas": "log_aktivitas", "umur": "1d", "aksi": "delete", "kondis": {}}]}`, "kondis"},
+		{"aksi tidak dikenal", `{"aturan": [{"nama": "a", "entitas": "log_aktivitas", "umur": "1d", "aksi": "purge"}]}`, `aksi "purge"`},
+		{"tanpa umur", `{"aturan": [{"nama": "a", "entitas": "log_aktivitas", "aksi": "delete"}]}`, "umur wajib"},
+		{"entitas tidak dikenal", `{"aturan": [{"nama": "a", "entitas": "pasien", "umur": "1d", "aksi": "delete"}]}`, "pasien"},
+		{"nama ganda", `{"aturan": [{"nama": "a", "entitas": "log_aktivitas", "umur": "1d", "aksi": "delete"}, {"nama": "a", "entitas": "log_aktivitas", "umur": "2d", "aksi": "archive"}]}`, "nama ganda"},
+		{"batch terlalu besar", `{"batch": 5000, "aturan": [{"nama": "a", "entitas": "log_aktivitas", "umur": "1d", "aksi": "delete"}]}`, "batch"},
+		{"kondisi bukan kolom", `{"aturan": [{"nama": "a", "entitas": "pemesanan_obat", "umur": "1d", "aksi": "archive", "kondisi": {"warna": "merah"}}]}`, `kondisi "warna"`},
+		{"kondisi daftar kosong", `{"aturan": [{"nama": "a", "entitas": "pemesanan_obat", "umur": "1d", "aksi": "archive", "kondisi": {"status_pemesanan": []}}]}`, "daftar nilai kosong"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.json))
+			if err == nil {
+				t.Fatal("Parse = nil error, ingin error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q tidak memuat %q", err, tt.want)
+			}
+		})
+	}
+}
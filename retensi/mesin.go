@@ -0,0 +1,263 @@
+package retensi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"src/queries"
+)
+
+// ===============================================
+//   MESIN RETENSI
+// ===============================================
+//
+// Setiap aturan membaca entitasnya halaman demi halaman (ukuran batch),
+// memilih record yang cocok dengan kondisi dan lebih tua dari umur, lalu
+// menjalankan aksinya per batch. Laju aksi dibatasi per_detik record dan
+// jumlahnya per run dibatasi maks. Pada DryRun tidak ada yang diubah.
+
+// Opsi mengatur satu run kebijakan.
+type Opsi struct {
+	DryRun bool
+	// Aturan membatasi run ke aturan bernama ini (termasuk yang nonaktif);
+	// kosong berarti semua aturan aktif.
+	Aturan []string
+	// Arsip menggantikan ArsipBerkas{Dir: Kebijakan.Arsip} untuk aksi archive.
+	Arsip Pengarsip
+	Now   time.Time
+	Logf  func(format string, args ...interface{})
+	// Contoh adalah jumlah kunci record cocok yang disimpan di laporan.
+	Contoh int
+}
+
+// Laporan adalah hasil satu aturan.
+type Laporan struct {
+	Aturan   string        `json:"aturan"`
+	Entitas  string        `json:"entitas"`
+	Aksi     string        `json:"aksi"`
+	Batas    time.Time     `json:"batas"` // nol bila umur 0
+	Dipindai int           `json:"dipindai"`
+	Cocok    int           `json:"cocok"`
+	Diproses int           `json:"diproses"`
+	Dilewati int           `json:"dilewati"` // cocok tetapi tidak diubah, mis. janji temu dengan resep
+	Batch    int           `json:"batch"`
+	Durasi   time.Duration `json:"durasi"`
+	Contoh   []string      `json:"contoh,omitempty"`
+	Err      error         `json:"-"`
+}
+
+// Status meringkas laporan untuk tabel dan riwayat job.
+func (l Laporan) Status() string {
+	if l.Err != nil {
+		return "gagal: " + l.Err.Error()
+	}
+	return "ok"
+}
+
+// Pilih mengembalikan aturan yang dijalankan sesuai opsi.
+func (k *Kebijakan) Pilih(nama []string) ([]*Aturan, error) {
+	var out []*Aturan
+	if len(nama) == 0 {
+		for i := range k.Aturan {
+			if !k.Aturan[i].Nonaktif {
+				out = append(out, &k.Aturan[i])
+			}
+		}
+		return out, nil
+	}
+	for _, n := range nama {
+		found := false
+		for i := range k.Aturan {
+			if k.Aturan[i].Nama == n {
+				out = append(out, &k.Aturan[i])
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("aturan %q tidak ada di kebijakan", n)
+		}
+	}
+	return out, nil
+}
+
+// Butuh melaporkan store yang dipakai aturan terpilih.
+func (k *Kebijakan) Butuh(nama []string) (cassandra, neo4j bool) {
+	aturan, _ := k.Pilih(nama)
+	for _, a := range aturan {
+		switch a.entitas().Store {
+		case queries.StoreCassandra:
+			cassandra = true
+		case queries.StoreNeo4j:
+			neo4j = true
+		}
+	}
+	return cassandra, neo4j
+}
+
+// Jalankan menjalankan aturan terpilih berurutan. Kegagalan satu aturan
+// dicatat di laporannya dan tidak menghentikan aturan berikutnya. Bila ctx
+// dibatalkan, aturan yang sedang berjalan berhenti di antara batch dan
+// Jalankan mengembalikan laporan sejauh itu bersama ctx.Err().
+func (k *Kebijakan) Jalankan(ctx context.Context, o Opsi) ([]Laporan, error) {
+	aturan, err := k.Pilih(o.Aturan)
+	if err != nil {
+		return nil, err
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	if o.Logf == nil {
+		o.Logf = func(string, ...interface{}) {}
+	}
+	if o.Arsip == nil {
+		o.Arsip = ArsipBerkas{Dir: k.Arsip}
+	}
+	out := make([]Laporan, 0, len(aturan))
+	for _, a := range aturan {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		l := k.jalankanAturan(ctx, a, o)
+		if l.Err != nil {
+			o.Logf("aturan %s gagal: %v\n", a.Nama, l.Err)
+		}
+		out = append(out, l)
+	}
+	return out, ctx.Err()
+}
+
+func (k *Kebijakan) jalankanAturan(ctx context.Context, a *Aturan, o Opsi) Laporan {
+	e := a.entitas()
+	l := Laporan{Aturan: a.Nama, Entitas: e.Nama, Aksi: a.Aksi}
+	if !a.Umur.IsZero() {
+		l.Batas = a.Umur.Before(o.Now)
+	}
+	batch, perDetik := k.Batch, k.PerDetik
+	if a.Batch > 0 {
+		batch = a.Batch
+	}
+	if a.PerDetik > 0 {
+		perDetik = a.PerDetik
+	}
+	kolomWaktu := a.KolomWaktu()
+
+	start := time.Now()
+	pace := pacer{perDetik: perDetik, start: start}
+	var buf []Record
+	flush := func() error {
+		if len(buf) == 0 {
+			return nil
+		}
+		l.Batch++
+		if o.DryRun {
+			buf = buf[:0]
+			return nil
+		}
+		n, err := k.aksi(a, e, buf, o)
+		l.Diproses += n
+		if err == nil {
+			l.Dilewati += len(buf) - n
+		}
+		o.Logf("aturan %s: batch %d, %d %s\n", a.Nama, l.Batch, n, kataAksi(a.Aksi))
+		pace.wait(l.Diproses + l.Dilewati)
+		buf = buf[:0]
+		return err
+	}
+
+	var cursor []byte
+	for penuh := false; !penuh; {
+		if l.Err = ctx.Err(); l.Err != nil {
+			break
+		}
+		page, next, err := e.pindai(cursor, batch)
+		if err != nil {
+			l.Err = err
+			break
+		}
+		for _, r := range page {
+			l.Dipindai++
+			if !a.cocok(r) || !l.lebihTua(r, kolomWaktu) {
+				continue
+			}
+			if a.Aksi == AksiAnonimkan && e.dianonimkan(r) {
+				continue
+			}
+			l.Cocok++
+			if len(l.Contoh) < o.Contoh {
+				l.Contoh = append(l.Contoh, e.KunciRecord(r))
+			}
+			buf = append(buf, r)
+			if len(buf) >= batch {
+				if l.Err = ctx.Err(); l.Err != nil {
+					break
+				}
+				if l.Err = flush(); l.Err != nil {
+					break
+				}
+			}
+			if a.Maks > 0 && l.Cocok >= a.Maks {
+				penuh = true
+				break
+			}
+		}
+		if l.Err != nil || next == nil {
+			break
+		}
+		cursor = next
+	}
+	if l.Err == nil {
+		l.Err = flush()
+	}
+	l.Durasi = time.Since(start)
+	return l
+}
+
+// lebihTua melaporkan apakah kolom waktu record sebelum batas. Record tanpa
+// waktu yang terbaca tidak pernah cocok kecuali umur 0.
+func (l *Laporan) lebihTua(r Record, kolom string) bool {
+	if l.Batas.IsZero() {
+		return true
+	}
+	t, ok := waktu(r[kolom])
+	return ok && t.Before(l.Batas)
+}
+
+func (k *Kebijakan) aksi(a *Aturan, e *Entitas, recs []Record, o Opsi) (int, error) {
+	switch a.Aksi {
+	case AksiAnonimkan:
+		return e.anonimkan(recs)
+	case AksiArsip:
+		if err := o.Arsip.Arsipkan(e, a.Nama, recs, o.Now); err != nil {
+			return 0, err
+		}
+	}
+	return e.hapus(recs, o.Now)
+}
+
+func kataAksi(aksi string) string {
+	switch aksi {
+	case AksiArsip:
+		return "diarsipkan & dihapus"
+	case AksiAnonimkan:
+		return "dianonimkan"
+	}
+	return "dihapus"
+}
+
+// pacer menahan laju agar rata-rata record yang diproses sejak start tidak
+// melebihi perDetik.
+type pacer struct {
+	perDetik int
+	start    time.Time
+}
+
+func (p pacer) wait(done int) {
+	if p.perDetik <= 0 {
+		return
+	}
+	target := p.start.Add(time.Duration(done) * time.Second / time.Duration(p.perDetik))
+	if d := time.Until(target); d > 0 {
+		time.Sleep(d)
+	}
+}
@@ -0,0 +1,84 @@
+package retensi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ===============================================
+//   UMUR: rentang waktu mundur dari sekarang
+// ===============================================
+
+// Umur adalah rentang waktu seperti "48h", "2d", "2w", "6mo" atau "1y".
+// Satuan kalender (d/w/mo/y) dihitung dengan AddDate sehingga "6mo" sama
+// dengan enam bulan kalender, bukan 180 hari. Umur juga flag.Value dan
+// dibaca dari string JSON.
+type Umur struct {
+	text                string
+	years, months, days int
+	dur                 time.Duration
+}
+
+var umurPattern = regexp.MustCompile(`^([0-9]+)(d|w|mo|y)$`)
+
+// ParseUmur membaca rentang waktu; lihat Umur.
+func ParseUmur(s string) (Umur, error) {
+	u := Umur{text: s}
+	if m := umurPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "d":
+			u.days = n
+		case "w":
+			u.days = 7 * n
+		case "mo":
+			u.months = n
+		case "y":
+			u.years = n
+		}
+		return u, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return Umur{}, fmt.Errorf("rentang waktu %q tidak valid (contoh: 48h, 2d, 2w, 6mo, 1y)", s)
+	}
+	if d < 0 {
+		return Umur{}, fmt.Errorf("rentang waktu %q tidak boleh negatif", s)
+	}
+	u.dur = d
+	return u, nil
+}
+
+func (u *Umur) String() string { return u.text }
+
+func (u *Umur) Set(s string) error {
+	parsed, err := ParseUmur(s)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+func (u *Umur) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("umur harus string seperti \"30d\" atau \"6mo\"")
+	}
+	return u.Set(s)
+}
+
+func (u Umur) MarshalJSON() ([]byte, error) { return json.Marshal(u.text) }
+
+// IsZero melaporkan apakah rentang waktunya nol (semua umur cocok).
+func (u Umur) IsZero() bool {
+	return u.years == 0 && u.months == 0 && u.days == 0 && u.dur == 0
+}
+
+// Before mengembalikan titik waktu sepanjang u sebelum now.
+func (u Umur) Before(now time.Time) time.Time {
+	return now.AddDate(-u.years, -u.months, -u.days).Add(-u.dur)
+}
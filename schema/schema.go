@@ -0,0 +1,424 @@
+package schema
+
+import (
+	"fmt"
+	"log"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/queries"
+)
+
+// ===============================================
+//   SCHEMA CASSANDRA
+// ===============================================
+
+var cassandraTables = []string{
+	`CREATE TABLE IF NOT EXISTS log_aktivitas (
+		id_perangkat TEXT,
+		waktu_aktivitas TIMESTAMP,
+		detail_aktivitas TEXT,
+		PRIMARY KEY ((id_perangkat), waktu_aktivitas)
+	) WITH CLUSTERING ORDER BY (waktu_aktivitas DESC);`,
+
+	`CREATE TABLE IF NOT EXISTS pemesanan_obat (
+		id_pesanan TEXT PRIMARY KEY,
+		email_pemesan TEXT,
+		waktu_pemesanan TIMESTAMP,
+		status_pemesanan TEXT,
+		total_harga DOUBLE,
+		stok_dipesan BOOLEAN
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS detail_pesanan_obat (
+		id_pesanan TEXT PRIMARY KEY,
+		daftar_obat MAP<TEXT, INT>
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS obat (
+		id_obat TEXT PRIMARY KEY,
+		nama TEXT,
+		label TEXT,
+		harga DOUBLE,
+		stok INT
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS pemesanan_layanan (
+		id_pesanan TEXT PRIMARY KEY,
+		email_pemesan TEXT,
+		waktu_pemesanan TIMESTAMP,
+		jadwal_pelaksanaan TIMESTAMP,
+		status_pemesanan TEXT,
+		id_rs TEXT,
+		id_layanan TEXT,
+		nama_layanan TEXT,
+		biaya_layanan DOUBLE
+	);`,
+
+	// Tabel query pemesanan layanan: per pasien dan jadwal per rumah sakit.
+	// Status tetap dibaca dari pemesanan_layanan.
+	`CREATE TABLE IF NOT EXISTS pemesanan_layanan_per_pasien (
+		email_pemesan TEXT,
+		jadwal_pelaksanaan TIMESTAMP,
+		id_pesanan TEXT,
+		id_rs TEXT,
+		id_layanan TEXT,
+		nama_layanan TEXT,
+		biaya_layanan DOUBLE,
+		PRIMARY KEY ((email_pemesan), jadwal_pelaksanaan, id_pesanan)
+	) WITH CLUSTERING ORDER BY (jadwal_pelaksanaan DESC, id_pesanan ASC);`,
+
+	// Jadwal per RS dibagi per tanggal lokal (YYYY-MM-DD) agar partition
+	// RS yang ramai tidak tumbuh tanpa batas; rentang tanggal dibaca per hari.
+	`CREATE TABLE IF NOT EXISTS pemesanan_layanan_per_rs (
+		id_rs TEXT,
+		tanggal TEXT,
+		jadwal_pelaksanaan TIMESTAMP,
+		id_pesanan TEXT,
+		email_pemesan TEXT,
+		id_layanan TEXT,
+		nama_layanan TEXT,
+		PRIMARY KEY ((id_rs, tanggal), jadwal_pelaksanaan, id_pesanan)
+	);`,
+
+	// Jumlah pemesanan aktif per layanan per RS per tanggal (YYYY-MM-DD),
+	// diubah dengan LWT untuk membatasi kapasitas harian.
+	`CREATE TABLE IF NOT EXISTS kuota_layanan (
+		id_rs TEXT,
+		id_layanan TEXT,
+		tanggal TEXT,
+		terpakai INT,
+		PRIMARY KEY ((id_rs, id_layanan), tanggal)
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS lokasi_layanan (
+		id_rs TEXT,
+		id_layanan TEXT,
+		nama_layanan TEXT,
+		biaya_layanan DOUBLE,
+		kapasitas_harian INT,
+		PRIMARY KEY (id_rs, id_layanan)
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS resep_pemesanan_obat (
+		id_resep TEXT PRIMARY KEY,
+		id_pesanan TEXT,
+		email_pasien TEXT,
+		waktu_dibuat TIMESTAMP
+	);`,
+
+	// Tagihan (rs invoice): nominal dalam sen (BIGINT), tarif_ppn dalam
+	// seperseratus persen. tagihan_pesanan memastikan satu pesanan hanya
+	// masuk satu tagihan; nomor_tagihan menyimpan nomor urut per bulan.
+	`CREATE TABLE IF NOT EXISTS tagihan (
+		nomor TEXT PRIMARY KEY,
+		email_pasien TEXT,
+		dibuat TIMESTAMP,
+		jatuh_tempo TIMESTAMP,
+		status TEXT,
+		subtotal BIGINT,
+		diskon BIGINT,
+		keterangan_diskon TEXT,
+		dpp BIGINT,
+		tarif_ppn INT,
+		ppn BIGINT,
+		total BIGINT
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS tagihan_baris (
+		nomor TEXT,
+		urutan INT,
+		jenis TEXT,
+		id_pesanan TEXT,
+		kode TEXT,
+		deskripsi TEXT,
+		jumlah INT,
+		harga BIGINT,
+		subtotal BIGINT,
+		PRIMARY KEY ((nomor), urutan)
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS tagihan_per_pasien (
+		email_pasien TEXT,
+		dibuat TIMESTAMP,
+		nomor TEXT,
+		total BIGINT,
+		PRIMARY KEY ((email_pasien), dibuat, nomor)
+	) WITH CLUSTERING ORDER BY (dibuat DESC, nomor ASC);`,
+
+	`CREATE TABLE IF NOT EXISTS tagihan_pesanan (
+		id_pesanan TEXT PRIMARY KEY,
+		nomor TEXT,
+		jenis TEXT,
+		waktu_dibuat TIMESTAMP
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS nomor_tagihan (
+		periode TEXT PRIMARY KEY,
+		terakhir INT
+	);`,
+
+	// Pembayaran tagihan lewat gateway; jumlah dalam sen. pembayaran_aktif
+	// memastikan satu tagihan hanya punya satu pembayaran yang berjalan,
+	// webhook_pembayaran mencatat event yang sudah diterima agar webhook
+	// yang dikirim ulang tidak diproses dua kali.
+	`CREATE TABLE IF NOT EXISTS pembayaran (
+		id_pembayaran TEXT PRIMARY KEY,
+		nomor_tagihan TEXT,
+		email_pasien TEXT,
+		gateway TEXT,
+		ref_gateway TEXT,
+		url_bayar TEXT,
+		jumlah BIGINT,
+		status TEXT,
+		alasan TEXT,
+		dibuat TIMESTAMP,
+		kedaluwarsa TIMESTAMP,
+		diperbarui TIMESTAMP
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS pembayaran_per_tagihan (
+		nomor_tagihan TEXT,
+		dibuat TIMESTAMP,
+		id_pembayaran TEXT,
+		PRIMARY KEY ((nomor_tagihan), dibuat, id_pembayaran)
+	) WITH CLUSTERING ORDER BY (dibuat DESC, id_pembayaran ASC);`,
+
+	`CREATE TABLE IF NOT EXISTS pembayaran_aktif (
+		nomor_tagihan TEXT PRIMARY KEY,
+		id_pembayaran TEXT
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS webhook_pembayaran (
+		gateway TEXT,
+		id_event TEXT,
+		id_pembayaran TEXT,
+		jenis TEXT,
+		diterima TIMESTAMP,
+		PRIMARY KEY ((gateway, id_event))
+	);`,
+
+	// Job pemeliharaan (rs worker): jadwal & jeda per job, lease agar satu
+	// job hanya dijalankan satu instance (LWT + TTL), dan riwayat run.
+	`CREATE TABLE IF NOT EXISTS job_konfigurasi (
+		nama TEXT PRIMARY KEY,
+		jadwal TEXT,
+		dijeda BOOLEAN,
+		diubah TIMESTAMP
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS job_lease (
+		nama TEXT PRIMARY KEY,
+		pemilik TEXT,
+		sampai TIMESTAMP
+	);`,
+
+	`CREATE TABLE IF NOT EXISTS job_riwayat (
+		nama TEXT,
+		mulai TIMESTAMP,
+		id_run TEXT,
+		selesai TIMESTAMP,
+		durasi_ms INT,
+		status TEXT,
+		jumlah INT,
+		pesan TEXT,
+		pemilik TEXT,
+		pemicu TEXT,
+		PRIMARY KEY ((nama), mulai, id_run)
+	) WITH CLUSTERING ORDER BY (mulai DESC, id_run ASC);`,
+}
+
+// cassandraColumns adalah kolom yang ditambahkan setelah tabelnya pertama
+// kali dibuat. CREATE TABLE IF NOT EXISTS tidak mengubah tabel lama, jadi
+// kolom yang belum ada ditambahkan dengan ALTER TABLE.
+var cassandraColumns = []struct{ Table, Column, Type string }{
+	{"pemesanan_obat", "total_harga", "DOUBLE"},
+	{"pemesanan_obat", "stok_dipesan", "BOOLEAN"},
+	{"pemesanan_layanan", "id_rs", "TEXT"},
+	{"pemesanan_layanan", "id_layanan", "TEXT"},
+	{"pemesanan_layanan", "nama_layanan", "TEXT"},
+	{"pemesanan_layanan", "biaya_layanan", "DOUBLE"},
+	{"lokasi_layanan", "kapasitas_harian", "INT"},
+}
+
+// cassandraRekeyed adalah tabel query yang primary key-nya berubah. Primary
+// key tidak bisa diubah dengan ALTER TABLE, jadi tabel lama (yang belum
+// punya Column) di-drop sebelum CREATE TABLE lalu diisi ulang dari tabel
+// utamanya dengan Refill.
+var cassandraRekeyed = []struct {
+	Table, Column string
+	Refill        func() (int, error)
+}{
+	{"pemesanan_layanan_per_rs", "tanggal", queries.IsiUlangJadwalLayananRS},
+}
+
+// CreateCassandra membuat keyspace dan tabel, lalu membuka Session global
+// cassandra ke keyspace tersebut.
+func CreateCassandra(cfg cassandra.Config) error {
+	fmt.Println("Creating Cassandra keyspace and tables ...")
+
+	// Koneksi sementara tanpa keyspace
+	temp := cfg
+	temp.Keyspace = ""
+	tempSession, err := temp.Cluster().CreateSession()
+	if err != nil {
+		return fmt.Errorf("koneksi awal Cassandra gagal: %v", err)
+	}
+	defer tempSession.Close()
+
+	err = tempSession.Query(fmt.Sprintf(`
+		CREATE KEYSPACE IF NOT EXISTS %s
+		WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1};
+	`, cfg.Keyspace)).Exec()
+	if err != nil {
+		return fmt.Errorf("gagal membuat keyspace %s: %v", cfg.Keyspace, err)
+	}
+	fmt.Printf("Keyspace '%s' ready.\n", cfg.Keyspace)
+
+	// Koneksi ulang ke keyspace aplikasi
+	if cassandra.Session == nil {
+		if err := cassandra.Connect(cfg); err != nil {
+			return fmt.Errorf("koneksi Cassandra ke keyspace gagal: %v", err)
+		}
+	}
+
+	failed := 0
+	var refill []func() (int, error)
+	for _, r := range cassandraRekeyed {
+		dropped, err := dropOldTable(cfg.Keyspace, r.Table, r.Column)
+		if err != nil {
+			log.Println("Error rekeying table:", err)
+			failed++
+		}
+		if dropped {
+			refill = append(refill, r.Refill)
+		}
+	}
+	for _, q := range cassandraTables {
+		if err := cassandra.ExecCassandra(q); err != nil {
+			log.Println("Error executing query:", err)
+			failed++
+		}
+	}
+	for _, c := range cassandraColumns {
+		if err := addColumn(cfg.Keyspace, c.Table, c.Column, c.Type); err != nil {
+			log.Println("Error adding column:", err)
+			failed++
+		}
+	}
+	for _, fn := range refill {
+		n, err := fn()
+		if err != nil {
+			log.Println("Error refilling table:", err)
+			failed++
+			continue
+		}
+		fmt.Printf("%d baris tabel query diisi ulang.\n", n)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d query schema Cassandra gagal", failed)
+	}
+
+	fmt.Println("Cassandra denormalized schema created successfully.")
+	return nil
+}
+
+func columnExists(keyspace, table, column string) (bool, error) {
+	iter, _ := cassandra.SelectCassandra(`SELECT column_name FROM system_schema.columns
+		WHERE keyspace_name = ? AND table_name = ? AND column_name = ?`, keyspace, table, column)
+	exists := iter.NumRows() > 0
+	if err := iter.Close(); err != nil {
+		return false, fmt.Errorf("gagal membaca kolom %s.%s: %v", table, column, err)
+	}
+	return exists, nil
+}
+
+func tableExists(keyspace, table string) (bool, error) {
+	iter, _ := cassandra.SelectCassandra(`SELECT table_name FROM system_schema.tables
+		WHERE keyspace_name = ? AND table_name = ?`, keyspace, table)
+	exists := iter.NumRows() > 0
+	if err := iter.Close(); err != nil {
+		return false, fmt.Errorf("gagal membaca tabel %s: %v", table, err)
+	}
+	return exists, nil
+}
+
+// dropOldTable men-drop table bila sudah ada tetapi belum punya column.
+func dropOldTable(keyspace, table, column string) (bool, error) {
+	exists, err := tableExists(keyspace, table)
+	if err != nil || !exists {
+		return false, err
+	}
+	current, err := columnExists(keyspace, table, column)
+	if err != nil || current {
+		return false, err
+	}
+	if err := cassandra.ExecCassandra("DROP TABLE " + table); err != nil {
+		return false, fmt.Errorf("gagal men-drop tabel lama %s: %v", table, err)
+	}
+	fmt.Printf("Tabel %s dengan primary key lama di-drop.\n", table)
+	return true, nil
+}
+
+func addColumn(keyspace, table, column, typ string) error {
+	exists, err := columnExists(keyspace, table, column)
+	if err != nil || exists {
+		return err
+	}
+	if err := cassandra.ExecCassandra(fmt.Sprintf("ALTER TABLE %s ADD %s %s", table, column, typ)); err != nil {
+		return fmt.Errorf("gagal menambah kolom %s.%s: %v", table, column, err)
+	}
+	fmt.Printf("Kolom %s.%s ditambahkan.\n", table, column)
+	return nil
+}
+
+// ===============================================
+//   SCHEMA NEO4J
+// ===============================================
+
+var neo4jConstraints = []string{
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (p:Pasien) REQUIRE p.email IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (p:Pasien) REQUIRE p.nik IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (t:TenagaMedis) REQUIRE t.email IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (r:RumahSakit) REQUIRE r.id_rs IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (d:Departemen) REQUIRE d.nama_departemen IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (l:LayananMedis) REQUIRE l.id_layanan IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (b:Baymin) REQUIRE b.id_perangkat IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (j:JanjiTemu) REQUIRE j.id_janji_temu IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (r:Resep) REQUIRE r.id_resep IS UNIQUE;",
+	"CREATE CONSTRAINT IF NOT EXISTS FOR (dr:DetailResep) REQUIRE dr.id_detail_resep IS UNIQUE;",
+}
+
+// CreateNeo4j membuat constraint Neo4j lewat driver global neo4j.
+func CreateNeo4j() error {
+	fmt.Println("Creating Neo4j constraints and relationships...")
+
+	queries := append([]string(nil), neo4jConstraints...)
+
+	// Constraint lama DetailResep(id_obat) membuat satu obat hanya bisa muncul
+	// di satu resep. Hapus jika masih ada dari schema versi sebelumnya.
+	old, err := neo4j.ReadNeo4j(`
+		SHOW CONSTRAINTS YIELD name, labelsOrTypes, properties
+		WHERE labelsOrTypes = ['DetailResep'] AND properties = ['id_obat']
+		RETURN name`, nil)
+	if err != nil {
+		log.Printf("Neo4j Query failed: SHOW CONSTRAINTS\nError: %v\n", err)
+	}
+	for _, rec := range old {
+		queries = append(queries, fmt.Sprintf("DROP CONSTRAINT %v IF EXISTS;", rec["name"]))
+	}
+
+	failed := 0
+	for _, q := range queries {
+		if err := neo4j.CreateNeo4j(q, nil); err != nil {
+			log.Printf("Neo4j Query failed: %s\nError: %v\n", q, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d query schema Neo4j gagal", failed)
+	}
+
+	fmt.Println("Neo4j constraints created successfully.")
+	return nil
+}
@@ -0,0 +1,136 @@
+package script
+
+import (
+	"strings"
+)
+
+// ===============================================
+//   PEMECAH STATEMENT CQL & CYPHER
+// ===============================================
+//
+// Statement diakhiri ; di luar literal string dan komentar. Komentar dibuang
+// (baris baru dipertahankan agar nomor baris tetap benar):
+//   CQL    : -- ... , // ... , /* ... */
+//   Cypher : // ... , /* ... */   (-- adalah bagian pola relationship)
+// Literal yang dikenali:
+//   CQL    : '...' ('' = kutip), "identifier", $$ ... $$
+//   Cypher : '...' dan "..." (\ escape), `identifier`
+
+// Dialect menentukan aturan komentar dan literal.
+type Dialect int
+
+const (
+	CQL Dialect = iota
+	Cypher
+)
+
+func (d Dialect) String() string {
+	if d == Cypher {
+		return "cypher"
+	}
+	return "cql"
+}
+
+// Statement adalah satu statement lengkap tanpa ; penutup.
+type Statement struct {
+	Text string
+	Line int // baris (mulai 1) tempat statement diawali
+}
+
+// Split memecah src menjadi statement lengkap. rest adalah teks setelah ;
+// terakhir yang belum membentuk statement (kosong bila hanya berisi spasi
+// atau komentar); dipakai shell untuk input multi-baris.
+func Split(src string, d Dialect) (stmts []Statement, rest string) {
+	var buf strings.Builder
+	line, start := 1, 0 // start = baris karakter pertama non-spasi di buf
+
+	flush := func() {
+		text := strings.TrimSpace(buf.String())
+		if text != "" {
+			stmts = append(stmts, Statement{Text: text, Line: start})
+		}
+		buf.Reset()
+		start = 0
+	}
+	write := func(s string) {
+		if start == 0 && strings.TrimSpace(s) != "" {
+			start = line
+		}
+		buf.WriteString(s)
+		line += strings.Count(s, "\n")
+	}
+
+	for i := 0; i < len(src); {
+		rem := src[i:]
+		switch {
+		case strings.HasPrefix(rem, "//") || (d == CQL && strings.HasPrefix(rem, "--")):
+			end := strings.IndexByte(rem, '\n')
+			if end < 0 {
+				i = len(src)
+				continue
+			}
+			i += end // baris baru ditulis oleh iterasi berikutnya
+
+		case strings.HasPrefix(rem, "/*"):
+			end := strings.Index(rem[2:], "*/")
+			if end < 0 {
+				// komentar belum ditutup: biarkan sebagai sisa input
+				write(rem)
+				i = len(src)
+				continue
+			}
+			comment := rem[:end+4]
+			write(strings.Repeat("\n", strings.Count(comment, "\n")))
+			i += len(comment)
+
+		case rem[0] == ';':
+			flush()
+			i++
+
+		default:
+			n := literalLen(rem, d)
+			write(rem[:n])
+			i += n
+		}
+	}
+
+	rest = buf.String()
+	if strings.TrimSpace(rest) == "" {
+		rest = ""
+	}
+	return stmts, rest
+}
+
+// literalLen mengembalikan panjang token di awal s: seluruh literal string
+// (termasuk yang belum ditutup) atau satu byte biasa.
+func literalLen(s string, d Dialect) int {
+	if d == CQL && strings.HasPrefix(s, "$$") {
+		if end := strings.Index(s[2:], "$$"); end >= 0 {
+			return end + 4
+		}
+		return len(s)
+	}
+
+	quote := s[0]
+	switch {
+	case quote == '\'' || quote == '"':
+	case quote == '`' && d == Cypher:
+	default:
+		return 1
+	}
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && d == Cypher && quote != '`':
+			i++
+		case s[i] == quote:
+			// CQL: '' dan "" adalah kutip ter-escape di dalam literal
+			if d == CQL && i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,141 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		d    Dialect
+		want []Statement
+		rest string
+	}{
+		{"dua statement", "SELECT 1; SELECT 2;", CQL,
+			[]Statement{{"SELECT 1", 1}, {"SELECT 2", 1}}, ""},
+		{"nomor baris", "\n\nSELECT 1;\nSELECT\n  2;", CQL,
+			[]Statement{{"SELECT 1", 3}, {"SELECT\n  2", 4}}, ""},
+		{"; di kutip tunggal", "INSERT INTO t (a) VALUES ('x;y');", CQL,
+			[]Statement{{"INSERT INTO t (a) VALUES ('x;y')", 1}}, ""},
+		{"kutip ter-escape CQL", "SELECT 'it''s;ok' FROM t;", CQL,
+			[]Statement{{"SELECT 'it''s;ok' FROM t", 1}}, ""},
+		{"; di identifier CQL", `SELECT "a;b" FROM t;`, CQL,
+			[]Statement{{`SELECT "a;b" FROM t`, 1}}, ""},
+		{"komentar -- CQL", "SELECT 1; -- abaikan; ini\nSELECT 2;", CQL,
+			[]Statement{{"SELECT 1", 1}, {"SELECT 2", 2}}, ""},
+		{"komentar // CQL", "// judul; skrip\nSELECT 1; // akhir;", CQL,
+			[]Statement{{"SELECT 1", 2}}, ""},
+		{"komentar blok", "/* a;\nb */ SELECT 1;", CQL,
+			[]Statement{{"SELECT 1", 2}}, ""},
+		{"-- di dalam literal", "SELECT '--;' FROM t;", CQL,
+			[]Statement{{"SELECT '--;' FROM t", 1}}, ""},
+		{"blok $$", "CREATE FUNCTION f() RETURNS text LANGUAGE java AS $$ return \"a;b\"; // x\n$$;", CQL,
+			[]Statement{{"CREATE FUNCTION f() RETURNS text LANGUAGE java AS $$ return \"a;b\"; // x\n$$", 1}}, ""},
+		{"blok $$ belum ditutup", "SELECT 1; AS $$ a; b", CQL,
+			[]Statement{{"SELECT 1", 1}}, " AS $$ a; b"},
+		{"sisa tanpa ;", "SELECT 1; SELECT", CQL,
+			[]Statement{{"SELECT 1", 1}}, " SELECT"},
+		{"sisa hanya komentar", "SELECT 1; -- selesai", CQL,
+			[]Statement{{"SELECT 1", 1}}, ""},
+		{"kutip belum ditutup", "SELECT 'a;", CQL,
+			nil, "SELECT 'a;"},
+		{"statement kosong", ";;SELECT 1;;", CQL,
+			[]Statement{{"SELECT 1", 1}}, ""},
+		{"-- pola relationship Cypher", "MATCH (a)--(b) RETURN a;", Cypher,
+			[]Statement{{"MATCH (a)--(b) RETURN a", 1}}, ""},
+		{"komentar // Cypher", "MATCH (n) // cari; semua\nRETURN n;", Cypher,
+			[]Statement{{"MATCH (n) \nRETURN n", 1}}, ""},
+		{"escape backslash Cypher", `RETURN 'a\';b';`, Cypher,
+			[]Statement{{`RETURN 'a\';b'`, 1}}, ""},
+		{"backtick Cypher", "MATCH (n:`A;B`) RETURN n;", Cypher,
+			[]Statement{{"MATCH (n:`A;B`) RETURN n", 1}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest := Split(tt.src, tt.d)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, ingin %q", tt.src, got, tt.want)
+			}
+			if rest != tt.rest {
+				t.Errorf("Split(%q) rest = %q, ingin %q", tt.src, rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestBindCQL(t *testing.T) {
+	params := map[string]interface{}{"email": "a@b.c", "n": 5}
+	tests := []struct {
+		name    string
+		stmt    string
+		want    string
+		args    []interface{}
+		wantErr bool
+	}{
+		{"parameter", "SELECT * FROM t WHERE email = :email LIMIT :n",
+			"SELECT * FROM t WHERE email = ? LIMIT ?", []interface{}{"a@b.c", 5}, false},
+		{"parameter berulang", "UPDATE t SET a = :n WHERE b = :n",
+			"UPDATE t SET a = ? WHERE b = ?", []interface{}{5, 5}, false},
+		{"di dalam kutip", "SELECT * FROM t WHERE a = ':email' AND b = :n",
+			"SELECT * FROM t WHERE a = ':email' AND b = ?", []interface{}{5}, false},
+		{"kutip ter-escape", "SELECT * FROM t WHERE a = 'it'':email' AND b = :n",
+			"SELECT * FROM t WHERE a = 'it'':email' AND b = ?", []interface{}{5}, false},
+		{"di dalam identifier", `SELECT ":email" FROM t`,
+			`SELECT ":email" FROM t`, nil, false},
+		{"di dalam blok $$", "CREATE FUNCTION f() RETURNS int LANGUAGE java AS $$ return x?1:n; $$",
+			"CREATE FUNCTION f() RETURNS int LANGUAGE java AS $$ return x?1:n; $$", nil, false},
+		{"literal map", "UPDATE t SET m = {'a':1, 'b':2} WHERE k = :n",
+			"UPDATE t SET m = {'a':1, 'b':2} WHERE k = ?", []interface{}{5}, false},
+		{"waktu dalam literal", "SELECT * FROM t WHERE w = '2025-01-01 09:00'",
+			"SELECT * FROM t WHERE w = '2025-01-01 09:00'", nil, false},
+		{"belum diset", "SELECT * FROM t WHERE a = :lain", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := BindCQL(tt.stmt, params)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BindCQL(%q) = %q, ingin error", tt.stmt, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindCQL(%q): %v", tt.stmt, err)
+			}
+			if got != tt.want || !reflect.DeepEqual(args, tt.args) {
+				t.Fatalf("BindCQL(%q) = %q %v, ingin %q %v", tt.stmt, got, args, tt.want, tt.args)
+			}
+		})
+	}
+}
+
+func TestPositional(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want int
+	}{
+		{"SELECT * FROM t WHERE a = ? AND b = ?", 2},
+		{"SELECT * FROM t WHERE a = '?'", 0},
+		{`SELECT "?" FROM t WHERE a = ?`, 1},
+		{"CREATE FUNCTION f() RETURNS int LANGUAGE java AS $$ return x ? 1 : 0; $$", 0},
+	}
+	for _, tt := range tests {
+		if got := Positional(tt.stmt); got != tt.want {
+			t.Errorf("Positional(%q) = %d, ingin %d", tt.stmt, got, tt.want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"ks": "rumah_sakit"}
+	if got, err := Expand("USE ${ks}; SELECT ${ks}", vars); err != nil || got != "USE rumah_sakit; SELECT rumah_sakit" {
+		t.Fatalf("Expand = %q, %v", got, err)
+	}
+	for _, in := range []string{"USE ${lain}", "USE ${ks"} {
+		if got, err := Expand(in, vars); err == nil {
+			t.Errorf("Expand(%q) = %q, ingin error", in, got)
+		}
+	}
+}
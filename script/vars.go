@@ -0,0 +1,110 @@
+package script
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ===============================================
+//   VARIABEL & PARAMETER
+// ===============================================
+//
+// Dua cara memakai variabel di statement:
+//   ${nama}  diganti teks apa adanya sebelum statement dikirim (mis. nama
+//            keyspace, label, atau potongan query)
+//   $nama    parameter Cypher, dikirim terpisah oleh driver
+//   :nama    parameter CQL, diubah menjadi ? oleh BindCQL
+
+// DialectOf menebak dialek dari ekstensi file: .cql untuk Cassandra,
+// .cypher/.cyp untuk Neo4j.
+func DialectOf(path string) (Dialect, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".cql":
+		return CQL, true
+	case ".cypher", ".cyp":
+		return Cypher, true
+	}
+	return CQL, false
+}
+
+// Expand mengganti setiap ${nama} di text dengan vars[nama]. Variabel yang
+// belum diset adalah error agar statement tidak terkirim setengah jadi.
+func Expand(text string, vars map[string]string) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			out.WriteString(text)
+			return out.String(), nil
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("${ tanpa } penutup")
+		}
+		name := text[start+2 : start+end]
+		v, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("variabel ${%s} belum diset", name)
+		}
+		out.WriteString(text[:start])
+		out.WriteString(v)
+		text = text[start+end+1:]
+	}
+}
+
+// BindCQL mengganti parameter bernama :nama di luar literal ('...', "..."
+// dan $$ ... $$) dengan ? dan mengembalikan nilainya sesuai urutan.
+func BindCQL(stmt string, params map[string]interface{}) (string, []interface{}, error) {
+	var out strings.Builder
+	var args []interface{}
+	for i := 0; i < len(stmt); i++ {
+		ch := stmt[i]
+		switch {
+		case ch == '\'' || ch == '"' || strings.HasPrefix(stmt[i:], "$$"):
+			n := literalLen(stmt[i:], CQL)
+			out.WriteString(stmt[i : i+n])
+			i += n - 1
+			continue
+		case ch == ':' && (i == 0 || !isIdent(stmt[i-1])) && i+1 < len(stmt) && isIdentStart(stmt[i+1]):
+			j := i + 1
+			for j < len(stmt) && isIdent(stmt[j]) {
+				j++
+			}
+			name := stmt[i+1 : j]
+			v, ok := params[name]
+			if !ok {
+				return "", nil, fmt.Errorf("parameter :%s belum diset", name)
+			}
+			args = append(args, v)
+			out.WriteByte('?')
+			i = j - 1
+			continue
+		}
+		out.WriteByte(ch)
+	}
+	return out.String(), args, nil
+}
+
+// Positional menghitung placeholder ? di luar literal; BindCQL tidak
+// mengisinya, jadi statement seperti ini hanya bisa dijalankan lewat kode.
+func Positional(stmt string) int {
+	n := 0
+	for i := 0; i < len(stmt); i++ {
+		switch ch := stmt[i]; {
+		case ch == '\'' || ch == '"' || strings.HasPrefix(stmt[i:], "$$"):
+			i += literalLen(stmt[i:], CQL) - 1
+		case ch == '?':
+			n++
+		}
+	}
+	return n
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdent(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
@@ -0,0 +1,357 @@
+package seeder
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ===============================================
+//   KONFIGURASI SEEDER
+// ===============================================
+
+// Config menentukan jumlah data per entitas, rasio-rasio generator,
+// seed random dan subset entitas yang akan ditulis.
+type Config struct {
+	Seed int64     // 0 = acak (seed yang dipakai dicetak di laporan)
+	Now  time.Time // waktu acuan untuk timestamp relatif
+
+	NumPasien           int
+	NumTenagaMedis      int
+	NumRumahSakit       int
+	NumDepartemen       int
+	NumLayanan          int
+	NumObat             int
+	NumPemesananLayanan int
+	NumPemesananObat    int
+	NumLogAktivitas     int
+	NumJanjiTemu        int
+
+	MinLayananPerRS    int     // jumlah layanan minimum yang ditawarkan tiap RS
+	MaxLayananPerRS    int     // jumlah layanan maksimum yang ditawarkan tiap RS
+	RasioBaymin        float64 // proporsi pasien yang memiliki perangkat Baymin
+	RasioPesananLampau float64 // proporsi pemesanan obat di masa lalu (sisanya masa depan)
+	ObatPerPesanan     int     // jumlah jenis obat per pemesanan obat
+	ObatPerResep       int     // jumlah DetailResep per resep
+
+	Only []string // subset store / entitas; kosong = semua
+
+	CassandraWorkers int           // jumlah worker penulis Cassandra
+	Neo4jWorkers     int           // jumlah worker penulis Neo4j
+	BatchSize        int           // jumlah baris per job (Neo4j ditulis dengan UNWIND)
+	QueueSize        int           // kapasitas antrian job per store (0 = 2x worker)
+	MaxRetries       int           // percobaan ulang untuk error sementara
+	RetryDelay       time.Duration // jeda awal retry, berlipat dua tiap percobaan
+	ProgressInterval time.Duration // interval laporan progres (0 = mati)
+
+	StateFile string // file checkpoint progres per entitas
+	Resume    bool   // lanjutkan run sebelumnya dari StateFile
+}
+
+// pipelineDefaults diterapkan ke semua profil.
+func pipelineDefaults(c Config) Config {
+	c.CassandraWorkers = 16
+	c.Neo4jWorkers = 4
+	c.BatchSize = 500
+	c.MaxRetries = 3
+	c.RetryDelay = 200 * time.Millisecond
+	c.ProgressInterval = 2 * time.Second
+	c.StateFile = ".seed-state.json"
+	return c
+}
+
+// Profiles berisi preset ukuran dataset yang bisa dipilih dengan --profile.
+var Profiles = map[string]Config{
+	"tiny": {
+		NumPasien:           50,
+		NumTenagaMedis:      20,
+		NumRumahSakit:       5,
+		NumDepartemen:       10,
+		NumLayanan:          20,
+		NumObat:             30,
+		NumPemesananLayanan: 50,
+		NumPemesananObat:    50,
+		NumLogAktivitas:     100,
+		NumJanjiTemu:        50,
+		MinLayananPerRS:     3,
+		MaxLayananPerRS:     5,
+		RasioBaymin:         1.0,
+		RasioPesananLampau:  0.7,
+		ObatPerPesanan:      2,
+		ObatPerResep:        2,
+	},
+	"default": {
+		NumPasien:           9000,
+		NumTenagaMedis:      1000,
+		NumRumahSakit:       100,
+		NumDepartemen:       500,
+		NumLayanan:          500,
+		NumObat:             1000,
+		NumPemesananLayanan: 10000,
+		NumPemesananObat:    10000,
+		NumLogAktivitas:     10000,
+		NumJanjiTemu:        10000,
+		MinLayananPerRS:     5,
+		MaxLayananPerRS:     10,
+		RasioBaymin:         1.0,
+		RasioPesananLampau:  0.7,
+		ObatPerPesanan:      2,
+		ObatPerResep:        2,
+	},
+	"large": {
+		NumPasien:           100000,
+		NumTenagaMedis:      10000,
+		NumRumahSakit:       1000,
+		NumDepartemen:       5000,
+		NumLayanan:          2000,
+		NumObat:             5000,
+		NumPemesananLayanan: 200000,
+		NumPemesananObat:    200000,
+		NumLogAktivitas:     500000,
+		NumJanjiTemu:        200000,
+		MinLayananPerRS:     5,
+		MaxLayananPerRS:     20,
+		RasioBaymin:         1.0,
+		RasioPesananLampau:  0.7,
+		ObatPerPesanan:      3,
+		ObatPerResep:        3,
+	},
+}
+
+// DefaultConfig mengembalikan konfigurasi profil "default".
+func DefaultConfig() Config {
+	return pipelineDefaults(Profiles["default"])
+}
+
+// ProfileNames mengembalikan nama profil yang tersedia secara terurut.
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ===============================================
+//   FLAGS
+// ===============================================
+
+// bindFlags mendaftarkan semua flag yang mengubah Config.
+func bindFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.Int64Var(&cfg.Seed, "seed", cfg.Seed, "seed random untuk output deterministik (0 = seed checkpoint, atau acak)")
+	fs.Var((*timeValue)(&cfg.Now), "now", "waktu acuan RFC3339 untuk timestamp relatif (default: sekarang, atau 2025-01-01T00:00:00Z bila --seed diisi)")
+
+	fs.IntVar(&cfg.NumPasien, "pasien", cfg.NumPasien, "jumlah Pasien")
+	fs.IntVar(&cfg.NumTenagaMedis, "tenaga-medis", cfg.NumTenagaMedis, "jumlah TenagaMedis")
+	fs.IntVar(&cfg.NumRumahSakit, "rumah-sakit", cfg.NumRumahSakit, "jumlah RumahSakit")
+	fs.IntVar(&cfg.NumDepartemen, "departemen", cfg.NumDepartemen, "jumlah Departemen")
+	fs.IntVar(&cfg.NumLayanan, "layanan", cfg.NumLayanan, "jumlah LayananMedis")
+	fs.IntVar(&cfg.NumObat, "obat", cfg.NumObat, "jumlah obat")
+	fs.IntVar(&cfg.NumPemesananLayanan, "pemesanan-layanan", cfg.NumPemesananLayanan, "jumlah pemesanan_layanan")
+	fs.IntVar(&cfg.NumPemesananObat, "pemesanan-obat", cfg.NumPemesananObat, "jumlah pemesanan_obat")
+	fs.IntVar(&cfg.NumLogAktivitas, "log-aktivitas", cfg.NumLogAktivitas, "jumlah log_aktivitas")
+	fs.IntVar(&cfg.NumJanjiTemu, "janji-temu", cfg.NumJanjiTemu, "jumlah JanjiTemu")
+
+	fs.IntVar(&cfg.MinLayananPerRS, "min-layanan-per-rs", cfg.MinLayananPerRS, "layanan minimum per rumah sakit")
+	fs.IntVar(&cfg.MaxLayananPerRS, "max-layanan-per-rs", cfg.MaxLayananPerRS, "layanan maksimum per rumah sakit")
+	fs.Float64Var(&cfg.RasioBaymin, "rasio-baymin", cfg.RasioBaymin, "proporsi pasien yang memiliki Baymin (0..1)")
+	fs.Float64Var(&cfg.RasioPesananLampau, "rasio-pesanan-lampau", cfg.RasioPesananLampau, "proporsi pemesanan obat di masa lalu (0..1)")
+	fs.IntVar(&cfg.ObatPerPesanan, "obat-per-pesanan", cfg.ObatPerPesanan, "jumlah jenis obat per pemesanan")
+	fs.IntVar(&cfg.ObatPerResep, "obat-per-resep", cfg.ObatPerResep, "jumlah DetailResep per resep")
+
+	fs.Var((*listValue)(&cfg.Only), "only", "hanya tulis store/entitas tertentu, dipisah koma (mis. cassandra, obat)")
+
+	fs.IntVar(&cfg.CassandraWorkers, "cassandra-workers", cfg.CassandraWorkers, "jumlah worker penulis Cassandra")
+	fs.IntVar(&cfg.Neo4jWorkers, "neo4j-workers", cfg.Neo4jWorkers, "jumlah worker penulis Neo4j")
+	fs.IntVar(&cfg.BatchSize, "batch-size", cfg.BatchSize, "jumlah baris per job")
+	fs.IntVar(&cfg.QueueSize, "queue-size", cfg.QueueSize, "kapasitas antrian job per store (0 = 2x worker)")
+	fs.IntVar(&cfg.MaxRetries, "retries", cfg.MaxRetries, "percobaan ulang untuk error sementara")
+	fs.DurationVar(&cfg.RetryDelay, "retry-delay", cfg.RetryDelay, "jeda awal sebelum retry (berlipat dua tiap percobaan)")
+	fs.DurationVar(&cfg.ProgressInterval, "progress", cfg.ProgressInterval, "interval laporan progres (0 = mati)")
+
+	fs.StringVar(&cfg.StateFile, "state", cfg.StateFile, "file checkpoint progres seeding")
+	fs.BoolVar(&cfg.Resume, "resume", cfg.Resume, "lanjutkan run sebelumnya dari file checkpoint (seed & jumlah data diambil dari checkpoint)")
+}
+
+// ParseFlags membaca argumen command line menjadi Config. Profil dipakai
+// sebagai dasar, lalu flag yang diberikan secara eksplisit menimpanya.
+func ParseFlags(fs *flag.FlagSet, args []string) (Config, error) {
+	cfg := DefaultConfig()
+	profile := fs.String("profile", "default", "preset ukuran dataset: "+strings.Join(ProfileNames(), ", "))
+	bindFlags(fs, &cfg)
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *profile != "default" {
+		base, ok := Profiles[*profile]
+		if !ok {
+			return Config{}, fmt.Errorf("profil %q tidak dikenal (pilihan: %s)", *profile, strings.Join(ProfileNames(), ", "))
+		}
+		base = pipelineDefaults(base)
+
+		// Terapkan ulang flag eksplisit di atas profil
+		override := flag.NewFlagSet("override", flag.ContinueOnError)
+		bindFlags(override, &base)
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "profile" || override.Lookup(f.Name) == nil || err != nil {
+				return
+			}
+			err = override.Set(f.Name, f.Value.String())
+		})
+		if err != nil {
+			return Config{}, err
+		}
+		cfg = base
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// DefaultNow adalah waktu acuan bila --seed diisi tanpa --now, agar seed
+// yang sama menghasilkan data yang identik kapan pun dijalankan.
+var DefaultNow = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// reuse menyamakan seed dan waktu acuan dengan run sebelumnya yang
+// tercatat di checkpoint. Tanpa --seed keduanya dipakai ulang; seed atau
+// --now yang berbeda ditolak karena data lama tidak akan tertimpa dan
+// tidak lagi terhapus oleh --reset.
+func (c *Config) reuse(saved Config) error {
+	if c.Seed == 0 {
+		c.Seed = saved.Seed
+	}
+	if c.Now.IsZero() && c.Seed == saved.Seed {
+		c.Now = saved.Now
+	}
+	switch {
+	case c.Seed != saved.Seed:
+		return fmt.Errorf("checkpoint %s berisi data seed %d; pakai --reset untuk menggantinya dengan seed %d, atau hapus --seed", c.StateFile, saved.Seed, c.Seed)
+	case !c.Now.Equal(saved.Now):
+		return fmt.Errorf("checkpoint %s berisi data dengan --now %s; pakai --reset untuk menggantinya, atau hapus --now", c.StateFile, saved.Now.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ResolveSeed mengisi Seed dan Now yang kosong. Tanpa seed keduanya diambil
+// dari jam sekarang; dengan seed eksplisit Now bawaannya DefaultNow. Now
+// selalu disimpan dalam UTC agar hasilnya tidak bergantung zona mesin.
+func (c *Config) ResolveSeed() {
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+		if c.Now.IsZero() {
+			c.Now = time.Now().Truncate(time.Second)
+		}
+	} else if c.Now.IsZero() {
+		c.Now = DefaultNow
+	}
+	c.Now = c.Now.UTC()
+}
+
+// Validate memeriksa nilai konfigurasi yang tidak masuk akal.
+func (c Config) Validate() error {
+	counts := map[string]int{
+		"pasien":            c.NumPasien,
+		"tenaga-medis":      c.NumTenagaMedis,
+		"rumah-sakit":       c.NumRumahSakit,
+		"departemen":        c.NumDepartemen,
+		"layanan":           c.NumLayanan,
+		"obat":              c.NumObat,
+		"pemesanan-layanan": c.NumPemesananLayanan,
+		"pemesanan-obat":    c.NumPemesananObat,
+		"log-aktivitas":     c.NumLogAktivitas,
+		"janji-temu":        c.NumJanjiTemu,
+	}
+	for name, n := range counts {
+		if n < 0 {
+			return fmt.Errorf("--%s tidak boleh negatif", name)
+		}
+	}
+
+	// Entitas yang menjadi referensi entitas lain tidak boleh kosong
+	if c.NumJanjiTemu > 0 && (c.NumPasien == 0 || c.NumTenagaMedis == 0 || c.NumRumahSakit == 0) {
+		return fmt.Errorf("--janji-temu membutuhkan pasien, tenaga medis dan rumah sakit")
+	}
+	if c.NumTenagaMedis > 0 && c.NumDepartemen == 0 {
+		return fmt.Errorf("--tenaga-medis membutuhkan minimal satu departemen")
+	}
+	if c.NumDepartemen > 0 && c.NumRumahSakit == 0 {
+		return fmt.Errorf("--departemen membutuhkan minimal satu rumah sakit")
+	}
+	if c.NumPemesananObat > 0 && c.NumObat == 0 {
+		return fmt.Errorf("--pemesanan-obat membutuhkan minimal satu obat")
+	}
+	if (c.NumPemesananObat > 0 || c.NumPemesananLayanan > 0) && c.NumPasien == 0 {
+		return fmt.Errorf("pemesanan obat/layanan membutuhkan minimal satu pasien")
+	}
+
+	if c.MinLayananPerRS < 0 || c.MaxLayananPerRS < c.MinLayananPerRS {
+		return fmt.Errorf("rentang layanan per RS tidak valid: %d..%d", c.MinLayananPerRS, c.MaxLayananPerRS)
+	}
+	if c.RasioBaymin < 0 || c.RasioBaymin > 1 {
+		return fmt.Errorf("--rasio-baymin harus di antara 0 dan 1")
+	}
+	if c.RasioPesananLampau < 0 || c.RasioPesananLampau > 1 {
+		return fmt.Errorf("--rasio-pesanan-lampau harus di antara 0 dan 1")
+	}
+	if c.ObatPerPesanan < 1 || c.ObatPerResep < 0 {
+		return fmt.Errorf("--obat-per-pesanan minimal 1 dan --obat-per-resep tidak boleh negatif")
+	}
+
+	if c.CassandraWorkers < 1 || c.Neo4jWorkers < 1 || c.BatchSize < 1 {
+		return fmt.Errorf("--cassandra-workers, --neo4j-workers dan --batch-size minimal 1")
+	}
+	if c.QueueSize < 0 || c.MaxRetries < 0 {
+		return fmt.Errorf("--queue-size dan --retries tidak boleh negatif")
+	}
+
+	for _, name := range c.Only {
+		if !isKnownSelector(name) {
+			return fmt.Errorf("--only %q tidak dikenal (pilihan: cassandra, neo4j, %s)", name, strings.Join(EntityNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// --- Flag value types ---
+
+type timeValue time.Time
+
+func (t *timeValue) String() string {
+	if t == nil || time.Time(*t).IsZero() {
+		return ""
+	}
+	return time.Time(*t).Format(time.RFC3339)
+}
+
+func (t *timeValue) Set(s string) error {
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("format waktu harus RFC3339 (mis. 2025-01-31T08:00:00Z): %v", err)
+	}
+	*t = timeValue(parsed)
+	return nil
+}
+
+type listValue []string
+
+func (l *listValue) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listValue) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(strings.ToLower(part))
+		if part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
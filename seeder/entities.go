@@ -0,0 +1,219 @@
+package seeder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Row adalah satu baris/node/relationship hasil generator. Nominal uang
+// dihitung sebagai uang.Rupiah lalu disimpan di Row sebagai float rupiah,
+// sesuai kolom DOUBLE Cassandra dan properti float Neo4j.
+type Row = map[string]interface{}
+
+const (
+	StoreCassandra = "cassandra"
+	StoreNeo4j     = "neo4j"
+)
+
+// Entity mendeskripsikan bagaimana satu jenis data ditulis ke store-nya.
+// Tepat satu dari Table, Label atau RelType terisi.
+type Entity struct {
+	Name  string // nama entitas untuk --only dan laporan
+	Group string // entitas induk (untuk relationship), mis. "baymin"
+	Store string
+
+	// Cassandra
+	Table        string
+	Columns      []string
+	PartitionKey string   // kolom partition key, untuk batch per partisi
+	PrimaryKey   []string // semua kolom primary key, untuk --reset
+
+	// Neo4j node
+	Label string
+	Key   string // property unik node
+	Props []string
+
+	// Neo4j relationship: (From)-[:RelType]->(To)
+	RelType string
+	From    NodeRef
+	To      NodeRef
+
+	rows func(*Dataset) []Row
+}
+
+// NodeRef menunjuk node ujung relationship berdasarkan property unik.
+type NodeRef struct {
+	Label string
+	Key   string // property di node
+	Field string // key di Row
+}
+
+// Query mengembalikan statement tulis entitas: INSERT per baris untuk
+// Cassandra, atau UNWIND untuk Neo4j sehingga banyak baris dapat ditulis
+// dalam satu transaksi (baris dikirim sebagai parameter $rows).
+//
+// Semua statement bersifat upsert (INSERT Cassandra menimpa baris dengan
+// primary key yang sama, node & relationship Neo4j memakai MERGE pada
+// property unik) sehingga seeding aman diulang.
+func (e Entity) Query() string {
+	switch {
+	case e.Table != "":
+		marks := strings.TrimSuffix(strings.Repeat("?, ", len(e.Columns)), ", ")
+		return fmt.Sprintf("INSERT INTO rumahsakit.%s (%s) VALUES (%s)", e.Table, strings.Join(e.Columns, ", "), marks)
+	case e.Label != "":
+		var sets []string
+		for _, p := range e.Props {
+			if p != e.Key {
+				sets = append(sets, fmt.Sprintf("n.%s = row.%s", p, p))
+			}
+		}
+		query := fmt.Sprintf("UNWIND $rows AS row MERGE (n:%s {%s: row.%s})", e.Label, e.Key, e.Key)
+		if len(sets) > 0 {
+			query += " SET " + strings.Join(sets, ", ")
+		}
+		return query
+	default:
+		return fmt.Sprintf("UNWIND $rows AS row MATCH (a:%s {%s: row.%s}) MATCH (b:%s {%s: row.%s}) MERGE (a)-[:%s]->(b)",
+			e.From.Label, e.From.Key, e.From.Field, e.To.Label, e.To.Key, e.To.Field, e.RelType)
+	}
+}
+
+// Args mengembalikan parameter positional (Cassandra) sesuai urutan kolom.
+func (e Entity) Args(row Row) []interface{} {
+	args := make([]interface{}, len(e.Columns))
+	for i, col := range e.Columns {
+		args[i] = row[col]
+	}
+	return args
+}
+
+// Rows mengembalikan baris entitas dari dataset.
+func (e Entity) Rows(ds *Dataset) []Row {
+	return e.rows(ds)
+}
+
+// Entities adalah daftar entitas dalam urutan penulisan. Node Neo4j harus
+// ditulis sebelum relationship yang mereferensikannya.
+var Entities = []Entity{
+	// --- Cassandra ---
+	{Name: "obat", Store: StoreCassandra, Table: "obat", PartitionKey: "id_obat", PrimaryKey: []string{"id_obat"},
+		Columns: []string{"id_obat", "nama", "label", "harga", "stok"},
+		rows:    func(d *Dataset) []Row { return d.Obat }},
+	{Name: "pemesanan_layanan", Store: StoreCassandra, Table: "pemesanan_layanan", PartitionKey: "id_pesanan", PrimaryKey: []string{"id_pesanan"},
+		Columns: []string{"id_pesanan", "email_pemesan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan", "waktu_pemesanan", "jadwal_pelaksanaan", "status_pemesanan"},
+		rows:    func(d *Dataset) []Row { return d.PemesananLayanan }},
+	{Name: "pemesanan_layanan_per_pasien", Group: "pemesanan_layanan", Store: StoreCassandra, Table: "pemesanan_layanan_per_pasien", PartitionKey: "email_pemesan", PrimaryKey: []string{"email_pemesan", "jadwal_pelaksanaan", "id_pesanan"},
+		Columns: []string{"email_pemesan", "jadwal_pelaksanaan", "id_pesanan", "id_rs", "id_layanan", "nama_layanan", "biaya_layanan"},
+		rows:    func(d *Dataset) []Row { return d.PemesananLayanan }},
+	{Name: "pemesanan_layanan_per_rs", Group: "pemesanan_layanan", Store: StoreCassandra, Table: "pemesanan_layanan_per_rs", PartitionKey: "id_rs", PrimaryKey: []string{"id_rs", "tanggal", "jadwal_pelaksanaan", "id_pesanan"},
+		Columns: []string{"id_rs", "tanggal", "jadwal_pelaksanaan", "id_pesanan", "email_pemesan", "id_layanan", "nama_layanan"},
+		rows:    func(d *Dataset) []Row { return d.PemesananLayanan }},
+	{Name: "kuota_layanan", Group: "pemesanan_layanan", Store: StoreCassandra, Table: "kuota_layanan", PartitionKey: "id_rs", PrimaryKey: []string{"id_rs", "id_layanan", "tanggal"},
+		Columns: []string{"id_rs", "id_layanan", "tanggal", "terpakai"},
+		rows:    func(d *Dataset) []Row { return d.KuotaLayanan }},
+	{Name: "lokasi_layanan", Store: StoreCassandra, Table: "lokasi_layanan", PartitionKey: "id_rs", PrimaryKey: []string{"id_rs", "id_layanan"},
+		Columns: []string{"id_rs", "id_layanan", "nama_layanan", "biaya_layanan"},
+		rows:    func(d *Dataset) []Row { return d.Penawaran }},
+	{Name: "log_aktivitas", Store: StoreCassandra, Table: "log_aktivitas", PartitionKey: "id_perangkat", PrimaryKey: []string{"id_perangkat", "waktu_aktivitas"},
+		Columns: []string{"id_perangkat", "waktu_aktivitas", "detail_aktivitas"},
+		rows:    func(d *Dataset) []Row { return d.LogAktivitas }},
+	{Name: "pemesanan_obat", Store: StoreCassandra, Table: "pemesanan_obat", PartitionKey: "id_pesanan", PrimaryKey: []string{"id_pesanan"},
+		Columns: []string{"id_pesanan", "email_pemesan", "waktu_pemesanan", "status_pemesanan", "total_harga"},
+		rows:    func(d *Dataset) []Row { return d.PemesananObat }},
+	{Name: "detail_pesanan_obat", Group: "pemesanan_obat", Store: StoreCassandra, Table: "detail_pesanan_obat", PartitionKey: "id_pesanan", PrimaryKey: []string{"id_pesanan"},
+		Columns: []string{"id_pesanan", "daftar_obat"},
+		rows:    func(d *Dataset) []Row { return d.DetailPesananObat }},
+
+	// --- Neo4j nodes ---
+	{Name: "pasien", Store: StoreNeo4j, Label: "Pasien", Key: "email",
+		Props: []string{"email", "nik", "kata_sandi", "nama_lengkap", "jenis_kelamin", "tanggal_lahir", "nomor_telepon", "provinsi", "kota", "kecamatan", "jalan"},
+		rows:  func(d *Dataset) []Row { return d.Pasien }},
+	{Name: "tenaga_medis", Store: StoreNeo4j, Label: "TenagaMedis", Key: "email",
+		Props: []string{"email", "NIKes", "nik", "profesi", "kata_sandi", "nama_lengkap", "jenis_kelamin", "tanggal_lahir", "nomor_telepon", "provinsi", "kota", "kecamatan", "jalan"},
+		rows:  func(d *Dataset) []Row { return d.TenagaMedis }},
+	{Name: "rumah_sakit", Store: StoreNeo4j, Label: "RumahSakit", Key: "id_rs",
+		Props: []string{"id_rs", "email", "nama_rumah_sakit", "no_telepon", "provinsi", "kota", "kecamatan", "jalan"},
+		rows:  func(d *Dataset) []Row { return d.RumahSakit }},
+	{Name: "departemen", Store: StoreNeo4j, Label: "Departemen", Key: "nama_departemen",
+		Props: []string{"nama_departemen", "gedung"},
+		rows:  func(d *Dataset) []Row { return d.Departemen }},
+	{Name: "layanan_medis", Store: StoreNeo4j, Label: "LayananMedis", Key: "id_layanan",
+		Props: []string{"id_layanan", "nama_layanan", "biaya_layanan"},
+		rows:  func(d *Dataset) []Row { return d.LayananMedis }},
+	{Name: "baymin", Store: StoreNeo4j, Label: "Baymin", Key: "id_perangkat",
+		Props: []string{"id_perangkat", "warna", "email_pasien"},
+		rows:  func(d *Dataset) []Row { return d.Baymin }},
+	{Name: "janji_temu", Store: StoreNeo4j, Label: "JanjiTemu", Key: "id_janji_temu",
+		Props: []string{"id_janji_temu", "waktu_pelaksanaan", "alasan", "status"},
+		rows:  func(d *Dataset) []Row { return d.JanjiTemu }},
+	{Name: "resep", Group: "janji_temu", Store: StoreNeo4j, Label: "Resep", Key: "id_resep",
+		Props: []string{"id_resep", "penyakit"},
+		rows:  func(d *Dataset) []Row { return d.Resep }},
+	{Name: "detail_resep", Group: "janji_temu", Store: StoreNeo4j, Label: "DetailResep", Key: "id_detail_resep",
+		Props: []string{"id_detail_resep", "id_resep", "id_obat", "dosis"},
+		rows:  func(d *Dataset) []Row { return d.DetailResep }},
+
+	// --- Neo4j relationships ---
+	{Name: "memiliki_perangkat", Group: "baymin", Store: StoreNeo4j, RelType: "memiliki_perangkat",
+		From: NodeRef{"Pasien", "email", "email_pasien"}, To: NodeRef{"Baymin", "id_perangkat", "id_perangkat"},
+		rows: func(d *Dataset) []Row { return d.Baymin }},
+	{Name: "bekerja_di", Group: "tenaga_medis", Store: StoreNeo4j, RelType: "bekerja_di",
+		From: NodeRef{"TenagaMedis", "email", "email_tm"}, To: NodeRef{"Departemen", "nama_departemen", "nama_dept"},
+		rows: func(d *Dataset) []Row { return d.BekerjaDi }},
+	{Name: "memiliki_departemen", Group: "departemen", Store: StoreNeo4j, RelType: "memiliki_departemen",
+		From: NodeRef{"RumahSakit", "id_rs", "id_rs"}, To: NodeRef{"Departemen", "nama_departemen", "nama_dept"},
+		rows: func(d *Dataset) []Row { return d.MemilikiDepartemen }},
+	{Name: "menawarkan_layanan", Group: "layanan_medis", Store: StoreNeo4j, RelType: "menawarkan_layanan",
+		From: NodeRef{"RumahSakit", "id_rs", "id_rs"}, To: NodeRef{"LayananMedis", "id_layanan", "id_layanan"},
+		rows: func(d *Dataset) []Row { return d.Penawaran }},
+	{Name: "memiliki_janji", Group: "janji_temu", Store: StoreNeo4j, RelType: "memiliki_janji",
+		From: NodeRef{"JanjiTemu", "id_janji_temu", "id_janji_temu"}, To: NodeRef{"Pasien", "email", "p_email"},
+		rows: func(d *Dataset) []Row { return d.JanjiTemu }},
+	{Name: "dengan_dokter", Group: "janji_temu", Store: StoreNeo4j, RelType: "dengan_dokter",
+		From: NodeRef{"JanjiTemu", "id_janji_temu", "id_janji_temu"}, To: NodeRef{"TenagaMedis", "email", "t_email"},
+		rows: func(d *Dataset) []Row { return d.JanjiTemu }},
+	{Name: "di_rs", Group: "janji_temu", Store: StoreNeo4j, RelType: "di_rs",
+		From: NodeRef{"JanjiTemu", "id_janji_temu", "id_janji_temu"}, To: NodeRef{"RumahSakit", "id_rs", "id_rs"},
+		rows: func(d *Dataset) []Row { return d.JanjiTemu }},
+	{Name: "menghasilkan_resep", Group: "janji_temu", Store: StoreNeo4j, RelType: "menghasilkan_resep",
+		From: NodeRef{"JanjiTemu", "id_janji_temu", "id_janji_temu"}, To: NodeRef{"Resep", "id_resep", "id_resep"},
+		rows: func(d *Dataset) []Row { return d.Resep }},
+	{Name: "memiliki_detail", Group: "janji_temu", Store: StoreNeo4j, RelType: "memiliki_detail",
+		From: NodeRef{"Resep", "id_resep", "id_resep"}, To: NodeRef{"DetailResep", "id_detail_resep", "id_detail_resep"},
+		rows: func(d *Dataset) []Row { return d.DetailResep }},
+}
+
+// EntityNames mengembalikan nama semua entitas sesuai urutan penulisan.
+func EntityNames() []string {
+	names := make([]string, len(Entities))
+	for i, e := range Entities {
+		names[i] = e.Name
+	}
+	return names
+}
+
+func isKnownSelector(name string) bool {
+	if name == StoreCassandra || name == StoreNeo4j {
+		return true
+	}
+	for _, e := range Entities {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Selected melaporkan apakah entitas termasuk subset --only.
+// Selector dapat berupa nama store, nama entitas, atau nama grup.
+func (c Config) Selected(e Entity) bool {
+	if len(c.Only) == 0 {
+		return true
+	}
+	for _, s := range c.Only {
+		if s == e.Store || s == e.Name || (e.Group != "" && s == e.Group) {
+			return true
+		}
+	}
+	return false
+}
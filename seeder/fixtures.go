@@ -0,0 +1,521 @@
+package seeder
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ===============================================
+//   FIXTURE EXPORT / IMPORT
+// ===============================================
+//
+// Layout direktori (format csv):
+//
+//   manifest.json
+//   cassandra/<tabel>.csv          header = nama kolom, untuk cqlsh COPY
+//   cassandra/copy.cql             perintah COPY ... FROM untuk semua tabel
+//   neo4j/nodes_<Label>.csv        header neo4j-admin (email:ID(Pasien), harga:double, ...)
+//   neo4j/rels_<tipe>.csv          header :START_ID(..),:END_ID(..),:TYPE
+//   neo4j/import.sh                perintah neo4j-admin database import full
+//
+// Format jsonl menulis jsonl/<entitas>.jsonl, satu objek JSON per baris.
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+
+	manifestFile = "manifest.json"
+
+	// cqlsh COPY menerima timestamp dengan offset zona waktu
+	cqlTimestampLayout = "2006-01-02 15:04:05.000-0700"
+)
+
+// columnTypes berisi tipe kolom/property yang bukan text. Nama kolom di
+// schema ini konsisten antar tabel sehingga cukup dipetakan per nama.
+var columnTypes = map[string]string{
+	"harga":              "double",
+	"biaya_layanan":      "double",
+	"total_harga":        "double",
+	"stok":               "int",
+	"terpakai":           "int",
+	"kapasitas_harian":   "int",
+	"waktu_pemesanan":    "timestamp",
+	"jadwal_pelaksanaan": "timestamp",
+	"waktu_aktivitas":    "timestamp",
+	"daftar_obat":        "map<text,int>",
+}
+
+// Manifest mendeskripsikan isi direktori fixture.
+type Manifest struct {
+	Format   string          `json:"format"`
+	Seed     int64           `json:"seed"`
+	Now      time.Time       `json:"now"`
+	Entities []ManifestEntry `json:"entities"`
+}
+
+// ManifestEntry adalah satu file fixture.
+type ManifestEntry struct {
+	Name  string `json:"name"`
+	Store string `json:"store"`
+	File  string `json:"file"`
+	Rows  int    `json:"rows"`
+}
+
+// fields mengembalikan key Row yang disimpan untuk entitas.
+func (e Entity) fields() []string {
+	switch {
+	case e.Table != "":
+		return e.Columns
+	case e.Label != "":
+		return e.Props
+	default:
+		return []string{e.From.Field, e.To.Field}
+	}
+}
+
+func (e Entity) fixtureFile(format string) string {
+	if format == FormatJSONL {
+		return filepath.Join("jsonl", e.Name+".jsonl")
+	}
+	switch {
+	case e.Table != "":
+		return filepath.Join("cassandra", e.Table+".csv")
+	case e.Label != "":
+		return filepath.Join("neo4j", "nodes_"+e.Label+".csv")
+	default:
+		return filepath.Join("neo4j", "rels_"+e.RelType+".csv")
+	}
+}
+
+// ===============================================
+//   EXPORT
+// ===============================================
+
+// Export membangkitkan dataset lalu menulisnya ke direktori fixture tanpa
+// menyentuh database.
+func Export(cfg Config, dir, format string) (Report, error) {
+	start := time.Now()
+	cfg.ResolveSeed()
+	report := Report{Config: cfg}
+
+	fmt.Printf("Generating dataset (seed %d)...\n", cfg.Seed)
+	ds := Generate(cfg)
+
+	manifest := Manifest{Format: format, Seed: cfg.Seed, Now: cfg.Now}
+	var exported []Entity
+	for _, e := range Entities {
+		if !cfg.Selected(e) {
+			continue
+		}
+		entityStart := time.Now()
+		rows := e.Rows(ds)
+		file := e.fixtureFile(format)
+		fmt.Printf("   -> Exporting %s (%d rows) ke %s...\n", e.Name, len(rows), file)
+
+		if err := writeFixture(filepath.Join(dir, file), e, rows, format); err != nil {
+			return report, fmt.Errorf("gagal menulis %s: %v", file, err)
+		}
+		manifest.Entities = append(manifest.Entities, ManifestEntry{Name: e.Name, Store: e.Store, File: file, Rows: len(rows)})
+		report.Entities = append(report.Entities, EntityStats{
+			Entity: e, Rows: len(rows), Written: len(rows), Duration: time.Since(entityStart),
+		})
+		exported = append(exported, e)
+	}
+
+	if format == FormatCSV {
+		if err := writeImportScripts(dir, exported); err != nil {
+			return report, err
+		}
+	}
+	if err := writeJSONFile(filepath.Join(dir, manifestFile), manifest); err != nil {
+		return report, err
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func writeFixture(path string, e Entity, rows []Row, format string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if format == FormatJSONL {
+		err = writeJSONL(w, e, rows)
+	} else {
+		err = writeCSV(w, e, rows)
+	}
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func writeJSONL(w io.Writer, e Entity, rows []Row) error {
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		obj := make(map[string]interface{}, len(e.fields()))
+		for _, field := range e.fields() {
+			obj[field] = row[field]
+		}
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, e Entity, rows []Row) error {
+	cw := csv.NewWriter(w)
+	fields := e.fields()
+
+	if err := cw.Write(csvHeader(e)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, 0, len(fields)+1)
+		for _, field := range fields {
+			record = append(record, formatCSVValue(row[field]))
+		}
+		if e.RelType != "" {
+			record = append(record, e.RelType)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvHeader mengikuti format header neo4j-admin untuk Neo4j dan nama kolom
+// polos untuk cqlsh COPY.
+func csvHeader(e Entity) []string {
+	switch {
+	case e.Table != "":
+		return e.Columns
+	case e.Label != "":
+		header := make([]string, len(e.Props))
+		for i, p := range e.Props {
+			switch {
+			case p == e.Key:
+				header[i] = fmt.Sprintf("%s:ID(%s)", p, e.Label)
+			case columnTypes[p] == "double" || columnTypes[p] == "int":
+				header[i] = p + ":" + columnTypes[p]
+			default:
+				header[i] = p
+			}
+		}
+		return header
+	default:
+		return []string{
+			fmt.Sprintf(":START_ID(%s)", e.From.Label),
+			fmt.Sprintf(":END_ID(%s)", e.To.Label),
+			":TYPE",
+		}
+	}
+}
+
+func formatCSVValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case int:
+		return strconv.Itoa(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return val.UTC().Format(cqlTimestampLayout)
+	case map[string]int:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = fmt.Sprintf("'%s': %d", k, val[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// writeImportScripts menulis perintah siap pakai untuk cqlsh COPY dan
+// neo4j-admin database import.
+func writeImportScripts(dir string, entities []Entity) error {
+	var cql, nodes, rels []string
+	for _, e := range entities {
+		switch {
+		case e.Table != "":
+			cql = append(cql, fmt.Sprintf("COPY rumahsakit.%s (%s) FROM '%s' WITH HEADER = TRUE;",
+				e.Table, strings.Join(e.Columns, ", "), filepath.Base(e.fixtureFile(FormatCSV))))
+		case e.Label != "":
+			nodes = append(nodes, fmt.Sprintf("  --nodes=%s=%s", e.Label, filepath.Base(e.fixtureFile(FormatCSV))))
+		default:
+			rels = append(rels, fmt.Sprintf("  --relationships=%s", filepath.Base(e.fixtureFile(FormatCSV))))
+		}
+	}
+
+	if len(cql) > 0 {
+		content := "-- Jalankan dari direktori ini: cqlsh -f copy.cql\n" + strings.Join(cql, "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "cassandra", "copy.cql"), []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	if len(nodes)+len(rels) > 0 {
+		args := append(nodes, rels...)
+		content := "#!/bin/sh\n# Jalankan dari direktori ini saat database neo4j dalam keadaan berhenti.\n" +
+			"neo4j-admin database import full neo4j --overwrite-destination \\\n" +
+			strings.Join(args, " \\\n") + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "neo4j", "import.sh"), []byte(content), 0o755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ===============================================
+//   IMPORT
+// ===============================================
+
+// Import membaca direktori fixture lalu menulisnya ke store melalui
+// pipeline yang sama dengan seeding (UNWIND untuk Neo4j, batch per
+// partisi untuk Cassandra).
+func Import(ctx context.Context, cfg Config, dir string) (Report, error) {
+	start := time.Now()
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return Report{}, fmt.Errorf("gagal membaca manifest: %v", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Report{}, fmt.Errorf("manifest tidak valid: %v", err)
+	}
+
+	cfg.Seed, cfg.Now = manifest.Seed, manifest.Now
+	report := Report{Config: cfg}
+
+	rows := map[string][]Row{}
+	var selected []Entity
+	for _, entry := range manifest.Entities {
+		e, ok := entityByName(entry.Name)
+		if !ok {
+			return report, fmt.Errorf("entitas %q di manifest tidak dikenal", entry.Name)
+		}
+		if !cfg.Selected(e) {
+			continue
+		}
+
+		fmt.Printf("   -> Reading %s dari %s...\n", e.Name, entry.File)
+		rows[e.Name], err = readFixture(filepath.Join(dir, entry.File), e, manifest.Format)
+		if err != nil {
+			return report, fmt.Errorf("gagal membaca %s: %v", entry.File, err)
+		}
+		selected = append(selected, e)
+	}
+
+	// Ikuti urutan Entities agar node ditulis sebelum relationship
+	sort.SliceStable(selected, func(i, j int) bool { return entityIndex(selected[i]) < entityIndex(selected[j]) })
+
+	closeStores := connectStores(selected)
+	defer closeStores()
+
+	report.Entities = runPipeline(ctx, cfg, func(e Entity) []Row { return rows[e.Name] }, selected, nil)
+	report.Interrupted = ctx.Err() != nil
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+func entityByName(name string) (Entity, bool) {
+	for _, e := range Entities {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Entity{}, false
+}
+
+func entityIndex(e Entity) int {
+	for i, candidate := range Entities {
+		if candidate.Name == e.Name {
+			return i
+		}
+	}
+	return len(Entities)
+}
+
+func readFixture(path string, e Entity, format string) ([]Row, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if format == FormatJSONL {
+		return readJSONL(f, e)
+	}
+	return readCSV(f, e)
+}
+
+func readJSONL(r io.Reader, e Entity) ([]Row, error) {
+	var rows []Row
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	for {
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		row := make(Row, len(obj))
+		for _, field := range e.fields() {
+			v, err := parseJSONValue(field, obj[field])
+			if err != nil {
+				return nil, fmt.Errorf("baris %d kolom %s: %v", len(rows)+1, field, err)
+			}
+			row[field] = v
+		}
+		rows = append(rows, row)
+	}
+}
+
+func readCSV(r io.Reader, e Entity) ([]Row, error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file kosong (header tidak ada)")
+	}
+
+	fields := e.fields()
+	rows := make([]Row, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) < len(fields) {
+			return nil, fmt.Errorf("baris %d: jumlah kolom kurang", i+2)
+		}
+		row := make(Row, len(fields))
+		for j, field := range fields {
+			v, err := parseCSVValue(field, record[j])
+			if err != nil {
+				return nil, fmt.Errorf("baris %d kolom %s: %v", i+2, field, err)
+			}
+			row[field] = v
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseCSVValue(field, s string) (interface{}, error) {
+	switch columnTypes[field] {
+	case "double":
+		return strconv.ParseFloat(s, 64)
+	case "int":
+		return strconv.Atoi(s)
+	case "timestamp":
+		return time.Parse(cqlTimestampLayout, s)
+	case "map<text,int>":
+		m := map[string]int{}
+		body := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}"))
+		if body == "" {
+			return m, nil
+		}
+		for _, pair := range strings.Split(body, ",") {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("entri map tidak valid: %q", pair)
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+			if err != nil {
+				return nil, err
+			}
+			m[strings.Trim(strings.TrimSpace(kv[0]), "'")] = n
+		}
+		return m, nil
+	default:
+		return s, nil
+	}
+}
+
+// parseJSONValue mengubah nilai JSONL ke tipe kolom. Nilai bertipe salah
+// (mis. teks untuk kolom int) ditolak; readJSONL menambahkan nomor baris
+// dan nama kolom ke error-nya.
+func parseJSONValue(field string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch typ := columnTypes[field]; typ {
+	case "double", "int":
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("harus angka (%s), bukan %T", typ, v)
+		}
+		if typ == "double" {
+			return n.Float64()
+		}
+		i, err := n.Int64()
+		return int(i), err
+	case "timestamp":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("harus teks RFC3339, bukan %T", v)
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case "map<text,int>":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("harus objek {teks: angka}, bukan %T", v)
+		}
+		m := map[string]int{}
+		for k, n := range obj {
+			num, ok := n.(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("nilai %s harus angka, bukan %T", k, n)
+			}
+			i, err := num.Int64()
+			if err != nil {
+				return nil, err
+			}
+			m[k] = int(i)
+		}
+		return m, nil
+	default:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprint(v), nil
+	}
+}
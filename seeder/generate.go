@@ -0,0 +1,469 @@
+package seeder
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	faker "github.com/go-faker/faker/v4"
+
+	"src/indonesia"
+	"src/queries"
+	"src/uang"
+)
+
+// Dataset menampung seluruh data hasil generator sebelum ditulis.
+type Dataset struct {
+	Pasien       []Row
+	TenagaMedis  []Row
+	RumahSakit   []Row
+	Departemen   []Row
+	LayananMedis []Row
+	Baymin       []Row
+	Obat         []Row
+
+	PemesananLayanan  []Row
+	KuotaLayanan      []Row
+	LogAktivitas      []Row
+	PemesananObat     []Row
+	DetailPesananObat []Row
+
+	JanjiTemu   []Row
+	Resep       []Row
+	DetailResep []Row
+
+	BekerjaDi          []Row
+	MemilikiDepartemen []Row
+
+	// Penawaran adalah satu rencana pasangan RS-layanan yang menjadi sumber
+	// lokasi_layanan (Cassandra) sekaligus menawarkan_layanan (Neo4j).
+	Penawaran []Row
+}
+
+// generator membungkus sumber random sehingga seluruh data (termasuk
+// faker) dapat direproduksi dari satu seed.
+type generator struct {
+	cfg Config
+	rng *rand.Rand
+	id  *indonesia.Generator // nama, alamat, NIK & telepon berlokal Indonesia
+}
+
+func newGenerator(cfg Config) *generator {
+	faker.SetRandomSource(faker.NewSafeSource(rand.NewSource(cfg.Seed)))
+	faker.SetCryptoSource(rand.New(rand.NewSource(cfg.Seed + 1)))
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	return &generator{cfg: cfg, rng: rng, id: indonesia.New(rng)}
+}
+
+// Generate membangkitkan dataset lengkap dari konfigurasi. Semua entitas
+// selalu dibangkitkan (terlepas dari --only) agar hasilnya tetap sama.
+func Generate(cfg Config) *Dataset {
+	g := newGenerator(cfg)
+	ds := &Dataset{}
+
+	ds.Pasien = g.pasien()
+	ds.TenagaMedis = g.tenagaMedis()
+	ds.RumahSakit = g.rumahSakit()
+	ds.Departemen = g.departemen()
+	ds.LayananMedis = g.layananMedis()
+	ds.Baymin = g.baymin(ds.Pasien)
+	ds.Obat = g.obat()
+
+	ds.Penawaran = g.penawaran(ds.RumahSakit, ds.LayananMedis)
+	ds.PemesananLayanan = g.pemesananLayanan(ds.Pasien, ds.Penawaran)
+	ds.KuotaLayanan = kuotaLayanan(ds.PemesananLayanan)
+	ds.LogAktivitas = g.logAktivitas(ds.Baymin)
+	ds.PemesananObat, ds.DetailPesananObat = g.pemesananObat(ds.Obat, ds.Pasien)
+
+	ds.BekerjaDi = g.bekerjaDi(ds.TenagaMedis, ds.Departemen)
+	ds.MemilikiDepartemen = g.memilikiDepartemen(ds.RumahSakit, ds.Departemen)
+	ds.JanjiTemu, ds.Resep, ds.DetailResep = g.janjiTemu(ds.Pasien, ds.TenagaMedis, ds.Obat, ds.BekerjaDi, ds.MemilikiDepartemen)
+
+	return ds
+}
+
+// ===============================================
+//   HELPER RANDOM
+// ===============================================
+
+func (g *generator) pick(options []string) string {
+	return options[g.rng.Intn(len(options))]
+}
+
+func (g *generator) randomStatusPemesanan() string {
+	return g.pick([]string{"belum dibayar", "dijadwalkan", "sedang berlangsung", "selesai", "dibatalkan"})
+}
+
+func (g *generator) randomLayananEnum() string {
+	return g.pick([]string{"vaksinasi", "fisioterapi", "laboratorium", "radiologi", "konsultasi", "rehabilitasi"})
+}
+
+func (g *generator) randomLabelObat() string {
+	return g.pick([]string{"analgesik", "antibiotik", "obat herbal"})
+}
+
+func (g *generator) shuffled(rows []Row) []Row {
+	out := make([]Row, len(rows))
+	copy(out, rows)
+	g.rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// ===============================================
+//   MASTER DATA
+// ===============================================
+
+// Semua ID dibentuk dari nomor urut (bukan faker) sehingga stabil antar run
+// dan aman di-upsert ulang.
+func (g *generator) pasien() []Row {
+	data := make([]Row, g.cfg.NumPasien)
+	for i := range data {
+		o := g.id.Orang(g.cfg.Now, 0, 90)
+		data[i] = Row{
+			"email":         fmt.Sprintf("pasien%d@mail.com", i+1),
+			"nik":           o.NIK,
+			"kata_sandi":    "pass123",
+			"nama_lengkap":  o.Nama,
+			"jenis_kelamin": o.JenisKelamin,
+			"tanggal_lahir": o.TanggalLahir.Format("2006-01-02"),
+			"nomor_telepon": o.NomorHP,
+			"provinsi":      o.Wilayah.Provinsi,
+			"kota":          o.Wilayah.Kota,
+			"kecamatan":     o.Wilayah.Kecamatan,
+			"jalan":         o.Jalan,
+		}
+	}
+	return data
+}
+
+func (g *generator) tenagaMedis() []Row {
+	data := make([]Row, g.cfg.NumTenagaMedis)
+	professions := []string{"Dokter Umum", "Dokter Spesialis Anak", "Perawat", "Bidan", "Ahli Gizi", "Dokter Gigi"}
+	for i := range data {
+		o := g.id.Orang(g.cfg.Now, 24, 65)
+		profesi := professions[i%len(professions)]
+		data[i] = Row{
+			"email":         fmt.Sprintf("tm%d@rs.com", i+1),
+			"NIKes":         fmt.Sprintf("%08d", g.rng.Intn(99999999)),
+			"nik":           o.NIK,
+			"profesi":       profesi,
+			"kata_sandi":    "docpass",
+			"nama_lengkap":  gelar(profesi, o.Nama),
+			"jenis_kelamin": o.JenisKelamin,
+			"tanggal_lahir": o.TanggalLahir.Format("2006-01-02"),
+			"nomor_telepon": o.NomorHP,
+			"provinsi":      o.Wilayah.Provinsi,
+			"kota":          o.Wilayah.Kota,
+			"kecamatan":     o.Wilayah.Kecamatan,
+			"jalan":         o.Jalan,
+		}
+	}
+	return data
+}
+
+// gelar menambahkan gelar profesi pada nama tenaga medis.
+func gelar(profesi, nama string) string {
+	switch profesi {
+	case "Dokter Umum":
+		return "dr. " + nama
+	case "Dokter Spesialis Anak":
+		return "dr. " + nama + ", Sp.A"
+	case "Dokter Gigi":
+		return "drg. " + nama
+	default:
+		return nama
+	}
+}
+
+func (g *generator) rumahSakit() []Row {
+	data := make([]Row, g.cfg.NumRumahSakit)
+	for i := range data {
+		idRs := fmt.Sprintf("RS%03d", i+1)
+		w := g.id.Wilayah()
+		data[i] = Row{
+			"id_rs":            idRs,
+			"email":            "info@" + idRs + ".com",
+			"nama_rumah_sakit": g.id.NamaRumahSakit(w),
+			"no_telepon":       g.id.TeleponKantor(w),
+			"provinsi":         w.Provinsi,
+			"kota":             w.Kota,
+			"kecamatan":        w.Kecamatan,
+			"jalan":            g.id.Jalan(),
+		}
+	}
+	return data
+}
+
+func (g *generator) departemen() []Row {
+	names := []string{"Poli Umum", "Poli Anak", "Gawat Darurat", "Poli Gigi", "Poli Jantung", "Farmasi"}
+	data := make([]Row, g.cfg.NumDepartemen)
+	for i := range data {
+		data[i] = Row{
+			"nama_departemen": names[i%len(names)] + " " + strconv.Itoa(i+1),
+			"gedung":          "Gedung " + string(rune('A'+i%5)),
+		}
+	}
+	return data
+}
+
+func (g *generator) layananMedis() []Row {
+	data := make([]Row, g.cfg.NumLayanan)
+	for i := range data {
+		data[i] = Row{
+			"id_layanan":    fmt.Sprintf("L%03d", i+1),
+			"nama_layanan":  g.randomLayananEnum(),
+			"biaya_layanan": (uang.Rupiah(g.rng.Intn(400)+100) * 1000 * uang.Rp).Float(), // 100k - 500k
+		}
+	}
+	return data
+}
+
+func (g *generator) baymin(pasien []Row) []Row {
+	colors := []string{"Merah", "Biru", "Hijau", "Kuning", "Putih", "Hitam"}
+	data := make([]Row, 0, len(pasien))
+	for _, p := range pasien {
+		if g.rng.Float64() >= g.cfg.RasioBaymin {
+			continue
+		}
+		data = append(data, Row{
+			"email_pasien": p["email"],
+			"id_perangkat": fmt.Sprintf("BAYMIN-%04d", len(data)+1),
+			"warna":        g.pick(colors),
+		})
+	}
+	return data
+}
+
+func (g *generator) obat() []Row {
+	data := make([]Row, g.cfg.NumObat)
+	for i := range data {
+		data[i] = Row{
+			"id_obat": fmt.Sprintf("O%04d", i+1),
+			"nama":    faker.Word() + " " + faker.Word(),
+			"label":   g.randomLabelObat(),
+			"harga":   (uang.Rupiah(g.rng.Intn(50)+5) * 1000 * uang.Rp).Float(),
+			"stok":    g.rng.Intn(200) + 50,
+		}
+	}
+	return data
+}
+
+// ===============================================
+//   DATA TRANSAKSIONAL (CASSANDRA)
+// ===============================================
+
+// pemesananLayanan memetakan pesanan ke-i ke penawaran ke-(i mod n) secara
+// deterministik (tanpa memakai rng) agar data lain tetap sama untuk seed
+// yang sama.
+func (g *generator) pemesananLayanan(pasien, penawaran []Row) []Row {
+	now := g.cfg.Now
+	data := make([]Row, g.cfg.NumPemesananLayanan)
+	for i := range data {
+		waktuPemesanan := now.Add(time.Duration(g.rng.Intn(1000)) * time.Hour)
+		email := pasien[g.rng.Intn(len(pasien))]["email"]
+		jadwal := waktuPemesanan.Add(time.Duration(g.rng.Intn(72)) * time.Hour) // up to 3 days after pemesanan
+		data[i] = Row{
+			"id_pesanan":         fmt.Sprintf("PL%06d", i+1),
+			"email_pemesan":      email,
+			"waktu_pemesanan":    waktuPemesanan,
+			"jadwal_pelaksanaan": jadwal,
+			"tanggal":            queries.TanggalJadwal(jadwal), // bucket pemesanan_layanan_per_rs
+			"status_pemesanan":   g.randomStatusPemesanan(),
+		}
+		if len(penawaran) > 0 {
+			offer := penawaran[i%len(penawaran)]
+			for _, k := range []string{"id_rs", "id_layanan", "nama_layanan", "biaya_layanan"} {
+				data[i][k] = offer[k]
+			}
+		}
+	}
+	return data
+}
+
+// kuotaLayanan menghitung pesanan yang belum dibatalkan per (RS, layanan,
+// tanggal jadwal) sebagai isi awal kuota_layanan.
+func kuotaLayanan(pesanan []Row) []Row {
+	var data []Row
+	index := map[[3]string]int{}
+	for _, p := range pesanan {
+		idRS, _ := p["id_rs"].(string)
+		if idRS == "" || p["status_pemesanan"] == "dibatalkan" {
+			continue
+		}
+		idLayanan, _ := p["id_layanan"].(string)
+		tanggal := queries.TanggalJadwal(p["jadwal_pelaksanaan"].(time.Time))
+		key := [3]string{idRS, idLayanan, tanggal}
+		if i, ok := index[key]; ok {
+			data[i]["terpakai"] = data[i]["terpakai"].(int) + 1
+			continue
+		}
+		index[key] = len(data)
+		data = append(data, Row{"id_rs": idRS, "id_layanan": idLayanan, "tanggal": tanggal, "terpakai": 1})
+	}
+	return data
+}
+
+func (g *generator) numLayananPerRS() int {
+	return g.cfg.MinLayananPerRS + g.rng.Intn(g.cfg.MaxLayananPerRS-g.cfg.MinLayananPerRS+1)
+}
+
+// penawaran menentukan layanan yang ditawarkan tiap RS. Hasilnya dipakai
+// untuk lokasi_layanan dan relationship menawarkan_layanan sekaligus.
+func (g *generator) penawaran(rsData, layananData []Row) []Row {
+	var data []Row
+	for _, rs := range rsData {
+		offered := g.shuffled(layananData)
+		n := g.numLayananPerRS()
+		for i := 0; i < n && i < len(offered); i++ {
+			data = append(data, Row{
+				"id_rs":         rs["id_rs"],
+				"id_layanan":    offered[i]["id_layanan"],
+				"nama_layanan":  offered[i]["nama_layanan"],
+				"biaya_layanan": offered[i]["biaya_layanan"],
+			})
+		}
+	}
+	return data
+}
+
+// logAktivitas hanya memakai perangkat yang dimiliki pasien.
+func (g *generator) logAktivitas(bayminData []Row) []Row {
+	var owned []Row
+	for _, b := range bayminData {
+		if b["email_pasien"] != nil {
+			owned = append(owned, b)
+		}
+	}
+	if len(owned) == 0 {
+		return nil
+	}
+
+	now := g.cfg.Now
+	data := make([]Row, g.cfg.NumLogAktivitas)
+	for i := range data {
+		data[i] = Row{
+			"id_perangkat":     owned[g.rng.Intn(len(owned))]["id_perangkat"],
+			"waktu_aktivitas":  now.Add(-time.Duration(i+1) * time.Hour),
+			"detail_aktivitas": "Status perangkat: " + faker.Sentence(),
+		}
+	}
+	return data
+}
+
+func (g *generator) pemesananObat(obatData, pasien []Row) ([]Row, []Row) {
+	now := g.cfg.Now
+	pesanan := make([]Row, g.cfg.NumPemesananObat)
+	detail := make([]Row, g.cfg.NumPemesananObat)
+	for i := range pesanan {
+		poID := fmt.Sprintf("POB%05d", i+1)
+
+		daftarObat := make(map[string]int)
+		harga := make(map[string]uang.Rupiah)
+		for j := 0; j < g.cfg.ObatPerPesanan; j++ {
+			o := obatData[g.rng.Intn(len(obatData))]
+			daftarObat[o["id_obat"].(string)] = g.rng.Intn(5) + 1
+			harga[o["id_obat"].(string)] = uang.DariFloat(o["harga"].(float64))
+		}
+		var total uang.Rupiah
+		for id, jumlah := range daftarObat {
+			total += harga[id].Kali(jumlah)
+		}
+
+		// Sebagian masa lalu (untuk testing update1), sebagian masa depan
+		var waktuPemesanan time.Time
+		if g.rng.Float64() < g.cfg.RasioPesananLampau {
+			waktuPemesanan = now.Add(-time.Duration(g.rng.Intn(30)*24) * time.Hour) // 0-30 hari yang lalu
+		} else {
+			waktuPemesanan = now.Add(time.Duration(g.rng.Intn(7)*24) * time.Hour) // 0-7 hari ke depan
+		}
+
+		pesanan[i] = Row{
+			"id_pesanan":       poID,
+			"email_pemesan":    pasien[g.rng.Intn(len(pasien))]["email"],
+			"waktu_pemesanan":  waktuPemesanan,
+			"status_pemesanan": g.randomStatusPemesanan(),
+			"total_harga":      total.Float(),
+		}
+		detail[i] = Row{"id_pesanan": poID, "daftar_obat": daftarObat}
+	}
+	return pesanan, detail
+}
+
+// ===============================================
+//   RELATIONSHIPS & TRANSAKSI (NEO4J)
+// ===============================================
+
+func (g *generator) bekerjaDi(tenagaMedis, departemen []Row) []Row {
+	data := make([]Row, len(tenagaMedis))
+	for i, tm := range tenagaMedis {
+		data[i] = Row{"email_tm": tm["email"], "nama_dept": departemen[i%len(departemen)]["nama_departemen"]}
+	}
+	return data
+}
+
+func (g *generator) memilikiDepartemen(rsData, departemen []Row) []Row {
+	data := make([]Row, len(departemen))
+	for i, dept := range departemen {
+		data[i] = Row{"id_rs": rsData[i%len(rsData)]["id_rs"], "nama_dept": dept["nama_departemen"]}
+	}
+	return data
+}
+
+// janjiTemu membuat JanjiTemu beserta Resep/DetailResep. Rumah sakit janji
+// temu adalah rumah sakit tempat dokter bekerja (via departemennya), dan
+// DetailResep selalu menunjuk obat yang ada di katalog.
+func (g *generator) janjiTemu(pasien, tenagaMedis, obatData, bekerjaDi, memilikiDepartemen []Row) ([]Row, []Row, []Row) {
+	dosisOptions := []string{"1x Sehari", "2x Sehari", "3x Sehari"}
+
+	rsDepartemen := make(map[interface{}]interface{}, len(memilikiDepartemen))
+	for _, r := range memilikiDepartemen {
+		rsDepartemen[r["nama_dept"]] = r["id_rs"]
+	}
+	rsDokter := make(map[interface{}]interface{}, len(bekerjaDi))
+	for _, r := range bekerjaDi {
+		rsDokter[r["email_tm"]] = rsDepartemen[r["nama_dept"]]
+	}
+
+	janji := make([]Row, g.cfg.NumJanjiTemu)
+	var resep, detail []Row
+
+	for i := range janji {
+		offsetDays := g.rng.Intn(730) - 365 // -365..+364
+		jtID := fmt.Sprintf("JT%05d", i+1)
+		status := g.randomStatusPemesanan()
+		dokter := tenagaMedis[g.rng.Intn(len(tenagaMedis))]
+
+		janji[i] = Row{
+			"id_janji_temu":     jtID,
+			"waktu_pelaksanaan": g.cfg.Now.Add(time.Duration(offsetDays*24) * time.Hour).Format("2006-01-02 15:04:05"),
+			"alasan":            g.id.Keluhan(),
+			"status":            status,
+			"p_email":           pasien[g.rng.Intn(len(pasien))]["email"],
+			"t_email":           dokter["email"],
+			"id_rs":             rsDokter[dokter["email"]],
+		}
+
+		if !strings.EqualFold(status, "selesai") || len(obatData) == 0 {
+			continue
+		}
+
+		resepID := fmt.Sprintf("R%05d", i+1)
+		resep = append(resep, Row{
+			"id_resep":      resepID,
+			"penyakit":      g.id.Diagnosis(),
+			"id_janji_temu": jtID,
+		})
+
+		for _, obat := range g.shuffled(obatData)[:min(g.cfg.ObatPerResep, len(obatData))] {
+			detail = append(detail, Row{
+				"id_detail_resep": resepID + "-" + obat["id_obat"].(string),
+				"id_resep":        resepID,
+				"id_obat":         obat["id_obat"],
+				"dosis":           g.pick(dosisOptions),
+			})
+		}
+	}
+	return janji, resep, detail
+}
@@ -0,0 +1,415 @@
+package seeder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// ===============================================
+//   PIPELINE: generator -> antrian -> worker pool
+// ===============================================
+//
+// Setiap entitas dipecah menjadi job berisi BatchSize baris. Job dikirim ke
+// antrian berkapasitas terbatas milik store-nya (backpressure: producer
+// menunggu bila antrian penuh) dan dikerjakan oleh worker pool store itu.
+// Node Neo4j dan tabel Cassandra ditulis bersamaan pada fase pertama;
+// relationship Neo4j menunggu seluruh node selesai.
+//
+// Batch yang seluruh barisnya tertulis dicatat ke checkpoint sehingga run
+// yang terputus (Ctrl+C, crash) dapat dilanjutkan dengan --resume.
+
+// task melacak progres satu entitas.
+type task struct {
+	entity Entity
+	rows   []Row
+	query  string
+	skip   map[int]bool // indeks batch yang sudah selesai di run sebelumnya
+
+	skipped int          // baris yang dilewati karena sudah ada di checkpoint
+	done    atomic.Int64 // baris yang sudah diproses (berhasil + gagal)
+	written atomic.Int64
+	failed  atomic.Int64
+	retries atomic.Int64
+	errs    errorLog
+
+	pending sync.WaitGroup // job yang belum selesai
+	mu      sync.Mutex
+	start   time.Time
+	end     time.Time
+}
+
+func (t *task) started() (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.start, !t.start.IsZero()
+}
+
+func (t *task) finished() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.end.IsZero()
+}
+
+type job struct {
+	task  *task
+	index int // indeks batch dalam entitas
+	rows  []Row
+}
+
+// pool adalah sekumpulan worker yang mengambil job dari satu antrian.
+type pool struct {
+	jobs chan job
+	wg   sync.WaitGroup
+}
+
+func newPool(workers, queue int, exec func(job)) *pool {
+	p := &pool{jobs: make(chan job, queue)}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for j := range p.jobs {
+				exec(j)
+				j.task.pending.Done()
+			}
+		}()
+	}
+	return p
+}
+
+func (p *pool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+type pipeline struct {
+	ctx   context.Context
+	cfg   Config
+	cp    *checkpoint
+	pools map[string]*pool
+}
+
+// runPipeline menulis semua entitas dan mengembalikan statistiknya sesuai
+// urutan entitas. rowsFor menyediakan baris tiap entitas (hasil generator
+// atau hasil membaca fixture). cp boleh nil bila progres tidak perlu
+// dicatat. Bila ctx dibatalkan, batch yang sedang berjalan diselesaikan
+// dan sisanya tidak dikirim.
+func runPipeline(ctx context.Context, cfg Config, rowsFor func(Entity) []Row, entities []Entity, cp *checkpoint) []EntityStats {
+	p := &pipeline{ctx: ctx, cfg: cfg, cp: cp, pools: map[string]*pool{}}
+
+	queue := func(workers int) int {
+		if cfg.QueueSize > 0 {
+			return cfg.QueueSize
+		}
+		return workers * 2
+	}
+	p.pools[StoreCassandra] = newPool(cfg.CassandraWorkers, queue(cfg.CassandraWorkers), p.checkpointed(p.execCassandra))
+	p.pools[StoreNeo4j] = newPool(cfg.Neo4jWorkers, queue(cfg.Neo4jWorkers), p.checkpointed(p.execNeo4j))
+
+	tasks := make([]*task, len(entities))
+	for i, e := range entities {
+		t := &task{entity: e, rows: rowsFor(e), query: e.Query()}
+		t.skip = cp.completed(e.Name, len(t.rows))
+		for b := range t.skip {
+			t.skipped += min(cfg.BatchSize, len(t.rows)-b*cfg.BatchSize)
+		}
+		tasks[i] = t
+	}
+
+	stopProgress := startProgress(tasks, cfg.ProgressInterval)
+
+	for _, phase := range [][]*task{filterTasks(tasks, false), filterTasks(tasks, true)} {
+		var wg sync.WaitGroup
+		for _, t := range phase {
+			wg.Add(1)
+			go func(t *task) {
+				defer wg.Done()
+				p.produce(t)
+			}(t)
+		}
+		wg.Wait()
+	}
+
+	stopProgress()
+	for _, pl := range p.pools {
+		pl.close()
+	}
+
+	stats := make([]EntityStats, len(tasks))
+	for i, t := range tasks {
+		stats[i] = EntityStats{
+			Entity:   t.entity,
+			Rows:     len(t.rows),
+			Skipped:  t.skipped,
+			Written:  int(t.written.Load()),
+			Failed:   int(t.failed.Load()),
+			Retries:  int(t.retries.Load()),
+			Errors:   t.errs.samples(),
+			Duration: t.end.Sub(t.start),
+		}
+	}
+	return stats
+}
+
+func filterTasks(tasks []*task, relationships bool) []*task {
+	var out []*task
+	for _, t := range tasks {
+		if (t.entity.RelType != "") == relationships {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// produce memecah baris entitas menjadi job lalu menunggu semuanya selesai.
+func (p *pipeline) produce(t *task) {
+	t.mu.Lock()
+	t.start = time.Now()
+	t.mu.Unlock()
+
+	jobs := p.pools[t.entity.Store].jobs
+produce:
+	for i, batch := 0, 0; i < len(t.rows); i, batch = i+p.cfg.BatchSize, batch+1 {
+		if p.ctx.Err() != nil {
+			break
+		}
+		if t.skip[batch] {
+			continue
+		}
+		end := min(i+p.cfg.BatchSize, len(t.rows))
+		t.pending.Add(1)
+		select {
+		case jobs <- job{task: t, index: batch, rows: t.rows[i:end]}: // blok bila antrian penuh
+		case <-p.ctx.Done():
+			t.pending.Done()
+			break produce
+		}
+	}
+	t.pending.Wait()
+
+	t.mu.Lock()
+	t.end = time.Now()
+	t.mu.Unlock()
+}
+
+// checkpointed mencatat batch ke checkpoint bila tidak ada baris yang gagal.
+func (p *pipeline) checkpointed(exec func(job) int) func(job) {
+	return func(j job) {
+		if exec(j) == 0 {
+			p.cp.markBatch(j.task.entity.Name, j.index)
+		}
+	}
+}
+
+// maxCassandraBatch membatasi jumlah statement per batch agar tetap di
+// bawah batch_size_fail_threshold Cassandra.
+const maxCassandraBatch = 50
+
+// execCassandra mengelompokkan baris per partition key. Baris dalam
+// partisi yang sama ditulis dengan satu UNLOGGED batch; sisanya ditulis
+// per baris karena batch lintas partisi tidak memberi keuntungan.
+// Mengembalikan jumlah baris yang gagal.
+func (p *pipeline) execCassandra(j job) int {
+	t := j.task
+	var order []interface{}
+	groups := map[interface{}][][]interface{}{}
+	for _, row := range j.rows {
+		key := row[t.entity.PartitionKey]
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], t.entity.Args(row))
+	}
+
+	insert := func(args []interface{}) error {
+		return p.retry(t, cassandra.IsTransient, func() error {
+			return cassandra.InsertCassandra(t.query, args...)
+		})
+	}
+
+	failed := 0
+	for _, key := range order {
+		rows := groups[key]
+		for len(rows) > 0 {
+			chunk := rows[:min(maxCassandraBatch, len(rows))]
+			rows = rows[len(chunk):]
+
+			if len(chunk) == 1 {
+				failed += t.record(insert(chunk[0]), 1)
+				continue
+			}
+			err := p.retry(t, cassandra.IsTransient, func() error {
+				return cassandra.BatchInsertCassandra(t.query, chunk)
+			})
+			if err == nil {
+				t.record(nil, len(chunk))
+				continue
+			}
+			for _, args := range chunk {
+				failed += t.record(insert(args), 1)
+			}
+		}
+	}
+	return failed
+}
+
+// execNeo4j menulis satu batch dalam satu transaksi. Bila batch gagal
+// karena error permanen (mis. pelanggaran constraint), batch diulang per
+// baris agar baris yang valid tetap tertulis. Mengembalikan jumlah baris
+// yang gagal.
+func (p *pipeline) execNeo4j(j job) int {
+	t := j.task
+	write := func(rows []Row) error {
+		return p.retry(t, neo4j.IsTransient, func() error {
+			return neo4j.WriteBatchNeo4j(t.query, rows)
+		})
+	}
+
+	err := write(j.rows)
+	if err == nil || len(j.rows) == 1 {
+		return t.record(err, len(j.rows))
+	}
+	failed := 0
+	for _, row := range j.rows {
+		failed += t.record(write([]Row{row}), 1)
+	}
+	return failed
+}
+
+// retry menjalankan fn dengan exponential backoff selama error bersifat
+// sementara dan batas percobaan belum habis.
+func (p *pipeline) retry(t *task, transient func(error) bool, fn func() error) error {
+	delay := p.cfg.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.cfg.MaxRetries || !transient(err) {
+			return err
+		}
+		t.retries.Add(1)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// record mencatat hasil n baris dan mengembalikan jumlah yang gagal.
+func (t *task) record(err error, n int) int {
+	t.done.Add(int64(n))
+	if err != nil {
+		t.failed.Add(int64(n))
+		t.errs.add(err)
+		return n
+	}
+	t.written.Add(int64(n))
+	return 0
+}
+
+// ===============================================
+//   ERROR LOG
+// ===============================================
+
+const maxErrorSamples = 3
+
+// errorLog menghitung error per pesan dan menyimpan beberapa contoh.
+type errorLog struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (l *errorLog) add(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counts == nil {
+		l.counts = map[string]int{}
+	}
+	l.counts[err.Error()]++
+}
+
+// samples mengembalikan pesan error terbanyak beserta jumlahnya.
+func (l *errorLog) samples() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	msgs := make([]string, 0, len(l.counts))
+	for msg := range l.counts {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if l.counts[msgs[i]] != l.counts[msgs[j]] {
+			return l.counts[msgs[i]] > l.counts[msgs[j]]
+		}
+		return msgs[i] < msgs[j]
+	})
+
+	out := make([]string, 0, maxErrorSamples)
+	for i := 0; i < len(msgs) && i < maxErrorSamples; i++ {
+		out = append(out, fmt.Sprintf("%dx %s", l.counts[msgs[i]], msgs[i]))
+	}
+	return out
+}
+
+// ===============================================
+//   PROGRESS
+// ===============================================
+
+// startProgress mencetak rows/s dan ETA per entitas yang sedang berjalan
+// secara berkala. Fungsi yang dikembalikan menghentikan reporter.
+func startProgress(tasks []*task, interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				printProgress(tasks)
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		wg.Wait()
+	}
+}
+
+func printProgress(tasks []*task) {
+	var lines []string
+	for _, t := range tasks {
+		total := int64(len(t.rows) - t.skipped)
+		start, ok := t.started()
+		if !ok || t.finished() || total == 0 {
+			continue
+		}
+
+		done := t.done.Load()
+		elapsed := time.Since(start).Seconds()
+		rate := float64(done) / elapsed
+		eta := "-"
+		if rate > 0 {
+			eta = time.Duration(float64(total-done) / rate * float64(time.Second)).Round(time.Second).String()
+		}
+
+		lines = append(lines, fmt.Sprintf("   [%s] %-20s %8d/%-8d %5.1f%% %8.0f rows/s  ETA %s",
+			t.entity.Store, t.entity.Name, done, total, float64(done)*100/float64(total), rate, eta))
+	}
+	if len(lines) > 0 {
+		fmt.Println(strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,180 @@
+package seeder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// ===============================================
+//   RESET (--reset)
+// ===============================================
+//
+// Reset hanya menghapus data yang ditulis seeder: dataset run sebelumnya
+// dibangkitkan ulang dari checkpoint (seed, waktu acuan, jumlah data) dan
+// barisnya dihapus per key. Data yang dibuat aplikasi (API, simulator,
+// booking) di tabel/label yang sama tidak ikut terhapus.
+
+// resetBatchSize membatasi jumlah node/relationship yang dihapus per
+// transaksi agar tidak menghabiskan memori heap Neo4j.
+const resetBatchSize = 5000
+
+// Reset menghapus baris Cassandra dan node/relationship Neo4j hasil
+// seeding sebelumnya untuk entitas yang dipilih (--only dihormati). Dataset
+// yang dihapus diambil dari checkpoint; tanpa checkpoint, cfg dipakai
+// sehingga --seed (dan --now serta jumlah data) harus sama dengan run
+// sebelumnya. Setelahnya entitas tersebut dihapus dari checkpoint.
+func Reset(cfg Config) error {
+	saved, err := loadState(cfg.StateFile)
+	if err != nil {
+		return err
+	}
+	target := cfg
+	switch {
+	case saved != nil:
+		if len(cfg.Only) > 0 && cfg.Seed != 0 && cfg.Seed != saved.Config.Seed {
+			return fmt.Errorf("--reset --only hanya menghapus sebagian data seed %d; seed berbeda (%d) butuh --reset tanpa --only", saved.Config.Seed, cfg.Seed)
+		}
+		target = saved.Config
+	case cfg.Seed == 0:
+		return fmt.Errorf("checkpoint %s tidak ada; --reset butuh --seed, --now dan jumlah data yang sama dengan run sebelumnya", cfg.StateFile)
+	default:
+		target.ResolveSeed()
+	}
+
+	var selected []Entity
+	for _, e := range Entities {
+		if cfg.Selected(e) {
+			selected = append(selected, e)
+		}
+	}
+	closeStores := connectStores(selected)
+	defer closeStores()
+
+	fmt.Printf("Reset data seeding (seed %d, now %s)...\n", target.Seed, target.Now.Format(time.RFC3339))
+	ds := Generate(target)
+	labels := map[string]bool{}
+	for _, e := range selected {
+		if e.Label != "" {
+			labels[e.Label] = true
+		}
+	}
+
+	for _, e := range selected {
+		rows := e.Rows(ds)
+		switch {
+		case e.Table != "":
+			n, err := deleteRows(e, rows, cfg.CassandraWorkers)
+			if err != nil {
+				return fmt.Errorf("gagal menghapus baris %s: %v", e.Table, err)
+			}
+			fmt.Printf("   -> %d baris rumahsakit.%s dihapus\n", n, e.Table)
+
+		case e.Label != "":
+			keys := make([]map[string]interface{}, len(rows))
+			for i, row := range rows {
+				keys[i] = map[string]interface{}{"key": row[e.Key]}
+			}
+			n, err := deleteInBatches(fmt.Sprintf("UNWIND $rows AS row MATCH (n:%s {%s: row.key}) DETACH DELETE n RETURN count(*) AS deleted", e.Label, e.Key), keys)
+			if err != nil {
+				return fmt.Errorf("gagal menghapus node %s: %v", e.Label, err)
+			}
+			fmt.Printf("   -> %d node %s dihapus\n", n, e.Label)
+
+		default:
+			// Relationship ikut terhapus bila salah satu node ujungnya dihapus
+			if labels[e.From.Label] || labels[e.To.Label] {
+				continue
+			}
+			n, err := deleteInBatches(fmt.Sprintf("UNWIND $rows AS row MATCH (:%s {%s: row.%s})-[r:%s]->(:%s {%s: row.%s}) DELETE r RETURN count(*) AS deleted",
+				e.From.Label, e.From.Key, e.From.Field, e.RelType, e.To.Label, e.To.Key, e.To.Field), rows)
+			if err != nil {
+				return fmt.Errorf("gagal menghapus relationship %s: %v", e.RelType, err)
+			}
+			fmt.Printf("   -> %d relationship %s dihapus\n", n, e.RelType)
+		}
+	}
+
+	if saved == nil {
+		return nil
+	}
+	if len(cfg.Only) == 0 {
+		if err := os.Remove(cfg.StateFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("gagal menghapus checkpoint %s: %v", cfg.StateFile, err)
+		}
+		return nil
+	}
+	cp := &checkpoint{path: cfg.StateFile, state: *saved}
+	cp.forget(selected)
+	if err := cp.save(); err != nil {
+		return fmt.Errorf("gagal memperbarui checkpoint %s: %v", cfg.StateFile, err)
+	}
+	return nil
+}
+
+// deleteRows menghapus baris Cassandra per primary key dengan beberapa
+// worker sekaligus.
+func deleteRows(e Entity, rows []Row, workers int) (int, error) {
+	where := make([]string, len(e.PrimaryKey))
+	for i, col := range e.PrimaryKey {
+		where[i] = col + " = ?"
+	}
+	query := fmt.Sprintf("DELETE FROM rumahsakit.%s WHERE %s", e.Table, strings.Join(where, " AND "))
+
+	jobs := make(chan Row)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		deleted  int
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				args := make([]interface{}, len(e.PrimaryKey))
+				for i, col := range e.PrimaryKey {
+					args[i] = row[col]
+				}
+				err := cassandra.DeleteCassandra(query, args...)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				} else if err == nil {
+					deleted++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, row := range rows {
+		jobs <- row
+	}
+	close(jobs)
+	wg.Wait()
+	return deleted, firstErr
+}
+
+// deleteInBatches menjalankan query hapus untuk rows per resetBatchSize,
+// masing-masing dalam transaksi sendiri. Query menerima $rows.
+func deleteInBatches(query string, rows []map[string]interface{}) (int64, error) {
+	var total int64
+	for start := 0; start < len(rows); start += resetBatchSize {
+		end := min(start+resetBatchSize, len(rows))
+		records, err := neo4j.CreateAndReturnNeo4j(query, map[string]interface{}{"rows": rows[start:end]})
+		if err != nil {
+			return total, err
+		}
+		if len(records) > 0 {
+			deleted, _ := records[0]["deleted"].(int64)
+			total += deleted
+		}
+	}
+	return total, nil
+}
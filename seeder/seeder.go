@@ -0,0 +1,175 @@
+package seeder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+)
+
+// EntityStats mencatat hasil penulisan satu entitas.
+type EntityStats struct {
+	Entity   Entity
+	Rows     int
+	Skipped  int // sudah tertulis di run sebelumnya (--resume)
+	Written  int
+	Failed   int
+	Retries  int
+	Errors   []string // contoh pesan error terbanyak
+	Duration time.Duration
+}
+
+// Report adalah ringkasan satu kali proses seeding.
+type Report struct {
+	Config      Config
+	Entities    []EntityStats
+	Duration    time.Duration
+	Interrupted bool // dihentikan sebelum selesai (mis. Ctrl+C)
+}
+
+// Failed mengembalikan total baris yang gagal ditulis.
+func (r Report) Failed() int {
+	total := 0
+	for _, s := range r.Entities {
+		total += s.Failed
+	}
+	return total
+}
+
+// Run membangkitkan dataset dan menulis entitas yang dipilih ke store.
+// Progres dicatat ke cfg.StateFile; dengan cfg.Resume, konfigurasi dataset
+// diambil dari checkpoint dan batch yang sudah selesai dilewati. Tanpa
+// --resume, seed dan waktu acuan checkpoint dipakai ulang bila --seed
+// kosong, dan seed lain ditolak (lihat Config.reuse). Bila ctx
+// dibatalkan, Run berhenti setelah batch yang sedang berjalan selesai.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	start := time.Now()
+
+	var cp *checkpoint
+	if cfg.Resume {
+		var err error
+		if cp, err = loadCheckpoint(cfg.StateFile); err != nil {
+			return Report{Config: cfg}, err
+		}
+		cfg = resumeConfig(cfg, cp.state.Config)
+		fmt.Printf("Melanjutkan run dari %s (dimulai %s)\n", cfg.StateFile, cp.state.Started.Format(time.RFC3339))
+	} else {
+		saved, err := loadState(cfg.StateFile)
+		if err != nil {
+			return Report{Config: cfg}, err
+		}
+		if saved != nil {
+			if err := cfg.reuse(saved.Config); err != nil {
+				return Report{Config: cfg}, err
+			}
+		}
+		cfg.ResolveSeed()
+		cp = newCheckpoint(cfg.StateFile, cfg)
+	}
+	report := Report{Config: cfg}
+
+	fmt.Printf("Generating dataset (seed %d)...\n", cfg.Seed)
+	ds := Generate(cfg)
+
+	var selected []Entity
+	for _, e := range Entities {
+		if cfg.Selected(e) {
+			selected = append(selected, e)
+		}
+	}
+
+	closeStores := connectStores(selected)
+	defer closeStores()
+
+	fmt.Printf("Seeding %d entitas (cassandra workers: %d, neo4j workers: %d, batch: %d)...\n",
+		len(selected), cfg.CassandraWorkers, cfg.Neo4jWorkers, cfg.BatchSize)
+	saveCheckpoint := cp.autosave(time.Second)
+	report.Entities = runPipeline(ctx, cfg, func(e Entity) []Row { return e.Rows(ds) }, selected, cp)
+	report.Interrupted = ctx.Err() != nil
+	if err := saveCheckpoint(); err != nil {
+		fmt.Println("   [WARN] gagal menyimpan checkpoint:", err)
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// connectStores membuka koneksi hanya ke store yang dipakai entitas.
+// Fungsi yang dikembalikan menutup koneksi tersebut.
+func connectStores(entities []Entity) func() {
+	needs := map[string]bool{}
+	for _, e := range entities {
+		needs[e.Store] = true
+	}
+
+	if needs[StoreCassandra] {
+		cassandra.InitCassandra()
+	}
+	if needs[StoreNeo4j] {
+		neo4j.InitNeo4j()
+	}
+	return func() {
+		if needs[StoreCassandra] {
+			cassandra.Close()
+		}
+		if needs[StoreNeo4j] {
+			neo4j.CloseNeo4j()
+		}
+	}
+}
+
+// Print mencetak ringkasan hasil seeding dalam bentuk tabel, diikuti
+// ringkasan error per entitas bila ada.
+func (r Report) Print() {
+	fmt.Println("\n" + strings.Repeat("=", 100))
+	fmt.Println("     RINGKASAN SEEDING")
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("%-22s %-10s %9s %9s %9s %8s %7s %10s %9s\n", "Entitas", "Store", "Rows", "Dilewati", "Ditulis", "Gagal", "Retry", "Durasi", "Rows/s")
+	fmt.Println(strings.Repeat("-", 100))
+
+	var rows, skipped, written, failed, retries int
+	for _, s := range r.Entities {
+		fmt.Printf("%-22s %-10s %9d %9d %9d %8d %7d %10s %9.0f\n",
+			s.Entity.Name, s.Entity.Store, s.Rows, s.Skipped, s.Written, s.Failed, s.Retries,
+			s.Duration.Round(time.Millisecond), rate(s.Written+s.Failed, s.Duration))
+		rows += s.Rows
+		skipped += s.Skipped
+		written += s.Written
+		failed += s.Failed
+		retries += s.Retries
+	}
+
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-22s %-10s %9d %9d %9d %8d %7d %10s %9.0f\n", "TOTAL", "", rows, skipped, written, failed, retries,
+		r.Duration.Round(time.Millisecond), rate(written+failed, r.Duration))
+	fmt.Println(strings.Repeat("=", 100))
+
+	if failed > 0 {
+		fmt.Println("\nRINGKASAN ERROR")
+		for _, s := range r.Entities {
+			if s.Failed == 0 {
+				continue
+			}
+			fmt.Printf("  %s (%d gagal):\n", s.Entity.Name, s.Failed)
+			for _, msg := range s.Errors {
+				fmt.Printf("    - %s\n", msg)
+			}
+		}
+	}
+
+	if r.Interrupted {
+		fmt.Printf("\nSeeding dihentikan sebelum selesai. Lanjutkan dengan: --resume --state %s\n", r.Config.StateFile)
+	}
+	fmt.Printf("\nSeed: %d  Now: %s\n", r.Config.Seed, r.Config.Now.Format(time.RFC3339))
+	fmt.Printf("Ulangi dataset yang sama dengan: --seed %d --now %s\n\n", r.Config.Seed, r.Config.Now.Format(time.RFC3339))
+}
+
+func rate(n int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
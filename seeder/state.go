@@ -0,0 +1,210 @@
+package seeder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ===============================================
+//   CHECKPOINT (--resume)
+// ===============================================
+//
+// Progres disimpan per entitas sebagai daftar indeks batch yang seluruh
+// barisnya sudah tertulis. Karena generator deterministik dan penulisan
+// bersifat upsert (INSERT Cassandra, MERGE Neo4j), run yang terputus bisa
+// dilanjutkan dengan membangkitkan dataset yang sama lalu melewati batch
+// yang sudah selesai.
+
+// State adalah isi file checkpoint.
+type State struct {
+	Config   Config                  `json:"config"`
+	Started  time.Time               `json:"started"`
+	Updated  time.Time               `json:"updated"`
+	Entities map[string]*EntityState `json:"entities"`
+}
+
+// EntityState adalah progres satu entitas.
+type EntityState struct {
+	Rows    int   `json:"rows"`
+	Batches []int `json:"batches"` // indeks batch yang sudah tertulis penuh
+}
+
+// Done melaporkan apakah semua batch entitas sudah tertulis.
+func (s *EntityState) Done(batchSize int) bool {
+	return len(s.Batches) >= (s.Rows+batchSize-1)/batchSize
+}
+
+// checkpoint menyimpan State ke disk secara berkala selama pipeline berjalan.
+type checkpoint struct {
+	path  string
+	mu    sync.Mutex
+	state State
+	dirty bool
+}
+
+func newCheckpoint(path string, cfg Config) *checkpoint {
+	return &checkpoint{path: path, state: State{
+		Config:   cfg,
+		Started:  time.Now(),
+		Entities: map[string]*EntityState{},
+	}}
+}
+
+// loadCheckpoint membaca file checkpoint run sebelumnya.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca checkpoint %s: %w", path, err)
+	}
+	cp := &checkpoint{path: path}
+	if err := json.Unmarshal(data, &cp.state); err != nil {
+		return nil, fmt.Errorf("checkpoint %s tidak valid: %v", path, err)
+	}
+	if cp.state.Entities == nil {
+		cp.state.Entities = map[string]*EntityState{}
+	}
+	return cp, nil
+}
+
+// loadState membaca file checkpoint bila ada; nil berarti belum pernah ada
+// run yang tercatat.
+func loadState(path string) (*State, error) {
+	cp, err := loadCheckpoint(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp.state, nil
+}
+
+// resumeConfig mengambil seed, waktu acuan, jumlah data dan batch size dari
+// checkpoint agar dataset dan pembagian batch sama persis. Pengaturan
+// pipeline (worker, retry, --only) tetap dari cfg.
+func resumeConfig(cfg, saved Config) Config {
+	out := saved
+	out.Only = cfg.Only
+	out.CassandraWorkers = cfg.CassandraWorkers
+	out.Neo4jWorkers = cfg.Neo4jWorkers
+	out.QueueSize = cfg.QueueSize
+	out.MaxRetries = cfg.MaxRetries
+	out.RetryDelay = cfg.RetryDelay
+	out.ProgressInterval = cfg.ProgressInterval
+	out.StateFile = cfg.StateFile
+	out.Resume = cfg.Resume
+	return out
+}
+
+// completed mengembalikan batch entitas yang sudah selesai.
+func (c *checkpoint) completed(entity string, rows int) map[int]bool {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	es, ok := c.state.Entities[entity]
+	if !ok || es.Rows != rows {
+		c.state.Entities[entity] = &EntityState{Rows: rows}
+		c.dirty = true
+		return nil
+	}
+	done := make(map[int]bool, len(es.Batches))
+	for _, b := range es.Batches {
+		done[b] = true
+	}
+	return done
+}
+
+// markBatch mencatat batch yang seluruh barisnya berhasil ditulis.
+func (c *checkpoint) markBatch(entity string, batch int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	es := c.state.Entities[entity]
+	es.Batches = append(es.Batches, batch)
+	c.dirty = true
+}
+
+// forget menghapus progres entitas (setelah --reset --only) agar --resume
+// menulisnya ulang.
+func (c *checkpoint) forget(entities []Entity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range entities {
+		delete(c.state.Entities, e.Name)
+	}
+	c.dirty = true
+}
+
+// save menulis checkpoint secara atomik (file sementara lalu rename).
+func (c *checkpoint) save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	for _, es := range c.state.Entities {
+		sort.Ints(es.Batches)
+	}
+	c.state.Updated = time.Now()
+	data, err := json.MarshalIndent(c.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// autosave menyimpan checkpoint setiap interval sampai fungsi yang
+// dikembalikan dipanggil, lalu menyimpan sekali lagi.
+func (c *checkpoint) autosave(interval time.Duration) func() error {
+	if c == nil {
+		return func() error { return nil }
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := c.save(); err != nil {
+					fmt.Println("   [WARN] gagal menyimpan checkpoint:", err)
+				}
+			}
+		}
+	}()
+
+	return func() error {
+		close(stop)
+		wg.Wait()
+		return c.save()
+	}
+}
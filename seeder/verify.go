@@ -0,0 +1,326 @@
+package seeder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"src/cassandra"
+	"src/indonesia"
+	"src/neo4j"
+)
+
+// ===============================================
+//   VERIFIKASI KONSISTENSI LINTAS STORE
+// ===============================================
+
+const maxViolationSamples = 5
+
+// Check adalah hasil satu aturan konsistensi.
+type Check struct {
+	Name       string
+	Checked    int      // jumlah data yang diperiksa
+	Violations int      // jumlah data yang melanggar
+	Samples    []string // contoh pelanggaran
+	Err        error    // error saat membaca data
+}
+
+// OK melaporkan apakah aturan terpenuhi.
+func (c Check) OK() bool {
+	return c.Err == nil && c.Violations == 0
+}
+
+func (c *Check) violate(format string, args ...interface{}) {
+	c.Violations++
+	if len(c.Samples) < maxViolationSamples {
+		c.Samples = append(c.Samples, fmt.Sprintf(format, args...))
+	}
+}
+
+// Verify memeriksa bahwa data di Cassandra dan Neo4j saling mereferensikan
+// dengan benar. Koneksi ke kedua store dibuka dan ditutup di sini.
+func Verify() []Check {
+	cassandra.InitCassandra()
+	defer cassandra.Close()
+	neo4j.InitNeo4j()
+	defer neo4j.CloseNeo4j()
+
+	pasien, pasienErr := neo4jSet(`MATCH (p:Pasien) RETURN p.email AS k`)
+	obat, obatErr := cassandraSet(`SELECT id_obat FROM obat`)
+
+	return []Check{
+		checkReferences("pemesanan_obat.email_pemesan -> Pasien",
+			`SELECT id_pesanan, email_pemesan FROM pemesanan_obat`, pasien, pasienErr),
+		checkReferences("pemesanan_layanan.email_pemesan -> Pasien",
+			`SELECT id_pesanan, email_pemesan FROM pemesanan_layanan`, pasien, pasienErr),
+		checkPenawaran(),
+		checkLogPerangkat(),
+		checkDetailResepObat(obat, obatErr),
+		checkDetailPesananObat(obat, obatErr),
+		checkJanjiTemu(),
+		checkNIK(),
+	}
+}
+
+// checkReferences memeriksa kolom kedua hasil query (Cassandra) ada di set.
+func checkReferences(name, query string, set map[string]bool, setErr error) Check {
+	c := Check{Name: name, Err: setErr}
+	if setErr != nil {
+		return c
+	}
+
+	iter, _ := cassandra.SelectCassandra(query)
+	var id, ref string
+	for iter.Scan(&id, &ref) {
+		c.Checked++
+		if !set[ref] {
+			c.violate("%s: %s", id, ref)
+		}
+	}
+	c.Err = iter.Close()
+	return c
+}
+
+// checkPenawaran memastikan lokasi_layanan dan menawarkan_layanan berisi
+// pasangan RS-layanan yang sama persis.
+func checkPenawaran() Check {
+	c := Check{Name: "lokasi_layanan <-> menawarkan_layanan"}
+
+	lokasi := map[string]bool{}
+	iter, _ := cassandra.SelectCassandra(`SELECT id_rs, id_layanan FROM lokasi_layanan`)
+	var idRs, idLayanan string
+	for iter.Scan(&idRs, &idLayanan) {
+		lokasi[idRs+"/"+idLayanan] = true
+	}
+	if c.Err = iter.Close(); c.Err != nil {
+		return c
+	}
+
+	menawarkan, err := neo4jSet(`MATCH (r:RumahSakit)-[:menawarkan_layanan]->(l:LayananMedis) RETURN r.id_rs + '/' + l.id_layanan AS k`)
+	if c.Err = err; err != nil {
+		return c
+	}
+
+	for _, key := range sortedKeys(lokasi) {
+		c.Checked++
+		if !menawarkan[key] {
+			c.violate("%s ada di lokasi_layanan tapi tidak di Neo4j", key)
+		}
+	}
+	for _, key := range sortedKeys(menawarkan) {
+		if !lokasi[key] {
+			c.Checked++
+			c.violate("%s ada di Neo4j tapi tidak di lokasi_layanan", key)
+		}
+	}
+	return c
+}
+
+// checkLogPerangkat memastikan setiap log berasal dari Baymin milik pasien.
+func checkLogPerangkat() Check {
+	c := Check{Name: "log_aktivitas.id_perangkat -> Baymin milik Pasien"}
+
+	owned, err := neo4jSet(`MATCH (:Pasien)-[:memiliki_perangkat]->(b:Baymin) RETURN b.id_perangkat AS k`)
+	if c.Err = err; err != nil {
+		return c
+	}
+
+	iter, _ := cassandra.SelectCassandra(`SELECT DISTINCT id_perangkat FROM log_aktivitas`)
+	var id string
+	for iter.Scan(&id) {
+		c.Checked++
+		if !owned[id] {
+			c.violate("%s", id)
+		}
+	}
+	c.Err = iter.Close()
+	return c
+}
+
+func checkDetailResepObat(obat map[string]bool, obatErr error) Check {
+	c := Check{Name: "DetailResep.id_obat -> obat", Err: obatErr}
+	if obatErr != nil {
+		return c
+	}
+
+	records, err := neo4j.ReadNeo4j(`MATCH (dr:DetailResep) RETURN dr.id_detail_resep AS id, dr.id_obat AS id_obat`, nil)
+	if c.Err = err; err != nil {
+		return c
+	}
+	for _, r := range records {
+		c.Checked++
+		if idObat, _ := r["id_obat"].(string); !obat[idObat] {
+			c.violate("%v: %v", r["id"], r["id_obat"])
+		}
+	}
+	return c
+}
+
+func checkDetailPesananObat(obat map[string]bool, obatErr error) Check {
+	c := Check{Name: "detail_pesanan_obat.daftar_obat -> obat", Err: obatErr}
+	if obatErr != nil {
+		return c
+	}
+
+	iter, _ := cassandra.SelectCassandra(`SELECT id_pesanan, daftar_obat FROM detail_pesanan_obat`)
+	var id string
+	var daftar map[string]int
+	for iter.Scan(&id, &daftar) {
+		for idObat := range daftar {
+			c.Checked++
+			if !obat[idObat] {
+				c.violate("%s: %s", id, idObat)
+			}
+		}
+		daftar = nil
+	}
+	c.Err = iter.Close()
+	return c
+}
+
+// checkJanjiTemu memastikan setiap JanjiTemu terhubung ke pasien, dokter dan
+// RS, serta RS-nya adalah tempat dokter tersebut bekerja.
+func checkJanjiTemu() Check {
+	c := Check{Name: "JanjiTemu -> Pasien, TenagaMedis, RS dokter"}
+
+	records, err := neo4j.ReadNeo4j(`
+		MATCH (j:JanjiTemu)
+		OPTIONAL MATCH (j)-[:memiliki_janji]->(p:Pasien)
+		OPTIONAL MATCH (j)-[:dengan_dokter]->(t:TenagaMedis)
+		OPTIONAL MATCH (j)-[:di_rs]->(rs:RumahSakit)
+		OPTIONAL MATCH (t)-[:bekerja_di]->(:Departemen)<-[:memiliki_departemen]-(rsDokter:RumahSakit)
+		RETURN j.id_janji_temu AS id,
+		       p IS NOT NULL AS ada_pasien,
+		       t IS NOT NULL AS ada_dokter,
+		       rs.id_rs AS id_rs,
+		       collect(DISTINCT rsDokter.id_rs) AS rs_dokter
+	`, nil)
+	if c.Err = err; err != nil {
+		return c
+	}
+
+	for _, r := range records {
+		c.Checked++
+		idRs, _ := r["id_rs"].(string)
+		var missing []string
+		if r["ada_pasien"] != true {
+			missing = append(missing, "pasien")
+		}
+		if r["ada_dokter"] != true {
+			missing = append(missing, "dokter")
+		}
+		if idRs == "" {
+			missing = append(missing, "rumah sakit")
+		}
+		if len(missing) > 0 {
+			c.violate("%v tanpa %s", r["id"], strings.Join(missing, ", "))
+			continue
+		}
+
+		found := false
+		for _, rs := range r["rs_dokter"].([]interface{}) {
+			found = found || rs == idRs
+		}
+		if !found {
+			c.violate("%v di %s, dokter bekerja di %v", r["id"], idRs, r["rs_dokter"])
+		}
+	}
+	return c
+}
+
+// checkNIK memastikan NIK pasien konsisten dengan kecamatan, tanggal lahir
+// dan jenis kelaminnya. Pasien tanpa NIK (data lama) dilewati.
+func checkNIK() Check {
+	c := Check{Name: "Pasien.nik -> wilayah, tanggal lahir, jenis kelamin"}
+
+	records, err := neo4j.ReadNeo4j(`
+		MATCH (p:Pasien) WHERE p.nik IS NOT NULL
+		RETURN p.email AS email, p.nik AS nik, p.tanggal_lahir AS lahir, p.jenis_kelamin AS jk,
+		       p.provinsi AS provinsi, p.kota AS kota, p.kecamatan AS kecamatan
+	`, nil)
+	if c.Err = err; err != nil {
+		return c
+	}
+
+	for _, r := range records {
+		c.Checked++
+		str := func(k string) string { s, _ := r[k].(string); return s }
+
+		info, err := indonesia.ParseNIK(str("nik"))
+		if err != nil {
+			c.violate("%s: %v", str("email"), err)
+			continue
+		}
+		w := info.Wilayah
+		if w.Provinsi != str("provinsi") || w.Kota != str("kota") || w.Kecamatan != str("kecamatan") {
+			c.violate("%s: NIK dari %s, alamat di %s", str("email"), w.Kecamatan, str("kecamatan"))
+			continue
+		}
+		lahir, err := time.Parse("2006-01-02", str("lahir"))
+		if err != nil || !info.CocokLahir(lahir) {
+			c.violate("%s: tanggal lahir %s tidak cocok dengan NIK %s", str("email"), str("lahir"), str("nik"))
+			continue
+		}
+		if info.JenisKelamin != str("jk") {
+			c.violate("%s: jenis kelamin %s tidak cocok dengan NIK %s", str("email"), str("jk"), str("nik"))
+		}
+	}
+	return c
+}
+
+// PrintChecks mencetak hasil verifikasi.
+func PrintChecks(checks []Check) {
+	fmt.Println("\n" + strings.Repeat("=", 78))
+	fmt.Println("     VERIFIKASI KONSISTENSI DATA")
+	fmt.Println(strings.Repeat("=", 78))
+	for _, c := range checks {
+		status := "OK"
+		if !c.OK() {
+			status = "GAGAL"
+		}
+		fmt.Printf("[%-5s] %-50s %8d diperiksa, %d pelanggaran\n", status, c.Name, c.Checked, c.Violations)
+		if c.Err != nil {
+			fmt.Printf("          error: %v\n", c.Err)
+		}
+		for _, s := range c.Samples {
+			fmt.Printf("          - %s\n", s)
+		}
+	}
+	fmt.Println(strings.Repeat("=", 78) + "\n")
+}
+
+// --- Helper ---
+
+func neo4jSet(query string) (map[string]bool, error) {
+	records, err := neo4j.ReadNeo4j(query, nil)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(records))
+	for _, r := range records {
+		if k, ok := r["k"].(string); ok {
+			set[k] = true
+		}
+	}
+	return set, nil
+}
+
+func cassandraSet(query string) (map[string]bool, error) {
+	iter, _ := cassandra.SelectCassandra(query)
+	set := map[string]bool{}
+	var k string
+	for iter.Scan(&k) {
+		set[k] = true
+	}
+	return set, iter.Close()
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,103 @@
+package simulator
+
+import (
+	"container/heap"
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ===============================================
+//   JAM VIRTUAL & ANTRIAN EVENT
+// ===============================================
+
+// Clock memetakan waktu nyata ke waktu virtual: setiap detik nyata sama
+// dengan Speed detik virtual. Dengan Speed 0 jam hanya maju mengikuti event
+// (mode secepat mungkin).
+type Clock struct {
+	start     time.Time
+	realStart time.Time
+	speed     float64
+	current   atomic.Int64 // unix nano, dipakai saat speed 0
+}
+
+func NewClock(start time.Time, speed float64) *Clock {
+	c := &Clock{start: start, realStart: time.Now(), speed: speed}
+	c.current.Store(start.UnixNano())
+	return c
+}
+
+// Now mengembalikan waktu virtual saat ini.
+func (c *Clock) Now() time.Time {
+	if c.speed == 0 {
+		return time.Unix(0, c.current.Load()).In(c.start.Location())
+	}
+	elapsed := time.Since(c.realStart)
+	return c.start.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// WaitUntil menunggu (dalam waktu nyata) sampai jam virtual mencapai t.
+// Mengembalikan false bila ctx dibatalkan lebih dulu.
+func (c *Clock) WaitUntil(ctx context.Context, t time.Time) bool {
+	if c.speed == 0 {
+		if t.UnixNano() > c.current.Load() {
+			c.current.Store(t.UnixNano())
+		}
+		return ctx.Err() == nil
+	}
+
+	wait := time.Duration(float64(t.Sub(c.Now())) / c.speed)
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// event adalah sesuatu yang terjadi pada waktu virtual At.
+type event struct {
+	At  time.Time
+	seq int // urutan penjadwalan, untuk event dengan waktu sama
+	run func(at time.Time)
+}
+
+// schedule adalah min-heap event berdasarkan waktu virtual.
+type schedule struct {
+	items []*event
+	seq   int
+}
+
+func (s *schedule) Len() int { return len(s.items) }
+func (s *schedule) Less(i, j int) bool {
+	if s.items[i].At.Equal(s.items[j].At) {
+		return s.items[i].seq < s.items[j].seq
+	}
+	return s.items[i].At.Before(s.items[j].At)
+}
+func (s *schedule) Swap(i, j int)      { s.items[i], s.items[j] = s.items[j], s.items[i] }
+func (s *schedule) Push(x interface{}) { s.items = append(s.items, x.(*event)) }
+func (s *schedule) Pop() interface{} {
+	last := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return last
+}
+
+// at menjadwalkan fn pada waktu virtual t.
+func (s *schedule) at(t time.Time, fn func(at time.Time)) {
+	s.seq++
+	heap.Push(s, &event{At: t, seq: s.seq, run: fn})
+}
+
+func (s *schedule) next() *event {
+	return heap.Pop(s).(*event)
+}
+
+func (s *schedule) peek() *event {
+	return s.items[0]
+}
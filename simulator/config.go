@@ -0,0 +1,109 @@
+package simulator
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// ===============================================
+//   KONFIGURASI SIMULATOR
+// ===============================================
+
+// Config menentukan laju event per jam virtual, kecepatan jam virtual dan
+// kapan simulasi berhenti.
+type Config struct {
+	Seed  int64     // 0 = acak
+	Start time.Time // waktu virtual awal (default: sekarang)
+	Speed float64   // detik virtual per detik nyata; 0 = secepat mungkin (butuh Until)
+
+	Until    time.Time     // berhenti saat jam virtual melewati waktu ini (opsional)
+	Duration time.Duration // berhenti setelah durasi nyata ini (0 = sampai Ctrl+C)
+
+	// Laju kedatangan per jam virtual (proses Poisson)
+	TelemetriPerJam        float64
+	PemesananObatPerJam    float64
+	PemesananLayananPerJam float64
+	JanjiTemuPerJam        float64
+
+	Workers        int           // jumlah worker penulis
+	ReportInterval time.Duration // interval laporan progres (waktu nyata)
+	DryRun         bool          // cetak event tanpa menulis ke database
+}
+
+// DefaultConfig mengembalikan laju yang kira-kira setara satu rumah sakit
+// sibuk berjalan real-time.
+func DefaultConfig() Config {
+	return Config{
+		Speed:                  1,
+		TelemetriPerJam:        600,
+		PemesananObatPerJam:    30,
+		PemesananLayananPerJam: 15,
+		JanjiTemuPerJam:        20,
+		Workers:                8,
+		ReportInterval:         5 * time.Second,
+	}
+}
+
+// ParseFlags membaca argumen command line menjadi Config.
+func ParseFlags(fs *flag.FlagSet, args []string) (Config, error) {
+	cfg := DefaultConfig()
+	var start, until string
+
+	fs.Int64Var(&cfg.Seed, "seed", cfg.Seed, "seed random untuk urutan event yang deterministik (0 = acak)")
+	fs.StringVar(&start, "start", "", "waktu virtual awal RFC3339 (default: sekarang)")
+	fs.Float64Var(&cfg.Speed, "speed", cfg.Speed, "kecepatan jam virtual, mis. 1440 = 1 hari per menit (0 = secepat mungkin)")
+	fs.StringVar(&until, "until", "", "berhenti saat jam virtual mencapai waktu RFC3339 ini")
+	fs.DurationVar(&cfg.Duration, "duration", cfg.Duration, "berhenti setelah durasi nyata ini (0 = sampai Ctrl+C)")
+
+	fs.Float64Var(&cfg.TelemetriPerJam, "telemetri", cfg.TelemetriPerJam, "log_aktivitas Baymin per jam virtual")
+	fs.Float64Var(&cfg.PemesananObatPerJam, "pemesanan-obat", cfg.PemesananObatPerJam, "pemesanan_obat baru per jam virtual")
+	fs.Float64Var(&cfg.PemesananLayananPerJam, "pemesanan-layanan", cfg.PemesananLayananPerJam, "pemesanan_layanan baru per jam virtual")
+	fs.Float64Var(&cfg.JanjiTemuPerJam, "janji-temu", cfg.JanjiTemuPerJam, "JanjiTemu baru per jam virtual")
+
+	fs.IntVar(&cfg.Workers, "workers", cfg.Workers, "jumlah worker penulis")
+	fs.DurationVar(&cfg.ReportInterval, "report", cfg.ReportInterval, "interval laporan progres (0 = mati)")
+	fs.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "cetak event tanpa menulis ke database (data referensi dari seeder profil tiny)")
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	var err error
+	if start != "" {
+		if cfg.Start, err = time.Parse(time.RFC3339, start); err != nil {
+			return Config{}, fmt.Errorf("--start harus RFC3339: %v", err)
+		}
+	} else {
+		cfg.Start = time.Now().Truncate(time.Second)
+	}
+	if until != "" {
+		if cfg.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return Config{}, fmt.Errorf("--until harus RFC3339: %v", err)
+		}
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate memeriksa nilai konfigurasi yang tidak masuk akal.
+func (c Config) Validate() error {
+	if c.Speed < 0 {
+		return fmt.Errorf("--speed tidak boleh negatif")
+	}
+	if c.Speed == 0 && c.Until.IsZero() {
+		return fmt.Errorf("--speed 0 (secepat mungkin) membutuhkan --until")
+	}
+	if !c.Until.IsZero() && !c.Until.After(c.Start) {
+		return fmt.Errorf("--until harus setelah --start")
+	}
+	if c.TelemetriPerJam < 0 || c.PemesananObatPerJam < 0 || c.PemesananLayananPerJam < 0 || c.JanjiTemuPerJam < 0 {
+		return fmt.Errorf("laju event tidak boleh negatif")
+	}
+	if c.Workers < 1 {
+		return fmt.Errorf("--workers minimal 1")
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"src/cassandra"
+	"src/neo4j"
+	"src/seeder"
+)
+
+// Report adalah ringkasan satu kali simulasi.
+type Report struct {
+	Config       Config
+	VirtualStart time.Time
+	VirtualEnd   time.Time
+	Duration     time.Duration
+	Counts       map[string]int // "<arus> <op>" -> jumlah berhasil
+	Failed       int
+	Errors       []string // contoh error
+	Pending      int      // event terjadwal yang belum sempat terjadi
+}
+
+const maxErrorSamples = 5
+
+// stats dipakai bersama oleh worker dan reporter.
+type stats struct {
+	mu     sync.Mutex
+	counts map[string]int
+	failed int
+	errors []string
+}
+
+func (s *stats) record(w write, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failed++
+		if len(s.errors) < maxErrorSamples {
+			s.errors = append(s.errors, fmt.Sprintf("%s: %v", w.Desc, err))
+		}
+		return
+	}
+	s.counts[w.Kind+" "+w.Op]++
+}
+
+func (s *stats) snapshot() (map[string]int, int, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[string]int, len(s.counts))
+	for k, v := range s.counts {
+		counts[k] = v
+	}
+	return counts, s.failed, append([]string(nil), s.errors...)
+}
+
+// Run menjalankan simulasi sampai ctx dibatalkan, cfg.Duration habis, atau
+// jam virtual melewati cfg.Until.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	var ref Reference
+	if cfg.DryRun {
+		ref = datasetReference(cfg.Seed)
+	} else {
+		cassandra.InitCassandra()
+		defer cassandra.Close()
+		neo4j.InitNeo4j()
+		defer neo4j.CloseNeo4j()
+
+		var err error
+		if ref, err = LoadReference(); err != nil {
+			return Report{Config: cfg}, err
+		}
+	}
+	fmt.Printf("Referensi: %d pasien, %d perangkat, %d dokter, %d obat, %d lokasi layanan\n",
+		len(ref.Pasien), len(ref.Perangkat), len(ref.Dokter), len(ref.Obat), len(ref.Layanan))
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	st := &stats{counts: map[string]int{}}
+	clock := NewClock(cfg.Start, cfg.Speed)
+	sched := &schedule{}
+
+	// Worker dipilih berdasarkan hash key agar perubahan status satu data
+	// tidak mendahului pembuatannya.
+	queues := make([]chan write, cfg.Workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan write, 256)
+		wg.Add(1)
+		go func(q chan write) {
+			defer wg.Done()
+			for w := range q {
+				st.record(w, w.exec())
+			}
+		}(queues[i])
+	}
+
+	emit := func(at time.Time, w write) {
+		if cfg.DryRun {
+			fmt.Printf("[%s] %s\n", at.Format("2006-01-02 15:04:05"), w.Desc)
+			st.record(w, nil)
+			return
+		}
+		h := fnv.New32a()
+		h.Write([]byte(w.Key))
+		queues[h.Sum32()%uint32(len(queues))] <- w
+	}
+
+	wl := newWorkload(cfg, ref, sched, emit)
+	wl.start()
+
+	start := time.Now()
+	stopReport := startReporter(st, clock, cfg.ReportInterval)
+
+	speed := fmt.Sprintf("%gx", cfg.Speed)
+	if cfg.Speed == 0 {
+		speed = "secepat mungkin"
+	}
+	fmt.Printf("Simulasi dimulai pada %s (kecepatan %s)...\n", cfg.Start.Format(time.RFC3339), speed)
+	virtualEnd := cfg.Start
+	for sched.Len() > 0 {
+		ev := sched.peek()
+		if !cfg.Until.IsZero() && ev.At.After(cfg.Until) {
+			virtualEnd = cfg.Until
+			break
+		}
+		if !clock.WaitUntil(ctx, ev.At) {
+			virtualEnd = clock.Now()
+			break
+		}
+		sched.next()
+		ev.run(ev.At)
+		virtualEnd = ev.At
+	}
+
+	stopReport()
+	for _, q := range queues {
+		close(q)
+	}
+	wg.Wait()
+
+	report := Report{
+		Config:       cfg,
+		VirtualStart: cfg.Start,
+		VirtualEnd:   virtualEnd,
+		Duration:     time.Since(start),
+		Pending:      sched.Len(),
+	}
+	report.Counts, report.Failed, report.Errors = st.snapshot()
+	return report, nil
+}
+
+// LoadReference membaca data master yang dibutuhkan simulasi. Koneksi
+// Cassandra dan Neo4j harus sudah dibuka.
+func LoadReference() (Reference, error) {
+	var ref Reference
+
+	records, err := neo4j.ReadNeo4j(`MATCH (p:Pasien) RETURN p.email AS k`, nil)
+	if err != nil {
+		return ref, fmt.Errorf("gagal membaca pasien: %v", err)
+	}
+	ref.Pasien = stringColumn(records, "k")
+
+	records, err = neo4j.ReadNeo4j(`MATCH (:Pasien)-[:memiliki_perangkat]->(b:Baymin) RETURN b.id_perangkat AS k`, nil)
+	if err != nil {
+		return ref, fmt.Errorf("gagal membaca perangkat Baymin: %v", err)
+	}
+	ref.Perangkat = stringColumn(records, "k")
+
+	// Janji temu dipesan lewat paket booking, jadi hanya dokter yang punya
+	// jadwal praktik (miliknya, departemen atau rumah sakit) yang dipakai.
+	records, err = neo4j.ReadNeo4j(`
+		MATCH (t:TenagaMedis)-[:bekerja_di]->(d:Departemen)<-[:memiliki_departemen]-(rs:RumahSakit)
+		WHERE (t)-[:memiliki_jadwal]->(:JadwalPraktik)
+		   OR (d)-[:memiliki_jadwal]->(:JadwalPraktik)
+		   OR (rs)-[:memiliki_jadwal]->(:JadwalPraktik)
+		RETURN DISTINCT t.email AS email, rs.id_rs AS id_rs
+		ORDER BY email, id_rs`, nil)
+	if err != nil {
+		return ref, fmt.Errorf("gagal membaca dokter: %v", err)
+	}
+	for _, r := range records {
+		email, _ := r["email"].(string)
+		idRs, _ := r["id_rs"].(string)
+		ref.Dokter = append(ref.Dokter, Dokter{Email: email, IdRs: idRs})
+	}
+
+	iter, _ := cassandra.SelectCassandra(`SELECT id_obat FROM obat`)
+	var id string
+	for iter.Scan(&id) {
+		ref.Obat = append(ref.Obat, id)
+	}
+	if err := iter.Close(); err != nil {
+		return ref, fmt.Errorf("gagal membaca obat: %v", err)
+	}
+
+	iter, _ = cassandra.SelectCassandra(`SELECT id_rs, id_layanan FROM lokasi_layanan`)
+	var l Penawaran
+	for iter.Scan(&l.IdRs, &l.IdLayanan) {
+		ref.Layanan = append(ref.Layanan, l)
+	}
+	if err := iter.Close(); err != nil {
+		return ref, fmt.Errorf("gagal membaca lokasi layanan: %v", err)
+	}
+
+	// Urutan hasil query tidak dijamin; urutkan agar pilihan acak
+	// deterministik untuk seed yang sama.
+	sort.Strings(ref.Pasien)
+	sort.Strings(ref.Perangkat)
+	sort.Strings(ref.Obat)
+	sort.Slice(ref.Layanan, func(i, j int) bool {
+		a, b := ref.Layanan[i], ref.Layanan[j]
+		return a.IdRs < b.IdRs || (a.IdRs == b.IdRs && a.IdLayanan < b.IdLayanan)
+	})
+
+	if len(ref.Pasien) == 0 {
+		return ref, fmt.Errorf("belum ada data pasien, jalankan seed terlebih dahulu")
+	}
+	return ref, nil
+}
+
+// datasetReference membangun referensi dari dataset seeder profil tiny,
+// dipakai oleh --dry-run agar bisa berjalan tanpa database.
+func datasetReference(seed int64) Reference {
+	cfg := seeder.Profiles["tiny"]
+	cfg.Seed, cfg.Now = seed, time.Unix(0, 0).UTC()
+	ds := seeder.Generate(cfg)
+
+	var ref Reference
+	for _, p := range ds.Pasien {
+		ref.Pasien = append(ref.Pasien, p["email"].(string))
+	}
+	for _, b := range ds.Baymin {
+		ref.Perangkat = append(ref.Perangkat, b["id_perangkat"].(string))
+	}
+	for _, o := range ds.Obat {
+		ref.Obat = append(ref.Obat, o["id_obat"].(string))
+	}
+	for _, l := range ds.Penawaran {
+		ref.Layanan = append(ref.Layanan, Penawaran{IdRs: l["id_rs"].(string), IdLayanan: l["id_layanan"].(string)})
+	}
+	rsDept := map[interface{}]string{}
+	for _, r := range ds.MemilikiDepartemen {
+		rsDept[r["nama_dept"]] = r["id_rs"].(string)
+	}
+	for _, r := range ds.BekerjaDi {
+		ref.Dokter = append(ref.Dokter, Dokter{Email: r["email_tm"].(string), IdRs: rsDept[r["nama_dept"]]})
+	}
+	return ref
+}
+
+func stringColumn(records []map[string]interface{}, key string) []string {
+	out := make([]string, 0, len(records))
+	for _, r := range records {
+		if s, ok := r[key].(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ===============================================
+//   LAPORAN
+// ===============================================
+
+func startReporter(st *stats, clock *Clock, interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				counts, failed, _ := st.snapshot()
+				fmt.Printf("   [virtual %s] %s, gagal %d\n", clock.Now().Format("2006-01-02 15:04"), summarize(counts), failed)
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		wg.Wait()
+	}
+}
+
+// summarize menjumlahkan event per arus, mis. "pemesanan_obat 12".
+func summarize(counts map[string]int) string {
+	perKind := map[string]int{}
+	for k, n := range counts {
+		perKind[strings.SplitN(k, " ", 2)[0]] += n
+	}
+	kinds := make([]string, 0, len(perKind))
+	for k := range perKind {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		parts[i] = fmt.Sprintf("%s %d", k, perKind[k])
+	}
+	if len(parts) == 0 {
+		return "belum ada event"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Print mencetak ringkasan simulasi.
+func (r Report) Print() {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("     RINGKASAN SIMULASI")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("Waktu virtual : %s -> %s (%s)\n", r.VirtualStart.Format(time.RFC3339), r.VirtualEnd.Format(time.RFC3339),
+		r.VirtualEnd.Sub(r.VirtualStart).Round(time.Minute))
+	fmt.Printf("Waktu nyata   : %s\n", r.Duration.Round(time.Millisecond))
+	fmt.Println(strings.Repeat("-", 60))
+
+	keys := make([]string, 0, len(r.Counts))
+	for k := range r.Counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	total := 0
+	for _, k := range keys {
+		fmt.Printf("%-45s %10d\n", k, r.Counts[k])
+		total += r.Counts[k]
+	}
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("%-45s %10d\n", "TOTAL", total)
+	fmt.Printf("%-45s %10d\n", "Gagal", r.Failed)
+	fmt.Printf("%-45s %10d\n", "Event terjadwal yang belum terjadi", r.Pending)
+	fmt.Println(strings.Repeat("=", 60))
+
+	for _, e := range r.Errors {
+		fmt.Printf("  - %s\n", e)
+	}
+	fmt.Printf("\nSeed: %d  (ulangi dengan --seed %d --start %s)\n\n", r.Config.Seed, r.Config.Seed, r.VirtualStart.Format(time.RFC3339))
+}
@@ -0,0 +1,365 @@
+package simulator
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"src/apotek"
+	"src/booking"
+	"src/cassandra"
+	"src/indonesia"
+	"src/layanan"
+	"src/neo4j"
+)
+
+// ===============================================
+//   WORKLOAD: arus event & siklus hidup data
+// ===============================================
+//
+// Setiap arus (telemetri, pemesanan obat, pemesanan layanan, janji temu)
+// datang sebagai proses Poisson pada jam virtual. Saat sebuah data dibuat,
+// seluruh perubahan status berikutnya langsung dijadwalkan sehingga urutan
+// event hanya bergantung pada seed.
+
+// Reference adalah data master yang sudah ada di database dan dipakai
+// sebagai referensi oleh event baru.
+type Reference struct {
+	Pasien    []string // email pasien
+	Perangkat []string // id_perangkat Baymin yang dimiliki pasien
+	Dokter    []Dokter
+	Obat      []string // id_obat
+	Layanan   []Penawaran
+}
+
+// Penawaran adalah layanan yang ditawarkan satu rumah sakit (lokasi_layanan).
+type Penawaran struct {
+	IdRs      string
+	IdLayanan string
+}
+
+// Dokter adalah tenaga medis beserta rumah sakit tempatnya bekerja.
+type Dokter struct {
+	Email string
+	IdRs  string
+}
+
+// write adalah satu operasi tulis ke database. Operasi dengan Key yang sama
+// selalu dikerjakan worker yang sama agar urutannya terjaga.
+type write struct {
+	Kind string // arus, mis. "pemesanan_obat"
+	Op   string // "buat" atau status baru
+	Key  string
+	Desc string
+	exec func() error
+}
+
+type workload struct {
+	cfg    Config
+	ref    Reference
+	rng    *rand.Rand
+	id     *indonesia.Generator
+	sched  *schedule
+	emit   func(at time.Time, w write)
+	prefix string
+	seq    map[string]int
+}
+
+func newWorkload(cfg Config, ref Reference, sched *schedule, emit func(time.Time, write)) *workload {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	return &workload{
+		cfg:    cfg,
+		ref:    ref,
+		rng:    rng,
+		id:     indonesia.New(rng),
+		sched:  sched,
+		emit:   emit,
+		prefix: fmt.Sprintf("%06X", uint64(cfg.Seed)&0xFFFFFF),
+		seq:    map[string]int{},
+	}
+}
+
+// start menjadwalkan kedatangan pertama setiap arus yang aktif.
+func (w *workload) start() {
+	streams := []struct {
+		rate   float64
+		arrive func(at time.Time)
+	}{
+		{w.cfg.TelemetriPerJam, w.telemetri},
+		{w.cfg.PemesananObatPerJam, w.pemesananObat},
+		{w.cfg.PemesananLayananPerJam, w.pemesananLayanan},
+		{w.cfg.JanjiTemuPerJam, w.janjiTemu},
+	}
+	for _, s := range streams {
+		if s.rate <= 0 {
+			continue
+		}
+		rate, arrive := s.rate, s.arrive
+		var next func(at time.Time)
+		next = func(at time.Time) {
+			arrive(at)
+			w.sched.at(at.Add(w.interarrival(rate)), next)
+		}
+		w.sched.at(w.cfg.Start.Add(w.interarrival(rate)), next)
+	}
+}
+
+// --- Helper ---
+
+// interarrival mengambil jeda eksponensial untuk laju per jam.
+func (w *workload) interarrival(perJam float64) time.Duration {
+	return time.Duration(w.rng.ExpFloat64() / perJam * float64(time.Hour))
+}
+
+func (w *workload) between(min, max time.Duration) time.Duration {
+	return min + time.Duration(w.rng.Int63n(int64(max-min)+1))
+}
+
+func (w *workload) pick(options []string) string {
+	return options[w.rng.Intn(len(options))]
+}
+
+func (w *workload) nextID(kind string) string {
+	w.seq[kind]++
+	return fmt.Sprintf("%s-%s-%06d", kind, w.prefix, w.seq[kind])
+}
+
+// later menjadwalkan operasi tulis pada waktu virtual t.
+func (w *workload) later(t time.Time, op write) {
+	w.sched.at(t, func(at time.Time) { w.emit(at, op) })
+}
+
+// ===============================================
+//   TELEMETRI BAYMIN
+// ===============================================
+
+func (w *workload) telemetri(at time.Time) {
+	if len(w.ref.Perangkat) == 0 {
+		return
+	}
+	id := w.pick(w.ref.Perangkat)
+	var detail string
+	switch w.rng.Intn(5) {
+	case 0:
+		detail = fmt.Sprintf("Detak jantung %d bpm", 60+w.rng.Intn(50))
+	case 1:
+		detail = fmt.Sprintf("Suhu tubuh %.1f C", 36+w.rng.Float64()*2)
+	case 2:
+		detail = fmt.Sprintf("Saturasi oksigen %d%%", 92+w.rng.Intn(8))
+	case 3:
+		detail = fmt.Sprintf("Langkah harian %d", w.rng.Intn(12000))
+	default:
+		detail = fmt.Sprintf("Baterai %d%%", 5+w.rng.Intn(95))
+	}
+	detail = "Status perangkat: " + detail
+
+	w.emit(at, write{Kind: "log_aktivitas", Op: "buat", Key: id,
+		Desc: fmt.Sprintf("log %s: %s", id, detail),
+		exec: func() error {
+			return cassandra.InsertCassandra(`INSERT INTO log_aktivitas (id_perangkat, waktu_aktivitas, detail_aktivitas) VALUES (?, ?, ?)`,
+				id, at, detail)
+		}})
+}
+
+// ===============================================
+//   PEMESANAN OBAT
+// ===============================================
+
+// pemesananObat: dipesan lewat paket apotek (stok dipotong), lalu belum
+// dibayar -> dijadwalkan -> sedang berlangsung -> selesai (70%), dibatalkan
+// pemesan (10%, stok dikembalikan), atau dibiarkan belum dibayar (20%,
+// kandidat job kedaluwarsa).
+func (w *workload) pemesananObat(at time.Time) {
+	if len(w.ref.Pasien) == 0 || len(w.ref.Obat) == 0 {
+		return
+	}
+	id := w.nextID("POB")
+	email := w.pick(w.ref.Pasien)
+	daftar := map[string]int{}
+	for i := 0; i < 1+w.rng.Intn(3); i++ {
+		daftar[w.pick(w.ref.Obat)] = 1 + w.rng.Intn(5)
+	}
+
+	w.emit(at, write{Kind: "pemesanan_obat", Op: "buat", Key: id,
+		Desc: fmt.Sprintf("pemesanan_obat %s oleh %s: %v", id, email, daftar),
+		exec: func() error {
+			_, err := apotek.Pesan(apotek.Permintaan{IdPesanan: id, EmailPasien: email, Obat: daftar}, at)
+			return err
+		}})
+
+	switch p := w.rng.Float64(); {
+	case p < 0.7:
+		t := at.Add(w.between(5*time.Minute, 6*time.Hour))
+		w.later(t, w.statusPesanan("pemesanan_obat", id, "dijadwalkan"))
+		t = t.Add(w.between(time.Hour, 12*time.Hour))
+		w.later(t, w.statusPesanan("pemesanan_obat", id, "sedang berlangsung"))
+		t = t.Add(w.between(time.Hour, 24*time.Hour))
+		w.later(t, w.statusPesanan("pemesanan_obat", id, "selesai"))
+	case p < 0.8:
+		w.later(at.Add(w.between(10*time.Minute, 24*time.Hour)), write{Kind: "pemesanan_obat", Op: "dibatalkan", Key: id,
+			Desc: fmt.Sprintf("pemesanan_obat %s -> dibatalkan", id),
+			exec: func() error {
+				b, err := apotek.Batalkan(id)
+				if err == nil && !b.Applied {
+					err = fmt.Errorf("pesanan %s sudah %s", id, b.Status)
+				}
+				return err
+			}})
+	}
+}
+
+// ===============================================
+//   PEMESANAN LAYANAN
+// ===============================================
+
+// pemesananLayanan: dipesan lewat paket layanan (kuota harian diambil),
+// lalu dibayar sebelum jadwal (80%), berlangsung saat jadwal lalu selesai;
+// dibatalkan (10%, kuota dikembalikan); atau tidak pernah dibayar (10%).
+func (w *workload) pemesananLayanan(at time.Time) {
+	if len(w.ref.Pasien) == 0 || len(w.ref.Layanan) == 0 {
+		return
+	}
+	id := w.nextID("PL")
+	email := w.pick(w.ref.Pasien)
+	offer := w.ref.Layanan[w.rng.Intn(len(w.ref.Layanan))]
+	jadwal := at.Add(w.between(2*time.Hour, 72*time.Hour)).Truncate(30 * time.Minute)
+
+	w.emit(at, write{Kind: "pemesanan_layanan", Op: "buat", Key: id,
+		Desc: fmt.Sprintf("pemesanan_layanan %s oleh %s: %s di %s, jadwal %s", id, email, offer.IdLayanan, offer.IdRs, jadwal.Format("2006-01-02 15:04")),
+		exec: func() error {
+			_, err := layanan.Pesan(layanan.Permintaan{
+				IdPesanan: id, EmailPasien: email, IdRS: offer.IdRs, IdLayanan: offer.IdLayanan, Jadwal: jadwal,
+			}, at)
+			return err
+		}})
+
+	switch p := w.rng.Float64(); {
+	case p < 0.8:
+		bayar := at.Add(w.between(5*time.Minute, 2*time.Hour))
+		if bayar.After(jadwal) {
+			bayar = jadwal
+		}
+		w.later(bayar, w.statusPesanan("pemesanan_layanan", id, "dijadwalkan"))
+		w.later(jadwal, w.statusPesanan("pemesanan_layanan", id, "sedang berlangsung"))
+		w.later(jadwal.Add(w.between(30*time.Minute, 2*time.Hour)), w.statusPesanan("pemesanan_layanan", id, "selesai"))
+	case p < 0.9:
+		w.later(at.Add(w.between(10*time.Minute, jadwal.Sub(at))), write{Kind: "pemesanan_layanan", Op: "dibatalkan", Key: id,
+			Desc: fmt.Sprintf("pemesanan_layanan %s -> dibatalkan", id),
+			exec: func() error {
+				_, err := layanan.Batal(id)
+				return err
+			}})
+	}
+}
+
+// statusPesanan mengubah status pesanan obat/layanan.
+func (w *workload) statusPesanan(table, id, status string) write {
+	return write{Kind: table, Op: status, Key: id,
+		Desc: fmt.Sprintf("%s %s -> %s", table, id, status),
+		exec: func() error {
+			return cassandra.UpdateCassandra(fmt.Sprintf(`UPDATE %s SET status_pemesanan = ? WHERE id_pesanan = ?`, table), status, id)
+		}}
+}
+
+// ===============================================
+//   JANJI TEMU & RESEP
+// ===============================================
+
+const completeJanjiTemuQuery = `
+	MATCH (j:JanjiTemu {id_janji_temu: $id})
+	SET j.status = 'selesai'
+	MERGE (r:Resep {id_resep: $id_resep})
+	SET r.penyakit = $penyakit
+	MERGE (j)-[:menghasilkan_resep]->(r)
+	WITH r
+	UNWIND $detail AS d
+	MERGE (dr:DetailResep {id_detail_resep: d.id_detail_resep})
+	SET dr.id_resep = $id_resep, dr.id_obat = d.id_obat, dr.dosis = d.dosis
+	MERGE (r)-[:memiliki_detail]->(dr)
+`
+
+// janjiTemu: dipesan lewat paket booking pada slot kosong pertama mulai 1
+// jam - 7 hari ke depan, lalu selesai (85%, 70% di antaranya menghasilkan
+// resep) atau dibatalkan (15%).
+func (w *workload) janjiTemu(at time.Time) {
+	if len(w.ref.Pasien) == 0 || len(w.ref.Dokter) == 0 {
+		return
+	}
+	id := w.nextID("JT")
+	email := w.pick(w.ref.Pasien)
+	dokter := w.ref.Dokter[w.rng.Intn(len(w.ref.Dokter))]
+	waktu := at.Add(w.between(time.Hour, 7*24*time.Hour)).Truncate(15 * time.Minute)
+	alasan := w.id.Keluhan()
+
+	w.emit(at, write{Kind: "janji_temu", Op: "buat", Key: id,
+		Desc: fmt.Sprintf("janji_temu %s: %s dengan %s di %s pada %s", id, email, dokter.Email, dokter.IdRs, waktu.Format("2006-01-02 15:04")),
+		exec: func() error {
+			slot, err := slotKosong(dokter.Email, waktu, at)
+			if err != nil {
+				return err
+			}
+			_, err = booking.Pesan(booking.Permintaan{
+				ID: id, EmailPasien: email, EmailDokter: dokter.Email, Waktu: slot, Alasan: alasan,
+			}, at)
+			return err
+		}})
+
+	if w.rng.Float64() < 0.15 {
+		w.later(at.Add(w.between(10*time.Minute, waktu.Sub(at))), write{Kind: "janji_temu", Op: "dibatalkan", Key: id,
+			Desc: fmt.Sprintf("janji_temu %s -> dibatalkan", id),
+			exec: func() error {
+				_, err := booking.Batal(id)
+				return err
+			}})
+		return
+	}
+
+	selesai := waktu.Add(w.between(15*time.Minute, time.Hour))
+	if w.rng.Float64() >= 0.7 || len(w.ref.Obat) == 0 {
+		w.later(selesai, write{Kind: "janji_temu", Op: "selesai", Key: id,
+			Desc: fmt.Sprintf("janji_temu %s -> selesai", id),
+			exec: func() error {
+				return neo4j.UpdateNeo4j(`MATCH (j:JanjiTemu {id_janji_temu: $id}) SET j.status = 'selesai'`, map[string]interface{}{"id": id})
+			}})
+		return
+	}
+
+	idResep := "R" + id[len("JT"):]
+	penyakit := w.id.Diagnosis()
+	dosis := []string{"1x Sehari", "2x Sehari", "3x Sehari"}
+	var detail []map[string]interface{}
+	seen := map[string]bool{}
+	for i := 0; i < 1+w.rng.Intn(3); i++ {
+		obat := w.pick(w.ref.Obat)
+		if seen[obat] {
+			continue
+		}
+		seen[obat] = true
+		detail = append(detail, map[string]interface{}{
+			"id_detail_resep": idResep + "-" + obat, "id_obat": obat, "dosis": w.pick(dosis),
+		})
+	}
+
+	w.later(selesai, write{Kind: "janji_temu", Op: "selesai+resep", Key: id,
+		Desc: fmt.Sprintf("janji_temu %s -> selesai, resep %s (%s, %d obat)", id, idResep, penyakit, len(detail)),
+		exec: func() error {
+			return neo4j.UpdateNeo4j(completeJanjiTemuQuery, map[string]interface{}{
+				"id": id, "id_resep": idResep, "penyakit": penyakit, "detail": detail,
+			})
+		}})
+}
+
+// slotKosong mengembalikan awal slot praktik kosong pertama dokter pada atau
+// setelah waktu, paling jauh 7 hari.
+func slotKosong(email string, waktu, now time.Time) (time.Time, error) {
+	_, slots, err := booking.SlotKosong(email, waktu, 7, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, s := range slots {
+		if !s.Mulai.Before(waktu) {
+			return s.Mulai, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s tidak punya slot kosong sejak %s", email, waktu.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,150 @@
+package tagihan
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"src/format"
+	"src/uang"
+)
+
+// ===============================================
+//   RENDER TAGIHAN: teks, HTML, JSON
+// ===============================================
+
+// Format dokumen tagihan.
+const (
+	Teks = "text"
+	HTML = "html"
+	JSON = "json"
+)
+
+var Formats = []string{Teks, HTML, JSON}
+
+// CekFormat memastikan format dokumen dikenal.
+func CekFormat(f string) error {
+	for _, known := range Formats {
+		if f == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("format tagihan %q tidak dikenal (pilihan: %s)", f, strings.Join(Formats, ", "))
+}
+
+// Tulis merender tagihan ke w dalam format f.
+func Tulis(w io.Writer, t *Tagihan, f string) error {
+	switch f {
+	case Teks:
+		return tulisTeks(w, t)
+	case HTML:
+		return halaman.Execute(w, t)
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	}
+	return CekFormat(f)
+}
+
+// judul adalah nomor tagihan, atau penanda draf untuk hasil Susun.
+func (t *Tagihan) judul() string {
+	if t.Nomor == "" {
+		return "DRAF (belum disimpan)"
+	}
+	return t.Nomor
+}
+
+const lebarDeskripsi = 38
+
+func tulisTeks(w io.Writer, t *Tagihan) error {
+	var b strings.Builder
+	garis := strings.Repeat("=", 92)
+	fmt.Fprintln(&b, garis)
+	fmt.Fprintf(&b, "TAGIHAN %s\n", t.judul())
+	fmt.Fprintln(&b, garis)
+	fmt.Fprintf(&b, "Pasien      : %s\n", t.EmailPasien)
+	fmt.Fprintf(&b, "Tanggal     : %s\n", t.Dibuat.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "Jatuh tempo : %s\n", t.JatuhTempo.Local().Format("2006-01-02"))
+	fmt.Fprintf(&b, "Status      : %s\n\n", t.Status)
+
+	fmt.Fprintf(&b, "%3s  %-14s %-*s %4s %16s %16s\n", "No", "Pesanan", lebarDeskripsi, "Deskripsi", "Jml", "Harga", "Subtotal")
+	fmt.Fprintln(&b, strings.Repeat("-", 92))
+	for i, r := range t.Baris {
+		desk := r.Deskripsi
+		if r.Kode != "" {
+			desk = r.Kode + " " + desk
+		}
+		fmt.Fprintf(&b, "%3d  %-14s %-*s %4d %16s %16s\n", i+1, format.Truncate(r.IdPesanan, 14),
+			lebarDeskripsi, format.Truncate(desk, lebarDeskripsi), r.Jumlah, r.Harga, r.Subtotal)
+	}
+	fmt.Fprintln(&b, strings.Repeat("-", 92))
+
+	total := func(label, nilai string) { fmt.Fprintf(&b, "%74s %17s\n", label, nilai) }
+	total("Subtotal", t.Subtotal.String())
+	if t.Diskon != 0 {
+		total(t.KeteranganDiskon, (-t.Diskon).String())
+	}
+	total("DPP", t.DPP.String())
+	total("PPN "+t.TarifPPN.String(), t.PPN.String())
+	fmt.Fprintf(&b, "%74s %17s\n", "", strings.Repeat("-", 17))
+	total("TOTAL", t.Total.String())
+	fmt.Fprintln(&b, garis)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var halaman = template.Must(template.New("tagihan").Funcs(template.FuncMap{
+	"judul": (*Tagihan).judul,
+	"inc":   func(i int) int { return i + 1 },
+	"neg":   func(r uang.Rupiah) uang.Rupiah { return -r },
+}).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Tagihan {{judul .}}</title>
+<style>
+  body { font-family: sans-serif; max-width: 60rem; margin: 2rem auto; color: #222; }
+  h1 { font-size: 1.4rem; margin-bottom: .25rem; }
+  dl { display: grid; grid-template-columns: 8rem auto; gap: .25rem 1rem; }
+  dt { color: #666; }
+  table { width: 100%; border-collapse: collapse; margin-top: 1.5rem; }
+  th, td { padding: .4rem .5rem; border-bottom: 1px solid #ddd; text-align: left; }
+  td.angka, th.angka { text-align: right; white-space: nowrap; }
+  tfoot td { border-bottom: none; }
+  tfoot tr.total td { font-weight: bold; border-top: 2px solid #222; }
+</style>
+</head>
+<body>
+<h1>Tagihan {{judul .}}</h1>
+<dl>
+  <dt>Pasien</dt><dd>{{.EmailPasien}}</dd>
+  <dt>Tanggal</dt><dd>{{.Dibuat.Local.Format "2006-01-02 15:04"}}</dd>
+  <dt>Jatuh tempo</dt><dd>{{.JatuhTempo.Local.Format "2006-01-02"}}</dd>
+  <dt>Status</dt><dd>{{.Status}}</dd>
+</dl>
+<table>
+  <thead>
+    <tr><th>No</th><th>Pesanan</th><th>Kode</th><th>Deskripsi</th><th class="angka">Jml</th><th class="angka">Harga</th><th class="angka">Subtotal</th></tr>
+  </thead>
+  <tbody>
+{{- range $i, $b := .Baris}}
+    <tr><td>{{inc $i}}</td><td>{{$b.IdPesanan}}</td><td>{{$b.Kode}}</td><td>{{$b.Deskripsi}}</td><td class="angka">{{$b.Jumlah}}</td><td class="angka">{{$b.Harga}}</td><td class="angka">{{$b.Subtotal}}</td></tr>
+{{- end}}
+  </tbody>
+  <tfoot>
+    <tr><td colspan="6" class="angka">Subtotal</td><td class="angka">{{.Subtotal}}</td></tr>
+{{- if .Diskon}}
+    <tr><td colspan="6" class="angka">{{.KeteranganDiskon}}</td><td class="angka">{{neg .Diskon}}</td></tr>
+{{- end}}
+    <tr><td colspan="6" class="angka">DPP</td><td class="angka">{{.DPP}}</td></tr>
+    <tr><td colspan="6" class="angka">PPN {{.TarifPPN}}</td><td class="angka">{{.PPN}}</td></tr>
+    <tr class="total"><td colspan="6" class="angka">Total</td><td class="angka">{{.Total}}</td></tr>
+  </tfoot>
+</table>
+</body>
+</html>
+`))
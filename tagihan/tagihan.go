@@ -0,0 +1,487 @@
+package tagihan
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"src/apotek"
+	"src/domain"
+	"src/layanan"
+	"src/queries"
+	"src/uang"
+)
+
+// ===============================================
+//   TAGIHAN (INVOICE) PASIEN
+// ===============================================
+//
+// Alur buat: kumpulkan pesanan obat dan pemesanan layanan pasien yang
+// berstatus "belum dibayar" dan belum masuk tagihan lain → susun baris
+// (obat per item dengan harga katalog, layanan dengan biaya saat dipesan)
+// → subtotal, diskon, DPP (subtotal - diskon), PPN atas DPP dan total →
+// ambil nomor urut bulan ini dengan LWT → klaim setiap pesanan di
+// tagihan_pesanan (IF NOT EXISTS) → simpan header dan baris dalam satu
+// LOGGED BATCH. Bila klaim atau penyimpanan gagal, klaim dilepas lagi;
+// nomor yang sudah diambil tidak dipakai ulang.
+//
+// Semua perhitungan memakai uang.Rupiah (sen). Dipakai oleh rs invoice dan
+// endpoint tagihan di paket api.
+
+// StatusBaru adalah status tagihan yang baru dibuat, sama seperti pesanan
+// yang ditagihkan.
+const StatusBaru = "belum dibayar"
+
+// StatusDraf dipakai tagihan hasil Susun yang belum disimpan.
+const StatusDraf = "draf"
+
+// TarifPPNDefault adalah tarif PPN bila tidak diisi.
+const TarifPPNDefault uang.Persen = 1100
+
+// JatuhTempoDefault adalah jumlah hari sampai tagihan jatuh tempo.
+const JatuhTempoDefault = 7
+
+// MaksJatuhTempo membatasi --jatuh-tempo.
+const MaksJatuhTempo = 90
+
+// maksLayanan membatasi pemesanan layanan yang dibaca per pasien.
+const maksLayanan = 1000
+
+const casRetry = 5
+
+// Jenis baris tagihan.
+const (
+	JenisObat        = "obat"
+	JenisLayanan     = "layanan"
+	JenisPenyesuaian = "penyesuaian"
+)
+
+// Baris adalah satu baris tagihan.
+type Baris struct {
+	Jenis     string      `json:"jenis"`
+	IdPesanan string      `json:"id_pesanan"`
+	Kode      string      `json:"kode"`
+	Deskripsi string      `json:"deskripsi"`
+	Jumlah    int         `json:"jumlah"`
+	Harga     uang.Rupiah `json:"harga"`
+	Subtotal  uang.Rupiah `json:"subtotal"`
+}
+
+// Tagihan adalah invoice satu pasien beserta rinciannya.
+type Tagihan struct {
+	Nomor            string      `json:"nomor"`
+	EmailPasien      string      `json:"email_pasien"`
+	Dibuat           time.Time   `json:"dibuat"`
+	JatuhTempo       time.Time   `json:"jatuh_tempo"`
+	Status           string      `json:"status"`
+	Baris            []Baris     `json:"baris"`
+	Subtotal         uang.Rupiah `json:"subtotal"`
+	Diskon           uang.Rupiah `json:"diskon"`
+	KeteranganDiskon string      `json:"keterangan_diskon,omitempty"`
+	DPP              uang.Rupiah `json:"dpp"`
+	TarifPPN         uang.Persen `json:"tarif_ppn"`
+	PPN              uang.Rupiah `json:"ppn"`
+	Total            uang.Rupiah `json:"total"`
+}
+
+// Pesanan mengembalikan id pesanan yang ditagihkan, sesuai urutan baris.
+func (t *Tagihan) Pesanan() []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, b := range t.Baris {
+		if !seen[b.IdPesanan] {
+			seen[b.IdPesanan] = true
+			ids = append(ids, b.IdPesanan)
+		}
+	}
+	return ids
+}
+
+// Diskon adalah potongan atas subtotal: persen atau nominal tetap.
+type Diskon struct {
+	Persen  uang.Persen
+	Nominal uang.Rupiah
+}
+
+// ParseDiskon membaca diskon seperti "10%" atau "50000"; kosong berarti
+// tanpa diskon.
+func ParseDiskon(s string) (Diskon, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Diskon{}, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		p, err := uang.ParsePersen(s)
+		return Diskon{Persen: p}, err
+	}
+	r, err := uang.Parse(s)
+	if err == nil && r < 0 {
+		err = fmt.Errorf("diskon %q tidak boleh negatif", s)
+	}
+	return Diskon{Nominal: r}, err
+}
+
+// hitung mengembalikan potongan atas subtotal (tidak melebihi subtotal)
+// beserta keterangannya.
+func (d Diskon) hitung(subtotal uang.Rupiah) (uang.Rupiah, string) {
+	switch {
+	case d.Persen > 0:
+		return subtotal.Porsi(d.Persen), "diskon " + d.Persen.String()
+	case d.Nominal > 0:
+		if d.Nominal > subtotal {
+			return subtotal, "diskon " + d.Nominal.String() + " (dibatasi subtotal)"
+		}
+		return d.Nominal, "diskon " + d.Nominal.String()
+	}
+	return 0, ""
+}
+
+// Permintaan adalah data untuk menyusun tagihan.
+type Permintaan struct {
+	EmailPasien string
+	Pesanan     []string // kosong = semua pesanan yang belum dibayar dan belum ditagih
+	Diskon      Diskon
+	TarifPPN    uang.Persen // 0 = tanpa PPN; pemanggil mengisi TarifPPNDefault
+	JatuhTempo  int         // hari; 0 = JatuhTempoDefault
+}
+
+func (p Permintaan) validate() error {
+	var details []string
+	if err := queries.ValidEmail(p.EmailPasien); err != nil {
+		details = append(details, "email_pasien: "+err.Error())
+	}
+	if p.TarifPPN < 0 || p.TarifPPN > uang.Penuh {
+		details = append(details, "tarif PPN harus antara 0% dan 100%")
+	}
+	if p.Diskon.Persen < 0 || p.Diskon.Persen > uang.Penuh || p.Diskon.Nominal < 0 {
+		details = append(details, "diskon harus antara 0% dan 100% atau nominal >= 0")
+	}
+	if p.JatuhTempo < 0 || p.JatuhTempo > MaksJatuhTempo {
+		details = append(details, fmt.Sprintf("jatuh tempo harus 0-%d hari", MaksJatuhTempo))
+	}
+	if len(details) > 0 {
+		return &domain.Error{Kind: domain.Invalid, Message: "permintaan tagihan tidak valid", Details: details}
+	}
+	return nil
+}
+
+// Susun menghitung tagihan tanpa menyimpannya (nomor kosong, status draf).
+func Susun(p Permintaan, now time.Time) (*Tagihan, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	obat, err := queries.PemesananObatPasien(p.EmailPasien)
+	if err != nil {
+		return nil, err
+	}
+	pesananLayanan, err := layanan.PesananPasien(p.EmailPasien, maksLayanan)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kandidat: pesanan yang belum dibayar, dibatasi p.Pesanan bila diisi.
+	diminta := map[string]bool{}
+	for _, id := range p.Pesanan {
+		diminta[id] = true
+	}
+	pilih := func(id, status string) bool {
+		return status == StatusBaru && (len(diminta) == 0 || diminta[id])
+	}
+	var obatDipilih []queries.PemesananObat
+	var layananDipilih []queries.PemesananLayanan
+	var ids []string
+	for _, o := range obat {
+		if pilih(o.IdPesanan, o.StatusPemesanan) {
+			obatDipilih = append(obatDipilih, o)
+			ids = append(ids, o.IdPesanan)
+		}
+	}
+	for _, l := range pesananLayanan {
+		if pilih(l.IdPesanan, l.StatusPemesanan) {
+			layananDipilih = append(layananDipilih, l)
+			ids = append(ids, l.IdPesanan)
+		}
+	}
+	var unknown []string
+	for _, id := range p.Pesanan {
+		if !contains(ids, id) {
+			unknown = append(unknown, fmt.Sprintf("pesanan %s tidak ada, bukan milik %s, atau tidak berstatus %q", id, p.EmailPasien, StatusBaru))
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: "pesanan tidak bisa ditagihkan", Details: unknown}
+	}
+
+	// Pesanan yang sudah masuk tagihan lain tidak ditagih lagi.
+	sudah, err := queries.TagihanPesanan(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Pesanan) > 0 && len(sudah) > 0 {
+		var details []string
+		for _, id := range p.Pesanan {
+			if nomor, ok := sudah[id]; ok {
+				details = append(details, fmt.Sprintf("pesanan %s sudah ada di tagihan %s", id, nomor))
+			}
+		}
+		return nil, &domain.Error{Kind: domain.Conflict, Message: "pesanan sudah ditagihkan", Details: details}
+	}
+
+	t := &Tagihan{
+		EmailPasien: p.EmailPasien,
+		Dibuat:      now.UTC().Truncate(time.Millisecond),
+		Status:      StatusDraf,
+		TarifPPN:    p.TarifPPN,
+	}
+	hari := p.JatuhTempo
+	if hari == 0 {
+		hari = JatuhTempoDefault
+	}
+	t.JatuhTempo = t.Dibuat.AddDate(0, 0, hari)
+
+	sort.Slice(obatDipilih, func(i, j int) bool { return obatDipilih[i].WaktuPemesanan.Before(obatDipilih[j].WaktuPemesanan) })
+	for _, o := range obatDipilih {
+		if _, ok := sudah[o.IdPesanan]; ok {
+			continue
+		}
+		baris, err := barisObat(o.IdPesanan)
+		if err != nil {
+			return nil, err
+		}
+		t.Baris = append(t.Baris, baris...)
+	}
+	sort.Slice(layananDipilih, func(i, j int) bool {
+		return layananDipilih[i].JadwalPelaksanaan.Before(layananDipilih[j].JadwalPelaksanaan)
+	})
+	for _, l := range layananDipilih {
+		if _, ok := sudah[l.IdPesanan]; ok {
+			continue
+		}
+		t.Baris = append(t.Baris, Baris{
+			Jenis:     JenisLayanan,
+			IdPesanan: l.IdPesanan,
+			Kode:      l.IdLayanan,
+			Deskripsi: fmt.Sprintf("%s di %s, %s", l.NamaLayanan, l.IdRS, l.JadwalPelaksanaan.Local().Format("2006-01-02 15:04")),
+			Jumlah:    1,
+			Harga:     l.BiayaLayanan,
+			Subtotal:  l.BiayaLayanan,
+		})
+	}
+	if len(t.Baris) == 0 {
+		return nil, domain.Fail(domain.NotFound, "tidak ada pesanan %q yang belum ditagih untuk %s", StatusBaru, p.EmailPasien)
+	}
+
+	t.hitung(p.Diskon)
+	return t, nil
+}
+
+// barisObat menyusun satu baris per obat dengan harga katalog saat ini.
+// Bila jumlahnya berbeda dari total yang tersimpan saat pesan (harga
+// katalog sudah berubah), selisihnya menjadi baris penyesuaian sehingga
+// yang ditagih tetap total saat pesan.
+func barisObat(id string) ([]Baris, error) {
+	r, err := apotek.Lihat(id)
+	if err != nil {
+		return nil, err
+	}
+	var out []Baris
+	var jumlah uang.Rupiah
+	for _, b := range r.Obat {
+		out = append(out, Baris{
+			Jenis:     JenisObat,
+			IdPesanan: id,
+			Kode:      b.IdObat,
+			Deskripsi: orDash(b.Nama),
+			Jumlah:    b.Jumlah,
+			Harga:     b.Harga,
+			Subtotal:  b.Subtotal,
+		})
+		jumlah += b.Subtotal
+	}
+	if selisih := r.TotalHarga - jumlah; selisih != 0 {
+		out = append(out, Baris{
+			Jenis:     JenisPenyesuaian,
+			IdPesanan: id,
+			Deskripsi: "Penyesuaian ke total saat pesan",
+			Jumlah:    1,
+			Harga:     selisih,
+			Subtotal:  selisih,
+		})
+	}
+	return out, nil
+}
+
+// hitung mengisi subtotal, diskon, DPP, PPN dan total dari baris.
+func (t *Tagihan) hitung(d Diskon) {
+	t.Subtotal = 0
+	for _, b := range t.Baris {
+		t.Subtotal += b.Subtotal
+	}
+	t.Diskon, t.KeteranganDiskon = d.hitung(t.Subtotal)
+	t.DPP = t.Subtotal - t.Diskon
+	t.PPN = t.DPP.Porsi(t.TarifPPN)
+	t.Total = t.DPP + t.PPN
+}
+
+// Buat menyusun tagihan, memberi nomor dan menyimpannya.
+func Buat(p Permintaan, now time.Time) (*Tagihan, error) {
+	t, err := Susun(p, now)
+	if err != nil {
+		return nil, err
+	}
+	t.Nomor, err = ambilNomor(t.Dibuat)
+	if err != nil {
+		return nil, err
+	}
+	t.Status = StatusBaru
+
+	jenis := map[string]string{}
+	for _, b := range t.Baris {
+		if b.Jenis != JenisPenyesuaian {
+			jenis[b.IdPesanan] = b.Jenis
+		}
+	}
+	var diklaim []string
+	for _, id := range t.Pesanan() {
+		applied, nomor, err := queries.KlaimPesanan(id, t.Nomor, jenis[id], t.Dibuat)
+		if err == nil && !applied {
+			err = domain.Fail(domain.Conflict, "pesanan %s baru saja masuk tagihan %s, coba lagi", id, nomor)
+		}
+		if err != nil {
+			lepas(diklaim, t.Nomor)
+			return nil, err
+		}
+		diklaim = append(diklaim, id)
+	}
+
+	baris := make([]queries.BarisTagihan, len(t.Baris))
+	for i, b := range t.Baris {
+		baris[i] = queries.BarisTagihan{
+			Urutan: i + 1, Jenis: b.Jenis, IdPesanan: b.IdPesanan, Kode: b.Kode, Deskripsi: b.Deskripsi,
+			Jumlah: b.Jumlah, Harga: b.Harga, Subtotal: b.Subtotal,
+		}
+	}
+	err = queries.SimpanTagihan(queries.Tagihan{
+		Nomor:            t.Nomor,
+		EmailPasien:      t.EmailPasien,
+		Dibuat:           t.Dibuat,
+		JatuhTempo:       t.JatuhTempo,
+		Status:           t.Status,
+		Subtotal:         t.Subtotal,
+		Diskon:           t.Diskon,
+		KeteranganDiskon: t.KeteranganDiskon,
+		DPP:              t.DPP,
+		TarifPPN:         t.TarifPPN,
+		PPN:              t.PPN,
+		Total:            t.Total,
+	}, baris)
+	if err != nil {
+		lepas(diklaim, t.Nomor)
+		return nil, err
+	}
+	return t, nil
+}
+
+// lepas menghapus klaim pesanan tagihan yang gagal dibuat. Kegagalan hanya
+// dicatat karena tagihan sudah dianggap gagal.
+func lepas(ids []string, nomor string) {
+	for _, id := range ids {
+		if err := queries.LepasPesanan(id, nomor); err != nil {
+			log.Printf("%v; hapus baris tagihan_pesanan %s secara manual", err, id)
+		}
+	}
+}
+
+// ambilNomor mengambil nomor urut berikutnya pada bulan waktu (zona lokal),
+// mis. INV-202501-00001.
+func ambilNomor(waktu time.Time) (string, error) {
+	periode := waktu.In(time.Local).Format("200601")
+	for i := 0; i < casRetry; i++ {
+		terakhir, ada, err := queries.NomorTerakhir(periode)
+		if err != nil {
+			return "", err
+		}
+		if !ada {
+			applied, err := queries.MulaiNomor(periode)
+			if err != nil {
+				return "", err
+			}
+			if applied {
+				return Nomor(periode, 1), nil
+			}
+			continue
+		}
+		applied, _, err := queries.UbahNomor(periode, terakhir, terakhir+1)
+		if err != nil {
+			return "", err
+		}
+		if applied {
+			return Nomor(periode, terakhir+1), nil
+		}
+	}
+	return "", domain.Fail(domain.Conflict, "nomor tagihan sedang dipakai tagihan lain, coba lagi")
+}
+
+// Nomor memformat nomor tagihan dari periode (YYYYMM) dan nomor urut.
+func Nomor(periode string, urut int) string {
+	return fmt.Sprintf("INV-%s-%05d", periode, urut)
+}
+
+// Lihat membaca tagihan yang sudah disimpan.
+func Lihat(nomor string) (*Tagihan, error) {
+	h, baris, err := queries.DetailTagihan(nomor)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return nil, domain.Fail(domain.NotFound, "tagihan %s tidak ditemukan", nomor)
+	}
+	t := &Tagihan{
+		Nomor:            h.Nomor,
+		EmailPasien:      h.EmailPasien,
+		Dibuat:           h.Dibuat,
+		JatuhTempo:       h.JatuhTempo,
+		Status:           h.Status,
+		Subtotal:         h.Subtotal,
+		Diskon:           h.Diskon,
+		KeteranganDiskon: h.KeteranganDiskon,
+		DPP:              h.DPP,
+		TarifPPN:         h.TarifPPN,
+		PPN:              h.PPN,
+		Total:            h.Total,
+	}
+	for _, b := range baris {
+		t.Baris = append(t.Baris, Baris{
+			Jenis: b.Jenis, IdPesanan: b.IdPesanan, Kode: b.Kode, Deskripsi: b.Deskripsi,
+			Jumlah: b.Jumlah, Harga: b.Harga, Subtotal: b.Subtotal,
+		})
+	}
+	return t, nil
+}
+
+// DaftarPasien mengembalikan paling banyak limit (0 = semua) tagihan
+// pasien (tanpa baris), terbaru lebih dulu.
+func DaftarPasien(email string, limit int) ([]queries.Tagihan, error) {
+	if err := queries.ValidEmail(email); err != nil {
+		return nil, &domain.Error{Kind: domain.Invalid, Message: "email pasien tidak valid", Details: []string{err.Error()}}
+	}
+	return queries.TagihanPasien(email, limit)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package tagihan
+
+import (
+	"testing"
+
+	"src/uang"
+)
+
+func TestDiskon(t *testing.T) {
+	tests := []struct {
+		in         string
+		subtotal   uang.Rupiah
+		want       uang.Rupiah
+		keterangan string
+	}{
+		{"", 100000 * uang.Rp, 0, ""},
+		{"10%", 100000 * uang.Rp, 10000 * uang.Rp, "diskon 10%"},
+		// 12,5% dari Rp 33,33 = 416,625 sen
+		{"12,5%", 3333 * uang.Sen, 417 * uang.Sen, "diskon 12,5%"},
+		// 15% dari Rp 0,10 = 1,5 sen, setengah sen dibulatkan ke atas
+		{"15%", 10 * uang.Sen, 2 * uang.Sen, "diskon 15%"},
+		{"50000", 100000 * uang.Rp, 50000 * uang.Rp, "diskon Rp 50.000,00"},
+		{"Rp 150.000", 100000 * uang.Rp, 100000 * uang.Rp, "diskon Rp 150.000,00 (dibatasi subtotal)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d, err := ParseDiskon(tt.in)
+			if err != nil {
+				t.Fatalf("ParseDiskon(%q): %v", tt.in, err)
+			}
+			got, keterangan := d.hitung(tt.subtotal)
+			if got != tt.want || keterangan != tt.keterangan {
+				t.Fatalf("diskon %q atas %s = %s %q, ingin %s %q", tt.in, tt.subtotal, got, keterangan, tt.want, tt.keterangan)
+			}
+		})
+	}
+
+	for _, in := range []string{"-5000", "101%", "10,125%", "sepuluh"} {
+		if _, err := ParseDiskon(in); err == nil {
+			t.Errorf("ParseDiskon(%q) = nil error, ingin error", in)
+		}
+	}
+}
+
+func TestHitung(t *testing.T) {
+	tests := []struct {
+		name     string
+		baris    []uang.Rupiah
+		diskon   Diskon
+		tarif    uang.Persen
+		subtotal uang.Rupiah
+		potongan uang.Rupiah
+		dpp      uang.Rupiah
+		ppn      uang.Rupiah
+		total    uang.Rupiah
+	}{
+		{"tanpa diskon dan ppn", []uang.Rupiah{15000 * uang.Rp, 250000 * uang.Rp}, Diskon{}, 0,
+			265000 * uang.Rp, 0, 265000 * uang.Rp, 0, 265000 * uang.Rp},
+		// PPN 11% dari Rp 12.345,67 = 135.802,37 sen
+		{"ppn dibulatkan ke sen", []uang.Rupiah{1234567 * uang.Sen}, Diskon{}, TarifPPNDefault,
+			1234567 * uang.Sen, 0, 1234567 * uang.Sen, 135802 * uang.Sen, 1370369 * uang.Sen},
+		// PPN 11% dari 50 sen = 5,5 sen
+		{"setengah sen ppn", []uang.Rupiah{50 * uang.Sen}, Diskon{}, TarifPPNDefault,
+			50 * uang.Sen, 0, 50 * uang.Sen, 6 * uang.Sen, 56 * uang.Sen},
+		{"ppn dari dpp setelah diskon", []uang.Rupiah{45000 * uang.Rp, 250000 * uang.Rp}, Diskon{Persen: 1000}, TarifPPNDefault,
+			295000 * uang.Rp, 29500 * uang.Rp, 265500 * uang.Rp, 29205 * uang.Rp, 294705 * uang.Rp},
+		// PPN 12,5% dari Rp 10,01 = 125,125 sen
+		{"tarif pecahan", []uang.Rupiah{1001 * uang.Sen}, Diskon{}, 1250,
+			1001 * uang.Sen, 0, 1001 * uang.Sen, 125 * uang.Sen, 1126 * uang.Sen},
+		{"penyesuaian negatif", []uang.Rupiah{10000 * uang.Rp, -500 * uang.Rp}, Diskon{Nominal: 2000 * uang.Rp}, 0,
+			9500 * uang.Rp, 2000 * uang.Rp, 7500 * uang.Rp, 0, 7500 * uang.Rp},
+		{"diskon melebihi subtotal", []uang.Rupiah{1000 * uang.Rp}, Diskon{Nominal: 5000 * uang.Rp}, TarifPPNDefault,
+			1000 * uang.Rp, 1000 * uang.Rp, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &Tagihan{TarifPPN: tt.tarif}
+			for _, s := range tt.baris {
+				tg.Baris = append(tg.Baris, Baris{Jumlah: 1, Harga: s, Subtotal: s})
+			}
+			tg.hitung(tt.diskon)
+			got := []uang.Rupiah{tg.Subtotal, tg.Diskon, tg.DPP, tg.PPN, tg.Total}
+			want := []uang.Rupiah{tt.subtotal, tt.potongan, tt.dpp, tt.ppn, tt.total}
+			for i, nama := range []string{"subtotal", "diskon", "DPP", "PPN", "total"} {
+				if got[i] != want[i] {
+					t.Errorf("%s = %s, ingin %s", nama, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPermintaanValidate(t *testing.T) {
+	valid := Permintaan{EmailPasien: "pasien1@mail.com", TarifPPN: TarifPPNDefault}
+	tests := []struct {
+		name    string
+		ubah    func(p *Permintaan)
+		wantErr bool
+	}{
+		{"valid", func(p *Permintaan) {}, false},
+		{"email tidak valid", func(p *Permintaan) { p.EmailPasien = "pasien" }, true},
+		{"ppn lebih dari 100%", func(p *Permintaan) { p.TarifPPN = uang.Penuh + 1 }, true},
+		{"diskon nominal negatif", func(p *Permintaan) { p.Diskon.Nominal = -1 }, true},
+		{"jatuh tempo maksimum", func(p *Permintaan) { p.JatuhTempo = MaksJatuhTempo }, false},
+		{"jatuh tempo terlalu lama", func(p *Permintaan) { p.JatuhTempo = MaksJatuhTempo + 1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.ubah(&p)
+			if err := p.validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("validate = %v, ingin error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
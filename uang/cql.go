@@ -0,0 +1,45 @@
+package uang
+
+import (
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// ===============================================
+//   KONVERSI KOLOM CASSANDRA
+// ===============================================
+//
+// Rupiah bisa langsung dipakai sebagai parameter query dan tujuan Scan.
+// Kolom DOUBLE (harga, total_harga, biaya_layanan) berisi rupiah dan
+// dibulatkan ke sen; kolom BIGINT (tabel tagihan) berisi sen.
+
+func (r Rupiah) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	switch info.Type() {
+	case gocql.TypeDouble:
+		return gocql.Marshal(info, r.Float())
+	case gocql.TypeBigInt, gocql.TypeCounter:
+		return gocql.Marshal(info, int64(r))
+	}
+	return nil, fmt.Errorf("nominal rupiah tidak bisa disimpan ke kolom %s", info.Type())
+}
+
+func (r *Rupiah) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	switch info.Type() {
+	case gocql.TypeDouble:
+		var f float64
+		if err := gocql.Unmarshal(info, data, &f); err != nil {
+			return err
+		}
+		*r = DariFloat(f)
+		return nil
+	case gocql.TypeBigInt, gocql.TypeCounter:
+		var n int64
+		if err := gocql.Unmarshal(info, data, &n); err != nil {
+			return err
+		}
+		*r = Rupiah(n)
+		return nil
+	}
+	return fmt.Errorf("kolom %s tidak bisa dibaca sebagai nominal rupiah", info.Type())
+}
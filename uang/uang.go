@@ -0,0 +1,302 @@
+package uang
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ===============================================
+//   NOMINAL RUPIAH
+// ===============================================
+//
+// Rupiah menyimpan nominal sebagai bilangan bulat sen (1/100 rupiah) agar
+// penjumlahan subtotal, diskon dan pajak tidak menumpuk galat float.
+// Penyimpanan belum seluruhnya sen: hanya tabel tagihan yang BIGINT. Kolom
+// harga/biaya lama di Cassandra tetap DOUBLE, properti Neo4j tetap float
+// dan seeder menulis float rupiah. Konversi dilakukan sekali di batas
+// (cql.go untuk driver Cassandra, Float/DariFloat untuk Neo4j, JSON arsip
+// dan seeder), bukan di setiap perhitungan.
+
+// Rupiah adalah nominal uang dalam sen.
+type Rupiah int64
+
+const (
+	Sen Rupiah = 1
+	Rp  Rupiah = 100 * Sen
+)
+
+// DariFloat membulatkan nominal rupiah float ke sen terdekat.
+func DariFloat(f float64) Rupiah {
+	return Rupiah(math.Round(f * 100))
+}
+
+// Float mengembalikan nominal dalam rupiah untuk kolom DOUBLE dan Neo4j.
+func (r Rupiah) Float() float64 {
+	return float64(r) / 100
+}
+
+// Kali mengembalikan r × n, mis. harga satuan × jumlah.
+func (r Rupiah) Kali(n int) Rupiah {
+	return r * Rupiah(n)
+}
+
+// Porsi mengembalikan p persen dari r, dibulatkan ke sen terdekat
+// (setengah sen menjauhi nol).
+func (r Rupiah) Porsi(p Persen) Rupiah {
+	return bagiBulat(int64(r)*int64(p), int64(Penuh))
+}
+
+// Sen mengembalikan nominal dalam sen.
+func (r Rupiah) Sen() int64 {
+	return int64(r)
+}
+
+// Angka memformat nominal dengan pemisah ribuan titik dan desimal koma,
+// mis. 123456750 sen -> "1.234.567,50".
+func (r Rupiah) Angka() string {
+	sen := int64(r)
+	sign := ""
+	if sen < 0 {
+		sign, sen = "-", -sen
+	}
+	intPart := strconv.FormatInt(sen/100, 10)
+
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return fmt.Sprintf("%s%s,%02d", sign, b.String(), sen%100)
+}
+
+// String memformat nominal untuk tampilan, mis. "Rp 1.234.567,50".
+func (r Rupiah) String() string {
+	if r < 0 {
+		return "-Rp " + (-r).Angka()
+	}
+	return "Rp " + r.Angka()
+}
+
+// Desimal memformat nominal sebagai angka desimal bertitik tanpa pemisah
+// ribuan, mis. "1234567.50" (atau "1234567" bila tanpa sen).
+func (r Rupiah) Desimal() string {
+	sen := int64(r)
+	sign := ""
+	if sen < 0 {
+		sign, sen = "-", -sen
+	}
+	if sen%100 == 0 {
+		return fmt.Sprintf("%s%d", sign, sen/100)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, sen/100, sen%100)
+}
+
+// MarshalText dipakai CSV dan kunci map: angka desimal mentah.
+func (r Rupiah) MarshalText() ([]byte, error) {
+	return []byte(r.Desimal()), nil
+}
+
+// MarshalJSON menulis nominal sebagai angka rupiah (bukan sen) agar format
+// API tetap sama seperti saat field masih float.
+func (r Rupiah) MarshalJSON() ([]byte, error) {
+	return []byte(r.Desimal()), nil
+}
+
+// UnmarshalJSON menerima angka rupiah atau teks yang bisa dibaca Parse.
+func (r *Rupiah) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := Parse(s)
+		*r = v
+		return err
+	}
+	v, err := desimal(string(data))
+	*r = v
+	return err
+}
+
+// Parse membaca nominal rupiah dari input pengguna: "150000", "150000.5",
+// "Rp 150.000,50" atau "150,000.50". Tanda - atau + hanya boleh di awal
+// input. Bagian bulat dan desimal hanya berisi angka; pemisah ribuan harus
+// satu jenis, berbeda dari pemisah desimal, dan memisahkan kelompok tiga
+// angka. Lebih dari dua angka desimal ditolak
+// karena tidak bisa disimpan dalam sen.
+func Parse(s string) (Rupiah, error) {
+	invalid := fmt.Errorf("nominal %q tidak valid", s)
+	t := strings.TrimSpace(s)
+	neg := false
+	if t != "" && (t[0] == '-' || t[0] == '+') {
+		neg = t[0] == '-'
+		t = t[1:]
+	}
+	t = strings.TrimSpace(strings.TrimPrefix(t, "Rp"))
+	t = strings.ReplaceAll(t, " ", "")
+	if t == "" {
+		return 0, fmt.Errorf("nominal %q kosong", s)
+	}
+
+	// Pemisah terakhir (titik atau koma) yang diikuti 1-2 angka adalah
+	// desimal; pemisah lainnya adalah pemisah ribuan.
+	intPart, decPart := t, ""
+	if i := strings.LastIndexAny(t, ".,"); i >= 0 && len(t)-i-1 <= 2 {
+		intPart, decPart = t[:i], t[i+1:]
+		if decPart == "" || !angka(decPart) || strings.IndexByte(intPart, t[i]) >= 0 {
+			return 0, invalid
+		}
+	}
+	if strings.Contains(intPart, ".") && strings.Contains(intPart, ",") {
+		return 0, invalid
+	}
+	groups := strings.Split(strings.ReplaceAll(intPart, ",", "."), ".")
+	for i, g := range groups {
+		if !angka(g) {
+			return 0, invalid
+		}
+		if len(groups) > 1 && (i == 0 && len(g) > 3 || i > 0 && len(g) != 3) {
+			return 0, invalid
+		}
+	}
+	rp, err := strconv.ParseInt(strings.Join(groups, ""), 10, 64)
+	if err != nil || rp > math.MaxInt64/100-1 {
+		return 0, invalid
+	}
+	for len(decPart) < 2 {
+		decPart += "0"
+	}
+	sen, _ := strconv.ParseInt(decPart, 10, 64)
+	v := Rupiah(rp*100 + sen)
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// angka melaporkan apakah s tidak kosong dan hanya berisi angka 0-9.
+func angka(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// desimal membaca angka JSON, mis. "150000.5", "-20" atau "1.5e5". Berbeda
+// dengan Parse, titik di sini selalu desimal. Nilai dihitung eksak (bukan
+// float) sehingga notasi eksponen tetap diterima selama hasilnya tepat
+// dalam sen.
+func desimal(s string) (Rupiah, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("nominal %s bukan angka", s)
+	}
+	r.Mul(r, big.NewRat(100, 1))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("nominal %s lebih dari dua angka desimal", s)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("nominal %s terlalu besar", s)
+	}
+	return Rupiah(r.Num().Int64()), nil
+}
+
+// Jumlah menjumlahkan beberapa nominal.
+func Jumlah(list ...Rupiah) Rupiah {
+	var total Rupiah
+	for _, r := range list {
+		total += r
+	}
+	return total
+}
+
+// ===============================================
+//   PERSENTASE
+// ===============================================
+
+// Persen adalah persentase dalam seperseratus persen (basis poin), mis.
+// 1100 = 11%, sehingga tarif seperti 12,5% tetap eksak.
+type Persen int64
+
+// Penuh adalah 100%.
+const Penuh Persen = 10000
+
+// ParsePersen membaca persentase seperti "11", "11%" atau "12,5%".
+func ParsePersen(s string) (Persen, error) {
+	t := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	t = strings.Replace(t, ",", ".", 1)
+	intPart, decPart, _ := strings.Cut(t, ".")
+	if len(decPart) > 2 {
+		return 0, fmt.Errorf("persen %q paling banyak dua angka desimal", s)
+	}
+	for len(decPart) < 2 {
+		decPart += "0"
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	n, err := strconv.ParseInt(intPart+decPart, 10, 64)
+	if err != nil || n < 0 || Persen(n) > Penuh {
+		return 0, fmt.Errorf("persen %q harus antara 0 dan 100", s)
+	}
+	return Persen(n), nil
+}
+
+// String memformat persentase, mis. "11%" atau "12,5%".
+func (p Persen) String() string {
+	s := strconv.FormatInt(int64(p)/100, 10)
+	if sisa := int64(p) % 100; sisa != 0 {
+		s += "," + strings.TrimRight(fmt.Sprintf("%02d", sisa), "0")
+	}
+	return s + "%"
+}
+
+// MarshalJSON menulis persentase sebagai angka persen, mis. 11 atau 12.5.
+func (p Persen) MarshalJSON() ([]byte, error) {
+	s := strconv.FormatInt(int64(p)/100, 10)
+	if sisa := int64(p) % 100; sisa != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%02d", sisa), "0")
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON menerima angka persen atau teks yang bisa dibaca ParsePersen.
+func (p *Persen) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	v, err := ParsePersen(s)
+	*p = v
+	return err
+}
+
+// bagiBulat membagi a dengan b (b > 0) dan membulatkan setengah menjauhi nol.
+func bagiBulat(a, b int64) Rupiah {
+	q, sisa := a/b, a%b
+	if sisa < 0 {
+		sisa = -sisa
+	}
+	if 2*sisa >= b {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Rupiah(q)
+}
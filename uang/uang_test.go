@@ -0,0 +1,269 @@
+package uang
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rupiah
+	}{
+		{"0", 0},
+		{"150000", 150000 * Rp},
+		{"150000.5", 150000*Rp + 50*Sen},
+		{"150000,05", 150000*Rp + 5*Sen},
+		{"Rp 150.000,50", 150000*Rp + 50*Sen},
+		{"Rp150.000", 150000 * Rp},
+		{"150,000.50", 150000*Rp + 50*Sen},
+		{"1.234.567,89", 1234567*Rp + 89*Sen},
+		{"1,5", 1*Rp + 50*Sen},
+		{"1.500", 1500 * Rp},
+		{"1 500 000", 1500000 * Rp},
+		{"  42  ", 42 * Rp},
+		{"-150000", -150000 * Rp},
+		{"-Rp 1.500,25", -(1500*Rp + 25*Sen)},
+		{"+Rp 1.500", 1500 * Rp},
+		{"-0,01", -1 * Sen},
+		{"007", 7 * Rp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Parse(%q) = %d sen, ingin %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"-",
+		"Rp",
+		"-Rp",
+		"abc",
+		"12a",
+		"1e5",
+		"0x10",
+		"--5",
+		"+-5",
+		"-+5",
+		"Rp -5",
+		"5-",
+		"1,-5",
+		"1.+5",
+		"5.",
+		"5,",
+		".5",
+		",50",
+		"1..000",
+		"1.,000",
+		"1.2.3",
+		"12.34.56",
+		"1234.567,00",
+		"1.000.00.000",
+		"150.000,5a",
+		"1.234,567,89",
+		"99999999999999999999",
+	}
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if got, err := Parse(in); err == nil {
+				t.Fatalf("Parse(%q) = %d, ingin error", in, got)
+			}
+		})
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	tests := []Rupiah{0, 1, 99, 100, 150000*Rp + 50*Sen, 1234567*Rp + 89*Sen, -1, -(1500*Rp + 25*Sen), 999999999 * Rp}
+	for _, r := range tests {
+		for name, s := range map[string]string{"String": r.String(), "Angka": r.Angka(), "Desimal": r.Desimal()} {
+			got, err := Parse(s)
+			if err != nil {
+				t.Errorf("Parse(%s %q): %v", name, s, err)
+				continue
+			}
+			if got != r {
+				t.Errorf("Parse(%s %q) = %d, ingin %d", name, s, got, r)
+			}
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		r                       Rupiah
+		str, angka, desimalTeks string
+	}{
+		{0, "Rp 0,00", "0,00", "0"},
+		{5 * Sen, "Rp 0,05", "0,05", "0.05"},
+		{1234567*Rp + 50*Sen, "Rp 1.234.567,50", "1.234.567,50", "1234567.50"},
+		{100 * Rp, "Rp 100,00", "100,00", "100"},
+		{-(1500*Rp + 25*Sen), "-Rp 1.500,25", "-1.500,25", "-1500.25"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.str {
+			t.Errorf("String(%d) = %q, ingin %q", tt.r, got, tt.str)
+		}
+		if got := tt.r.Angka(); got != tt.angka {
+			t.Errorf("Angka(%d) = %q, ingin %q", tt.r, got, tt.angka)
+		}
+		if got := tt.r.Desimal(); got != tt.desimalTeks {
+			t.Errorf("Desimal(%d) = %q, ingin %q", tt.r, got, tt.desimalTeks)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Rupiah
+		wantErr bool
+	}{
+		{`150000`, 150000 * Rp, false},
+		{`150000.5`, 150000*Rp + 50*Sen, false},
+		{`150000.50`, 150000*Rp + 50*Sen, false},
+		{`150000.500`, 150000*Rp + 50*Sen, false},
+		{`-20.25`, -(20*Rp + 25*Sen), false},
+		{`1e5`, 100000 * Rp, false},
+		{`1.5E3`, 1500 * Rp, false},
+		{`2.5e-1`, 25 * Sen, false},
+		{`0.015`, 0, true},
+		{`1e-3`, 0, true},
+		{`1e30`, 0, true},
+		{`"Rp 150.000,50"`, 150000*Rp + 50*Sen, false},
+		{`"1e5"`, 0, true},
+		{`"--5"`, 0, true},
+		{`null`, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var r Rupiah
+			err := json.Unmarshal([]byte(tt.in), &r)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Unmarshal(%s) = %d, ingin error", tt.in, r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+			}
+			if r != tt.want {
+				t.Fatalf("Unmarshal(%s) = %d, ingin %d", tt.in, r, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	for _, r := range []Rupiah{0, 1, 150000*Rp + 50*Sen, -(1500*Rp + 5*Sen), math.MaxInt64 / 2} {
+		data, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Rupiah
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != r {
+			t.Errorf("round trip %d -> %s -> %d", r, data, got)
+		}
+	}
+}
+
+func TestDariFloat(t *testing.T) {
+	tests := []struct {
+		f    float64
+		want Rupiah
+	}{
+		{0.1 + 0.2, 30 * Sen},
+		{150000.005, 150000*Rp + 1*Sen},
+		{19.99, 19*Rp + 99*Sen},
+		{-2.675, -268 * Sen},
+	}
+	for _, tt := range tests {
+		if got := DariFloat(tt.f); got != tt.want {
+			t.Errorf("DariFloat(%v) = %d, ingin %d", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestPorsi(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rupiah
+		p    Persen
+		want Rupiah
+	}{
+		{"PPN 11% bulat", 100000 * Rp, 1100, 11000 * Rp},
+		{"PPN 11% dibulatkan ke bawah", 1234 * Sen, 1100, 136 * Sen},     // 135,74 sen
+		{"PPN 11% setengah sen ke atas", 50 * Sen, 1100, 6 * Sen},        // 5,5 sen
+		{"12,5% setengah sen ke atas", 1*Rp + 4*Sen, 1250, 13 * Sen},     // 13,0 sen
+		{"12,5% eksak", 1*Rp + 20*Sen, 1250, 15 * Sen},                   // 15 sen
+		{"diskon 10% setengah sen", 5 * Sen, 1000, 1 * Sen},              // 0,5 sen
+		{"negatif setengah sen menjauhi nol", -50 * Sen, 1100, -6 * Sen}, // -5,5 sen
+		{"negatif dibulatkan ke nol", -1234 * Sen, 1100, -136 * Sen},
+		{"0%", 150000 * Rp, 0, 0},
+		{"100%", 150000*Rp + 1*Sen, Penuh, 150000*Rp + 1*Sen},
+		{"nominal nol", 0, 1100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Porsi(tt.p); got != tt.want {
+				t.Fatalf("%d.Porsi(%s) = %d, ingin %d", tt.r, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePersen(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Persen
+		wantErr bool
+	}{
+		{"11", 1100, false},
+		{"11%", 1100, false},
+		{"12,5%", 1250, false},
+		{"12.25", 1225, false},
+		{"0", 0, false},
+		{"100", Penuh, false},
+		{"100.01", 0, true},
+		{"-1", 0, true},
+		{"12,345", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePersen(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePersen(%q) = %d, ingin error", tt.in, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParsePersen(%q) = %d, %v; ingin %d", tt.in, got, err, tt.want)
+			}
+			if back, err := ParsePersen(got.String()); err != nil || back != got {
+				t.Fatalf("ParsePersen(%q) = %d, %v; ingin %d", got.String(), back, err, got)
+			}
+		})
+	}
+}
+
+func TestJumlahKali(t *testing.T) {
+	if got := Jumlah(15*Rp+50*Sen, (2 * Rp).Kali(3), -1*Sen); got != 21*Rp+49*Sen {
+		t.Fatalf("Jumlah = %d", got)
+	}
+}